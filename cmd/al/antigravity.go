@@ -10,6 +10,7 @@ import (
 )
 
 func newAntigravityCmd() *cobra.Command {
+	var profile string
 	cmd := &cobra.Command{
 		Use:   messages.AntigravityUse,
 		Short: messages.AntigravityShort,
@@ -18,11 +19,12 @@ func newAntigravityCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			return clients.Run(root, "antigravity", func(cfg *config.Config) *bool {
+			return clients.Run(root, resolveLoadOptions(profile), "antigravity", func(cfg *config.Config) *bool {
 				return cfg.Agents.Antigravity.Enabled
 			}, antigravity.Launch)
 		},
 	}
+	addProfileFlag(cmd, &profile)
 
 	return cmd
 }
@@ -10,6 +10,7 @@ import (
 )
 
 func newClaudeCmd() *cobra.Command {
+	var profile string
 	cmd := &cobra.Command{
 		Use:   messages.ClaudeUse,
 		Short: messages.ClaudeShort,
@@ -18,11 +19,12 @@ func newClaudeCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			return clients.Run(root, "claude", func(cfg *config.Config) *bool {
+			return clients.Run(root, resolveLoadOptions(profile), "claude", func(cfg *config.Config) *bool {
 				return cfg.Agents.Claude.Enabled
 			}, claude.Launch)
 		},
 	}
+	addProfileFlag(cmd, &profile)
 
 	return cmd
 }
@@ -10,6 +10,7 @@ import (
 )
 
 func newCodexCmd() *cobra.Command {
+	var profile string
 	cmd := &cobra.Command{
 		Use:   messages.CodexUse,
 		Short: messages.CodexShort,
@@ -18,11 +19,12 @@ func newCodexCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			return clients.Run(root, "codex", func(cfg *config.Config) *bool {
+			return clients.Run(root, resolveLoadOptions(profile), "codex", func(cfg *config.Config) *bool {
 				return cfg.Agents.Codex.Enabled
 			}, codex.Launch)
 		},
 	}
+	addProfileFlag(cmd, &profile)
 
 	return cmd
 }
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+func newConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   messages.ConfigUse,
+		Short: messages.ConfigShort,
+	}
+	cmd.AddCommand(newConfigShowCmd(), newConfigGetCmd(), newConfigSetCmd(), newConfigUnsetCmd(), newConfigSchemaCmd())
+	return cmd
+}
+
+func newConfigSchemaCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   messages.ConfigSchemaUse,
+		Short: messages.ConfigSchemaShort,
+		Long:  messages.ConfigSchemaLong,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := config.JSONSchema()
+			if err != nil {
+				return err
+			}
+			_, err = cmd.OutOrStdout().Write(data)
+			return err
+		},
+	}
+}
+
+func newConfigShowCmd() *cobra.Command {
+	var showOrigin bool
+	var profile string
+	cmd := &cobra.Command{
+		Use:   messages.ConfigShowUse,
+		Short: messages.ConfigShowShort,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			layered, err := config.LoadLayeredConfig(config.DefaultPaths(root), resolveLoadOptions(profile).Profile)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if showOrigin {
+				return writeConfigOrigins(out, root, layered)
+			}
+			data, err := layered.MarshalTOML()
+			if err != nil {
+				return err
+			}
+			_, err = out.Write(data)
+			return err
+		},
+	}
+	cmd.Flags().BoolVar(&showOrigin, "origin", false, messages.ConfigShowFlagOrigin)
+	addProfileFlag(cmd, &profile)
+	return cmd
+}
+
+// writeConfigOrigins prints every effective key with its value and the layer that set it.
+func writeConfigOrigins(out io.Writer, root string, layered *config.LayeredConfig) error {
+	for _, entry := range layered.Entries {
+		origin := fmt.Sprintf(messages.ConfigShowOriginFmt, entry.Origin.Layer, displayConfigPath(root, entry.Origin.Path))
+		if _, err := fmt.Fprintf(out, "%s = %s  # %s\n", entry.Key, formatConfigValue(entry.Value), origin); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// displayConfigPath returns path relative to root when it lives inside the repo.
+func displayConfigPath(root string, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+// formatConfigValue renders a raw TOML value as an inline TOML literal.
+func formatConfigValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case []any:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, formatConfigValue(item))
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		parts := make([]string, 0, len(keys))
+		for _, key := range keys {
+			parts = append(parts, fmt.Sprintf("%s = %s", key, formatConfigValue(v[key])))
+		}
+		return "{ " + strings.Join(parts, ", ") + " }"
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
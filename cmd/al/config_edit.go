@@ -0,0 +1,205 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/fsutil"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/wizard"
+)
+
+var writeConfigFile = fsutil.WriteFileAtomic
+
+func newConfigGetCmd() *cobra.Command {
+	var profile string
+	cmd := &cobra.Command{
+		Use:   messages.ConfigGetUse,
+		Short: messages.ConfigGetShort,
+		Long:  messages.ConfigGetLong,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			layered, err := config.LoadLayeredConfig(config.DefaultPaths(root), resolveLoadOptions(profile).Profile)
+			if err != nil {
+				return err
+			}
+			return writeConfigValue(cmd.OutOrStdout(), layered, args[0])
+		},
+	}
+	addProfileFlag(cmd, &profile)
+	return cmd
+}
+
+func newConfigSetCmd() *cobra.Command {
+	var local, global bool
+	cmd := &cobra.Command{
+		Use:   messages.ConfigSetUse,
+		Short: messages.ConfigSetShort,
+		Long:  messages.ConfigSetLong,
+		Args:  configSetArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConfigEdit(cmd, configEditLayer(local, global), func(content string) (string, error) {
+				var err error
+				for i := 0; i < len(args); i += 2 {
+					content, err = wizard.SetConfigValue(content, args[i], wizard.ParseConfigValue(args[i+1]))
+					if err != nil {
+						return "", err
+					}
+				}
+				return content, nil
+			})
+		},
+	}
+	addConfigLayerFlags(cmd, &local, &global)
+	return cmd
+}
+
+func newConfigUnsetCmd() *cobra.Command {
+	var local, global bool
+	cmd := &cobra.Command{
+		Use:   messages.ConfigUnsetUse,
+		Short: messages.ConfigUnsetShort,
+		Long:  messages.ConfigUnsetLong,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConfigEdit(cmd, configEditLayer(local, global), func(content string) (string, error) {
+				return wizard.UnsetConfigValue(content, args[0])
+			})
+		},
+	}
+	addConfigLayerFlags(cmd, &local, &global)
+	return cmd
+}
+
+// configSetArgs requires one or more key/value pairs.
+func configSetArgs(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 || len(args)%2 != 0 {
+		return errors.New(messages.ConfigSetArgsPairs)
+	}
+	return nil
+}
+
+// addConfigLayerFlags registers the mutually exclusive --local and --global layer selectors.
+func addConfigLayerFlags(cmd *cobra.Command, local *bool, global *bool) {
+	cmd.Flags().BoolVar(local, "local", false, messages.ConfigFlagLocal)
+	cmd.Flags().BoolVar(global, "global", false, messages.ConfigFlagGlobal)
+	cmd.MarkFlagsMutuallyExclusive("local", "global")
+}
+
+// configEditLayer returns the layer name selected by the --local/--global flags.
+func configEditLayer(local bool, global bool) string {
+	switch {
+	case local:
+		return config.LayerLocal
+	case global:
+		return config.LayerUser
+	default:
+		return config.LayerProject
+	}
+}
+
+// runConfigEdit applies edit to one config layer, validates the merged result, and writes the layer atomically.
+// Nothing is written when the edited config would not load.
+func runConfigEdit(cmd *cobra.Command, layerName string, edit func(content string) (string, error)) error {
+	root, err := resolveRepoRoot()
+	if err != nil {
+		return err
+	}
+	paths := config.DefaultPaths(root)
+	layers := config.ConfigLayers(paths)
+	var target *config.ConfigLayer
+	for i := range layers {
+		if layers[i].Name == layerName {
+			target = &layers[i]
+		}
+	}
+	if target == nil {
+		return errors.New(messages.ConfigEditNoUserPath)
+	}
+	sources, err := config.ReadConfigLayers(layers)
+	if err != nil {
+		return err
+	}
+
+	var current []byte
+	edited := make([]config.LayerSource, 0, len(layers))
+	for _, src := range sources {
+		if src.Layer.Name != layerName {
+			edited = append(edited, src)
+		} else {
+			current = src.Data
+		}
+	}
+	updated, err := edit(string(current))
+	if err != nil {
+		return err
+	}
+	edited = append(edited, config.LayerSource{Layer: *target, Data: []byte(updated)})
+	sortLayerSources(edited, layers)
+	if _, err := config.MergeConfigLayers(edited, paths.ConfigPath, ""); err != nil {
+		return fmt.Errorf(messages.ConfigEditInvalidFmt, displayConfigPath(root, target.Path), err)
+	}
+
+	perm := os.FileMode(0o644)
+	if info, err := os.Stat(target.Path); err == nil {
+		perm = info.Mode().Perm()
+	}
+	if err := os.MkdirAll(filepath.Dir(target.Path), 0o755); err != nil {
+		return err
+	}
+	if err := writeConfigFile(target.Path, []byte(updated), perm); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(cmd.ErrOrStderr(), messages.ConfigUpdatedFmt, displayConfigPath(root, target.Path))
+	return err
+}
+
+// sortLayerSources restores merge order after a layer source was replaced or added.
+func sortLayerSources(sources []config.LayerSource, layers []config.ConfigLayer) {
+	rank := make(map[string]int, len(layers))
+	for i, layer := range layers {
+		rank[layer.Name] = i
+	}
+	for i := 1; i < len(sources); i++ {
+		for j := i; j > 0 && rank[sources[j].Layer.Name] < rank[sources[j-1].Layer.Name]; j-- {
+			sources[j], sources[j-1] = sources[j-1], sources[j]
+		}
+	}
+}
+
+// writeConfigValue prints the effective value of key, or every setting below it when key is a table.
+func writeConfigValue(out io.Writer, layered *config.LayeredConfig, key string) error {
+	if entry, ok := layered.Entry(key); ok {
+		value := formatConfigValue(entry.Value)
+		if s, isString := entry.Value.(string); isString {
+			value = s
+		}
+		_, err := fmt.Fprintln(out, value)
+		return err
+	}
+	found := false
+	for _, entry := range layered.Entries {
+		if !strings.HasPrefix(entry.Key, key+".") {
+			continue
+		}
+		found = true
+		if _, err := fmt.Fprintf(out, "%s = %s\n", strings.TrimPrefix(entry.Key, key+"."), formatConfigValue(entry.Value)); err != nil {
+			return err
+		}
+	}
+	if !found {
+		return fmt.Errorf(messages.ConfigKeyNotSetFmt, key)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+// runConfigCmd executes `al config <args>` and returns stdout.
+func runConfigCmd(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	cmd := newConfigCmd()
+	cmd.SetArgs(args)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestConfigSetGetUnset(t *testing.T) {
+	root := t.TempDir()
+	writeTestRepo(t, root)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	paths := config.DefaultPaths(root)
+
+	withWorkingDir(t, root, func() {
+		if _, err := runConfigCmd(t, "set", "approvals.mode", "commands", "agents.claude.model", "opus"); err != nil {
+			t.Fatalf("config set error: %v", err)
+		}
+		out, err := runConfigCmd(t, "get", "approvals.mode")
+		if err != nil || out != "commands\n" {
+			t.Fatalf("config get = %q, %v", out, err)
+		}
+		out, err = runConfigCmd(t, "get", "agents.claude")
+		if err != nil || !strings.Contains(out, "enabled = true\n") || !strings.Contains(out, "model = \"opus\"\n") {
+			t.Fatalf("config get table = %q, %v", out, err)
+		}
+
+		if _, err := runConfigCmd(t, "unset", "agents.claude.model"); err != nil {
+			t.Fatalf("config unset error: %v", err)
+		}
+		if _, err := runConfigCmd(t, "get", "agents.claude.model"); err == nil || !strings.Contains(err.Error(), "is not set") {
+			t.Fatalf("expected not set error, got %v", err)
+		}
+	})
+
+	data, err := os.ReadFile(paths.ConfigPath)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	if !strings.Contains(string(data), `mode = "commands"`) {
+		t.Fatalf("expected config.toml to be updated:\n%s", data)
+	}
+}
+
+func TestConfigSetRejectsInvalidConfig(t *testing.T) {
+	root := t.TempDir()
+	writeTestRepo(t, root)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	paths := config.DefaultPaths(root)
+	before, err := os.ReadFile(paths.ConfigPath)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+
+	withWorkingDir(t, root, func() {
+		for _, args := range [][]string{
+			{"set", "approvals.mode", "sometimes"},
+			{"set", "agents.claude.modle", "opus"},
+			{"set", "mcp.servers.extra.transport", "stdio"},
+		} {
+			if _, err := runConfigCmd(t, args...); err == nil || !strings.Contains(err.Error(), "not writing .agent-layer/config.toml") {
+				t.Fatalf("%v: expected validation error, got %v", args, err)
+			}
+		}
+		if _, err := runConfigCmd(t, "set", "approvals.mode"); err == nil || !strings.Contains(err.Error(), "pairs") {
+			t.Fatalf("expected pairs error, got %v", err)
+		}
+	})
+
+	after, err := os.ReadFile(paths.ConfigPath)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Fatalf("config.toml must not change when validation fails")
+	}
+}
+
+func TestConfigSetLocalAndGlobal(t *testing.T) {
+	root := t.TempDir()
+	writeTestRepo(t, root)
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	paths := config.DefaultPaths(root)
+
+	withWorkingDir(t, root, func() {
+		if _, err := runConfigCmd(t, "set", "--local", "approvals.mode", "none"); err != nil {
+			t.Fatalf("config set --local error: %v", err)
+		}
+		if _, err := runConfigCmd(t, "set", "--global", "agents.codex.reasoning_effort", "high"); err != nil {
+			t.Fatalf("config set --global error: %v", err)
+		}
+		if _, err := runConfigCmd(t, "set", "--local", "--global", "approvals.mode", "none"); err == nil {
+			t.Fatalf("expected --local and --global to be exclusive")
+		}
+		out, err := runConfigCmd(t, "get", "approvals.mode")
+		if err != nil || out != "none\n" {
+			t.Fatalf("config get = %q, %v", out, err)
+		}
+	})
+
+	if _, err := os.Stat(paths.LocalConfigPath); err != nil {
+		t.Fatalf("expected local config: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(xdg, "agent-layer", "config.toml")); err != nil {
+		t.Fatalf("expected user config: %v", err)
+	}
+}
+
+func TestConfigSetWriteError(t *testing.T) {
+	root := t.TempDir()
+	writeTestRepo(t, root)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	original := writeConfigFile
+	writeConfigFile = func(string, []byte, os.FileMode) error { return errors.New("disk full") }
+	t.Cleanup(func() { writeConfigFile = original })
+
+	withWorkingDir(t, root, func() {
+		if _, err := runConfigCmd(t, "set", "approvals.mode", "none"); err == nil || !strings.Contains(err.Error(), "disk full") {
+			t.Fatalf("expected write error, got %v", err)
+		}
+	})
+}
+
+func TestConfigEditLayer(t *testing.T) {
+	if configEditLayer(false, false) != config.LayerProject || configEditLayer(true, false) != config.LayerLocal || configEditLayer(false, true) != config.LayerUser {
+		t.Fatalf("unexpected layer selection")
+	}
+}
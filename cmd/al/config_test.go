@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+func TestConfigShowCommand(t *testing.T) {
+	root := t.TempDir()
+	writeTestRepo(t, root)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	paths := config.DefaultPaths(root)
+	if err := os.WriteFile(paths.LocalConfigPath, []byte("[agents.claude]\nmodel = \"opus\"\n"), 0o644); err != nil {
+		t.Fatalf("write local config: %v", err)
+	}
+
+	withWorkingDir(t, root, func() {
+		cmd := newConfigCmd()
+		cmd.SetArgs([]string{"show"})
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("config show error: %v", err)
+		}
+		if !strings.Contains(out.String(), "model = 'opus'") {
+			t.Fatalf("expected merged local value, got %q", out.String())
+		}
+	})
+}
+
+func TestConfigShowOrigin(t *testing.T) {
+	root := t.TempDir()
+	writeTestRepo(t, root)
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	userConfig := filepath.Join(xdg, "agent-layer", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(userConfig), 0o755); err != nil {
+		t.Fatalf("mkdir user config: %v", err)
+	}
+	if err := os.WriteFile(userConfig, []byte("[agents.codex]\nreasoning_effort = \"high\"\n"), 0o644); err != nil {
+		t.Fatalf("write user config: %v", err)
+	}
+	paths := config.DefaultPaths(root)
+	if err := os.WriteFile(paths.LocalConfigPath, []byte("[approvals]\nmode = \"none\"\n"), 0o644); err != nil {
+		t.Fatalf("write local config: %v", err)
+	}
+
+	withWorkingDir(t, root, func() {
+		cmd := newConfigCmd()
+		cmd.SetArgs([]string{"show", "--origin"})
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("config show error: %v", err)
+		}
+		got := out.String()
+		for _, want := range []string{
+			`approvals.mode = "none"  # local (.agent-layer/config.local.toml)`,
+			`agents.claude.enabled = true  # project (.agent-layer/config.toml)`,
+			`agents.codex.reasoning_effort = "high"  # user (` + userConfig + `)`,
+		} {
+			if !strings.Contains(got, want) {
+				t.Fatalf("expected %q in output:\n%s", want, got)
+			}
+		}
+	})
+}
+
+func TestConfigShowMissingRepo(t *testing.T) {
+	withWorkingDir(t, t.TempDir(), func() {
+		cmd := newConfigCmd()
+		cmd.SetArgs([]string{"show"})
+		cmd.SetOut(&bytes.Buffer{})
+		if err := cmd.Execute(); err == nil {
+			t.Fatalf("expected error without .agent-layer")
+		}
+	})
+}
+
+func TestFormatConfigValue(t *testing.T) {
+	cases := map[string]any{
+		`"a\"b"`:                 `a"b`,
+		`["x", 1]`:               []any{"x", int64(1)},
+		`{ A = "1", B = false }`: map[string]any{"B": false, "A": "1"},
+		`42`:                     int64(42),
+	}
+	for want, value := range cases {
+		if got := formatConfigValue(value); got != want {
+			t.Fatalf("formatConfigValue(%v) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestConfigSchemaCommand(t *testing.T) {
+	cmd := newConfigCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"schema"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("config schema error: %v", err)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(out.Bytes(), &schema); err != nil {
+		t.Fatalf("expected JSON schema output: %v", err)
+	}
+	if schema["title"] != "Agent Layer config" {
+		t.Fatalf("unexpected schema title: %v", schema["title"])
+	}
+}
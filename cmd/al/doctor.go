@@ -19,12 +19,13 @@ import (
 )
 
 var (
-	checkInstructions = warnings.CheckInstructions
+	checkInstructions = warnings.CheckClientInstructions
 	checkMCPServers   = warnings.CheckMCPServers
 )
 
 func newDoctorCmd() *cobra.Command {
-	return &cobra.Command{
+	var profile string
+	cmd := &cobra.Command{
 		Use:   messages.DoctorUse,
 		Short: messages.DoctorShort,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -41,7 +42,7 @@ func newDoctorCmd() *cobra.Command {
 			allResults = append(allResults, doctor.CheckStructure(root)...)
 
 			// 2. Check Config
-			configResults, cfg := doctor.CheckConfig(root)
+			configResults, cfg := doctor.CheckConfig(root, resolveLoadOptions(profile))
 			allResults = append(allResults, configResults...)
 
 			updateResult := doctor.Result{CheckName: messages.DoctorCheckNameUpdate}
@@ -86,20 +87,23 @@ func newDoctorCmd() *cobra.Command {
 				}
 			}
 
-			// 5. Run Warning System (Instructions + MCP)
+			// 5. Run Warning System (Instructions + Slash commands + MCP)
 			// Only run if basic config loaded successfully, otherwise we might crash or be useless.
 			var warningList []warnings.Warning
 			if cfg != nil {
 				fmt.Println(messages.DoctorWarningSystemHeader)
 
 				// Instructions check
-				instWarnings, err := checkInstructions(root, cfg.Config.Warnings.InstructionTokenThreshold)
+				instWarnings, err := checkInstructions(cfg)
 				if err != nil {
 					color.Red(messages.DoctorInstructionsCheckFailedFmt, err)
 					hasFail = true
 				} else {
 					warningList = append(warningList, instWarnings...)
 				}
+				warningList = append(warningList, warnings.CheckTokenizers(cfg)...)
+				warningList = append(warningList, warnings.CheckInstructionFrontMatter(cfg)...)
+				warningList = append(warningList, warnings.CheckSlashCommands(cfg)...)
 
 				// MCP check (Doctor runs discovery)
 				stopProgress := startMCPProgress(countEnabledMCPServers(cfg.Config.MCP.Servers))
@@ -132,6 +136,9 @@ func newDoctorCmd() *cobra.Command {
 			return nil
 		},
 	}
+	addProfileFlag(cmd, &profile)
+
+	return cmd
 }
 
 func printResult(r doctor.Result) {
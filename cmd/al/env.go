@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/envfile"
+	"github.com/conn-castle/agent-layer/internal/fsutil"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+var runEditor = runEditorCommand
+
+func newEnvCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   messages.EnvUse,
+		Short: messages.EnvShort,
+	}
+	cmd.AddCommand(newEnvListCmd(), newEnvSetCmd(), newEnvCheckCmd(), newEnvExampleCmd(), newEnvEditCmd())
+	return cmd
+}
+
+func newEnvEditCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   messages.EnvEditUse,
+		Short: messages.EnvEditShort,
+		Long:  messages.EnvEditLong,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			return runEnvEdit(config.DefaultPaths(root).EnvPath, cmd.InOrStdin(), cmd.OutOrStdout(), cmd.ErrOrStderr())
+		},
+	}
+}
+
+// runEnvEdit decrypts envPath+".age" (or starts from the plaintext envPath) into a private temp file,
+// opens the editor until the content parses, then re-encrypts and shreds the temp file.
+func runEnvEdit(envPath string, stdin io.Reader, stdout, stderr io.Writer) error {
+	encryptedPath := envPath + envfile.AgeSuffix
+	identity := config.AgeIdentityPath()
+	if _, err := os.Stat(identity); os.IsNotExist(err) {
+		return fmt.Errorf(messages.EnvfileAgeIdentityMissingFmt, identity, identity)
+	}
+
+	creating := false
+	original, err := os.ReadFile(encryptedPath)
+	switch {
+	case err == nil:
+		if original, err = config.DecodeEnvFile(encryptedPath, original); err != nil {
+			return err
+		}
+	case os.IsNotExist(err):
+		creating = true
+		original, err = os.ReadFile(envPath)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	default:
+		return err
+	}
+
+	tmp, err := os.CreateTemp("", "al-env-*.env")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer func() { _ = fsutil.ShredFile(tmpPath) }()
+	_, writeErr := tmp.Write(original)
+	if closeErr := tmp.Close(); writeErr == nil {
+		writeErr = closeErr
+	}
+	if writeErr != nil {
+		return writeErr
+	}
+
+	var edited, lastInvalid []byte
+	for {
+		if err := runEditor(tmpPath, stdin, stdout, stderr); err != nil {
+			return err
+		}
+		if edited, err = os.ReadFile(tmpPath); err != nil {
+			return err
+		}
+		_, parseErr := envfile.Parse(string(edited))
+		if parseErr == nil {
+			break
+		}
+		if lastInvalid != nil && bytes.Equal(edited, lastInvalid) {
+			return fmt.Errorf(messages.EnvEditAbortedFmt, encryptedPath, parseErr)
+		}
+		lastInvalid = edited
+		_, _ = fmt.Fprintf(stderr, messages.EnvEditInvalidFmt, parseErr)
+	}
+
+	if !creating && bytes.Equal(edited, original) {
+		_, err := fmt.Fprintf(stdout, messages.EnvEditNoChangesFmt, encryptedPath)
+		return err
+	}
+	ciphertext, err := config.EncodeEnvFile(encryptedPath, edited)
+	if err != nil {
+		return err
+	}
+	if err := fsutil.WriteFileAtomic(encryptedPath, ciphertext, 0o600); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(stdout, messages.EnvEditSavedFmt, encryptedPath); err != nil {
+		return err
+	}
+	if _, err := os.Stat(envPath); err == nil {
+		_, err = fmt.Fprintf(stdout, messages.EnvEditPlaintextRemainsFmt, envPath, encryptedPath)
+		return err
+	}
+	return nil
+}
+
+// runEditorCommand opens path in $VISUAL, $EDITOR, or the platform default editor.
+func runEditorCommand(path string, stdin io.Reader, stdout, stderr io.Writer) error {
+	editor := strings.Fields(os.Getenv("VISUAL"))
+	if len(editor) == 0 {
+		editor = strings.Fields(os.Getenv("EDITOR"))
+	}
+	if len(editor) == 0 {
+		editor = []string{"vi"}
+		if runtime.GOOS == "windows" {
+			editor = []string{"notepad"}
+		}
+	}
+	cmd := exec.Command(editor[0], append(editor[1:], path)...)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf(messages.EnvEditEditorFailedFmt, editor[0], err)
+	}
+	return nil
+}
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/envfile"
+	"github.com/conn-castle/agent-layer/internal/fsutil"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/wizard"
+)
+
+var promptSecret = func(title string, value *string) error {
+	return wizard.NewHuhUI().SecretInput(title, value)
+}
+
+// envState is the repo's .env content alongside the MCP servers that reference it.
+type envState struct {
+	paths config.Paths
+	// path is the file that holds the values: .env, or .env.age when it exists.
+	path    string
+	content string
+	values  map[string]string
+	servers []config.MCPServer
+}
+
+// loadEnvState reads the merged config (with profile applied) and the decrypted .env content.
+func loadEnvState(root string, profile string) (*envState, error) {
+	paths := config.DefaultPaths(root)
+	layered, err := config.LoadLayeredConfig(paths, profile)
+	if err != nil {
+		return nil, err
+	}
+	state := &envState{paths: paths, path: config.EnvFilePath(paths.EnvPath), servers: layered.Config.MCP.Servers}
+	data, err := os.ReadFile(state.path)
+	switch {
+	case err == nil:
+		if data, err = config.DecodeEnvFile(state.path, data); err != nil {
+			return nil, err
+		}
+		state.content = string(data)
+	case !os.IsNotExist(err):
+		return nil, err
+	}
+	if state.values, err = envfile.Parse(state.content); err != nil {
+		return nil, fmt.Errorf(messages.ConfigInvalidEnvFileFmt, state.path, err)
+	}
+	return state, nil
+}
+
+// lookup returns a key's effective value and its source; the process environment wins over .env,
+// matching how clients are launched.
+func (s *envState) lookup(key string) (string, string) {
+	if value := os.Getenv(key); value != "" {
+		return value, messages.EnvSourceProcess
+	}
+	if value := s.values[key]; value != "" {
+		return value, messages.EnvSourceEnvFile
+	}
+	return "", messages.EnvSourceNone
+}
+
+func newEnvListCmd() *cobra.Command {
+	var profile string
+	cmd := &cobra.Command{
+		Use:   messages.EnvListUse,
+		Short: messages.EnvListShort,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			state, err := loadEnvState(root, resolveLoadOptions(profile).Profile)
+			if err != nil {
+				return err
+			}
+			return writeEnvList(cmd.OutOrStdout(), state)
+		},
+	}
+	addProfileFlag(cmd, &profile)
+	return cmd
+}
+
+// writeEnvList prints every required or defined key with a masked value, its source, and its servers.
+func writeEnvList(out io.Writer, state *envState) error {
+	enabled := make(map[string]bool)
+	for _, server := range state.servers {
+		enabled[server.ID] = server.Enabled != nil && *server.Enabled
+	}
+	usage := config.RequiredEnvVarServers(state.servers)
+	keys := make(map[string]struct{})
+	for key := range usage {
+		keys[key] = struct{}{}
+	}
+	for key := range state.values {
+		keys[key] = struct{}{}
+	}
+
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(writer, messages.EnvListHeader); err != nil {
+		return err
+	}
+	for _, key := range sortedKeys(keys) {
+		value, source := state.lookup(key)
+		masked := messages.EnvNotSet
+		if value != "" {
+			masked = maskValue(value)
+		}
+		usedBy := make([]string, 0, len(usage[key]))
+		for _, id := range usage[key] {
+			if !enabled[id] {
+				id = fmt.Sprintf(messages.EnvDisabledServerFmt, id)
+			}
+			usedBy = append(usedBy, id)
+		}
+		users := messages.EnvUnused
+		if len(usedBy) > 0 {
+			users = strings.Join(usedBy, ", ")
+		}
+		if _, err := fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", key, masked, source, users); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
+// maskValue hides a value, keeping the last four characters only when the value is long enough
+// that they give nothing useful away.
+func maskValue(value string) string {
+	if len(value) < 12 {
+		return "****"
+	}
+	return "****" + value[len(value)-4:]
+}
+
+func newEnvSetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   messages.EnvSetUse,
+		Short: messages.EnvSetShort,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key := args[0]
+			if !config.IsValidEnvVarName(key) {
+				return fmt.Errorf(messages.EnvSetInvalidKeyFmt, key)
+			}
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			value, err := readEnvValue(cmd.InOrStdin(), key)
+			if err != nil {
+				return err
+			}
+			path, err := setEnvValue(config.DefaultPaths(root).EnvPath, key, value)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), messages.EnvSetUpdatedFmt, key, path)
+			return err
+		},
+	}
+}
+
+// readEnvValue prompts for a hidden value on a terminal and reads one line of stdin otherwise.
+func readEnvValue(stdin io.Reader, key string) (string, error) {
+	var value string
+	if isTerminal() {
+		if err := promptSecret(fmt.Sprintf(messages.EnvSetPromptFmt, key), &value); err != nil {
+			return "", err
+		}
+	} else {
+		line, err := bufio.NewReader(stdin).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", err
+		}
+		value = strings.TrimRight(line, "\r\n")
+	}
+	if value == "" {
+		return "", fmt.Errorf(messages.EnvSetEmptyValueFmt, key)
+	}
+	return value, nil
+}
+
+// setEnvValue patches key into the env file (re-encrypting .env.age when present) and returns the path written.
+func setEnvValue(envPath string, key string, value string) (string, error) {
+	path := config.EnvFilePath(envPath)
+	perm := os.FileMode(0o600)
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if info, statErr := os.Stat(path); statErr == nil {
+			perm = info.Mode().Perm()
+		}
+		if data, err = config.DecodeEnvFile(path, data); err != nil {
+			return "", err
+		}
+	case !os.IsNotExist(err):
+		return "", err
+	}
+	patched := envfile.Patch(string(data), map[string]string{key: value})
+	encoded, err := config.EncodeEnvFile(path, []byte(patched))
+	if err != nil {
+		return "", err
+	}
+	if err := fsutil.WriteFileAtomic(path, encoded, perm); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func newEnvCheckCmd() *cobra.Command {
+	var profile string
+	cmd := &cobra.Command{
+		Use:   messages.EnvCheckUse,
+		Short: messages.EnvCheckShort,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			state, err := loadEnvState(root, resolveLoadOptions(profile).Profile)
+			if err != nil {
+				return err
+			}
+			return checkEnv(cmd.OutOrStdout(), cmd.ErrOrStderr(), state)
+		},
+	}
+	addProfileFlag(cmd, &profile)
+	return cmd
+}
+
+// checkEnv reports keys required by enabled servers that are neither in .env nor the environment.
+func checkEnv(stdout, stderr io.Writer, state *envState) error {
+	var enabled []config.MCPServer
+	for _, server := range state.servers {
+		if server.Enabled != nil && *server.Enabled {
+			enabled = append(enabled, server)
+		}
+	}
+	usage := config.RequiredEnvVarServers(enabled)
+	keys := make(map[string]struct{}, len(usage))
+	for key := range usage {
+		keys[key] = struct{}{}
+	}
+	missing := 0
+	for _, key := range sortedKeys(keys) {
+		if value, _ := state.lookup(key); value != "" {
+			continue
+		}
+		missing++
+		if _, err := fmt.Fprintf(stderr, messages.EnvCheckMissingFmt, key, strings.Join(usage[key], ", ")); err != nil {
+			return err
+		}
+	}
+	if missing > 0 {
+		return fmt.Errorf(messages.EnvCheckFailedFmt, missing)
+	}
+	_, err := fmt.Fprintln(stdout, messages.EnvCheckOK)
+	return err
+}
+
+func newEnvExampleCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   messages.EnvExampleUse,
+		Short: messages.EnvExampleShort,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			state, err := loadEnvState(root, "")
+			if err != nil {
+				return err
+			}
+			usage := config.RequiredEnvVarServers(state.servers)
+			path := state.paths.EnvPath + ".example"
+			if err := fsutil.WriteFileAtomic(path, []byte(renderEnvExample(usage)), 0o644); err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), messages.EnvExampleWrittenFmt, path, len(usage))
+			return err
+		},
+	}
+}
+
+// renderEnvExample lists every required key with an empty value and the servers that use it.
+func renderEnvExample(usage map[string][]string) string {
+	keys := make([]string, 0, len(usage))
+	for key := range usage {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	b.WriteString(messages.EnvExampleHeader)
+	for _, key := range keys {
+		b.WriteString("\n")
+		fmt.Fprintf(&b, messages.EnvExampleUsedByFmt, strings.Join(usage[key], ", "))
+		b.WriteString(key + "=\n")
+	}
+	return b.String()
+}
+
+// sortedKeys returns the members of a string set in sorted order.
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+const envTestServers = `
+[[mcp.servers]]
+id = "github"
+enabled = true
+transport = "http"
+url = "https://example.com/mcp"
+headers = { Authorization = "Bearer ${GITHUB_TOKEN}" }
+
+[[mcp.servers]]
+id = "search"
+enabled = false
+transport = "stdio"
+command = "search-mcp"
+env = { SEARCH_KEY = "${SEARCH_KEY}", TOKEN = "${GITHUB_TOKEN}" }
+`
+
+// writeEnvTestRepo writes a repo with two servers and the given .env content.
+func writeEnvTestRepo(t *testing.T, env string) config.Paths {
+	t.Helper()
+	root := t.TempDir()
+	writeTestRepo(t, root)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("SEARCH_KEY", "")
+	paths := config.DefaultPaths(root)
+	f, err := os.OpenFile(paths.ConfigPath, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("open config: %v", err)
+	}
+	if _, err := f.WriteString(envTestServers); err != nil {
+		t.Fatalf("append servers: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("close config: %v", err)
+	}
+	if err := os.WriteFile(paths.EnvPath, []byte(env), 0o600); err != nil {
+		t.Fatalf("write env: %v", err)
+	}
+	return paths
+}
+
+// runEnvCmd executes `al env <args>` and returns stdout and stderr.
+func runEnvCmd(t *testing.T, stdin string, args ...string) (string, string, error) {
+	t.Helper()
+	cmd := newEnvCmd()
+	cmd.SetArgs(args)
+	cmd.SetIn(strings.NewReader(stdin))
+	var out, errOut bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	err := cmd.Execute()
+	return out.String(), errOut.String(), err
+}
+
+func TestEnvList(t *testing.T) {
+	paths := writeEnvTestRepo(t, "GITHUB_TOKEN=ghp_0123456789abcdef\nEXTRA=short\n")
+
+	withWorkingDir(t, paths.Root, func() {
+		out, _, err := runEnvCmd(t, "", "list")
+		if err != nil {
+			t.Fatalf("env list error: %v", err)
+		}
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		if len(lines) != 4 {
+			t.Fatalf("expected header and 3 keys, got:\n%s", out)
+		}
+		checks := map[int][]string{
+			1: {"EXTRA", "****", ".env", "-"},
+			2: {"GITHUB_TOKEN", "****cdef", ".env", "github, search (disabled)"},
+			3: {"SEARCH_KEY", "(not set)", "search (disabled)"},
+		}
+		for i, parts := range checks {
+			for _, part := range parts {
+				if !strings.Contains(lines[i], part) {
+					t.Fatalf("line %d %q missing %q", i, lines[i], part)
+				}
+			}
+		}
+		if strings.Contains(out, "0123456789") || strings.Contains(out, "short") {
+			t.Fatalf("expected values to be masked:\n%s", out)
+		}
+
+		t.Setenv("SEARCH_KEY", "from-shell-value")
+		out, _, err = runEnvCmd(t, "", "list")
+		if err != nil || !strings.Contains(out, "environment") {
+			t.Fatalf("expected process env source, got %q, %v", out, err)
+		}
+	})
+}
+
+func TestEnvSetFromStdin(t *testing.T) {
+	paths := writeEnvTestRepo(t, "# tokens\nGITHUB_TOKEN=old\n")
+	original := isTerminal
+	isTerminal = func() bool { return false }
+	t.Cleanup(func() { isTerminal = original })
+
+	withWorkingDir(t, paths.Root, func() {
+		out, _, err := runEnvCmd(t, "new value\n", "set", "GITHUB_TOKEN")
+		if err != nil {
+			t.Fatalf("env set error: %v", err)
+		}
+		if !strings.Contains(out, "Set GITHUB_TOKEN in "+paths.EnvPath) {
+			t.Fatalf("unexpected output %q", out)
+		}
+		data, err := os.ReadFile(paths.EnvPath)
+		if err != nil {
+			t.Fatalf("read env: %v", err)
+		}
+		if string(data) != "# tokens\nGITHUB_TOKEN=\"new value\"\n" {
+			t.Fatalf("unexpected env content %q", data)
+		}
+
+		if _, _, err := runEnvCmd(t, "", "set", "GITHUB_TOKEN"); err == nil || !strings.Contains(err.Error(), "no value given") {
+			t.Fatalf("expected empty value error, got %v", err)
+		}
+		if _, _, err := runEnvCmd(t, "x\n", "set", "BAD-KEY"); err == nil || !strings.Contains(err.Error(), "invalid env key") {
+			t.Fatalf("expected invalid key error, got %v", err)
+		}
+	})
+}
+
+func TestEnvSetPromptsOnTerminal(t *testing.T) {
+	paths := writeEnvTestRepo(t, "")
+	originalTerminal, originalPrompt := isTerminal, promptSecret
+	t.Cleanup(func() { isTerminal, promptSecret = originalTerminal, originalPrompt })
+	isTerminal = func() bool { return true }
+	var gotTitle string
+	promptSecret = func(title string, value *string) error {
+		gotTitle = title
+		*value = "typed"
+		return nil
+	}
+
+	withWorkingDir(t, paths.Root, func() {
+		if _, _, err := runEnvCmd(t, "", "set", "SEARCH_KEY"); err != nil {
+			t.Fatalf("env set error: %v", err)
+		}
+	})
+	if gotTitle != "Value for SEARCH_KEY" {
+		t.Fatalf("unexpected prompt %q", gotTitle)
+	}
+	if data, _ := os.ReadFile(paths.EnvPath); string(data) != "SEARCH_KEY=typed" {
+		t.Fatalf("unexpected env content %q", data)
+	}
+}
+
+func TestEnvCheck(t *testing.T) {
+	paths := writeEnvTestRepo(t, "")
+
+	withWorkingDir(t, paths.Root, func() {
+		_, stderr, err := runEnvCmd(t, "", "check")
+		if err == nil || !strings.Contains(err.Error(), "1 required environment variable(s) missing") {
+			t.Fatalf("expected check failure, got %v", err)
+		}
+		// Keys used only by disabled servers are not required.
+		if !strings.HasPrefix(stderr, "missing GITHUB_TOKEN (required by github)\n") || strings.Contains(stderr, "SEARCH_KEY") {
+			t.Fatalf("unexpected stderr %q", stderr)
+		}
+
+		if err := os.WriteFile(paths.EnvPath, []byte("GITHUB_TOKEN=abc\n"), 0o600); err != nil {
+			t.Fatalf("write env: %v", err)
+		}
+		out, _, err := runEnvCmd(t, "", "check")
+		if err != nil || !strings.Contains(out, "All required environment variables are set.") {
+			t.Fatalf("expected check success, got %q, %v", out, err)
+		}
+	})
+}
+
+func TestEnvExample(t *testing.T) {
+	paths := writeEnvTestRepo(t, "GITHUB_TOKEN=secret\n")
+
+	withWorkingDir(t, paths.Root, func() {
+		out, _, err := runEnvCmd(t, "", "example")
+		if err != nil {
+			t.Fatalf("env example error: %v", err)
+		}
+		examplePath := filepath.Join(paths.Root, ".agent-layer", ".env.example")
+		if !strings.Contains(out, "Wrote "+examplePath+" (2 keys)") {
+			t.Fatalf("unexpected output %q", out)
+		}
+		data, err := os.ReadFile(examplePath)
+		if err != nil {
+			t.Fatalf("read example: %v", err)
+		}
+		want := "# Required by: github, search\nGITHUB_TOKEN=\n\n# Required by: search\nSEARCH_KEY=\n"
+		if !strings.HasSuffix(string(data), want) || strings.Contains(string(data), "secret") {
+			t.Fatalf("unexpected example content:\n%s", data)
+		}
+	})
+}
+
+func TestMaskValue(t *testing.T) {
+	if got := maskValue("short"); got != "****" {
+		t.Fatalf("unexpected mask %q", got)
+	}
+	if got := maskValue("ghp_0123456789abcdef"); got != "****cdef" {
+		t.Fatalf("unexpected mask %q", got)
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+// writeFakeAge puts an `age` stand-in on PATH that "encrypts" by prefixing AGE:.
+func writeFakeAge(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+	dir := t.TempDir()
+	script := "#!/bin/sh\ncase \"$1\" in\n--encrypt) printf 'AGE:'; cat ;;\n--decrypt) tail -c +5 ;;\nesac\n"
+	if err := os.WriteFile(filepath.Join(dir, "age"), []byte(script), 0o755); err != nil {
+		t.Fatalf("write fake age: %v", err)
+	}
+	identity := filepath.Join(dir, "identity.txt")
+	if err := os.WriteFile(identity, []byte("AGE-SECRET-KEY-TEST\n"), 0o600); err != nil {
+		t.Fatalf("write identity: %v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv(config.AgeIdentityEnvVar, identity)
+}
+
+// stubEditor replaces the editor with edits applied in order; it records the temp file path.
+func stubEditor(t *testing.T, edits ...string) *string {
+	t.Helper()
+	var tmpPath string
+	original := runEditor
+	t.Cleanup(func() { runEditor = original })
+	calls := 0
+	runEditor = func(path string, stdin io.Reader, stdout, stderr io.Writer) error {
+		tmpPath = path
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("stat temp file: %v", err)
+		}
+		if runtime.GOOS != "windows" && info.Mode().Perm() != 0o600 {
+			t.Fatalf("expected private temp file, got %v", info.Mode().Perm())
+		}
+		if calls >= len(edits) {
+			t.Fatalf("editor opened %d times, expected %d", calls+1, len(edits))
+		}
+		edit := edits[calls]
+		calls++
+		if edit == "" {
+			return nil
+		}
+		return os.WriteFile(path, []byte(edit), 0o600)
+	}
+	return &tmpPath
+}
+
+func TestEnvEditReencryptsAndShreds(t *testing.T) {
+	writeFakeAge(t)
+	envPath := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(envPath+".age", []byte("AGE:TOKEN=old\n"), 0o600); err != nil {
+		t.Fatalf("write env.age: %v", err)
+	}
+	tmpPath := stubEditor(t, "TOKEN=new\n")
+
+	var out bytes.Buffer
+	if err := runEnvEdit(envPath, nil, &out, &bytes.Buffer{}); err != nil {
+		t.Fatalf("runEnvEdit error: %v", err)
+	}
+	data, err := os.ReadFile(envPath + ".age")
+	if err != nil || string(data) != "AGE:TOKEN=new\n" {
+		t.Fatalf("unexpected .env.age %q, %v", data, err)
+	}
+	if _, err := os.Stat(*tmpPath); !os.IsNotExist(err) {
+		t.Fatalf("expected temp file to be shredded, got %v", err)
+	}
+	if !strings.Contains(out.String(), "Encrypted "+envPath+".age") {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+}
+
+func TestEnvEditNoChanges(t *testing.T) {
+	writeFakeAge(t)
+	envPath := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(envPath+".age", []byte("AGE:TOKEN=old\n"), 0o600); err != nil {
+		t.Fatalf("write env.age: %v", err)
+	}
+	stubEditor(t, "")
+
+	var out bytes.Buffer
+	if err := runEnvEdit(envPath, nil, &out, &bytes.Buffer{}); err != nil {
+		t.Fatalf("runEnvEdit error: %v", err)
+	}
+	if !strings.Contains(out.String(), "No changes") {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+}
+
+func TestEnvEditCreatesFromPlaintext(t *testing.T) {
+	writeFakeAge(t)
+	envPath := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(envPath, []byte("TOKEN=plain\n"), 0o600); err != nil {
+		t.Fatalf("write env: %v", err)
+	}
+	stubEditor(t, "")
+
+	var out bytes.Buffer
+	if err := runEnvEdit(envPath, nil, &out, &bytes.Buffer{}); err != nil {
+		t.Fatalf("runEnvEdit error: %v", err)
+	}
+	data, err := os.ReadFile(envPath + ".age")
+	if err != nil || string(data) != "AGE:TOKEN=plain\n" {
+		t.Fatalf("unexpected .env.age %q, %v", data, err)
+	}
+	if !strings.Contains(out.String(), envPath+" is now ignored") {
+		t.Fatalf("expected plaintext hint, got %q", out.String())
+	}
+}
+
+func TestEnvEditInvalidReopensThenAborts(t *testing.T) {
+	writeFakeAge(t)
+	envPath := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(envPath+".age", []byte("AGE:TOKEN=old\n"), 0o600); err != nil {
+		t.Fatalf("write env.age: %v", err)
+	}
+
+	// Fixed on the second attempt.
+	stubEditor(t, "not-an-env-line\n", "TOKEN=fixed\n")
+	var stderr bytes.Buffer
+	if err := runEnvEdit(envPath, nil, &bytes.Buffer{}, &stderr); err != nil {
+		t.Fatalf("runEnvEdit error: %v", err)
+	}
+	if !strings.Contains(stderr.String(), "Reopening the editor") {
+		t.Fatalf("expected reopen notice, got %q", stderr.String())
+	}
+	if data, _ := os.ReadFile(envPath + ".age"); string(data) != "AGE:TOKEN=fixed\n" {
+		t.Fatalf("unexpected .env.age %q", data)
+	}
+
+	// Saved unchanged after an invalid edit aborts without writing.
+	stubEditor(t, "not-an-env-line\n", "")
+	err := runEnvEdit(envPath, nil, &bytes.Buffer{}, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "expected KEY=VALUE") {
+		t.Fatalf("expected abort error, got %v", err)
+	}
+	if data, _ := os.ReadFile(envPath + ".age"); string(data) != "AGE:TOKEN=fixed\n" {
+		t.Fatalf("expected .env.age to be untouched, got %q", data)
+	}
+}
+
+func TestEnvEditMissingIdentity(t *testing.T) {
+	t.Setenv(config.AgeIdentityEnvVar, filepath.Join(t.TempDir(), "missing.txt"))
+	err := runEnvEdit(filepath.Join(t.TempDir(), ".env"), nil, &bytes.Buffer{}, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "age-keygen") {
+		t.Fatalf("expected missing identity error, got %v", err)
+	}
+}
@@ -10,6 +10,7 @@ import (
 )
 
 func newGeminiCmd() *cobra.Command {
+	var profile string
 	cmd := &cobra.Command{
 		Use:   messages.GeminiUse,
 		Short: messages.GeminiShort,
@@ -18,11 +19,12 @@ func newGeminiCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			return clients.Run(root, "gemini", func(cfg *config.Config) *bool {
+			return clients.Run(root, resolveLoadOptions(profile), "gemini", func(cfg *config.Config) *bool {
 				return cfg.Agents.Gemini.Enabled
 			}, gemini.Launch)
 		},
 	}
+	addProfileFlag(cmd, &profile)
 
 	return cmd
 }
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/conn-castle/agent-layer/internal/importer"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	alsync "github.com/conn-castle/agent-layer/internal/sync"
+	"github.com/conn-castle/agent-layer/internal/warnings"
+)
+
+// importSync regenerates client files after an import, overwriting the adopted hand-written ones.
+var importSync = func(root string) ([]warnings.Warning, error) {
+	return alsync.RunWithOptions(root, alsync.Options{Force: true})
+}
+
+func newImportCmd() *cobra.Command {
+	var dryRun bool
+	var yes bool
+	cmd := &cobra.Command{
+		Use:   messages.ImportUse,
+		Short: messages.ImportShort,
+		Long:  messages.ImportLong,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			plan, err := importer.Scan(root)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if plan.Empty() {
+				_, err := fmt.Fprint(out, messages.ImportNothing)
+				return err
+			}
+			if err := writeImportPreview(out, root, plan); err != nil {
+				return err
+			}
+			if dryRun {
+				return nil
+			}
+			if !yes {
+				if !isTerminal() {
+					return fmt.Errorf(messages.ImportNeedsYes)
+				}
+				ok, err := promptYesNo(cmd.InOrStdin(), out, messages.ImportConfirmPrompt, true)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					_, err := fmt.Fprint(out, messages.ImportCanceled)
+					return err
+				}
+			}
+			return applyImport(cmd, root, plan)
+		},
+	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, messages.ImportFlagDryRun)
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, messages.ImportFlagYes)
+	return cmd
+}
+
+// applyImport writes plan and regenerates the client files from the result.
+func applyImport(cmd *cobra.Command, root string, plan *importer.Plan) error {
+	if err := importer.Apply(root, plan); err != nil {
+		return err
+	}
+	syncWarnings, err := importSync(root)
+	if err != nil {
+		return err
+	}
+	for _, w := range syncWarnings {
+		fmt.Fprintln(cmd.ErrOrStderr(), w.String())
+	}
+	_, err = fmt.Fprint(cmd.OutOrStdout(), messages.ImportDone)
+	return err
+}
+
+// writeImportPreview prints everything an import adds and the files it replaces. Secret values are masked.
+func writeImportPreview(out io.Writer, root string, plan *importer.Plan) error {
+	var b strings.Builder
+	if len(plan.Instructions) > 0 {
+		b.WriteString(messages.ImportInstructionsHead)
+		for _, file := range plan.Instructions {
+			name := ".agent-layer/instructions/" + file.Name
+			if len(file.Clients) > 0 {
+				name += " [" + strings.Join(file.Clients, ", ") + "]"
+			}
+			fmt.Fprintf(&b, messages.ImportInstructionFmt, name, strings.Join(file.From, ", "))
+		}
+	}
+	if len(plan.Servers) > 0 {
+		b.WriteString(messages.ImportServersHead)
+		for _, entry := range plan.Servers {
+			fmt.Fprintf(&b, messages.ImportServerFmt, entry.Server.ID, describeServer(entry), strings.Join(entry.From, ", "))
+		}
+	}
+	if len(plan.Secrets) > 0 {
+		fmt.Fprintf(&b, messages.ImportSecretsHeadFmt, displayConfigPath(root, plan.EnvPath))
+		for _, secret := range plan.Secrets {
+			fmt.Fprintf(&b, messages.ImportSecretFmt, secret.Key, maskValue(secret.Value), secret.From)
+		}
+	}
+	if len(plan.Commands) > 0 {
+		b.WriteString(messages.ImportCommandsHead)
+		for _, cmd := range plan.Commands {
+			fmt.Fprintf(&b, messages.ImportCommandFmt, cmd.Prefix, cmd.From)
+		}
+	}
+	if len(plan.Notes) > 0 {
+		b.WriteString(messages.ImportNotesHead)
+		for _, note := range plan.Notes {
+			fmt.Fprintf(&b, messages.ImportNoteFmt, note.Source, note.Detail)
+		}
+	}
+	if len(plan.Replaced) > 0 {
+		b.WriteString(messages.ImportRegeneratedHead)
+		for _, path := range plan.Replaced {
+			fmt.Fprintf(&b, messages.ImportFileFmt, path)
+		}
+	}
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+// describeServer summarizes how an imported server is launched and which clients get it.
+func describeServer(entry importer.Server) string {
+	server := entry.Server
+	summary := server.Transport + " " + server.URL
+	if server.Command != "" {
+		summary = strings.Join(append([]string{server.Transport, server.Command}, server.Args...), " ")
+	}
+	if len(server.Clients) > 0 {
+		summary += " [" + strings.Join(server.Clients, ", ") + "]"
+	}
+	return summary
+}
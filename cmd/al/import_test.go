@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/warnings"
+)
+
+func runImportCmd(t *testing.T, stdin string, args ...string) (string, error) {
+	t.Helper()
+	cmd := newImportCmd()
+	var out bytes.Buffer
+	cmd.SetIn(strings.NewReader(stdin))
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestImportCommand(t *testing.T) {
+	root := t.TempDir()
+	writeTestRepo(t, root)
+	paths := config.DefaultPaths(root)
+	if err := os.WriteFile(filepath.Join(root, "CLAUDE.md"), []byte("# Our rules\n"), 0o644); err != nil {
+		t.Fatalf("write CLAUDE.md: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".mcp.json"), []byte(`{"mcpServers": {"docs": {"type": "http", "url": "https://docs.example/mcp", "headers": {"Authorization": "Bearer abcdefghijklmnop"}}}}`), 0o644); err != nil {
+		t.Fatalf("write .mcp.json: %v", err)
+	}
+
+	originalTerminal, originalSync := isTerminal, importSync
+	t.Cleanup(func() { isTerminal, importSync = originalTerminal, originalSync })
+	isTerminal = func() bool { return false }
+	synced := 0
+	importSync = func(string) ([]warnings.Warning, error) {
+		synced++
+		return nil, nil
+	}
+
+	withWorkingDir(t, root, func() {
+		out, err := runImportCmd(t, "", "--dry-run")
+		if err != nil {
+			t.Fatalf("import --dry-run error: %v", err)
+		}
+		for _, want := range []string{
+			"  + .agent-layer/instructions/50_imported-claude.md (from CLAUDE.md)\n",
+			"  + docs: http https://docs.example/mcp (from .mcp.json)\n",
+			"  + DOCS_TOKEN=****mnop (from .mcp.json)\n",
+			"Replaced by generated files after import:\n  - CLAUDE.md\n  - .mcp.json\n",
+		} {
+			if !strings.Contains(out, want) {
+				t.Fatalf("preview missing %q:\n%s", want, out)
+			}
+		}
+		if strings.Contains(out, "abcdefghijklmnop") {
+			t.Fatalf("preview leaked a secret:\n%s", out)
+		}
+		if _, err := os.Stat(filepath.Join(paths.InstructionsDir, "50_imported-claude.md")); !os.IsNotExist(err) {
+			t.Fatalf("dry run wrote files: %v", err)
+		}
+
+		if _, err := runImportCmd(t, ""); err == nil || !strings.Contains(err.Error(), "--yes") {
+			t.Fatalf("expected confirmation error, got %v", err)
+		}
+
+		isTerminal = func() bool { return true }
+		out, err = runImportCmd(t, "n\n")
+		if err != nil || !strings.Contains(out, "Import canceled") || synced != 0 {
+			t.Fatalf("expected cancel, got %q, %v", out, err)
+		}
+
+		out, err = runImportCmd(t, "", "--yes")
+		if err != nil || !strings.Contains(out, "Imported into .agent-layer") {
+			t.Fatalf("import --yes failed: %q, %v", out, err)
+		}
+	})
+
+	if synced != 1 {
+		t.Fatalf("expected one forced sync, got %d", synced)
+	}
+	data, err := os.ReadFile(filepath.Join(paths.InstructionsDir, "50_imported-claude.md"))
+	if err != nil || string(data) != "# Our rules\n" {
+		t.Fatalf("unexpected imported instruction %q, %v", data, err)
+	}
+	env, err := config.LoadEnv(paths.EnvPath)
+	if err != nil || env["DOCS_TOKEN"] != "abcdefghijklmnop" {
+		t.Fatalf("expected secret in .env, got %v, %v", env, err)
+	}
+}
+
+func TestImportCommandNothingToImport(t *testing.T) {
+	root := t.TempDir()
+	writeTestRepo(t, root)
+	withWorkingDir(t, root, func() {
+		out, err := runImportCmd(t, "")
+		if err != nil || !strings.HasPrefix(out, "Nothing to import") {
+			t.Fatalf("unexpected output %q, %v", out, err)
+		}
+	})
+}
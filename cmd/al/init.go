@@ -12,6 +12,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/conn-castle/agent-layer/internal/dispatch"
+	"github.com/conn-castle/agent-layer/internal/importer"
 	"github.com/conn-castle/agent-layer/internal/install"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	alsync "github.com/conn-castle/agent-layer/internal/sync"
@@ -82,6 +83,9 @@ func newInitCmd() *cobra.Command {
 			if err := installRun(root, opts); err != nil {
 				return err
 			}
+			if err := offerImport(cmd, root); err != nil {
+				return err
+			}
 			if noWizard || !isTerminal() {
 				return nil
 			}
@@ -104,6 +108,35 @@ func newInitCmd() *cobra.Command {
 	return cmd
 }
 
+// offerImport looks for hand-written instruction files and client configs that sync would refuse to
+// overwrite, and offers to import them on a terminal; elsewhere it points at al import.
+func offerImport(cmd *cobra.Command, root string) error {
+	plan, err := importer.Scan(root)
+	if err != nil {
+		_, err = fmt.Fprintf(cmd.ErrOrStderr(), messages.InitImportScanFailedFmt, err)
+		return err
+	}
+	if plan.Empty() {
+		return nil
+	}
+	if !isTerminal() {
+		_, err := fmt.Fprint(cmd.OutOrStdout(), messages.InitImportHint)
+		return err
+	}
+	if err := writeImportPreview(cmd.OutOrStdout(), root, plan); err != nil {
+		return err
+	}
+	ok, err := promptYesNo(cmd.InOrStdin(), cmd.OutOrStdout(), messages.InitImportPrompt, true)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		_, err := fmt.Fprint(cmd.OutOrStdout(), messages.InitImportHint)
+		return err
+	}
+	return applyImport(cmd, root, plan)
+}
+
 // warnInitUpdate emits a warning when a newer Agent Layer release is available.
 func warnInitUpdate(cmd *cobra.Command, flagVersion string) {
 	if strings.TrimSpace(flagVersion) != "" {
@@ -1,7 +1,7 @@
 package main
 
 // NOTE: Tests in this file mutate package-level globals (getwd, isTerminal,
-// installRun, runWizard, checkForUpdate). Do not use t.Parallel() at the
+// installRun, runWizard, checkForUpdate, importSync). Do not use t.Parallel() at the
 // top level. Each test must restore globals via t.Cleanup().
 
 import (
@@ -17,6 +17,7 @@ import (
 	"github.com/conn-castle/agent-layer/internal/dispatch"
 	"github.com/conn-castle/agent-layer/internal/install"
 	"github.com/conn-castle/agent-layer/internal/update"
+	"github.com/conn-castle/agent-layer/internal/warnings"
 )
 
 type slowReader struct {
@@ -501,3 +502,66 @@ func TestInitCmd_UpdateWarningSkipped(t *testing.T) {
 		})
 	}
 }
+
+func TestInitCmd_OffersImport(t *testing.T) {
+	origGetwd := getwd
+	origIsTerminal := isTerminal
+	origInstallRun := installRun
+	origRunWizard := runWizard
+	origCheckForUpdate := checkForUpdate
+	origImportSync := importSync
+	t.Cleanup(func() {
+		getwd = origGetwd
+		isTerminal = origIsTerminal
+		installRun = origInstallRun
+		runWizard = origRunWizard
+		checkForUpdate = origCheckForUpdate
+		importSync = origImportSync
+	})
+	t.Setenv(dispatch.EnvNoNetwork, "1")
+
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "AGENTS.md"), []byte("# Team rules\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	getwd = func() (string, error) { return tmpDir, nil }
+	installRun = func(root string, _ install.Options) error {
+		writeTestRepo(t, root)
+		return nil
+	}
+	runWizard = func(string, string) error { return nil }
+	synced := 0
+	importSync = func(string) ([]warnings.Warning, error) {
+		synced++
+		return nil, nil
+	}
+
+	isTerminal = func() bool { return false }
+	cmd := newInitCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "run `al import`") || synced != 0 {
+		t.Fatalf("expected import hint without importing, got %q", out.String())
+	}
+
+	isTerminal = func() bool { return true }
+	cmd = newInitCmd()
+	out.Reset()
+	cmd.SetOut(&out)
+	cmd.SetIn(strings.NewReader("y\nn\n"))
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "50_imported-agents.md (from AGENTS.md)") || synced != 1 {
+		t.Fatalf("expected preview and import, got %q", out.String())
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".agent-layer", "instructions", "50_imported-agents.md")); err != nil {
+		t.Fatalf("expected imported instruction: %v", err)
+	}
+}
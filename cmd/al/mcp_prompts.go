@@ -2,32 +2,144 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/spf13/cobra"
 
 	"github.com/conn-castle/agent-layer/internal/config"
 	"github.com/conn-castle/agent-layer/internal/mcp"
 	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/projection"
 )
 
 var runPromptServer = mcp.RunPromptServer
 
+var servePromptsHTTP = mcp.ServePromptsHTTP
+
 func newMcpPromptsCmd() *cobra.Command {
+	var client string
+	var commandTools bool
+	var httpAddr string
 	cmd := &cobra.Command{
 		Use:   messages.McpPromptsUse,
 		Short: messages.McpPromptsShort,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if client != "" && !config.IsValidClient(client) {
+				return fmt.Errorf(messages.McpPromptsClientInvalidFmt, client)
+			}
+			if httpAddr != "" {
+				if client != "" {
+					return errors.New(messages.McpPromptsHTTPClientFlag)
+				}
+				if !config.ValidPromptServerAddress(httpAddr) {
+					return fmt.Errorf(messages.McpPromptsHTTPInvalidFmt, httpAddr)
+				}
+			}
 			root, err := resolveRepoRoot()
 			if err != nil {
 				return err
 			}
-			project, err := config.LoadProjectConfig(root)
+			newSource := func(client string) (mcp.PromptSource, error) {
+				// Reloads only reread the child layers found at startup, whose directories are the
+				// ones watched, instead of searching the repo on every change.
+				var childDirs []string
+				load := func() (*config.ProjectConfig, []config.SlashCommand, []mcp.Resource, error) {
+					project, err := config.LoadProjectConfigWithOptions(root, config.LoadOptions{SkipSecrets: true, ChildDirs: childDirs})
+					if err != nil {
+						return nil, nil, nil, err
+					}
+					commands, err := config.RenderSlashCommands(promptCommands(project, client), config.NewTemplateData(project, client).WithArgumentStyle(config.ArgumentsPromptServer))
+					if err != nil {
+						return nil, nil, nil, err
+					}
+					resources, err := mcp.LoadResources(project, client)
+					if err != nil {
+						return nil, nil, nil, err
+					}
+					return project, commands, resources, nil
+				}
+				project, commands, resources, err := load()
+				if err != nil {
+					return mcp.PromptSource{}, err
+				}
+				childDirs = make([]string, 0, len(project.Children))
+				for _, child := range project.Children {
+					childDirs = append(childDirs, child.Path)
+				}
+				return mcp.PromptSource{
+					Commands:  commands,
+					Resources: resources,
+					Reload: func() ([]config.SlashCommand, []mcp.Resource, error) {
+						_, commands, resources, err := load()
+						return commands, resources, err
+					},
+					WatchDirs:    promptServerWatchDirs(project),
+					MemoryDir:    memoryDir(project, client),
+					CommandTools: commandTools || httpAddr != "" && slashCommandTools(project, client),
+				}, nil
+			}
+			if httpAddr != "" {
+				project, err := config.LoadProjectConfigWithOptions(root, config.LoadOptions{SkipSecrets: true})
+				if err != nil {
+					return err
+				}
+				return servePromptsHTTP(context.Background(), Version, httpAddr, project.Env[config.PromptServerTokenEnvVar], newSource)
+			}
+			source, err := newSource(client)
 			if err != nil {
 				return err
 			}
-			return runPromptServer(context.Background(), Version, project.SlashCommands)
+			return runPromptServer(context.Background(), Version, source)
 		},
 	}
+	cmd.Flags().StringVar(&client, "client", "", messages.McpPromptsFlagClient)
+	cmd.Flags().BoolVar(&commandTools, "command-tools", false, messages.McpPromptsFlagCommandTools)
+	cmd.Flags().StringVar(&httpAddr, "http", "", messages.McpPromptsFlagHTTP)
 
 	return cmd
 }
+
+// promptCommands returns the slash commands served to client as prompts: none for Claude or Gemini
+// when their slash_commands setting is native, since they read their own command files instead.
+func promptCommands(project *config.ProjectConfig, client string) []config.SlashCommand {
+	switch {
+	case client == "claude" && !project.Config.Agents.Claude.MCPSlashCommands(),
+		client == "gemini" && !project.Config.Agents.Gemini.MCPSlashCommands():
+		return nil
+	}
+	return config.SlashCommandsForClient(project.SlashCommands, client)
+}
+
+// memoryDir returns the memory directory whose tools are served to client, or "" for none. Codex
+// runs every tool without asking, so it only gets the memory tools, which write files, when
+// approvals.mode auto-approves MCP tools.
+func memoryDir(project *config.ProjectConfig, client string) string {
+	if client == "codex" && !projection.BuildApprovals(project.Config, project.CommandsAllow).AllowMCP {
+		return ""
+	}
+	return config.DefaultPaths(project.Root).MemoryDir
+}
+
+// slashCommandTools reports whether client is configured to get slash commands as tools, which the
+// HTTP server decides per client instead of taking --command-tools from each client's config.
+func slashCommandTools(project *config.ProjectConfig, client string) bool {
+	switch client {
+	case "codex":
+		return project.Config.Agents.Codex.SlashCommandTools
+	case "vscode":
+		return project.Config.Agents.VSCode.SlashCommandTools
+	}
+	return false
+}
+
+// promptServerWatchDirs returns the directories whose files the prompt server serves: slash commands
+// of the root and child layers, root instructions, and memory files.
+func promptServerWatchDirs(project *config.ProjectConfig) []string {
+	paths := config.DefaultPaths(project.Root)
+	dirs := []string{paths.SlashCommandsDir, paths.InstructionsDir, paths.MemoryDir}
+	for _, child := range project.Children {
+		dirs = append(dirs, config.DefaultPaths(child.Path).SlashCommandsDir)
+	}
+	return dirs
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+func newMigrateCmd() *cobra.Command {
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   messages.MigrateUse,
+		Short: messages.MigrateShort,
+		Long:  messages.MigrateLong,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			report, err := config.Migrate(root, dryRun)
+			if err != nil {
+				return err
+			}
+			return writeMigrationReport(cmd.OutOrStdout(), report, dryRun)
+		},
+	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, messages.MigrateFlagDryRun)
+	return cmd
+}
+
+// writeMigrationReport prints the version change and every edited file.
+func writeMigrationReport(out io.Writer, report *config.MigrationReport, dryRun bool) error {
+	if len(report.Changes) == 0 {
+		_, err := fmt.Fprintf(out, messages.MigrateUpToDateFmt, report.To)
+		return err
+	}
+	from := messages.MigrateUnversioned
+	if report.From > 0 {
+		from = strconv.Itoa(report.From)
+	}
+	header := messages.MigrateHeaderFmt
+	if dryRun {
+		header = messages.MigrateDryRunHeaderFmt
+	}
+	if _, err := fmt.Fprintf(out, header, from, report.To); err != nil {
+		return err
+	}
+	for _, change := range report.Changes {
+		if _, err := fmt.Fprintf(out, messages.MigrateChangeFmt, change.Path, change.Description); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+func TestMigrateCommand(t *testing.T) {
+	root := t.TempDir()
+	writeTestRepo(t, root)
+	paths := config.DefaultPaths(root)
+
+	withWorkingDir(t, root, func() {
+		cmd := newMigrateCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetArgs([]string{"--dry-run"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("migrate --dry-run error: %v", err)
+		}
+		if !strings.Contains(out.String(), "Would migrate from config_version unversioned to 1:") {
+			t.Fatalf("unexpected dry-run output: %q", out.String())
+		}
+
+		cmd = newMigrateCmd()
+		out.Reset()
+		cmd.SetOut(&out)
+		cmd.SetArgs(nil)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("migrate error: %v", err)
+		}
+		if !strings.Contains(out.String(), ".agent-layer/config.toml: set config_version = 1") {
+			t.Fatalf("unexpected migrate output: %q", out.String())
+		}
+	})
+
+	data, err := os.ReadFile(paths.ConfigPath)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "config_version = 1\n") {
+		t.Fatalf("expected versioned config, got:\n%s", data)
+	}
+}
+
+func TestWriteMigrationReport(t *testing.T) {
+	var out bytes.Buffer
+	if err := writeMigrationReport(&out, &config.MigrationReport{From: 1, To: 1}, false); err != nil {
+		t.Fatalf("write report: %v", err)
+	}
+	if out.String() != "Already at config_version 1; nothing to migrate.\n" {
+		t.Fatalf("unexpected up-to-date output: %q", out.String())
+	}
+
+	out.Reset()
+	report := &config.MigrationReport{From: 1, To: 2, Changes: []config.MigrationChange{{Path: "a", Description: "b"}}}
+	if err := writeMigrationReport(&out, report, false); err != nil {
+		t.Fatalf("write report: %v", err)
+	}
+	if out.String() != "Migrated from config_version 1 to 2:\n  a: b\n" {
+		t.Fatalf("unexpected report output: %q", out.String())
+	}
+}
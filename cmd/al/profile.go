@@ -0,0 +1,25 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// addProfileFlag registers --profile on cmd, binding it to target.
+func addProfileFlag(cmd *cobra.Command, target *string) {
+	cmd.Flags().StringVar(target, "profile", "", messages.ProfileFlag)
+}
+
+// resolveLoadOptions returns config load options for the --profile flag value, falling back to AL_PROFILE.
+func resolveLoadOptions(profile string) config.LoadOptions {
+	profile = strings.TrimSpace(profile)
+	if profile == "" {
+		profile = strings.TrimSpace(os.Getenv(config.ProfileEnvVar))
+	}
+	return config.LoadOptions{Profile: profile}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+func TestResolveLoadOptions(t *testing.T) {
+	t.Setenv(config.ProfileEnvVar, "")
+	if got := resolveLoadOptions(""); got.Profile != "" {
+		t.Fatalf("expected no profile, got %q", got.Profile)
+	}
+
+	t.Setenv(config.ProfileEnvVar, " review ")
+	if got := resolveLoadOptions(""); got.Profile != "review" {
+		t.Fatalf("expected profile from env, got %q", got.Profile)
+	}
+	if got := resolveLoadOptions("build"); got.Profile != "build" {
+		t.Fatalf("expected flag to override env, got %q", got.Profile)
+	}
+}
+
+func TestProfileFlagRegistered(t *testing.T) {
+	for _, cmd := range []*cobra.Command{
+		newSyncCmd(),
+		newDoctorCmd(),
+		newGeminiCmd(),
+		newClaudeCmd(),
+		newCodexCmd(),
+		newVSCodeCmd(),
+		newAntigravityCmd(),
+		newConfigShowCmd(),
+	} {
+		if cmd.Flags().Lookup("profile") == nil {
+			t.Fatalf("expected --profile on %s", cmd.Name())
+		}
+	}
+}
@@ -31,6 +31,11 @@ func newRootCmd() *cobra.Command {
 	root.AddCommand(
 		newInitCmd(),
 		newSyncCmd(),
+		newConfigCmd(),
+		newMigrateCmd(),
+		newImportCmd(),
+		newStatsCmd(),
+		newEnvCmd(),
 		newMcpPromptsCmd(),
 		newGeminiCmd(),
 		newClaudeCmd(),
@@ -20,6 +20,7 @@ import (
 	"github.com/conn-castle/agent-layer/internal/config"
 	"github.com/conn-castle/agent-layer/internal/dispatch"
 	"github.com/conn-castle/agent-layer/internal/doctor"
+	"github.com/conn-castle/agent-layer/internal/mcp"
 	"github.com/conn-castle/agent-layer/internal/messages"
 	"github.com/conn-castle/agent-layer/internal/update"
 	"github.com/conn-castle/agent-layer/internal/warnings"
@@ -335,7 +336,7 @@ func TestClientCommandsSuccess(t *testing.T) {
 
 	original := runPromptServer
 	t.Cleanup(func() { runPromptServer = original })
-	runPromptServer = func(ctx context.Context, version string, commands []config.SlashCommand) error {
+	runPromptServer = func(ctx context.Context, version string, source mcp.PromptSource) error {
 		return nil
 	}
 
@@ -356,6 +357,234 @@ func TestClientCommandsSuccess(t *testing.T) {
 	})
 }
 
+func TestMcpPromptsReloadKeepsChildLayers(t *testing.T) {
+	root := t.TempDir()
+	writeTestRepo(t, root)
+	writeChildCommand := func(dir string, name string) {
+		commandsDir := config.DefaultPaths(filepath.Join(root, dir)).SlashCommandsDir
+		if err := os.MkdirAll(commandsDir, 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(commandsDir, name+".md"), []byte("---\ndescription: d\n---\nBody"), 0o644); err != nil {
+			t.Fatalf("write child command: %v", err)
+		}
+	}
+	writeChildCommand("svc", "deploy")
+
+	original := runPromptServer
+	t.Cleanup(func() { runPromptServer = original })
+	var reloaded []string
+	runPromptServer = func(ctx context.Context, version string, source mcp.PromptSource) error {
+		// A child layer added after startup is not watched, so reloads do not search for it.
+		writeChildCommand("svc", "rollback")
+		writeChildCommand("web", "build-web")
+		commands, _, err := source.Reload()
+		for _, cmd := range commands {
+			reloaded = append(reloaded, cmd.Name)
+		}
+		return err
+	}
+
+	withWorkingDir(t, root, func() {
+		cmd := newMcpPromptsCmd()
+		cmd.SetArgs([]string{"--client", "codex"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("mcp-prompts failed: %v", err)
+		}
+	})
+	if got := strings.Join(reloaded, ","); !strings.Contains(got, "deploy,rollback") || strings.Contains(got, "build-web") {
+		t.Fatalf("expected reload to reread only the known child layer, got %v", reloaded)
+	}
+}
+
+func TestMcpPromptsClientFilter(t *testing.T) {
+	root := t.TempDir()
+	writeTestRepo(t, root)
+	paths := config.DefaultPaths(root)
+	commands := map[string]string{
+		"all.md":    "---\ndescription: All\n---\nBody",
+		"vscode.md": "---\ndescription: VS Code only\nclients: [vscode]\n---\nBody",
+	}
+	for name, content := range commands {
+		if err := os.WriteFile(filepath.Join(paths.SlashCommandsDir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write slash command: %v", err)
+		}
+	}
+
+	original := runPromptServer
+	t.Cleanup(func() { runPromptServer = original })
+	var served []string
+	var reloaded []config.SlashCommand
+	var watched []string
+	var resources []mcp.Resource
+	var commandTools bool
+	var memoryDir string
+	runPromptServer = func(ctx context.Context, version string, source mcp.PromptSource) error {
+		served = nil
+		commandTools = source.CommandTools
+		memoryDir = source.MemoryDir
+		for _, cmd := range source.Commands {
+			served = append(served, cmd.Name)
+		}
+		watched = source.WatchDirs
+		resources = source.Resources
+		var err error
+		reloaded, _, err = source.Reload()
+		return err
+	}
+
+	withWorkingDir(t, root, func() {
+		cmd := newMcpPromptsCmd()
+		cmd.SetArgs([]string{"--client", "claude"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("mcp-prompts failed: %v", err)
+		}
+		if strings.Join(served, ",") != "all,alpha" {
+			t.Fatalf("unexpected served commands %v", served)
+		}
+		if len(reloaded) != len(served) {
+			t.Fatalf("expected reload to return the same commands, got %+v", reloaded)
+		}
+		if len(watched) != 3 || watched[0] != paths.SlashCommandsDir || watched[2] != paths.MemoryDir {
+			t.Fatalf("unexpected watched directories %v", watched)
+		}
+		if len(resources) == 0 {
+			t.Fatalf("expected instruction resources to be served")
+		}
+		if commandTools {
+			t.Fatalf("expected command tools to be off by default")
+		}
+		if memoryDir != paths.MemoryDir {
+			t.Fatalf("expected memory tools, got %q", memoryDir)
+		}
+
+		data, err := os.ReadFile(paths.ConfigPath)
+		if err != nil {
+			t.Fatalf("read config: %v", err)
+		}
+		native := strings.Replace(string(data), "[agents.claude]\nenabled = true\n", "[agents.claude]\nenabled = true\nslash_commands = \"native\"\n", 1)
+		if err := os.WriteFile(paths.ConfigPath, []byte(native), 0o644); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		cmd = newMcpPromptsCmd()
+		cmd.SetArgs([]string{"--client", "claude"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("mcp-prompts native failed: %v", err)
+		}
+		if len(served) != 0 || len(reloaded) != 0 || len(resources) == 0 {
+			t.Fatalf("expected native mode to serve resources but no prompts, got %v, %d resources", served, len(resources))
+		}
+		cmd = newMcpPromptsCmd()
+		cmd.SetArgs([]string{"--client", "gemini"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("mcp-prompts gemini failed: %v", err)
+		}
+		if len(served) != 0 || len(resources) == 0 {
+			t.Fatalf("expected Gemini to get resources but no prompts by default, got %v, %d resources", served, len(resources))
+		}
+		// Codex runs tools without asking, so it gets no memory tools unless approvals allow MCP.
+		readOnly := strings.Replace(string(data), "mode = \"all\"", "mode = \"commands\"", 1)
+		if err := os.WriteFile(paths.ConfigPath, []byte(readOnly), 0o644); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		cmd = newMcpPromptsCmd()
+		cmd.SetArgs([]string{"--client", "codex", "--command-tools"})
+		if err := cmd.Execute(); err != nil || !commandTools || memoryDir != "" {
+			t.Fatalf("expected command tools without memory tools for codex, got %v, %q, %v", commandTools, memoryDir, err)
+		}
+		cmd = newMcpPromptsCmd()
+		cmd.SetArgs([]string{"--client", "claude"})
+		if err := cmd.Execute(); err != nil || memoryDir != paths.MemoryDir {
+			t.Fatalf("expected claude to keep memory tools, got %q, %v", memoryDir, err)
+		}
+		if err := os.WriteFile(paths.ConfigPath, data, 0o644); err != nil {
+			t.Fatalf("restore config: %v", err)
+		}
+
+		cmd = newMcpPromptsCmd()
+		cmd.SetArgs([]string{"--client", "codex", "--command-tools"})
+		if err := cmd.Execute(); err != nil || !commandTools {
+			t.Fatalf("expected --command-tools to enable command tools, got %v, %v", commandTools, err)
+		}
+
+		cmd = newMcpPromptsCmd()
+		cmd.SetArgs([]string{"--client", "cursor"})
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "cursor") {
+			t.Fatalf("expected invalid client error, got %v", err)
+		}
+	})
+}
+
+func TestMcpPromptsHTTP(t *testing.T) {
+	root := t.TempDir()
+	writeTestRepo(t, root)
+	paths := config.DefaultPaths(root)
+	data, err := os.ReadFile(paths.ConfigPath)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	data = []byte(strings.Replace(string(data), "[agents.codex]\nenabled = true", "[agents.codex]\nenabled = true\nslash_command_tools = true", 1))
+	if err := os.WriteFile(paths.ConfigPath, data, 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if err := os.WriteFile(paths.EnvPath, []byte("AL_PROMPT_SERVER_TOKEN=secret\n"), 0o644); err != nil {
+		t.Fatalf("write env: %v", err)
+	}
+
+	originalRun := runPromptServer
+	originalServe := servePromptsHTTP
+	t.Cleanup(func() {
+		runPromptServer = originalRun
+		servePromptsHTTP = originalServe
+	})
+	runPromptServer = func(ctx context.Context, version string, source mcp.PromptSource) error {
+		t.Fatalf("expected --http not to run the stdio server")
+		return nil
+	}
+	var addr, token string
+	tools := map[string]bool{}
+	servePromptsHTTP = func(ctx context.Context, version string, gotAddr string, gotToken string, load func(string) (mcp.PromptSource, error)) error {
+		addr, token = gotAddr, gotToken
+		for _, client := range []string{"", "claude", "codex"} {
+			source, err := load(client)
+			if err != nil {
+				return err
+			}
+			tools[client] = source.CommandTools
+		}
+		return nil
+	}
+
+	withWorkingDir(t, root, func() {
+		cmd := newMcpPromptsCmd()
+		cmd.SetArgs([]string{"--http", "127.0.0.1:7420"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("mcp-prompts failed: %v", err)
+		}
+		if addr != "127.0.0.1:7420" || token != "secret" {
+			t.Fatalf("unexpected address %q or token %q", addr, token)
+		}
+		if tools[""] || tools["claude"] || !tools["codex"] {
+			t.Fatalf("expected command tools only for codex, got %v", tools)
+		}
+
+		for _, args := range [][]string{
+			{"--http", "7420"},
+			{"--http", "127.0.0.1:7420", "--client", "claude"},
+		} {
+			cmd = newMcpPromptsCmd()
+			cmd.SetArgs(args)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			if err := cmd.Execute(); err == nil {
+				t.Fatalf("expected %v to fail", args)
+			}
+		}
+	})
+}
+
 func TestDoctorCommand(t *testing.T) {
 	root := t.TempDir()
 	calls := stubUpdateCheck(t, update.CheckResult{Current: "1.0.0", Latest: "1.0.0"}, nil)
@@ -395,7 +624,7 @@ func TestDoctorCommand_UpdateSkippedNoNetwork(t *testing.T) {
 		checkInstructions = origInstructions
 		checkMCPServers = origMCP
 	})
-	checkInstructions = func(string, *int) ([]warnings.Warning, error) { return nil, nil }
+	checkInstructions = func(*config.ProjectConfig) ([]warnings.Warning, error) { return nil, nil }
 	checkMCPServers = func(context.Context, *config.ProjectConfig, warnings.Connector) ([]warnings.Warning, error) {
 		return nil, nil
 	}
@@ -423,7 +652,7 @@ func TestDoctorCommand_UpdateCheckError(t *testing.T) {
 		checkInstructions = origInstructions
 		checkMCPServers = origMCP
 	})
-	checkInstructions = func(string, *int) ([]warnings.Warning, error) { return nil, nil }
+	checkInstructions = func(*config.ProjectConfig) ([]warnings.Warning, error) { return nil, nil }
 	checkMCPServers = func(context.Context, *config.ProjectConfig, warnings.Connector) ([]warnings.Warning, error) {
 		return nil, nil
 	}
@@ -454,7 +683,7 @@ func TestDoctorCommand_UpdateCheckDevBuild(t *testing.T) {
 		checkInstructions = origInstructions
 		checkMCPServers = origMCP
 	})
-	checkInstructions = func(string, *int) ([]warnings.Warning, error) { return nil, nil }
+	checkInstructions = func(*config.ProjectConfig) ([]warnings.Warning, error) { return nil, nil }
 	checkMCPServers = func(context.Context, *config.ProjectConfig, warnings.Connector) ([]warnings.Warning, error) {
 		return nil, nil
 	}
@@ -483,7 +712,7 @@ func TestDoctorCommand_ConfigErrorSkipsWarningSystem(t *testing.T) {
 		checkInstructions = origInstructions
 		checkMCPServers = origMCP
 	})
-	checkInstructions = func(string, *int) ([]warnings.Warning, error) {
+	checkInstructions = func(*config.ProjectConfig) ([]warnings.Warning, error) {
 		calledInstructions = true
 		return nil, nil
 	}
@@ -538,7 +767,7 @@ func TestDoctorCommand_InstructionsError(t *testing.T) {
 		checkMCPServers = origMCP
 	})
 
-	checkInstructions = func(string, *int) ([]warnings.Warning, error) {
+	checkInstructions = func(*config.ProjectConfig) ([]warnings.Warning, error) {
 		return nil, errors.New("instructions failed")
 	}
 	checkMCPServers = func(context.Context, *config.ProjectConfig, warnings.Connector) ([]warnings.Warning, error) {
@@ -569,7 +798,7 @@ func TestDoctorCommand_MCPError(t *testing.T) {
 		checkMCPServers = origMCP
 	})
 
-	checkInstructions = func(string, *int) ([]warnings.Warning, error) {
+	checkInstructions = func(*config.ProjectConfig) ([]warnings.Warning, error) {
 		return nil, nil
 	}
 	checkMCPServers = func(context.Context, *config.ProjectConfig, warnings.Connector) ([]warnings.Warning, error) {
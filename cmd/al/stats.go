@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/warnings"
+)
+
+var discoverStatsTools = warnings.DiscoverMCPTools
+
+func newStatsCmd() *cobra.Command {
+	var profile string
+	var noMCP bool
+	cmd := &cobra.Command{
+		Use:   messages.StatsUse,
+		Short: messages.StatsShort,
+		Long:  messages.StatsLong,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := resolveRepoRoot()
+			if err != nil {
+				return err
+			}
+			project, err := config.LoadProjectConfigWithOptions(root, resolveLoadOptions(profile))
+			if err != nil {
+				return err
+			}
+			var results []warnings.DiscoveryResult
+			if !noMCP {
+				fmt.Fprintf(cmd.ErrOrStderr(), messages.StatsDiscoveringFmt, countEnabledMCPServers(project.Config.MCP.Servers))
+				if results, err = discoverStatsTools(cmd.Context(), project, nil); err != nil {
+					return fmt.Errorf(messages.StatsDiscoverFailedFmt, err)
+				}
+				if results == nil {
+					results = []warnings.DiscoveryResult{}
+				}
+			}
+			stats, err := warnings.CollectStats(project, results)
+			if err != nil {
+				return err
+			}
+			return writeStats(cmd.OutOrStdout(), stats)
+		},
+	}
+	addProfileFlag(cmd, &profile)
+	cmd.Flags().BoolVar(&noMCP, "no-mcp", false, messages.StatsFlagNoMCP)
+	return cmd
+}
+
+// writeStats prints the report as tab-aligned sections.
+func writeStats(out io.Writer, stats *warnings.Stats) error {
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, messages.StatsTokenizerFmt, stats.Tokenizer)
+	for _, note := range stats.Notes {
+		fmt.Fprintf(writer, messages.StatsNoteFmt, note)
+	}
+
+	writeItems(writer, messages.StatsFragmentsHead, messages.StatsFragmentsHeader, stats.Fragments)
+	writeItems(writer, messages.StatsCommandsHead, messages.StatsCommandsHeader, stats.Commands)
+
+	if stats.Servers != nil {
+		fmt.Fprint(writer, messages.StatsServersHead)
+		if len(stats.Servers) == 0 {
+			fmt.Fprint(writer, messages.StatsNone)
+		} else {
+			fmt.Fprintln(writer, messages.StatsServersHeader)
+			for _, server := range stats.Servers {
+				if server.Error != nil {
+					fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", server.ID, messages.StatsSkipped, fmt.Sprintf(messages.StatsUnreachableFmt, server.Error), clientList(server.Clients))
+					continue
+				}
+				fmt.Fprintf(writer, "%s\t%d\t%d\t%s\n", server.ID, len(server.Tools), server.SchemaTokens, clientList(server.Clients))
+			}
+
+			fmt.Fprint(writer, messages.StatsToolsHead)
+			fmt.Fprintln(writer, messages.StatsToolsHeader)
+			for _, server := range stats.Servers {
+				for _, tool := range server.Tools {
+					fmt.Fprintf(writer, "%s\t%s\t%d\n", server.ID, tool.Name, tool.Tokens)
+				}
+			}
+		}
+	}
+
+	fmt.Fprint(writer, messages.StatsClientsHead)
+	if len(stats.Clients) == 0 {
+		fmt.Fprint(writer, messages.StatsNone)
+	} else {
+		fmt.Fprintln(writer, messages.StatsClientsHeader)
+		for _, client := range stats.Clients {
+			instructionTokens := messages.StatsNotGenerated
+			if client.InstructionTokens >= 0 {
+				instructionTokens = fmt.Sprint(client.InstructionTokens)
+			}
+			servers, tools, schema := fmt.Sprint(client.Servers), messages.StatsSkipped, messages.StatsSkipped
+			if stats.Servers != nil {
+				tools, schema = fmt.Sprint(client.Tools), fmt.Sprint(client.SchemaTokens)
+			}
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%d\n", client.Client, client.Tokenizer, client.InstructionFile, instructionTokens, servers, tools, schema, client.Total())
+		}
+	}
+	return writer.Flush()
+}
+
+// writeItems prints one fragment or slash command section with a total row.
+func writeItems(writer io.Writer, head string, header string, items []warnings.ItemTokens) {
+	fmt.Fprint(writer, head)
+	if len(items) == 0 {
+		fmt.Fprint(writer, messages.StatsNone)
+		return
+	}
+	fmt.Fprintln(writer, header)
+	total := 0
+	for _, item := range items {
+		total += item.Tokens
+		fmt.Fprintf(writer, "%s\t%d\t%s\n", item.Name, item.Tokens, clientList(item.Clients))
+	}
+	fmt.Fprintf(writer, "%s\t%d\t\n", messages.StatsTotalLabel, total)
+}
+
+func clientList(clients []string) string {
+	if len(clients) == 0 {
+		return messages.StatsAllClients
+	}
+	return strings.Join(clients, ", ")
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/warnings"
+)
+
+func runStatsCmd(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	cmd := newStatsCmd()
+	var out, errOut bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	cmd.SetArgs(args)
+	cmd.SetContext(context.Background())
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestStatsCommand(t *testing.T) {
+	root := t.TempDir()
+	writeTestRepo(t, root)
+	paths := config.DefaultPaths(root)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	appendConfig := `
+[[mcp.servers]]
+id = "docs"
+enabled = true
+clients = ["claude"]
+transport = "stdio"
+command = "docs-mcp"
+
+[warnings.clients.claude]
+tokenizer = "cl100k_base"
+`
+	f, err := os.OpenFile(paths.ConfigPath, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("open config: %v", err)
+	}
+	if _, err := f.WriteString(appendConfig); err != nil {
+		t.Fatalf("append config: %v", err)
+	}
+	_ = f.Close()
+	if err := os.WriteFile(filepath.Join(root, "CLAUDE.md"), []byte(strings.Repeat("a", 300)), 0o644); err != nil {
+		t.Fatalf("write CLAUDE.md: %v", err)
+	}
+
+	original := discoverStatsTools
+	t.Cleanup(func() { discoverStatsTools = original })
+	discovered := 0
+	discoverStatsTools = func(context.Context, *config.ProjectConfig, warnings.Connector) ([]warnings.DiscoveryResult, error) {
+		discovered++
+		return []warnings.DiscoveryResult{{
+			ServerID:     "docs",
+			Tools:        []warnings.ToolDef{{Name: "search", Schema: strings.Repeat("b", 30)}},
+			SchemaTokens: 40,
+		}}, nil
+	}
+
+	withWorkingDir(t, root, func() {
+		out, err := runStatsCmd(t)
+		if err != nil {
+			t.Fatalf("stats error: %v", err)
+		}
+		for _, want := range []string{
+			"Tokenizer: heuristic\n",
+			"cl100k_base.tiktoken",
+			"00_base.md  3       all\n",
+			"docs    1      40             claude\n",
+			"docs    search  11\n",
+			fmt.Sprintf("claude       heuristic  CLAUDE.md                        %d", warnings.EstimateTokens(strings.Repeat("a", 300))),
+			"codex        heuristic  AGENTS.md                        not generated  0        0      0              0\n",
+		} {
+			if !strings.Contains(out, want) {
+				t.Fatalf("stats output missing %q:\n%s", want, out)
+			}
+		}
+
+		out, err = runStatsCmd(t, "--no-mcp")
+		if err != nil || discovered != 1 {
+			t.Fatalf("stats --no-mcp: discovered %d times, %v", discovered, err)
+		}
+		if strings.Contains(out, "MCP servers") || !strings.Contains(out, "  -      -              ") {
+			t.Fatalf("expected MCP sections to be skipped:\n%s", out)
+		}
+	})
+}
@@ -15,6 +15,8 @@ import (
 var ErrSyncCompletedWithWarnings = errors.New(messages.SyncCompletedWithWarnings)
 
 func newSyncCmd() *cobra.Command {
+	var profile string
+	var force bool
 	cmd := &cobra.Command{
 		Use:   messages.SyncUse,
 		Short: messages.SyncShort,
@@ -23,7 +25,7 @@ func newSyncCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			warnings, err := sync.Run(root)
+			warnings, err := sync.RunWithOptions(root, sync.Options{Load: resolveLoadOptions(profile), Force: force})
 			if err != nil {
 				return err
 			}
@@ -36,6 +38,8 @@ func newSyncCmd() *cobra.Command {
 			return nil
 		},
 	}
+	addProfileFlag(cmd, &profile)
+	cmd.Flags().BoolVar(&force, "force", false, messages.SyncFlagForce)
 
 	return cmd
 }
@@ -10,6 +10,7 @@ import (
 )
 
 func newVSCodeCmd() *cobra.Command {
+	var profile string
 	cmd := &cobra.Command{
 		Use:   messages.VSCodeUse,
 		Short: messages.VSCodeShort,
@@ -18,11 +19,12 @@ func newVSCodeCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			return clients.Run(root, "vscode", func(cfg *config.Config) *bool {
+			return clients.Run(root, resolveLoadOptions(profile), "vscode", func(cfg *config.Config) *bool {
 				return cfg.Agents.VSCode.Enabled
 			}, vscode.Launch)
 		},
 	}
+	addProfileFlag(cmd, &profile)
 
 	return cmd
 }
@@ -18,7 +18,7 @@ func TestLaunchClaude(t *testing.T) {
 	cfg := &config.ProjectConfig{
 		Config: config.Config{
 			Agents: config.AgentsConfig{
-				Claude: config.AgentConfig{Model: "test-model"},
+				Claude: config.ClaudeConfig{Model: "test-model"},
 			},
 		},
 		Root: root,
@@ -39,7 +39,7 @@ func TestLaunchClaudeError(t *testing.T) {
 	cfg := &config.ProjectConfig{
 		Config: config.Config{
 			Agents: config.AgentsConfig{
-				Claude: config.AgentConfig{Model: "test-model"},
+				Claude: config.ClaudeConfig{Model: "test-model"},
 			},
 		},
 		Root: root,
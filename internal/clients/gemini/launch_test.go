@@ -18,7 +18,7 @@ func TestLaunchGemini(t *testing.T) {
 	cfg := &config.ProjectConfig{
 		Config: config.Config{
 			Agents: config.AgentsConfig{
-				Gemini: config.AgentConfig{Model: "test-model"},
+				Gemini: config.GeminiConfig{Model: "test-model"},
 			},
 		},
 		Root: root,
@@ -39,7 +39,7 @@ func TestLaunchGeminiError(t *testing.T) {
 	cfg := &config.ProjectConfig{
 		Config: config.Config{
 			Agents: config.AgentsConfig{
-				Gemini: config.AgentConfig{Model: "test-model"},
+				Gemini: config.GeminiConfig{Model: "test-model"},
 			},
 		},
 		Root: root,
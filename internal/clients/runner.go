@@ -17,14 +17,15 @@ type LaunchFunc func(project *config.ProjectConfig, runInfo *run.Info, env []str
 type EnabledSelector func(cfg *config.Config) *bool
 
 // Run performs the standard client launch pipeline: load config, sync, create run dir, launch.
-// Warnings from sync are printed to stderr before launching.
-func Run(root string, name string, enabled EnabledSelector, launch LaunchFunc) error {
-	return RunWithStderr(root, name, enabled, launch, os.Stderr)
+// opts selects how the config is resolved (for example, the active profile); sync and launch
+// both use the resolved config. Warnings from sync are printed to stderr before launching.
+func Run(root string, opts config.LoadOptions, name string, enabled EnabledSelector, launch LaunchFunc) error {
+	return RunWithStderr(root, opts, name, enabled, launch, os.Stderr)
 }
 
 // RunWithStderr is like Run but allows specifying a custom stderr writer for testing.
-func RunWithStderr(root string, name string, enabled EnabledSelector, launch LaunchFunc, stderr io.Writer) error {
-	project, err := config.LoadProjectConfig(root)
+func RunWithStderr(root string, opts config.LoadOptions, name string, enabled EnabledSelector, launch LaunchFunc, stderr io.Writer) error {
+	project, err := config.LoadProjectConfigWithOptions(root, opts)
 	if err != nil {
 		return err
 	}
@@ -48,6 +49,8 @@ func RunWithStderr(root string, name string, enabled EnabledSelector, launch Lau
 	}
 
 	env := BuildEnv(os.Environ(), project.Env, runInfo)
+	// Secrets are only ever passed through the environment; generated configs reference them by name.
+	env = mergeEnv(env, project.SecretEnv())
 
 	return launch(project, runInfo, env)
 }
@@ -23,7 +23,7 @@ func TestRunPipeline(t *testing.T) {
 		return nil
 	}
 
-	err := Run(root, "gemini", func(cfg *config.Config) *bool {
+	err := Run(root, config.LoadOptions{}, "gemini", func(cfg *config.Config) *bool {
 		return cfg.Agents.Gemini.Enabled
 	}, launch)
 	if err != nil {
@@ -51,7 +51,7 @@ func TestRunDisabled(t *testing.T) {
 	writeMinimalRepo(t, root)
 
 	disabled := false
-	err := Run(root, "gemini", func(cfg *config.Config) *bool {
+	err := Run(root, config.LoadOptions{}, "gemini", func(cfg *config.Config) *bool {
 		return &disabled
 	}, func(project *config.ProjectConfig, runInfo *run.Info, env []string) error {
 		return nil
@@ -62,7 +62,7 @@ func TestRunDisabled(t *testing.T) {
 }
 
 func TestRunMissingConfig(t *testing.T) {
-	err := Run(t.TempDir(), "gemini", func(cfg *config.Config) *bool {
+	err := Run(t.TempDir(), config.LoadOptions{}, "gemini", func(cfg *config.Config) *bool {
 		return cfg.Agents.Gemini.Enabled
 	}, func(project *config.ProjectConfig, runInfo *run.Info, env []string) error {
 		return nil
@@ -83,7 +83,7 @@ func TestRunSyncError(t *testing.T) {
 		_ = os.Chmod(root, 0o700)
 	})
 
-	err := Run(root, "gemini", func(cfg *config.Config) *bool {
+	err := Run(root, config.LoadOptions{}, "gemini", func(cfg *config.Config) *bool {
 		return cfg.Agents.Gemini.Enabled
 	}, func(project *config.ProjectConfig, runInfo *run.Info, env []string) error {
 		return nil
@@ -102,7 +102,7 @@ func TestRunCreateError(t *testing.T) {
 		t.Fatalf("write tmp file: %v", err)
 	}
 
-	err := Run(root, "gemini", func(cfg *config.Config) *bool {
+	err := Run(root, config.LoadOptions{}, "gemini", func(cfg *config.Config) *bool {
 		return cfg.Agents.Gemini.Enabled
 	}, func(project *config.ProjectConfig, runInfo *run.Info, env []string) error {
 		return nil
@@ -116,7 +116,7 @@ func TestRunLaunchError(t *testing.T) {
 	root := t.TempDir()
 	writeMinimalRepo(t, root)
 
-	err := Run(root, "gemini", func(cfg *config.Config) *bool {
+	err := Run(root, config.LoadOptions{}, "gemini", func(cfg *config.Config) *bool {
 		return cfg.Agents.Gemini.Enabled
 	}, func(project *config.ProjectConfig, runInfo *run.Info, env []string) error {
 		return fmt.Errorf("launch failed")
@@ -126,6 +126,104 @@ func TestRunLaunchError(t *testing.T) {
 	}
 }
 
+func TestRunWithProfile(t *testing.T) {
+	root := t.TempDir()
+	writeMinimalRepo(t, root)
+	paths := config.DefaultPaths(root)
+	profile := "\n[profiles.review.approvals]\nmode = \"none\"\n\n[profiles.review.agents.gemini]\nmodel = \"flash\"\n"
+	f, err := os.OpenFile(paths.ConfigPath, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("open config: %v", err)
+	}
+	if _, err := f.WriteString(profile); err != nil {
+		t.Fatalf("append profile: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("close config: %v", err)
+	}
+
+	var got *config.ProjectConfig
+	err = Run(root, config.LoadOptions{Profile: "review"}, "gemini", func(cfg *config.Config) *bool {
+		return cfg.Agents.Gemini.Enabled
+	}, func(project *config.ProjectConfig, runInfo *run.Info, env []string) error {
+		got = project
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if got.Profile != "review" || got.Config.Approvals.Mode != "none" || got.Config.Agents.Gemini.Model != "flash" {
+		t.Fatalf("expected profile-resolved config, got profile=%q approvals=%q model=%q",
+			got.Profile, got.Config.Approvals.Mode, got.Config.Agents.Gemini.Model)
+	}
+
+	err = Run(root, config.LoadOptions{Profile: "missing"}, "gemini", func(cfg *config.Config) *bool {
+		return cfg.Agents.Gemini.Enabled
+	}, func(project *config.ProjectConfig, runInfo *run.Info, env []string) error {
+		return nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "unknown profile") {
+		t.Fatalf("expected unknown profile error, got %v", err)
+	}
+}
+
+func TestRunInjectsSecrets(t *testing.T) {
+	root := t.TempDir()
+	writeMinimalRepo(t, root)
+	paths := config.DefaultPaths(root)
+	secretsDir := filepath.Join(root, "secrets")
+	if err := os.MkdirAll(secretsDir, 0o700); err != nil {
+		t.Fatalf("mkdir secrets: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(secretsDir, "API_TOKEN"), []byte("s3cr3t\n"), 0o600); err != nil {
+		t.Fatalf("write secret: %v", err)
+	}
+	extra := `
+[[secrets.providers]]
+type = "file"
+dir = "secrets"
+
+[[mcp.servers]]
+id = "api"
+enabled = true
+transport = "http"
+url = "https://example.com/mcp"
+headers = { Authorization = "Bearer ${secret:API_TOKEN}" }
+`
+	f, err := os.OpenFile(paths.ConfigPath, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("open config: %v", err)
+	}
+	if _, err := f.WriteString(extra); err != nil {
+		t.Fatalf("append config: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("close config: %v", err)
+	}
+	t.Setenv("API_TOKEN", "from-shell")
+
+	var gotEnv []string
+	err = Run(root, config.LoadOptions{}, "gemini", func(cfg *config.Config) *bool {
+		return cfg.Agents.Gemini.Enabled
+	}, func(project *config.ProjectConfig, runInfo *run.Info, env []string) error {
+		gotEnv = env
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if value, ok := GetEnv(gotEnv, "API_TOKEN"); !ok || value != "s3cr3t" {
+		t.Fatalf("expected secret in launch env, got %q", value)
+	}
+	settings, err := os.ReadFile(filepath.Join(root, ".gemini", "settings.json"))
+	if err != nil {
+		t.Fatalf("read settings: %v", err)
+	}
+	if strings.Contains(string(settings), "s3cr3t") || !strings.Contains(string(settings), "${API_TOKEN}") {
+		t.Fatalf("expected settings to reference the secret by name:\n%s", settings)
+	}
+}
+
 func writeMinimalRepo(t *testing.T, root string) {
 	t.Helper()
 	paths := config.DefaultPaths(root)
@@ -0,0 +1,225 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+var argumentNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
+// ArgumentStyle selects how {{ arg "name" }} is written into a client's output.
+type ArgumentStyle int
+
+const (
+	// ArgumentsPromptServer writes ${arg:name}, which the MCP prompt server substitutes per request.
+	ArgumentsPromptServer ArgumentStyle = iota
+	// ArgumentsVSCodeInput writes ${input:name}, which VS Code asks for when the prompt runs.
+	ArgumentsVSCodeInput
+	// ArgumentsShell writes $ARGUMENTS for a single argument and $1, $2, ... otherwise, for native commands.
+	ArgumentsShell
+	// ArgumentsText writes <name>, for skills that list their arguments in prose.
+	ArgumentsText
+	// ArgumentsGemini writes {{args}} for a single argument and <name> otherwise; Gemini CLI appends
+	// the raw arguments to prompts that do not contain {{args}}.
+	ArgumentsGemini
+)
+
+// Placeholder returns the text written for args[i].
+func (s ArgumentStyle) Placeholder(args []SlashCommandArgument, i int) string {
+	switch s {
+	case ArgumentsVSCodeInput:
+		return "${input:" + args[i].Name + "}"
+	case ArgumentsShell:
+		if len(args) == 1 {
+			return "$ARGUMENTS"
+		}
+		return "$" + strconv.Itoa(i+1)
+	case ArgumentsGemini:
+		if len(args) == 1 {
+			return "{{args}}"
+		}
+		return "<" + args[i].Name + ">"
+	case ArgumentsText:
+		return "<" + args[i].Name + ">"
+	default:
+		return PromptArgumentPlaceholder(args[i].Name)
+	}
+}
+
+// PromptArgumentPlaceholder is the marker the prompt server replaces with the value of argument name.
+func PromptArgumentPlaceholder(name string) string {
+	return "${arg:" + name + "}"
+}
+
+// ArgumentHint summarizes args for argument-hint front matter: <required> [optional].
+func ArgumentHint(args []SlashCommandArgument) string {
+	parts := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg.Required {
+			parts = append(parts, "<"+arg.Name+">")
+		} else {
+			parts = append(parts, "["+arg.Name+"]")
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// EffectiveArgumentHint returns the argument-hint front matter value when set, else ArgumentHint(c.Arguments).
+func (c SlashCommand) EffectiveArgumentHint() string {
+	if c.ArgumentHint != "" {
+		return c.ArgumentHint
+	}
+	return ArgumentHint(c.Arguments)
+}
+
+// decodeArguments reads the arguments: front matter list. Entries are names (arguments: [issue, branch])
+// or mappings with name, description, required, and default.
+func decodeArguments(node *yaml.Node) ([]SlashCommandArgument, error) {
+	if node.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf(messages.ConfigSlashCommandArgumentsInvalid)
+	}
+	args := make([]SlashCommandArgument, 0, len(node.Content))
+	for _, item := range node.Content {
+		switch item.Kind {
+		case yaml.ScalarNode:
+			args = append(args, SlashCommandArgument{Name: item.Value})
+		case yaml.MappingNode:
+			var arg SlashCommandArgument
+			for i := 0; i+1 < len(item.Content); i += 2 {
+				if err := setArgumentField(&arg, item.Content[i].Value, item.Content[i+1]); err != nil {
+					return nil, err
+				}
+			}
+			args = append(args, arg)
+		default:
+			return nil, fmt.Errorf(messages.ConfigSlashCommandArgumentsInvalid)
+		}
+	}
+	return args, nil
+}
+
+func setArgumentField(arg *SlashCommandArgument, key string, value *yaml.Node) error {
+	if value.Kind != yaml.ScalarNode {
+		return fmt.Errorf(messages.ConfigSlashCommandArgumentsInvalid)
+	}
+	switch key {
+	case "name":
+		arg.Name = value.Value
+	case "description":
+		arg.Description = value.Value
+	case "default":
+		arg.Default = value.Value
+	case "required":
+		if value.Tag != "!!bool" {
+			return fmt.Errorf(messages.ConfigSlashCommandArgumentRequiredInvalidFmt, arg.Name)
+		}
+		return value.Decode(&arg.Required)
+	default:
+		return fmt.Errorf(messages.ConfigSlashCommandArgumentKeyUnknownFmt, key)
+	}
+	return nil
+}
+
+func validateArguments(args []SlashCommandArgument) error {
+	seen := make(map[string]bool, len(args))
+	for _, arg := range args {
+		if !argumentNamePattern.MatchString(arg.Name) {
+			return fmt.Errorf(messages.ConfigSlashCommandArgumentNameInvalidFmt, arg.Name)
+		}
+		if seen[arg.Name] {
+			return fmt.Errorf(messages.ConfigSlashCommandArgumentDuplicateFmt, arg.Name)
+		}
+		seen[arg.Name] = true
+		if arg.Required && arg.Default != "" {
+			return fmt.Errorf(messages.ConfigSlashCommandArgumentDefaultRequiredFmt, arg.Name)
+		}
+	}
+	return nil
+}
+
+// checkArgumentReferences rejects {{ arg "name" }} calls in body for names args does not declare.
+// line is the line of the source file where body starts. Bodies that do not parse are left for
+// rendering to report; arguments used only inside included files are checked when rendering.
+func checkArgumentReferences(body string, line int, args []SlashCommandArgument) error {
+	if !strings.Contains(body, "{{") {
+		return nil
+	}
+	tmpl, err := template.New("").Funcs(templateFuncNames).Parse(body)
+	if err != nil {
+		return nil
+	}
+	declared := make(map[string]bool, len(args))
+	for _, arg := range args {
+		declared[arg.Name] = true
+	}
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil {
+			continue
+		}
+		var undeclared error
+		walkArgumentCalls(t.Tree.Root, func(name string, pos parse.Pos) {
+			if undeclared == nil && !declared[name] {
+				undeclared = fmt.Errorf(messages.ConfigSlashCommandArgumentUndeclaredFmt, line+strings.Count(body[:pos], "\n"), name)
+			}
+		})
+		if undeclared != nil {
+			return undeclared
+		}
+	}
+	return nil
+}
+
+// walkArgumentCalls calls visit for every arg "name" call with a literal name under node.
+func walkArgumentCalls(node parse.Node, visit func(name string, pos parse.Pos)) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			walkArgumentCalls(child, visit)
+		}
+	case *parse.ActionNode:
+		walkArgumentCalls(n.Pipe, visit)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			walkArgumentCalls(cmd, visit)
+		}
+	case *parse.CommandNode:
+		if len(n.Args) >= 2 {
+			if ident, ok := n.Args[0].(*parse.IdentifierNode); ok && ident.Ident == "arg" {
+				if name, ok := n.Args[1].(*parse.StringNode); ok {
+					visit(name.Text, n.Position())
+				}
+			}
+		}
+		for _, arg := range n.Args {
+			walkArgumentCalls(arg, visit)
+		}
+	case *parse.IfNode:
+		walkArgumentBranch(&n.BranchNode, visit)
+	case *parse.RangeNode:
+		walkArgumentBranch(&n.BranchNode, visit)
+	case *parse.WithNode:
+		walkArgumentBranch(&n.BranchNode, visit)
+	case *parse.TemplateNode:
+		walkArgumentCalls(n.Pipe, visit)
+	}
+}
+
+func walkArgumentBranch(n *parse.BranchNode, visit func(name string, pos parse.Pos)) {
+	walkArgumentCalls(n.Pipe, visit)
+	walkArgumentCalls(n.List, visit)
+	walkArgumentCalls(n.ElseList, visit)
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSlashCommandArguments(t *testing.T) {
+	content := `---
+arguments:
+  - name: issue
+    description: "Issue number: the one to fix"
+    required: true
+  - name: branch
+    default: main
+  - verbose
+description: Fix an issue
+---
+Fix {{ arg "issue" }} on {{ if true }}{{ arg "branch" }}{{ end }}.
+`
+	cmd, err := parseSlashCommand(content)
+	if err != nil {
+		t.Fatalf("parseSlashCommand error: %v", err)
+	}
+	if cmd.Description != "Fix an issue" {
+		t.Fatalf("nested description leaked into the command: %q", cmd.Description)
+	}
+	want := []SlashCommandArgument{
+		{Name: "issue", Description: "Issue number: the one to fix", Required: true},
+		{Name: "branch", Default: "main"},
+		{Name: "verbose"},
+	}
+	if !reflect.DeepEqual(cmd.Arguments, want) {
+		t.Fatalf("unexpected arguments: %+v", cmd.Arguments)
+	}
+
+	cmd, err = parseSlashCommand("---\ndescription: d\narguments: [a, b]\n---\n{{ arg \"b\" }}\n")
+	if err != nil {
+		t.Fatalf("parseSlashCommand inline error: %v", err)
+	}
+	if !reflect.DeepEqual(cmd.Arguments, []SlashCommandArgument{{Name: "a"}, {Name: "b"}}) {
+		t.Fatalf("unexpected inline arguments: %+v", cmd.Arguments)
+	}
+}
+
+func TestParseSlashCommandArgumentErrors(t *testing.T) {
+	cases := map[string]struct {
+		frontMatter string
+		body        string
+		want        string
+	}{
+		"unknown key":      {"arguments:\n  - name: a\n    type: string\n", "", `unknown key "type"`},
+		"bad required":     {"arguments:\n  - name: a\n    required: yes\n", "", "required must be true or false"},
+		"missing name":     {"arguments:\n  - description: x\n", "", `invalid name ""`},
+		"invalid name":     {"arguments: [\"my arg\"]\n", "", `invalid name "my arg"`},
+		"duplicate":        {"arguments: [a, a]\n", "", `"a" is declared more than once`},
+		"required default": {"arguments:\n  - name: a\n    required: true\n    default: x\n", "", "cannot have a default"},
+		"no item":          {"arguments:\n  name: a\n", "", "arguments must be a list"},
+		"undeclared":       {"arguments: [a]\n", "ok\n{{ arg \"a\" }}\n{{ with .Client }}{{ arg \"b\" }}{{ end }}", `line 7: argument "b" is not declared`},
+		"no arguments":     {"template: true\n", "{{ arg \"a\" }}", `argument "a" is not declared`},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseSlashCommand("---\ndescription: d\n" + tc.frontMatter + "---\n" + tc.body + "\n")
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestArgumentStylePlaceholders(t *testing.T) {
+	args := []SlashCommandArgument{{Name: "issue", Required: true}, {Name: "branch"}}
+	cases := []struct {
+		style ArgumentStyle
+		args  []SlashCommandArgument
+		want  string
+	}{
+		{ArgumentsPromptServer, args, "${arg:issue} ${arg:branch}"},
+		{ArgumentsVSCodeInput, args, "${input:issue} ${input:branch}"},
+		{ArgumentsShell, args, "$1 $2"},
+		{ArgumentsShell, args[:1], "$ARGUMENTS "},
+		{ArgumentsText, args, "<issue> <branch>"},
+		{ArgumentsGemini, args, "<issue> <branch>"},
+		{ArgumentsGemini, args[:1], "{{args}} "},
+	}
+	for _, tc := range cases {
+		cmd := SlashCommand{Body: `{{ arg "issue" }} {{ if gt (len .Client) 0 }}{{ arg "branch" }}{{ end }}`, Arguments: tc.args, Template: true}
+		if len(tc.args) == 1 {
+			cmd.Body = `{{ arg "issue" }} `
+		}
+		rendered, err := RenderSlashCommands([]SlashCommand{cmd}, TemplateData{Client: "claude"}.WithArgumentStyle(tc.style))
+		if err != nil {
+			t.Fatalf("style %d: render error: %v", tc.style, err)
+		}
+		if rendered[0].Body != tc.want {
+			t.Fatalf("style %d: got %q, want %q", tc.style, rendered[0].Body, tc.want)
+		}
+	}
+
+	if got := ArgumentHint(args); got != "<issue> [branch]" {
+		t.Fatalf("unexpected argument hint %q", got)
+	}
+}
+
+func TestRenderArgErrors(t *testing.T) {
+	cmd := SlashCommand{SourcePath: "cmd.md", Line: 4, Body: `{{ arg "missing" }}`, Template: true}
+	if _, err := RenderSlashCommands([]SlashCommand{cmd}, TemplateData{}); err == nil || !strings.Contains(err.Error(), `arg "missing": not declared`) {
+		t.Fatalf("expected undeclared arg error, got %v", err)
+	}
+
+	files := []InstructionFile{{Name: "a.md", SourcePath: "a.md", Line: 1, Content: `{{ arg "x" }}`, Template: true}}
+	if _, err := RenderInstructions(files, TemplateData{}); err == nil || !strings.Contains(err.Error(), "only available in slash command bodies") {
+		t.Fatalf("expected arg outside command error, got %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// skippedChildDirs are dependency, build output, and virtualenv directories, which are never
+// searched for child layers.
+var skippedChildDirs = map[string]struct{}{
+	"node_modules":     {},
+	"bower_components": {},
+	"vendor":           {},
+	"target":           {},
+	"build":            {},
+	"dist":             {},
+	"venv":             {},
+	"__pycache__":      {},
+}
+
+// LoadChildLayers discovers nested .agent-layer/ directories below root, skipping directories that
+// match exclude (see ExcludesDir).
+// A nested .agent-layer/ without a config.toml is a child layer that inherits the root config and
+// contributes its own instructions and slash commands.
+func LoadChildLayers(root string, exclude []string) ([]ChildLayer, error) {
+	var dirs []string
+	err := WalkProjectDirs(root, exclude, func(dir string) error {
+		layerDir := filepath.Join(dir, ".agent-layer")
+		info, err := os.Stat(layerDir)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return fmt.Errorf(messages.ConfigChildLayerReadFailedFmt, layerDir, err)
+		}
+		if info.IsDir() {
+			dirs = append(dirs, dir)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return loadChildLayers(root, dirs)
+}
+
+// loadChildLayers reads the child layers in dirs, which were found by an earlier search.
+func loadChildLayers(root string, dirs []string) ([]ChildLayer, error) {
+	children := make([]ChildLayer, 0, len(dirs))
+	for _, dir := range dirs {
+		child, err := loadChildLayer(root, dir)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+	return children, nil
+}
+
+// ExcludesDir reports whether the repo-relative, slash-separated dir matches a [children] exclude
+// pattern. A pattern with a slash is matched against the whole path, like "services/legacy" or
+// "apps/*/fixtures"; a pattern without one matches a directory of that name at any depth.
+func ExcludesDir(exclude []string, dir string) bool {
+	for _, pattern := range exclude {
+		pattern = strings.TrimSuffix(pattern, "/")
+		target := dir
+		if !strings.Contains(pattern, "/") {
+			target = path.Base(dir)
+		}
+		if ok, _ := path.Match(pattern, target); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// WalkProjectDirs calls fn for every directory below root that belongs to root's project.
+// A nested .agent-layer/ with its own config.toml is an independent project and is skipped along with
+// everything below it. Hidden, skippedChildDirs, excluded, and unreadable directories are not searched.
+func WalkProjectDirs(root string, exclude []string, fn func(dir string) error) error {
+	return filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path != root && errors.Is(err, fs.ErrPermission) {
+				return filepath.SkipDir
+			}
+			return fmt.Errorf(messages.ConfigChildLayerReadFailedFmt, path, err)
+		}
+		if !entry.IsDir() || path == root {
+			return nil
+		}
+		name := entry.Name()
+		if name[0] == '.' {
+			return filepath.SkipDir
+		}
+		if _, ok := skippedChildDirs[name]; ok {
+			return filepath.SkipDir
+		}
+		if rel, err := filepath.Rel(root, path); err == nil && ExcludesDir(exclude, filepath.ToSlash(rel)) {
+			return filepath.SkipDir
+		}
+		if _, err := os.Stat(filepath.Join(path, ".agent-layer", "config.toml")); err == nil {
+			return filepath.SkipDir
+		}
+		return fn(path)
+	})
+}
+
+// loadChildLayer reads the optional instructions and slash commands of a child layer.
+func loadChildLayer(root string, dir string) (ChildLayer, error) {
+	rel, err := filepath.Rel(root, dir)
+	if err != nil {
+		return ChildLayer{}, fmt.Errorf(messages.ConfigChildLayerReadFailedFmt, dir, err)
+	}
+	child := ChildLayer{Dir: filepath.ToSlash(rel), Path: dir}
+	paths := DefaultPaths(dir)
+
+	if ok, err := dirExists(paths.InstructionsDir); err != nil {
+		return ChildLayer{}, err
+	} else if ok {
+		if child.Instructions, err = readInstructionDir(paths.InstructionsDir); err != nil {
+			return ChildLayer{}, err
+		}
+		for _, file := range child.Instructions {
+			if len(file.AppliesTo) > 0 {
+				return ChildLayer{}, fmt.Errorf(messages.ConfigChildAppliesToUnsupportedFmt, file.SourcePath)
+			}
+		}
+	}
+	if ok, err := dirExists(paths.SlashCommandsDir); err != nil {
+		return ChildLayer{}, err
+	} else if ok {
+		if child.SlashCommands, err = LoadSlashCommands(paths.SlashCommandsDir); err != nil {
+			return ChildLayer{}, err
+		}
+	}
+	return child, nil
+}
+
+// mergeChildSlashCommands appends child slash commands to the root set, rejecting duplicate names
+// and names that collide once namespaces are flattened.
+func mergeChildSlashCommands(commands []SlashCommand, children []ChildLayer) ([]SlashCommand, error) {
+	for _, child := range children {
+		commands = append(commands, child.SlashCommands...)
+	}
+	if err := checkSlashCommandNames(commands); err != nil {
+		return nil, err
+	}
+	return commands, nil
+}
+
+func dirExists(path string) (bool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf(messages.ConfigChildLayerReadFailedFmt, path, err)
+	}
+	return info.IsDir(), nil
+}
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeChildFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestLoadChildLayers(t *testing.T) {
+	root := t.TempDir()
+	writeChildFile(t, filepath.Join(root, ".agent-layer", "config.toml"), "")
+	writeChildFile(t, filepath.Join(root, "services", "api", ".agent-layer", "instructions", "00_api.md"), "\xEF\xBB\xBFapi")
+	writeChildFile(t, filepath.Join(root, "services", "api", ".agent-layer", "slash-commands", "deploy-api.md"),
+		"---\ndescription: Deploy the API.\n---\n\nDeploy.")
+	writeChildFile(t, filepath.Join(root, "services", "api", "handlers", ".agent-layer", "instructions", "00_handlers.md"), "handlers")
+	// Independent project: has its own config.toml, so it and its subtree are skipped.
+	writeChildFile(t, filepath.Join(root, "vendor-project", ".agent-layer", "config.toml"), "")
+	writeChildFile(t, filepath.Join(root, "vendor-project", "sub", ".agent-layer", "instructions", "00.md"), "x")
+	// Skipped directories.
+	writeChildFile(t, filepath.Join(root, "node_modules", "pkg", ".agent-layer", "instructions", "00.md"), "x")
+	writeChildFile(t, filepath.Join(root, ".hidden", ".agent-layer", "instructions", "00.md"), "x")
+	// Child with only slash commands.
+	writeChildFile(t, filepath.Join(root, "tools", ".agent-layer", "slash-commands", "lint.md"),
+		"---\ndescription: Lint.\n---\n\nLint.")
+
+	children, err := LoadChildLayers(root, nil)
+	if err != nil {
+		t.Fatalf("LoadChildLayers error: %v", err)
+	}
+	var dirs []string
+	for _, child := range children {
+		dirs = append(dirs, child.Dir)
+	}
+	if strings.Join(dirs, ",") != "services/api,services/api/handlers,tools" {
+		t.Fatalf("unexpected child layers: %v", dirs)
+	}
+	api := children[0]
+	if api.Path != filepath.Join(root, "services", "api") {
+		t.Fatalf("unexpected child path: %s", api.Path)
+	}
+	if len(api.Instructions) != 1 || api.Instructions[0].Content != "api" {
+		t.Fatalf("unexpected child instructions: %+v", api.Instructions)
+	}
+	if len(api.SlashCommands) != 1 || api.SlashCommands[0].Name != "deploy-api" {
+		t.Fatalf("unexpected child slash commands: %+v", api.SlashCommands)
+	}
+	if len(children[2].Instructions) != 0 {
+		t.Fatalf("expected tools child to have no instructions")
+	}
+}
+
+func TestLoadChildLayersInvalidSlashCommand(t *testing.T) {
+	root := t.TempDir()
+	writeChildFile(t, filepath.Join(root, "svc", ".agent-layer", "slash-commands", "bad.md"), "no front matter")
+
+	_, err := LoadChildLayers(root, nil)
+	if err == nil || !strings.Contains(err.Error(), "invalid slash command") {
+		t.Fatalf("expected invalid slash command error, got %v", err)
+	}
+}
+
+func TestLoadChildLayersRejectsAppliesTo(t *testing.T) {
+	root := t.TempDir()
+	writeChildFile(t, filepath.Join(root, "svc", ".agent-layer", "instructions", "00.md"), "---\napplies_to: [\"src/**\"]\n---\nx")
+
+	_, err := LoadChildLayers(root, nil)
+	if err == nil || !strings.Contains(err.Error(), "applies_to is only supported in the root") {
+		t.Fatalf("expected applies_to error, got %v", err)
+	}
+}
+
+func TestWalkProjectDirs(t *testing.T) {
+	root := t.TempDir()
+	writeChildFile(t, filepath.Join(root, "web", "src", "app.ts"), "")
+	writeChildFile(t, filepath.Join(root, "nested", ".agent-layer", "config.toml"), "")
+	writeChildFile(t, filepath.Join(root, "node_modules", "pkg", "index.js"), "")
+	writeChildFile(t, filepath.Join(root, ".git", "HEAD"), "")
+	writeChildFile(t, filepath.Join(root, "go", "vendor", "mod", "x.go"), "")
+	writeChildFile(t, filepath.Join(root, "web", "fixtures", "a.ts"), "")
+	writeChildFile(t, filepath.Join(root, "legacy", "app", "main.c"), "")
+
+	var dirs []string
+	if err := WalkProjectDirs(root, []string{"fixtures", "legacy/"}, func(dir string) error {
+		rel, _ := filepath.Rel(root, dir)
+		dirs = append(dirs, filepath.ToSlash(rel))
+		return nil
+	}); err != nil {
+		t.Fatalf("WalkProjectDirs error: %v", err)
+	}
+	if strings.Join(dirs, ",") != "go,web,web/src" {
+		t.Fatalf("unexpected dirs: %v", dirs)
+	}
+}
+
+func TestExcludesDir(t *testing.T) {
+	exclude := []string{"fixtures", "services/legacy/", "apps/*/gen"}
+	for dir, want := range map[string]bool{
+		"fixtures":               true,
+		"web/test/fixtures":      true,
+		"services/legacy":        true,
+		"services/legacy-api":    false,
+		"other/services/legacy":  false,
+		"apps/web/gen":           true,
+		"apps/web/src/gen":       false,
+		"web/fixtures-generator": false,
+	} {
+		if got := ExcludesDir(exclude, dir); got != want {
+			t.Fatalf("ExcludesDir(%q) = %v, want %v", dir, got, want)
+		}
+	}
+}
+
+func TestLoadChildLayersExclude(t *testing.T) {
+	root := t.TempDir()
+	writeChildFile(t, filepath.Join(root, ".agent-layer", "config.toml"), "")
+	writeChildFile(t, filepath.Join(root, "svc", ".agent-layer", "instructions", "00.md"), "svc")
+	writeChildFile(t, filepath.Join(root, "old", ".agent-layer", "instructions", "00.md"), "old")
+
+	children, err := LoadChildLayers(root, []string{"old"})
+	if err != nil || len(children) != 1 || children[0].Dir != "svc" {
+		t.Fatalf("expected only the svc child, got %+v, %v", children, err)
+	}
+	children, err = loadChildLayers(root, []string{filepath.Join(root, "old")})
+	if err != nil || len(children) != 1 || children[0].Dir != "old" || children[0].Instructions[0].Content != "old" {
+		t.Fatalf("expected the listed child to be loaded without a search, got %+v, %v", children, err)
+	}
+}
+
+func TestMergeChildSlashCommands(t *testing.T) {
+	commands := []SlashCommand{{Name: "review", SourcePath: "root/review.md"}}
+	children := []ChildLayer{{Dir: "svc", SlashCommands: []SlashCommand{{Name: "deploy", SourcePath: "svc/deploy.md"}}}}
+
+	merged, err := mergeChildSlashCommands(commands, children)
+	if err != nil {
+		t.Fatalf("mergeChildSlashCommands error: %v", err)
+	}
+	if len(merged) != 2 || merged[1].Name != "deploy" {
+		t.Fatalf("unexpected merged commands: %+v", merged)
+	}
+
+	children = append(children, ChildLayer{Dir: "other", SlashCommands: []SlashCommand{{Name: "review", SourcePath: "other/review.md"}}})
+	_, err = mergeChildSlashCommands(commands, children)
+	if err == nil || !strings.Contains(err.Error(), `duplicate slash command "review" in other/review.md (already defined in root/review.md)`) {
+		t.Fatalf("expected duplicate error, got %v", err)
+	}
+
+	children = []ChildLayer{{Dir: "svc", SlashCommands: []SlashCommand{{Name: "review-all", SourcePath: "svc/review-all.md"}}}}
+	commands = append(commands, SlashCommand{Name: "review:all", SourcePath: "root/review/all.md"})
+	_, err = mergeChildSlashCommands(commands, children)
+	if err == nil || !strings.Contains(err.Error(), `root/review/all.md and svc/review-all.md are both named "review-all"`) {
+		t.Fatalf("expected flattened name collision, got %v", err)
+	}
+}
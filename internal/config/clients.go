@@ -0,0 +1,67 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// validateClients rejects names in a clients: list that are not supported clients.
+func validateClients(clients []string) error {
+	for _, client := range clients {
+		if _, ok := validClients[client]; !ok {
+			return fmt.Errorf(messages.ConfigFrontMatterClientInvalidFmt, client, strings.Join(sortedSetKeys(validClients), ", "))
+		}
+	}
+	return nil
+}
+
+// targetsClient reports whether a clients: list includes client; an empty list targets every client.
+func targetsClient(clients []string, client string) bool {
+	if len(clients) == 0 {
+		return true
+	}
+	for _, c := range clients {
+		if c == client {
+			return true
+		}
+	}
+	return false
+}
+
+// InstructionsForClients returns the fragments that target any of the given clients, in order.
+// Fragments without a clients: list are always included.
+func InstructionsForClients(files []InstructionFile, clients ...string) []InstructionFile {
+	filtered := make([]InstructionFile, 0, len(files))
+	for _, file := range files {
+		for _, client := range clients {
+			if targetsClient(file.Clients, client) {
+				filtered = append(filtered, file)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// SlashCommandsForClient returns the commands that target client, in order.
+// An empty client returns every command.
+func SlashCommandsForClient(commands []SlashCommand, client string) []SlashCommand {
+	if client == "" {
+		return commands
+	}
+	filtered := make([]SlashCommand, 0, len(commands))
+	for _, cmd := range commands {
+		if targetsClient(cmd.Clients, client) {
+			filtered = append(filtered, cmd)
+		}
+	}
+	return filtered
+}
+
+// IsValidClient reports whether name is a supported client for clients: lists.
+func IsValidClient(name string) bool {
+	_, ok := validClients[name]
+	return ok
+}
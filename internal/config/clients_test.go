@@ -0,0 +1,32 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFilterByClient(t *testing.T) {
+	files := []InstructionFile{
+		{Name: "all"},
+		{Name: "claude", Clients: []string{"claude"}},
+		{Name: "codex", Clients: []string{"codex"}},
+	}
+	var names []string
+	for _, file := range InstructionsForClients(files, "gemini", "claude") {
+		names = append(names, file.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"all", "claude"}) {
+		t.Fatalf("unexpected instructions: %v", names)
+	}
+
+	commands := []SlashCommand{{Name: "all"}, {Name: "vscode", Clients: []string{"vscode"}}}
+	if got := SlashCommandsForClient(commands, "claude"); len(got) != 1 || got[0].Name != "all" {
+		t.Fatalf("unexpected commands: %+v", got)
+	}
+	if got := SlashCommandsForClient(commands, ""); len(got) != 2 {
+		t.Fatalf("expected every command without a client, got %+v", got)
+	}
+	if !IsValidClient("vscode") || IsValidClient("cursor") {
+		t.Fatalf("unexpected IsValidClient result")
+	}
+}
@@ -1,9 +1,9 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
-	"regexp"
 	"sort"
 	"strings"
 
@@ -12,62 +12,260 @@ import (
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
-var envVarPattern = regexp.MustCompile(`\$\{([A-Z0-9_]+)\}`)
-
 // EnvVarReplacer returns a replacement string for a resolved env var.
 type EnvVarReplacer func(name string, value string) string
 
-// ExtractEnvVarNames returns env var names referenced by ${VAR} placeholders.
-// input is a string that may contain placeholders; returns names in scan order.
+// Placeholder operators (shell-style; like the shell's colon forms, an empty value counts as unset).
+const (
+	placeholderDefault  = ":-"
+	placeholderRequired = ":?"
+)
+
+// SecretPlaceholderPrefix marks a ${secret:NAME} placeholder, resolved through secret providers
+// instead of .env. Parsed placeholder names keep the prefix.
+const SecretPlaceholderPrefix = "secret:"
+
+// SecretName returns the secret name referenced by a placeholder name such as secret:NAME.
+func SecretName(name string) (string, bool) {
+	return strings.CutPrefix(name, SecretPlaceholderPrefix)
+}
+
+// placeholder is a parsed ${NAME}, ${NAME:-default}, or ${NAME:?message} reference.
+type placeholder struct {
+	// name includes SecretPlaceholderPrefix for secret references.
+	name string
+	op   string
+	// arg is the raw default (which may contain nested placeholders) or the error message.
+	arg string
+}
+
+// placeholderSegment is literal text or a placeholder reference.
+type placeholderSegment struct {
+	literal string
+	ref     *placeholder
+}
+
+// parsePlaceholders splits input into literal text and placeholders.
+// $${...} escapes a placeholder and yields the literal ${...}; anything that is not a well-formed
+// placeholder (for example a client-specific ${env:NAME}) is kept as literal text.
+func parsePlaceholders(input string) []placeholderSegment {
+	var segments []placeholderSegment
+	var literal strings.Builder
+	flush := func() {
+		if literal.Len() > 0 {
+			segments = append(segments, placeholderSegment{literal: literal.String()})
+			literal.Reset()
+		}
+	}
+	for i := 0; i < len(input); {
+		if strings.HasPrefix(input[i:], "$${") {
+			if end := placeholderEnd(input, i+3); end >= 0 {
+				literal.WriteString(input[i+1 : end+1])
+				i = end + 1
+				continue
+			}
+		}
+		if strings.HasPrefix(input[i:], "${") {
+			if ref, end, ok := parsePlaceholder(input, i+2); ok {
+				flush()
+				segments = append(segments, placeholderSegment{ref: ref})
+				i = end + 1
+				continue
+			}
+		}
+		literal.WriteByte(input[i])
+		i++
+	}
+	flush()
+	return segments
+}
+
+// parsePlaceholder parses the body of a placeholder starting at start (just after "${").
+// It returns the reference and the index of its closing brace.
+func parsePlaceholder(input string, start int) (*placeholder, int, bool) {
+	nameStart := start
+	if strings.HasPrefix(input[start:], SecretPlaceholderPrefix) {
+		nameStart += len(SecretPlaceholderPrefix)
+	}
+	i := nameStart
+	for i < len(input) && isEnvVarNameChar(input[i]) {
+		i++
+	}
+	if i == nameStart || i >= len(input) {
+		return nil, 0, false
+	}
+	ref := &placeholder{name: input[start:i]}
+	if input[i] == '}' {
+		return ref, i, true
+	}
+	for _, op := range []string{placeholderDefault, placeholderRequired} {
+		if !strings.HasPrefix(input[i:], op) {
+			continue
+		}
+		end := placeholderEnd(input, i+len(op))
+		if end < 0 {
+			return nil, 0, false
+		}
+		ref.op = op
+		ref.arg = input[i+len(op) : end]
+		return ref, end, true
+	}
+	return nil, 0, false
+}
+
+// placeholderEnd returns the index of the brace closing a placeholder body that starts at start,
+// skipping nested ${...} references, or -1 when it is unterminated.
+func placeholderEnd(input string, start int) int {
+	depth := 0
+	for i := start; i < len(input); i++ {
+		switch {
+		case strings.HasPrefix(input[i:], "${"):
+			depth++
+			i++
+		case input[i] == '}':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// IsValidEnvVarName reports whether name can be referenced as a ${NAME} placeholder.
+func IsValidEnvVarName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		if !isEnvVarNameChar(name[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isEnvVarNameChar(c byte) bool {
+	return c == '_' || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// ExtractEnvVarNames returns the env var names referenced by placeholders in input, in scan order.
+// Names nested in a ${VAR:-default} default are included.
+// A ${secret:NAME} reference keeps its secret: prefix.
+// An escaped $${...} references nothing.
 func ExtractEnvVarNames(input string) []string {
-	matches := envVarPattern.FindAllStringSubmatch(input, -1)
-	if len(matches) == 0 {
-		return nil
-	}
-	names := make([]string, 0, len(matches))
-	for _, match := range matches {
-		if len(match) > 1 && match[1] != "" {
-			names = append(names, match[1])
+	var names []string
+	for _, segment := range parsePlaceholders(input) {
+		if segment.ref == nil {
+			continue
+		}
+		names = append(names, segment.ref.name)
+		if segment.ref.op == placeholderDefault {
+			names = append(names, ExtractEnvVarNames(segment.ref.arg)...)
 		}
 	}
 	return names
 }
 
-// SubstituteEnvVars replaces ${VAR} placeholders using env values.
+// RequiredEnvVarNames returns env var names that must be set for input to resolve:
+// ${VAR} and ${VAR:?message} references, but not ${VAR:-default} or names nested in defaults.
+func RequiredEnvVarNames(input string) []string {
+	var names []string
+	for _, segment := range parsePlaceholders(input) {
+		if segment.ref != nil && segment.ref.op != placeholderDefault {
+			names = append(names, segment.ref.name)
+		}
+	}
+	return names
+}
+
+// SubstituteEnvVars replaces placeholders using env values.
 func SubstituteEnvVars(input string, env map[string]string) (string, error) {
 	return SubstituteEnvVarsWith(input, env, nil)
 }
 
-// SubstituteEnvVarsWith replaces ${VAR} placeholders using env values and a replacer.
+// SubstituteEnvVarsWith replaces placeholders using env values and a replacer.
+// ${VAR} requires a non-empty value; ${VAR:-default} falls back to default (resolved recursively);
+// ${VAR:?message} fails with message when unset; $${...} yields a literal ${...}.
+// ${secret:NAME} forms look up env under the key secret:NAME (see ProjectConfig.PlaceholderEnv).
+// The replacer renders set variables (for example as a client-side placeholder); defaults are always
+// inlined because they are only used when the variable is unset at sync time.
 func SubstituteEnvVarsWith(input string, env map[string]string, replacer EnvVarReplacer) (string, error) {
 	if replacer == nil {
 		replacer = func(_ string, value string) string {
 			return value
 		}
 	}
-	missing := make(map[string]struct{})
-	result := envVarPattern.ReplaceAllStringFunc(input, func(match string) string {
-		varName := strings.TrimSuffix(strings.TrimPrefix(match, "${"), "}")
-		value, ok := env[varName]
-		if !ok || value == "" {
-			missing[varName] = struct{}{}
-			return match
-		}
-		return replacer(varName, value)
-	})
+	sub := &substitution{
+		env:            env,
+		replacer:       replacer,
+		missing:        make(map[string]struct{}),
+		missingSecrets: make(map[string]struct{}),
+	}
+	result := sub.render(input)
 
-	if len(missing) > 0 {
-		var names []string
-		for name := range missing {
-			names = append(names, name)
-		}
-		sort.Strings(names)
-		return "", fmt.Errorf(messages.ConfigMissingEnvVarsFmt, strings.Join(names, ", "))
+	var problems []string
+	if len(sub.missing) > 0 {
+		problems = append(problems, fmt.Sprintf(messages.ConfigMissingEnvVarsFmt, sortedNames(sub.missing)))
+	}
+	if len(sub.missingSecrets) > 0 {
+		problems = append(problems, fmt.Sprintf(messages.ConfigMissingSecretsFmt, sortedNames(sub.missingSecrets)))
+	}
+	problems = append(problems, sub.failures...)
+	if len(problems) > 0 {
+		return "", errors.New(strings.Join(problems, "; "))
 	}
 
 	return result, nil
 }
 
+// substitution accumulates unresolved references while rendering placeholders.
+type substitution struct {
+	env            map[string]string
+	replacer       EnvVarReplacer
+	missing        map[string]struct{}
+	missingSecrets map[string]struct{}
+	failures       []string
+}
+
+// sortedNames joins a name set in sorted order.
+func sortedNames(set map[string]struct{}) string {
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func (s *substitution) render(input string) string {
+	var out strings.Builder
+	for _, segment := range parsePlaceholders(input) {
+		if segment.ref == nil {
+			out.WriteString(segment.literal)
+			continue
+		}
+		ref := segment.ref
+		if value, ok := s.env[ref.name]; ok && value != "" {
+			out.WriteString(s.replacer(ref.name, value))
+			continue
+		}
+		switch {
+		case ref.op == placeholderDefault:
+			out.WriteString(s.render(ref.arg))
+		case ref.op == placeholderRequired && strings.TrimSpace(ref.arg) != "":
+			s.failures = append(s.failures, fmt.Sprintf(messages.ConfigRequiredEnvVarFmt, ref.name, strings.TrimSpace(ref.arg)))
+		default:
+			if secret, ok := SecretName(ref.name); ok {
+				s.missingSecrets[secret] = struct{}{}
+				continue
+			}
+			s.missing[ref.name] = struct{}{}
+		}
+	}
+	return out.String()
+}
+
 // BuiltinRepoRootEnvVar is the built-in placeholder for the repo root path.
 const BuiltinRepoRootEnvVar = "AL_REPO_ROOT"
 
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestSubstituteEnvVarsPlaceholderForms(t *testing.T) {
+	env := map[string]string{"HOST": "example.com", "EMPTY": "", "lower_name": "ok", "PORT": "8080"}
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "plain", input: "https://${HOST}", want: "https://example.com"},
+		{name: "lowercase name is client syntax", input: "${lower_name}", want: "${lower_name}"},
+		{name: "mixed-case name is client syntax", input: "${workspaceFolder}/server.js", want: "${workspaceFolder}/server.js"},
+		{name: "default unused", input: "${HOST:-fallback}", want: "example.com"},
+		{name: "default unset", input: "${MISSING:-fallback}", want: "fallback"},
+		{name: "default empty value", input: "${EMPTY:-fallback}", want: "fallback"},
+		{name: "empty default", input: "a${MISSING:-}b", want: "ab"},
+		{name: "nested default", input: "${MISSING:-${HOST}:${PORT}}", want: "example.com:8080"},
+		{name: "deep nesting", input: "${A:-${B:-${C:-deep}}}", want: "deep"},
+		{name: "required set", input: "${HOST:?set HOST in .env}", want: "example.com"},
+		{name: "escape", input: "$${HOST}", want: "${HOST}"},
+		{name: "escape with nested", input: "$${A:-${B}}", want: "${A:-${B}}"},
+		{name: "escape client syntax", input: "$${workspaceFolder}/bin", want: "${workspaceFolder}/bin"},
+		{name: "client syntax untouched", input: "${env:HOME}", want: "${env:HOME}"},
+		{name: "unterminated", input: "${HOST", want: "${HOST"},
+		{name: "bare dollar", input: "cost $5 ${HOST}", want: "cost $5 example.com"},
+		{name: "default in escape-free text", input: "x-${MISSING:-a b}-y", want: "x-a b-y"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := SubstituteEnvVars(tc.input, env)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("SubstituteEnvVars(%q) = %q, want %q", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSubstituteEnvVarsErrors(t *testing.T) {
+	env := map[string]string{"EMPTY": ""}
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "${EMPTY}", want: "missing environment variables: EMPTY"},
+		{input: "${B} ${A}", want: "missing environment variables: A, B"},
+		{input: "${TOKEN:?set TOKEN to your API key}", want: "TOKEN: set TOKEN to your API key"},
+		{input: "${TOKEN:?}", want: "missing environment variables: TOKEN"},
+		{input: "${X:-${Y}}", want: "missing environment variables: Y"},
+		{input: "${A} ${T:?needed}", want: "missing environment variables: A; T: needed"},
+	}
+	for _, tc := range tests {
+		if _, err := SubstituteEnvVars(tc.input, env); err == nil || err.Error() != tc.want {
+			t.Fatalf("SubstituteEnvVars(%q) error = %v, want %q", tc.input, err, tc.want)
+		}
+	}
+}
+
+func TestSubstituteEnvVarsWithReplacerInlinesDefaults(t *testing.T) {
+	env := map[string]string{"TOKEN": "abc"}
+	replacer := func(name string, _ string) string { return fmt.Sprintf("${env:%s}", name) }
+	got, err := SubstituteEnvVarsWith("${TOKEN:-x} ${MISSING:-${TOKEN}} ${MISSING:-plain} $${TOKEN}", env, replacer)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "${env:TOKEN} ${env:TOKEN} plain ${TOKEN}"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestRequiredEnvVarNames(t *testing.T) {
+	input := "${A} ${B:-${C}} ${D:?msg} $${E} ${env:F}"
+	if got := RequiredEnvVarNames(input); !reflect.DeepEqual(got, []string{"A", "D"}) {
+		t.Fatalf("RequiredEnvVarNames = %v", got)
+	}
+	if got := ExtractEnvVarNames(input); !reflect.DeepEqual(got, []string{"A", "B", "C", "D"}) {
+		t.Fatalf("ExtractEnvVarNames = %v", got)
+	}
+	server := MCPServer{URL: "https://x/${HOST:-localhost}", Headers: map[string]string{"Authorization": "Bearer ${TOKEN:?set TOKEN}"}}
+	if got := RequiredEnvVarsForMCPServer(server); !reflect.DeepEqual(got, []string{"TOKEN"}) {
+		t.Fatalf("RequiredEnvVarsForMCPServer = %v", got)
+	}
+}
+
+func TestIsValidEnvVarName(t *testing.T) {
+	for _, name := range []string{"TOKEN", "API_KEY", "A1"} {
+		if !IsValidEnvVarName(name) {
+			t.Fatalf("expected %q to be valid", name)
+		}
+	}
+	for _, name := range []string{"", "A-B", "A B", "A=B", "api_key", "workspaceFolder"} {
+		if IsValidEnvVarName(name) {
+			t.Fatalf("expected %q to be invalid", name)
+		}
+	}
+}
+
+func TestSubstituteSecretPlaceholders(t *testing.T) {
+	env := map[string]string{"secret:TOKEN": "s3cr3t", "TOKEN": "from-env"}
+	got, err := SubstituteEnvVarsWith("${secret:TOKEN} ${TOKEN} ${secret:MISSING:-none}", env, func(name string, value string) string {
+		return name + "=" + value
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "secret:TOKEN=s3cr3t TOKEN=from-env none"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	_, err = SubstituteEnvVars("${secret:B} ${secret:A} ${ENV}", map[string]string{})
+	want := "missing environment variables: ENV; missing secrets: A, B (run `al doctor` for provider details)"
+	if err == nil || err.Error() != want {
+		t.Fatalf("error = %v, want %q", err, want)
+	}
+
+	if got := ExtractEnvVarNames("${secret:A} ${B:-${secret:C}} ${secret:}"); !reflect.DeepEqual(got, []string{"secret:A", "B", "secret:C"}) {
+		t.Fatalf("ExtractEnvVarNames = %v", got)
+	}
+	if name, ok := SecretName("secret:A"); !ok || name != "A" {
+		t.Fatalf("SecretName = %q %v", name, ok)
+	}
+	if _, ok := SecretName("A"); ok {
+		t.Fatalf("expected A not to be a secret reference")
+	}
+}
+
+func TestParsePlaceholdersSegments(t *testing.T) {
+	segments := parsePlaceholders("a${B:-c}d")
+	if len(segments) != 3 || segments[0].literal != "a" || segments[2].literal != "d" {
+		t.Fatalf("unexpected segments: %+v", segments)
+	}
+	ref := segments[1].ref
+	if ref == nil || ref.name != "B" || ref.op != placeholderDefault || ref.arg != "c" {
+		t.Fatalf("unexpected placeholder: %+v", ref)
+	}
+	if got := parsePlaceholders("${:-x}"); len(got) != 1 || got[0].ref != nil || got[0].literal != "${:-x}" {
+		t.Fatalf("expected nameless placeholder to stay literal, got %+v", got)
+	}
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 )
@@ -77,3 +78,69 @@ func TestLoadEnvMissingKey(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+// writeFakeAge puts an `age` stand-in on PATH that "encrypts" by prefixing AGE: and
+// points AL_AGE_IDENTITY at a dummy identity file.
+func writeFakeAge(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+	dir := t.TempDir()
+	script := "#!/bin/sh\ncase \"$1\" in\n--encrypt) printf 'AGE:'; cat ;;\n--decrypt) tail -c +5 ;;\nesac\n"
+	if err := os.WriteFile(filepath.Join(dir, "age"), []byte(script), 0o755); err != nil {
+		t.Fatalf("write fake age: %v", err)
+	}
+	identity := filepath.Join(dir, "identity.txt")
+	if err := os.WriteFile(identity, []byte("AGE-SECRET-KEY-TEST\n"), 0o600); err != nil {
+		t.Fatalf("write identity: %v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv(AgeIdentityEnvVar, identity)
+}
+
+func TestLoadEnvPrefersEncrypted(t *testing.T) {
+	writeFakeAge(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+	if err := os.WriteFile(path, []byte("TOKEN=plain\n"), 0o600); err != nil {
+		t.Fatalf("write env: %v", err)
+	}
+	if err := os.WriteFile(path+".age", []byte("AGE:TOKEN=encrypted\n"), 0o600); err != nil {
+		t.Fatalf("write env.age: %v", err)
+	}
+
+	env, err := LoadEnv(path)
+	if err != nil {
+		t.Fatalf("LoadEnv error: %v", err)
+	}
+	if env["TOKEN"] != "encrypted" {
+		t.Fatalf("expected decrypted .env.age value, got %q", env["TOKEN"])
+	}
+	if EnvFilePath(path) != path+".age" {
+		t.Fatalf("expected EnvFilePath to prefer .env.age, got %s", EnvFilePath(path))
+	}
+
+	encoded, err := EncodeEnvFile(path+".age", []byte("A=1\n"))
+	if err != nil || string(encoded) != "AGE:A=1\n" {
+		t.Fatalf("EncodeEnvFile = %q, %v", encoded, err)
+	}
+	plain, err := EncodeEnvFile(path, []byte("A=1\n"))
+	if err != nil || string(plain) != "A=1\n" {
+		t.Fatalf("expected plaintext .env to be written as-is, got %q, %v", plain, err)
+	}
+}
+
+func TestLoadEnvDecryptError(t *testing.T) {
+	t.Setenv(AgeIdentityEnvVar, filepath.Join(t.TempDir(), "missing.txt"))
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+	if err := os.WriteFile(path+".age", []byte("ciphertext"), 0o600); err != nil {
+		t.Fatalf("write env.age: %v", err)
+	}
+
+	_, err := LoadEnv(path)
+	if err == nil || !strings.Contains(err.Error(), "failed to decrypt env file") || !strings.Contains(err.Error(), "age-keygen") {
+		t.Fatalf("expected decrypt error, got %v", err)
+	}
+}
@@ -16,6 +16,18 @@ var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
 
 // LoadInstructions reads .agent-layer/instructions/*.md in lexicographic order.
 func LoadInstructions(dir string) ([]InstructionFile, error) {
+	files, err := readInstructionDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf(messages.ConfigNoInstructionFilesFmt, dir)
+	}
+	return files, nil
+}
+
+// readInstructionDir reads *.md fragments from dir in lexicographic order; an empty result is not an error.
+func readInstructionDir(dir string) ([]InstructionFile, error) {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		return nil, fmt.Errorf(messages.ConfigMissingInstructionsDirFmt, dir, err)
@@ -32,10 +44,6 @@ func LoadInstructions(dir string) ([]InstructionFile, error) {
 		}
 	}
 
-	if len(names) == 0 {
-		return nil, fmt.Errorf(messages.ConfigNoInstructionFilesFmt, dir)
-	}
-
 	sort.Strings(names)
 
 	files := make([]InstructionFile, 0, len(names))
@@ -46,15 +54,109 @@ func LoadInstructions(dir string) ([]InstructionFile, error) {
 			return nil, fmt.Errorf(messages.ConfigFailedReadInstructionFmt, path, err)
 		}
 		data = bytes.TrimPrefix(data, utf8BOM)
-		files = append(files, InstructionFile{
-			Name:    name,
-			Content: string(data),
-		})
+		file, err := parseInstruction(name, string(data))
+		if err != nil {
+			return nil, fmt.Errorf(messages.ConfigInvalidInstructionFmt, path, err)
+		}
+		file.SourcePath = path
+		files = append(files, file)
 	}
 
 	return files, nil
 }
 
+// parseInstruction splits optional front matter (clients, applies_to, template) from an instruction fragment.
+// Content that does not start with a terminated "---" block has no front matter.
+func parseInstruction(name string, content string) (InstructionFile, error) {
+	lines := strings.Split(content, "\n")
+	end := -1
+	if strings.TrimSpace(lines[0]) == "---" {
+		for i := 1; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == "---" {
+				end = i
+				break
+			}
+		}
+	}
+	if end < 0 {
+		return InstructionFile{Name: name, Content: content, Line: 1}, nil
+	}
+
+	file := InstructionFile{Name: name}
+	if err := parseInstructionFrontMatter(lines[1:end], &file); err != nil {
+		return InstructionFile{}, err
+	}
+	body := strings.Join(lines[end+1:], "\n")
+	file.Content = strings.TrimLeft(body, "\r\n")
+	file.Line = end + 2 + strings.Count(body[:len(body)-len(file.Content)], "\n")
+	return file, nil
+}
+
+// parseInstructionFrontMatter decodes the YAML front matter lines into file. Unknown keys are
+// recorded in file.UnknownKeys, as for slash commands.
+func parseInstructionFrontMatter(lines []string, file *InstructionFile) error {
+	mapping, err := decodeFrontMatterMapping(lines)
+	if err != nil || mapping == nil {
+		return err
+	}
+	seen := make(map[string]bool, len(mapping.Content)/2)
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key, value := mapping.Content[i].Value, mapping.Content[i+1]
+		if seen[key] {
+			return fmt.Errorf(messages.ConfigFrontMatterDuplicateKeyFmt, key)
+		}
+		seen[key] = true
+		switch key {
+		case "clients":
+			if file.Clients, err = decodeFrontMatterList(key, value); err == nil {
+				err = validateClients(file.Clients)
+			}
+		case "applies_to":
+			file.AppliesTo, err = decodeFrontMatterList(key, value)
+		case "template":
+			file.Template, err = decodeFrontMatterBool(key, value)
+		default:
+			file.UnknownKeys = append(file.UnknownKeys, key)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for _, glob := range file.AppliesTo {
+		if !filepath.IsLocal(filepath.FromSlash(glob)) || strings.Contains(glob, "\\") {
+			return fmt.Errorf(messages.ConfigFrontMatterAppliesToInvalidFmt, glob)
+		}
+	}
+	return nil
+}
+
+// InstructionScopeDirs returns the distinct directories (slash-separated, relative to the repo root)
+// that contain a fragment's applies_to globs: the leading path segments before the first wildcard.
+// "" stands for the repo root; a fragment without applies_to has no scope directories.
+func InstructionScopeDirs(file InstructionFile) []string {
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, glob := range file.AppliesTo {
+		segments := strings.Split(strings.TrimSuffix(glob, "/"), "/")
+		end := len(segments) - 1
+		if strings.HasSuffix(glob, "/") {
+			end = len(segments)
+		}
+		for i, segment := range segments[:end] {
+			if strings.ContainsAny(segment, "*?[{") {
+				end = i
+				break
+			}
+		}
+		dir := strings.Join(segments[:end], "/")
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
 // WalkInstructionFiles is a helper to walk instruction files in a directory.
 func WalkInstructionFiles(dir string, fn func(path string, entry fs.DirEntry) error) error {
 	entries, err := os.ReadDir(dir)
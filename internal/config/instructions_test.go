@@ -36,6 +36,36 @@ func TestLoadInstructions(t *testing.T) {
 	}
 }
 
+func TestLoadInstructionsClientsFrontMatter(t *testing.T) {
+	dir := t.TempDir()
+	content := "---\nclients: [claude, codex]\n---\n\n# Sandbox\n"
+	if err := os.WriteFile(filepath.Join(dir, "00_sandbox.md"), []byte(content), 0o644); err != nil {
+		t.Fatalf("write instruction: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "10_rule.md"), []byte("---\n\nnot front matter"), 0o644); err != nil {
+		t.Fatalf("write instruction: %v", err)
+	}
+
+	files, err := LoadInstructions(dir)
+	if err != nil {
+		t.Fatalf("LoadInstructions error: %v", err)
+	}
+	if files[0].Content != "# Sandbox\n" || strings.Join(files[0].Clients, ",") != "claude,codex" {
+		t.Fatalf("unexpected instruction: %+v", files[0])
+	}
+	// An unterminated leading rule is content, not front matter.
+	if files[1].Content != "---\n\nnot front matter" || files[1].Clients != nil {
+		t.Fatalf("unexpected instruction: %+v", files[1])
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "20_bad.md"), []byte("---\nclients: [cursor]\n---\nbody"), 0o644); err != nil {
+		t.Fatalf("write instruction: %v", err)
+	}
+	if _, err := LoadInstructions(dir); err == nil || !strings.Contains(err.Error(), `unknown client "cursor"`) {
+		t.Fatalf("expected unknown client error, got %v", err)
+	}
+}
+
 func TestLoadInstructionsMissingDir(t *testing.T) {
 	_, err := LoadInstructions(filepath.Join(t.TempDir(), "missing"))
 	if err == nil {
@@ -95,3 +125,67 @@ func TestWalkInstructionFilesError(t *testing.T) {
 		t.Fatalf("expected error")
 	}
 }
+
+func TestParseInstructionAppliesTo(t *testing.T) {
+	file, err := parseInstruction("10_web.md", "---\napplies_to:\n  - \"web/**/*.ts\"\n  - web/\n---\nbody")
+	if err != nil {
+		t.Fatalf("parseInstruction error: %v", err)
+	}
+	if strings.Join(file.AppliesTo, ",") != "web/**/*.ts,web/" {
+		t.Fatalf("unexpected applies_to: %v", file.AppliesTo)
+	}
+	file, err = parseInstruction("10_web.md", "---\napplies_to: [\"web/{a,b}/*.ts\", 'docs/*.md']\n---\nbody")
+	if err != nil || len(file.AppliesTo) != 2 || file.AppliesTo[0] != "web/{a,b}/*.ts" || file.AppliesTo[1] != "docs/*.md" {
+		t.Fatalf("unexpected quoted brace glob: %q, %v", file.AppliesTo, err)
+	}
+	for _, glob := range []string{"/abs/**", "../up/**", `web\**`} {
+		_, err := parseInstruction("x.md", "---\napplies_to: ["+glob+"]\n---\nbody")
+		if err == nil || !strings.Contains(err.Error(), "relative to the repo root") {
+			t.Fatalf("expected invalid glob error for %q, got %v", glob, err)
+		}
+	}
+}
+
+func TestParseInstructionFrontMatterYAML(t *testing.T) {
+	file, err := parseInstruction("10_x.md", "---\r\nclients: [claude] # only claude\r\ntags:\r\n  - x\r\n---\r\n\r\nbody")
+	if err != nil {
+		t.Fatalf("parseInstruction error: %v", err)
+	}
+	if strings.Join(file.Clients, ",") != "claude" || strings.Join(file.UnknownKeys, ",") != "tags" {
+		t.Fatalf("unexpected front matter: %+v", file)
+	}
+	if file.Content != "body" || file.Line != 7 {
+		t.Fatalf("unexpected body %q at line %d", file.Content, file.Line)
+	}
+	file, err = parseInstruction("10_x.md", "---\nclients:\n  - 'codex'\n  - gemini\n---\nbody")
+	if err != nil || strings.Join(file.Clients, ",") != "codex,gemini" {
+		t.Fatalf("unexpected block list: %v %v", file.Clients, err)
+	}
+	if file, err := parseInstruction("10_x.md", "---\n---\nbody"); err != nil || file.Content != "body" || file.Line != 3 {
+		t.Fatalf("unexpected empty front matter: %+v %v", file, err)
+	}
+	if file, err := parseInstruction("10_x.md", "---\nno end"); err != nil || file.Content != "---\nno end" {
+		t.Fatalf("expected unterminated block to be content: %+v %v", file, err)
+	}
+	for content, want := range map[string]string{
+		"---\nclients: claude\n---\n":                     "must be a list",
+		"---\nclients: [claude]\nclients: [codex]\n---\n": "more than once",
+		"---\nclients: [\n---\n":                          "not valid YAML",
+		"---\n- claude\n---\n":                            "must be a mapping",
+	} {
+		if _, err := parseInstruction("x.md", content); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q error for %q, got %v", want, content, err)
+		}
+	}
+}
+
+func TestInstructionScopeDirs(t *testing.T) {
+	file := InstructionFile{AppliesTo: []string{"web/**/*.ts", "web/app/index.ts", "docs/", "**/*.md", "*.go", "web/**/*.tsx"}}
+	got := InstructionScopeDirs(file)
+	if strings.Join(got, "|") != "web|web/app|docs|" {
+		t.Fatalf("unexpected scope dirs: %q", got)
+	}
+	if InstructionScopeDirs(InstructionFile{}) != nil {
+		t.Fatalf("expected no scope dirs without applies_to")
+	}
+}
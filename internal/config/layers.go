@@ -0,0 +1,347 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// Config layer names, listed in merge order (later layers win).
+const (
+	LayerUser    = "user"
+	LayerProject = "project"
+	LayerLocal   = "local"
+)
+
+// ConfigLayer identifies one file in the config merge stack.
+type ConfigLayer struct {
+	Name     string
+	Path     string
+	Required bool
+}
+
+// LayerSource holds the raw TOML content of a single config layer.
+type LayerSource struct {
+	Layer ConfigLayer
+	Data  []byte
+}
+
+// Origin records the config layer that supplied an effective value.
+type Origin struct {
+	Layer string
+	Path  string
+}
+
+// ConfigEntry is one effective leaf value in the merged config.
+// Key uses dotted notation; MCP servers are addressed by id (mcp.servers.<id>.<field>).
+type ConfigEntry struct {
+	Key    string
+	Value  any
+	Origin Origin
+}
+
+// LayeredConfig is the merged, validated config and the origin of every effective value.
+type LayeredConfig struct {
+	Config  *Config
+	Profile string
+	Layers  []ConfigLayer
+	Entries []ConfigEntry
+	merged  map[string]any
+}
+
+// ConfigLayers returns the merge stack for paths: user-global, repo, then repo-local overrides.
+// Layers without a path (for example, no home directory) are omitted.
+func ConfigLayers(paths Paths) []ConfigLayer {
+	var layers []ConfigLayer
+	if paths.UserConfigPath != "" {
+		layers = append(layers, ConfigLayer{Name: LayerUser, Path: paths.UserConfigPath})
+	}
+	layers = append(layers, ConfigLayer{Name: LayerProject, Path: paths.ConfigPath, Required: true})
+	if paths.LocalConfigPath != "" {
+		layers = append(layers, ConfigLayer{Name: LayerLocal, Path: paths.LocalConfigPath})
+	}
+	return layers
+}
+
+// LoadLayeredConfig reads every config layer for paths, merges them, applies the named profile
+// (empty for none), and validates the result.
+// Optional layers that do not exist are skipped; a missing repo config is an error.
+func LoadLayeredConfig(paths Paths, profile string) (*LayeredConfig, error) {
+	sources, err := ReadConfigLayers(ConfigLayers(paths))
+	if err != nil {
+		return nil, err
+	}
+	return MergeConfigLayers(sources, paths.ConfigPath, profile)
+}
+
+// ReadConfigLayers reads the raw content of each layer, skipping optional layers that do not exist.
+func ReadConfigLayers(layers []ConfigLayer) ([]LayerSource, error) {
+	sources := make([]LayerSource, 0, len(layers))
+	for _, layer := range layers {
+		data, err := os.ReadFile(layer.Path)
+		if err != nil {
+			if !layer.Required && errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf(messages.ConfigMissingFileFmt, layer.Path, err)
+		}
+		sources = append(sources, LayerSource{Layer: layer, Data: data})
+	}
+	return sources, nil
+}
+
+// MergeConfigLayers merges layer sources in order and validates the merged config.
+// Tables merge key by key, scalars and arrays from later layers replace earlier values,
+// and [[mcp.servers]] entries are merged by id (new ids are appended in layer order).
+// A non-empty profile is overlaid after all layers.
+// source names the effective config in problems that cannot be tied to a file position.
+// Validation failures are returned together as a *ValidationError.
+func MergeConfigLayers(sources []LayerSource, source string, profile string) (*LayeredConfig, error) {
+	merged := make(map[string]any)
+	origins := make(map[string]Origin)
+	layers := make([]ConfigLayer, 0, len(sources))
+	indexed := make([]indexedLayer, 0, len(sources))
+	var problems []Problem
+	fatal := false
+	for _, src := range sources {
+		path := src.Layer.Path
+		var raw map[string]any
+		if err := toml.Unmarshal(src.Data, &raw); err != nil {
+			layerProblems, _ := decodeProblems(path, err)
+			return nil, &ValidationError{Problems: append(problems, layerProblems...)}
+		}
+		positions := indexKeyPositions(src.Data)
+		indexed = append(indexed, indexedLayer{Path: path, Positions: positions})
+
+		if err := decodeLayerStrict(src.Data); err != nil {
+			layerProblems, isFatal := decodeProblems(path, err)
+			problems = append(problems, layerProblems...)
+			fatal = fatal || isFatal
+		}
+		problems = append(problems, duplicateServerProblems(path, raw, positions)...)
+
+		origin := Origin{Layer: src.Layer.Name, Path: path}
+		if err := mergeTable(merged, raw, "", origin, origins); err != nil {
+			return nil, fmt.Errorf(messages.ConfigInvalidConfigFmt, path, err)
+		}
+		layers = append(layers, src.Layer)
+	}
+	version, versioned := merged["config_version"].(int64)
+	if versioned {
+		if err := checkConfigVersion(int(version), source); err != nil {
+			return nil, err
+		}
+	}
+	hint := ""
+	if !versioned {
+		hint = messages.ConfigUnversionedHint
+	}
+	if fatal {
+		return nil, &ValidationError{Problems: problems, Hint: hint}
+	}
+
+	cfg, err := decodeMergedConfig(merged, source)
+	if err != nil {
+		return nil, err
+	}
+	problems = append(problems, resolveProblems(cfg.validationProblems(), indexed, source)...)
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems, Hint: hint}
+	}
+	if profile != "" {
+		if err := applyProfile(merged, cfg, profile, origins); err != nil {
+			return nil, err
+		}
+		cfg, err = decodeMergedConfig(merged, source)
+		if err != nil {
+			return nil, err
+		}
+		if profileProblems := cfg.validationProblems(); len(profileProblems) > 0 {
+			return nil, &ValidationError{Problems: resolveProblems(profileProblems, indexed, source)}
+		}
+	}
+
+	var entries []ConfigEntry
+	flattenTable(merged, "", func(key string, value any) {
+		entries = append(entries, ConfigEntry{Key: key, Value: value, Origin: origins[key]})
+	})
+
+	return &LayeredConfig{
+		Config:  cfg,
+		Profile: profile,
+		Layers:  layers,
+		Entries: entries,
+		merged:  merged,
+	}, nil
+}
+
+// decodeLayerStrict decodes one layer into Config, rejecting keys that Config does not define.
+func decodeLayerStrict(data []byte) error {
+	decoder := toml.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var cfg Config
+	return decoder.Decode(&cfg)
+}
+
+// MarshalTOML renders the merged config (only keys set by some layer) as TOML.
+func (l *LayeredConfig) MarshalTOML() ([]byte, error) {
+	return toml.Marshal(l.merged)
+}
+
+// Entry returns the effective entry for a dotted key, if set.
+func (l *LayeredConfig) Entry(key string) (ConfigEntry, bool) {
+	for _, entry := range l.Entries {
+		if entry.Key == key {
+			return entry, true
+		}
+	}
+	return ConfigEntry{}, false
+}
+
+// decodeMergedConfig converts the merged raw tree into a typed Config.
+func decodeMergedConfig(merged map[string]any, source string) (*Config, error) {
+	data, err := toml.Marshal(merged)
+	if err != nil {
+		return nil, fmt.Errorf(messages.ConfigInvalidConfigFmt, source, err)
+	}
+	var cfg Config
+	if err := toml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf(messages.ConfigInvalidConfigFmt, source, err)
+	}
+	return &cfg, nil
+}
+
+// mergeTable merges src into dst, recording the origin of every leaf written.
+func mergeTable(dst map[string]any, src map[string]any, prefix string, origin Origin, origins map[string]Origin) error {
+	for _, key := range sortedKeys(src) {
+		value := src[key]
+		path := joinKey(prefix, key)
+		if prefix == "mcp" && key == "servers" {
+			if err := mergeServers(dst, value, path, origin, origins); err != nil {
+				return err
+			}
+			continue
+		}
+		if table, ok := value.(map[string]any); ok {
+			existing, ok := dst[key].(map[string]any)
+			if !ok {
+				forgetOrigins(origins, path)
+				existing = make(map[string]any)
+				dst[key] = existing
+			}
+			if err := mergeTable(existing, table, path, origin, origins); err != nil {
+				return err
+			}
+			continue
+		}
+		forgetOrigins(origins, path)
+		dst[key] = value
+		origins[path] = origin
+	}
+	return nil
+}
+
+// mergeServers merges [[mcp.servers]] entries by id.
+func mergeServers(dst map[string]any, value any, path string, origin Origin, origins map[string]Origin) error {
+	list, ok := value.([]any)
+	if !ok {
+		return fmt.Errorf(messages.ConfigLayerServersNotArrayFmt, path)
+	}
+	existing, _ := dst["servers"].([]any)
+	for _, item := range list {
+		server, ok := item.(map[string]any)
+		if !ok {
+			return fmt.Errorf(messages.ConfigLayerServersNotArrayFmt, path)
+		}
+		index := findServer(existing, server["id"])
+		if index < 0 {
+			existing = append(existing, make(map[string]any))
+			index = len(existing) - 1
+		}
+		target := existing[index].(map[string]any)
+		if err := mergeTable(target, server, serverKey(path, target, server, index), origin, origins); err != nil {
+			return err
+		}
+	}
+	dst["servers"] = existing
+	return nil
+}
+
+// findServer returns the index of the server with the given id, or -1.
+func findServer(servers []any, id any) int {
+	name, ok := id.(string)
+	if !ok || name == "" {
+		return -1
+	}
+	for i, item := range servers {
+		if server, ok := item.(map[string]any); ok && server["id"] == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// serverKey returns the key prefix for a server entry, preferring its id over its position.
+func serverKey(path string, target map[string]any, incoming map[string]any, index int) string {
+	for _, candidate := range []map[string]any{target, incoming} {
+		if id, ok := candidate["id"].(string); ok && id != "" {
+			return joinKey(path, id)
+		}
+	}
+	return fmt.Sprintf("%s.#%d", path, index)
+}
+
+// flattenTable walks a raw tree in key order (servers in declaration order) and reports every leaf.
+func flattenTable(table map[string]any, prefix string, visit func(key string, value any)) {
+	for _, key := range sortedKeys(table) {
+		value := table[key]
+		path := joinKey(prefix, key)
+		if prefix == "mcp" && key == "servers" {
+			if list, ok := value.([]any); ok {
+				for i, item := range list {
+					if server, ok := item.(map[string]any); ok {
+						flattenTable(server, serverKey(path, server, nil, i), visit)
+					}
+				}
+				continue
+			}
+		}
+		if nested, ok := value.(map[string]any); ok {
+			flattenTable(nested, path, visit)
+			continue
+		}
+		visit(path, value)
+	}
+}
+
+// forgetOrigins drops origins recorded at or below path (used when a value changes shape).
+func forgetOrigins(origins map[string]Origin, path string) {
+	for key := range origins {
+		if key == path || strings.HasPrefix(key, path+".") {
+			delete(origins, key)
+		}
+	}
+}
+
+func joinKey(prefix string, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,293 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const layerBaseConfig = `
+[approvals]
+mode = "all"
+
+[agents.gemini]
+enabled = true
+
+[agents.claude]
+enabled = true
+model = "sonnet"
+
+[agents.codex]
+enabled = true
+
+[agents.vscode]
+enabled = true
+
+[agents.antigravity]
+enabled = false
+
+[[mcp.servers]]
+id = "github"
+enabled = true
+transport = "http"
+url = "https://example.com/mcp"
+headers = { Authorization = "Bearer ${GITHUB_TOKEN}" }
+`
+
+func writeLayer(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func layerPaths(t *testing.T) Paths {
+	t.Helper()
+	paths := DefaultPaths(t.TempDir())
+	paths.UserConfigPath = filepath.Join(t.TempDir(), "agent-layer", "config.toml")
+	return paths
+}
+
+func TestLoadLayeredConfigProjectOnly(t *testing.T) {
+	paths := layerPaths(t)
+	writeLayer(t, paths.ConfigPath, layerBaseConfig)
+
+	layered, err := LoadLayeredConfig(paths, "")
+	if err != nil {
+		t.Fatalf("LoadLayeredConfig error: %v", err)
+	}
+	if len(layered.Layers) != 1 || layered.Layers[0].Name != LayerProject {
+		t.Fatalf("unexpected layers: %+v", layered.Layers)
+	}
+	if layered.Config.Agents.Claude.Model != "sonnet" {
+		t.Fatalf("unexpected claude model: %q", layered.Config.Agents.Claude.Model)
+	}
+	entry, ok := layered.Entry("mcp.servers.github.url")
+	if !ok || entry.Origin.Layer != LayerProject || entry.Origin.Path != paths.ConfigPath {
+		t.Fatalf("unexpected entry: %+v (found=%v)", entry, ok)
+	}
+}
+
+func TestLoadLayeredConfigMergesLayers(t *testing.T) {
+	paths := layerPaths(t)
+	writeLayer(t, paths.UserConfigPath, `
+[agents.claude]
+model = "opus"
+
+[agents.codex]
+reasoning_effort = "high"
+
+[[mcp.servers]]
+id = "personal"
+enabled = true
+transport = "stdio"
+command = "personal-mcp"
+`)
+	writeLayer(t, paths.ConfigPath, layerBaseConfig)
+	writeLayer(t, paths.LocalConfigPath, `
+[approvals]
+mode = "none"
+
+[[mcp.servers]]
+id = "github"
+enabled = false
+`)
+
+	layered, err := LoadLayeredConfig(paths, "")
+	if err != nil {
+		t.Fatalf("LoadLayeredConfig error: %v", err)
+	}
+	cfg := layered.Config
+	if cfg.Approvals.Mode != "none" {
+		t.Fatalf("expected local approvals override, got %q", cfg.Approvals.Mode)
+	}
+	if cfg.Agents.Claude.Model != "sonnet" {
+		t.Fatalf("expected repo model to override user model, got %q", cfg.Agents.Claude.Model)
+	}
+	if cfg.Agents.Codex.ReasoningEffort != "high" {
+		t.Fatalf("expected user reasoning effort to be kept, got %q", cfg.Agents.Codex.ReasoningEffort)
+	}
+	if len(cfg.MCP.Servers) != 2 {
+		t.Fatalf("expected 2 servers, got %d", len(cfg.MCP.Servers))
+	}
+	if cfg.MCP.Servers[0].ID != "personal" || cfg.MCP.Servers[1].ID != "github" {
+		t.Fatalf("unexpected server order: %s, %s", cfg.MCP.Servers[0].ID, cfg.MCP.Servers[1].ID)
+	}
+	github := cfg.MCP.Servers[1]
+	if github.Enabled == nil || *github.Enabled {
+		t.Fatalf("expected github disabled by local layer")
+	}
+	if github.URL != "https://example.com/mcp" || github.Headers["Authorization"] == "" {
+		t.Fatalf("expected unset fields to be inherited: %+v", github)
+	}
+
+	checks := map[string]string{
+		"approvals.mode":                           LayerLocal,
+		"agents.claude.model":                      LayerProject,
+		"agents.codex.reasoning_effort":            LayerUser,
+		"mcp.servers.github.enabled":               LayerLocal,
+		"mcp.servers.github.url":                   LayerProject,
+		"mcp.servers.personal.command":             LayerUser,
+		"mcp.servers.github.headers.Authorization": LayerProject,
+	}
+	for key, layer := range checks {
+		entry, ok := layered.Entry(key)
+		if !ok {
+			t.Fatalf("missing entry %s", key)
+		}
+		if entry.Origin.Layer != layer {
+			t.Fatalf("expected %s from %s, got %s", key, layer, entry.Origin.Layer)
+		}
+	}
+}
+
+func TestLoadLayeredConfigArraysReplace(t *testing.T) {
+	paths := layerPaths(t)
+	writeLayer(t, paths.ConfigPath, layerBaseConfig+`
+[[mcp.servers]]
+id = "local"
+enabled = true
+transport = "stdio"
+command = "tool"
+args = ["a", "b"]
+clients = ["claude", "codex"]
+`)
+	writeLayer(t, paths.LocalConfigPath, `
+[[mcp.servers]]
+id = "local"
+args = ["c"]
+`)
+
+	layered, err := LoadLayeredConfig(paths, "")
+	if err != nil {
+		t.Fatalf("LoadLayeredConfig error: %v", err)
+	}
+	server := layered.Config.MCP.Servers[1]
+	if len(server.Args) != 1 || server.Args[0] != "c" {
+		t.Fatalf("expected args replaced, got %v", server.Args)
+	}
+	if len(server.Clients) != 2 {
+		t.Fatalf("expected clients inherited, got %v", server.Clients)
+	}
+}
+
+func TestLoadLayeredConfigValidatesMergedResult(t *testing.T) {
+	paths := layerPaths(t)
+	writeLayer(t, paths.ConfigPath, layerBaseConfig)
+	writeLayer(t, paths.LocalConfigPath, `
+[approvals]
+mode = "sometimes"
+`)
+
+	_, err := LoadLayeredConfig(paths, "")
+	if err == nil || !strings.Contains(err.Error(), "approvals.mode") {
+		t.Fatalf("expected approvals validation error, got %v", err)
+	}
+}
+
+func TestLoadLayeredConfigPartialProjectLayer(t *testing.T) {
+	paths := layerPaths(t)
+	writeLayer(t, paths.UserConfigPath, `
+[agents.gemini]
+enabled = false
+
+[agents.antigravity]
+enabled = false
+`)
+	writeLayer(t, paths.ConfigPath, `
+[approvals]
+mode = "all"
+
+[agents.claude]
+enabled = true
+
+[agents.codex]
+enabled = true
+
+[agents.vscode]
+enabled = true
+`)
+
+	layered, err := LoadLayeredConfig(paths, "")
+	if err != nil {
+		t.Fatalf("LoadLayeredConfig error: %v", err)
+	}
+	if layered.Config.Agents.Gemini.Enabled == nil || *layered.Config.Agents.Gemini.Enabled {
+		t.Fatalf("expected gemini disabled from user layer")
+	}
+}
+
+func TestLoadLayeredConfigErrors(t *testing.T) {
+	t.Run("missing project config", func(t *testing.T) {
+		paths := layerPaths(t)
+		_, err := LoadLayeredConfig(paths, "")
+		if err == nil || !strings.Contains(err.Error(), "missing config file") {
+			t.Fatalf("expected missing config error, got %v", err)
+		}
+	})
+
+	t.Run("invalid toml in local layer", func(t *testing.T) {
+		paths := layerPaths(t)
+		writeLayer(t, paths.ConfigPath, layerBaseConfig)
+		writeLayer(t, paths.LocalConfigPath, "[approvals\n")
+		_, err := LoadLayeredConfig(paths, "")
+		if err == nil || !strings.Contains(err.Error(), paths.LocalConfigPath) {
+			t.Fatalf("expected local layer parse error, got %v", err)
+		}
+	})
+
+	t.Run("servers not an array of tables", func(t *testing.T) {
+		paths := layerPaths(t)
+		writeLayer(t, paths.ConfigPath, layerBaseConfig)
+		writeLayer(t, paths.LocalConfigPath, "[mcp]\nservers = \"nope\"\n")
+		_, err := LoadLayeredConfig(paths, "")
+		if err == nil || !strings.Contains(err.Error(), "array of tables") {
+			t.Fatalf("expected servers shape error, got %v", err)
+		}
+	})
+
+	t.Run("unreadable user layer", func(t *testing.T) {
+		paths := layerPaths(t)
+		writeLayer(t, paths.ConfigPath, layerBaseConfig)
+		if err := os.MkdirAll(paths.UserConfigPath, 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		_, err := LoadLayeredConfig(paths, "")
+		if err == nil {
+			t.Fatalf("expected error when user config is a directory")
+		}
+	})
+}
+
+func TestConfigLayersSkipsEmptyPaths(t *testing.T) {
+	layers := ConfigLayers(Paths{ConfigPath: "config.toml"})
+	if len(layers) != 1 || layers[0].Name != LayerProject || !layers[0].Required {
+		t.Fatalf("unexpected layers: %+v", layers)
+	}
+}
+
+func TestLayeredConfigMarshalTOML(t *testing.T) {
+	paths := layerPaths(t)
+	writeLayer(t, paths.ConfigPath, layerBaseConfig)
+	writeLayer(t, paths.LocalConfigPath, "[agents.claude]\nmodel = \"opus\"\n")
+
+	layered, err := LoadLayeredConfig(paths, "")
+	if err != nil {
+		t.Fatalf("LoadLayeredConfig error: %v", err)
+	}
+	data, err := layered.MarshalTOML()
+	if err != nil {
+		t.Fatalf("MarshalTOML error: %v", err)
+	}
+	parsed, err := ParseConfig(data, "merged")
+	if err != nil {
+		t.Fatalf("merged TOML does not round-trip: %v\n%s", err, data)
+	}
+	if parsed.Agents.Claude.Model != "opus" {
+		t.Fatalf("unexpected model after round-trip: %q", parsed.Agents.Claude.Model)
+	}
+}
@@ -3,22 +3,43 @@ package config
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/pelletier/go-toml/v2"
 
 	"github.com/conn-castle/agent-layer/internal/envfile"
 	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/secrets"
 	"github.com/conn-castle/agent-layer/internal/templates"
 )
 
+// LoadOptions controls how the project config is resolved.
+type LoadOptions struct {
+	// Profile names a [profiles.<name>] overlay to apply; empty applies none.
+	Profile string
+	// SkipSecrets leaves Secrets empty instead of running secret providers, for commands that
+	// never use secret values.
+	SkipSecrets bool
+	// ChildDirs, when non-nil, lists the child layer directories to load instead of searching the
+	// repo, for callers that reload a project whose children were found by an earlier load.
+	ChildDirs []string
+}
+
 // LoadProjectConfig reads and validates the full Agent Layer config from disk.
+// config.toml is merged with the user-global config and the repo-local config.local.toml.
 func LoadProjectConfig(root string) (*ProjectConfig, error) {
+	return LoadProjectConfigWithOptions(root, LoadOptions{})
+}
+
+// LoadProjectConfigWithOptions is like LoadProjectConfig but applies opts (for example, a profile).
+func LoadProjectConfigWithOptions(root string, opts LoadOptions) (*ProjectConfig, error) {
 	paths := DefaultPaths(root)
 
-	cfg, err := LoadConfig(paths.ConfigPath)
+	layered, err := LoadLayeredConfig(paths, opts.Profile)
 	if err != nil {
 		return nil, err
 	}
+	cfg := layered.Config
 
 	env, err := LoadEnv(paths.EnvPath)
 	if err != nil {
@@ -41,17 +62,43 @@ func LoadProjectConfig(root string) (*ProjectConfig, error) {
 		return nil, err
 	}
 
+	var children []ChildLayer
+	if opts.ChildDirs != nil {
+		children, err = loadChildLayers(root, opts.ChildDirs)
+	} else {
+		children, err = LoadChildLayers(root, cfg.Children.Exclude)
+	}
+	if err != nil {
+		return nil, err
+	}
+	slashCommands, err = mergeChildSlashCommands(slashCommands, children)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve secrets last: exec providers may prompt (for example to unlock a GPG key).
+	var resolvedSecrets map[string]secrets.Resolved
+	if !opts.SkipSecrets {
+		resolvedSecrets, err = ResolveSecrets(cfg, root)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &ProjectConfig{
 		Config:        *cfg,
 		Env:           env,
+		Secrets:       resolvedSecrets,
 		Instructions:  instructions,
 		SlashCommands: slashCommands,
 		CommandsAllow: commandsAllow,
 		Root:          root,
+		Profile:       opts.Profile,
+		Children:      children,
 	}, nil
 }
 
-// LoadConfig reads .agent-layer/config.toml and validates it.
+// LoadConfig reads a single config file (without layering) and validates it.
 func LoadConfig(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -60,6 +107,20 @@ func LoadConfig(path string) (*Config, error) {
 	return ParseConfig(data, path)
 }
 
+// LoadConfigLayer reads a single config layer without validating it.
+// Layers may be partial; only the merged config must pass validation.
+func LoadConfigLayer(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf(messages.ConfigMissingFileFmt, path, err)
+	}
+	var cfg Config
+	if err := toml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf(messages.ConfigInvalidConfigFmt, path, err)
+	}
+	return &cfg, nil
+}
+
 // LoadTemplateConfig returns the embedded default config template as a validated Config.
 func LoadTemplateConfig() (*Config, error) {
 	data, err := templates.Read("config.toml")
@@ -70,11 +131,17 @@ func LoadTemplateConfig() (*Config, error) {
 }
 
 // LoadEnv reads .agent-layer/.env into a key-value map.
+// When an encrypted path+".age" exists it is decrypted in memory and used instead.
 func LoadEnv(path string) (map[string]string, error) {
+	path = EnvFilePath(path)
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf(messages.ConfigMissingEnvFileFmt, path, err)
 	}
+	data, err = DecodeEnvFile(path, data)
+	if err != nil {
+		return nil, err
+	}
 
 	env, err := envfile.Parse(string(data))
 	if err != nil {
@@ -83,15 +150,55 @@ func LoadEnv(path string) (map[string]string, error) {
 	return env, nil
 }
 
-// ParseConfig parses and validates config TOML data from a source identifier.
-// data is the TOML content; source is used in error messages.
-func ParseConfig(data []byte, source string) (*Config, error) {
-	var cfg Config
-	if err := toml.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf(messages.ConfigInvalidConfigFmt, source, err)
+// EnvFilePath returns the encrypted envPath+".age" when it exists, otherwise envPath.
+func EnvFilePath(envPath string) string {
+	encrypted := envPath + envfile.AgeSuffix
+	if _, err := os.Stat(encrypted); err == nil {
+		return encrypted
 	}
-	if err := cfg.Validate(source); err != nil {
+	return envPath
+}
+
+// IsEncryptedEnvPath reports whether path is an age-encrypted env file.
+func IsEncryptedEnvPath(path string) bool {
+	return strings.HasSuffix(path, envfile.AgeSuffix)
+}
+
+// DecodeEnvFile returns the plaintext of env file data read from path, decrypting .age files
+// with the identity from AgeIdentityPath.
+func DecodeEnvFile(path string, data []byte) ([]byte, error) {
+	if !IsEncryptedEnvPath(path) {
+		return data, nil
+	}
+	plaintext, err := envfile.Decrypt(data, AgeIdentityPath())
+	if err != nil {
+		return nil, fmt.Errorf(messages.ConfigDecryptEnvFileFmt, path, err)
+	}
+	return plaintext, nil
+}
+
+// EncodeEnvFile returns the bytes to write to path for plaintext env content, encrypting .age files.
+func EncodeEnvFile(path string, plaintext []byte) ([]byte, error) {
+	if !IsEncryptedEnvPath(path) {
+		return plaintext, nil
+	}
+	ciphertext, err := envfile.Encrypt(plaintext, AgeIdentityPath())
+	if err != nil {
+		return nil, fmt.Errorf(messages.ConfigEncryptEnvFileFmt, path, err)
+	}
+	return ciphertext, nil
+}
+
+// ParseConfig parses and validates a single config file from a source identifier.
+// data is the TOML content; source is used in error messages. Validation failures
+// (including unknown keys and duplicate MCP server ids) are returned as a *ValidationError.
+func ParseConfig(data []byte, source string) (*Config, error) {
+	layered, err := MergeConfigLayers([]LayerSource{{
+		Layer: ConfigLayer{Name: LayerProject, Path: source, Required: true},
+		Data:  data,
+	}}, source, "")
+	if err != nil {
 		return nil, err
 	}
-	return &cfg, nil
+	return layered.Config, nil
 }
@@ -80,6 +80,77 @@ Do it.`
 	}
 }
 
+func TestLoadProjectConfigSkipSecrets(t *testing.T) {
+	root := t.TempDir()
+	paths := DefaultPaths(root)
+	for _, dir := range []string{paths.InstructionsDir, paths.SlashCommandsDir} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+	}
+	config := `
+[approvals]
+mode = "all"
+
+[agents.gemini]
+enabled = true
+
+[agents.claude]
+enabled = true
+
+[agents.codex]
+enabled = true
+
+[agents.vscode]
+enabled = true
+
+[agents.antigravity]
+enabled = false
+
+[[secrets.providers]]
+type = "exec"
+command = "sh"
+args = ["-c", "touch ran; echo value"]
+
+[[mcp.servers]]
+id = "api"
+enabled = true
+transport = "http"
+url = "https://example.com/mcp"
+headers = { Authorization = "Bearer ${secret:API_TOKEN}" }
+`
+	files := map[string]string{
+		paths.ConfigPath:    config,
+		paths.EnvPath:       "",
+		paths.CommandsAllow: "",
+		filepath.Join(paths.InstructionsDir, "00_base.md"): "base",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+	}
+
+	project, err := LoadProjectConfigWithOptions(root, LoadOptions{SkipSecrets: true})
+	if err != nil {
+		t.Fatalf("LoadProjectConfigWithOptions error: %v", err)
+	}
+	if project.Secrets != nil {
+		t.Fatalf("expected no secrets, got %v", project.Secrets)
+	}
+	if _, err := os.Stat(filepath.Join(root, "ran")); !os.IsNotExist(err) {
+		t.Fatalf("expected the exec provider not to run, stat: %v", err)
+	}
+
+	project, err = LoadProjectConfig(root)
+	if err != nil {
+		t.Fatalf("LoadProjectConfig error: %v", err)
+	}
+	if got := project.Secrets["API_TOKEN"]; got.Err != nil || got.Value != "value" {
+		t.Fatalf("unexpected API_TOKEN: %+v", got)
+	}
+}
+
 func TestLoadProjectConfigMissingConfig(t *testing.T) {
 	_, err := LoadProjectConfig(t.TempDir())
 	if err == nil {
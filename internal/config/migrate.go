@@ -0,0 +1,236 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/conn-castle/agent-layer/internal/fsutil"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// CurrentConfigVersion is the config_version this release reads and writes.
+// Bump it together with a new entry in migrations whenever the on-disk layout changes.
+const CurrentConfigVersion = 1
+
+// migration upgrades a repo to Version from Version-1.
+// Apply edits files through the workspace as text so comments and formatting survive.
+type migration struct {
+	Version int
+	Apply   func(w *migrationWorkspace) error
+}
+
+// migrations is the ordered registry of upgrade steps.
+var migrations = []migration{
+	{Version: 1, Apply: migrateToV1},
+}
+
+// MigrationChange describes one edit made by al migrate.
+type MigrationChange struct {
+	// Path is relative to the repo root, using forward slashes.
+	Path        string
+	Description string
+}
+
+// MigrationReport summarizes a migration run.
+type MigrationReport struct {
+	From    int
+	To      int
+	Changes []MigrationChange
+}
+
+// Migrate upgrades config.toml, commands.allow, and the memory files under root to CurrentConfigVersion.
+// Files are rewritten only after every step succeeds; dryRun reports the changes without writing.
+func Migrate(root string, dryRun bool) (*MigrationReport, error) {
+	paths := DefaultPaths(root)
+	data, err := os.ReadFile(paths.ConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf(messages.ConfigMissingFileFmt, paths.ConfigPath, err)
+	}
+	version, err := readConfigVersion(data)
+	if err != nil {
+		return nil, fmt.Errorf(messages.ConfigInvalidConfigFmt, paths.ConfigPath, err)
+	}
+	if version > CurrentConfigVersion {
+		return nil, fmt.Errorf(messages.ConfigVersionTooNewFmt, paths.ConfigPath, version, CurrentConfigVersion)
+	}
+
+	report := &MigrationReport{From: version, To: CurrentConfigVersion}
+	w := newMigrationWorkspace(root)
+	steps := append([]migration(nil), migrations...)
+	sort.Slice(steps, func(i, j int) bool { return steps[i].Version < steps[j].Version })
+	for _, step := range steps {
+		if step.Version <= version || step.Version > CurrentConfigVersion {
+			continue
+		}
+		if err := step.Apply(w); err != nil {
+			return nil, fmt.Errorf(messages.ConfigMigrationFailedFmt, step.Version, err)
+		}
+	}
+	if version < CurrentConfigVersion {
+		content, _, err := w.read(configRelPath)
+		if err != nil {
+			return nil, err
+		}
+		w.write(configRelPath, setConfigVersion(content, CurrentConfigVersion),
+			fmt.Sprintf(messages.ConfigMigrationSetVersionFmt, CurrentConfigVersion))
+	}
+	report.Changes = w.changes
+	if dryRun {
+		return report, nil
+	}
+	if err := w.commit(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// readConfigVersion returns config_version from raw config.toml content (0 when unset).
+func readConfigVersion(data []byte) (int, error) {
+	var header struct {
+		ConfigVersion int `toml:"config_version"`
+	}
+	if err := toml.Unmarshal(data, &header); err != nil {
+		return 0, err
+	}
+	return header.ConfigVersion, nil
+}
+
+// checkConfigVersion rejects configs written for a different config_version.
+// Unversioned configs (0) are accepted; their validation problems carry a hint to run al migrate instead.
+func checkConfigVersion(version int, source string) error {
+	switch {
+	case version > CurrentConfigVersion:
+		return fmt.Errorf(messages.ConfigVersionTooNewFmt, source, version, CurrentConfigVersion)
+	case version < 0:
+		return fmt.Errorf(messages.ConfigVersionInvalidFmt, source, version)
+	case version > 0 && version < CurrentConfigVersion:
+		return fmt.Errorf(messages.ConfigVersionOutdatedFmt, source, version, CurrentConfigVersion)
+	}
+	return nil
+}
+
+var writeMigratedFile = fsutil.WriteFileAtomic
+
+// migrationFile is the buffered state of one file touched by a migration run.
+type migrationFile struct {
+	content string
+	exists  bool
+	perm    os.FileMode
+	changed bool
+	removed bool
+}
+
+// migrationWorkspace buffers file edits so a migration run is all-or-nothing.
+// Paths are relative to the repo root with forward slashes.
+type migrationWorkspace struct {
+	root    string
+	files   map[string]*migrationFile
+	order   []string
+	changes []MigrationChange
+}
+
+func newMigrationWorkspace(root string) *migrationWorkspace {
+	return &migrationWorkspace{root: root, files: make(map[string]*migrationFile)}
+}
+
+// load returns the buffered file for rel, reading it from disk on first use.
+func (w *migrationWorkspace) load(rel string) (*migrationFile, error) {
+	if file, ok := w.files[rel]; ok {
+		return file, nil
+	}
+	file := &migrationFile{perm: 0o644}
+	path := filepath.Join(w.root, filepath.FromSlash(rel))
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		file.content = string(data)
+		file.exists = true
+		if info, statErr := os.Stat(path); statErr == nil {
+			file.perm = info.Mode().Perm()
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, fmt.Errorf(messages.ConfigMigrationReadFailedFmt, rel, err)
+	}
+	w.files[rel] = file
+	w.order = append(w.order, rel)
+	return file, nil
+}
+
+// read returns the current content of rel and whether it exists.
+func (w *migrationWorkspace) read(rel string) (string, bool, error) {
+	file, err := w.load(rel)
+	if err != nil {
+		return "", false, err
+	}
+	return file.content, file.exists, nil
+}
+
+// write replaces the content of rel and records descriptions in the summary (no-op when unchanged).
+func (w *migrationWorkspace) write(rel string, content string, descriptions ...string) {
+	file := w.files[rel]
+	if file == nil {
+		file = &migrationFile{perm: 0o644}
+		w.files[rel] = file
+		w.order = append(w.order, rel)
+	}
+	if file.exists && file.content == content {
+		return
+	}
+	file.content = content
+	file.exists = true
+	file.removed = false
+	file.changed = true
+	for _, description := range descriptions {
+		w.note(rel, description)
+	}
+}
+
+// rename moves from to to, keeping content and permissions.
+func (w *migrationWorkspace) rename(from string, to string, description string) error {
+	src, err := w.load(from)
+	if err != nil {
+		return err
+	}
+	if _, err := w.load(to); err != nil {
+		return err
+	}
+	dst := w.files[to]
+	dst.content, dst.exists, dst.perm, dst.changed, dst.removed = src.content, true, src.perm, true, false
+	src.content, src.exists, src.changed, src.removed = "", false, true, true
+	w.note(from, description)
+	return nil
+}
+
+func (w *migrationWorkspace) note(rel string, description string) {
+	w.changes = append(w.changes, MigrationChange{Path: rel, Description: description})
+}
+
+// commit writes changed files and then removes renamed-away files.
+func (w *migrationWorkspace) commit() error {
+	for _, rel := range w.order {
+		file := w.files[rel]
+		if !file.changed || file.removed {
+			continue
+		}
+		path := filepath.Join(w.root, filepath.FromSlash(rel))
+		if err := writeMigratedFile(path, []byte(file.content), file.perm); err != nil {
+			return fmt.Errorf(messages.ConfigMigrationWriteFailedFmt, rel, err)
+		}
+	}
+	for _, rel := range w.order {
+		file := w.files[rel]
+		if !file.removed {
+			continue
+		}
+		path := filepath.Join(w.root, filepath.FromSlash(rel))
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf(messages.ConfigMigrationWriteFailedFmt, rel, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const unversionedConfig = `[approvals]
+# one of: "all", "mcp", "commands", "none"
+mode = "all"
+
+[agents.gemini]
+enabled = true
+
+[agents.claude]
+# keep the default model
+model = "opus"
+
+[[mcp.servers]]
+id = "github" # team server
+transport = "http"
+url = "https://example.com/mcp"
+`
+
+func writeMigrationRepo(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string]string{
+		configRelPath:                  unversionedConfig,
+		commandsAllowRelPath:           "# prefixes\ngit status\nls\ngit status\n",
+		"docs/agent-layer/FEATURES.md": "# Features\n\n## Features\n\n- Feature one\n",
+		"docs/agent-layer/ISSUES.md":   "# Issues\n\n## Open issues\n",
+	}
+	for rel, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", rel, err)
+		}
+	}
+	return root
+}
+
+func readRepoFile(t *testing.T, root string, rel string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(rel)))
+	if err != nil {
+		t.Fatalf("read %s: %v", rel, err)
+	}
+	return string(data)
+}
+
+func TestMigrateUnversionedRepo(t *testing.T) {
+	root := writeMigrationRepo(t)
+
+	report, err := Migrate(root, false)
+	if err != nil {
+		t.Fatalf("Migrate error: %v", err)
+	}
+	if report.From != 0 || report.To != CurrentConfigVersion {
+		t.Fatalf("unexpected versions: %+v", report)
+	}
+
+	cfg := readRepoFile(t, root, configRelPath)
+	for _, want := range []string{
+		"config_version = 1\n\n[approvals]\n# one of:",
+		"[agents.claude]\nenabled = false\n# keep the default model\nmodel = \"opus\"",
+		"[agents.antigravity]\nenabled = false",
+		"[[mcp.servers]]\nenabled = true\nid = \"github\" # team server",
+	} {
+		if !strings.Contains(cfg, want) {
+			t.Fatalf("expected %q in migrated config:\n%s", want, cfg)
+		}
+	}
+	if _, err := ParseConfig([]byte(cfg), "config.toml"); err != nil {
+		t.Fatalf("migrated config should validate: %v", err)
+	}
+
+	if got := readRepoFile(t, root, commandsAllowRelPath); got != "# prefixes\ngit status\nls\n" {
+		t.Fatalf("unexpected commands.allow: %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(root, "docs", "agent-layer", "FEATURES.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected FEATURES.md to be renamed, stat err=%v", err)
+	}
+	if got := readRepoFile(t, root, "docs/agent-layer/BACKLOG.md"); got != "# Features\n\n## Features\n\n"+memoryEntriesMarker+"\n\n- Feature one\n" {
+		t.Fatalf("unexpected BACKLOG.md: %q", got)
+	}
+	if got := readRepoFile(t, root, "docs/agent-layer/ISSUES.md"); got != "# Issues\n\n## Open issues\n\n"+memoryEntriesMarker+"\n" {
+		t.Fatalf("unexpected ISSUES.md: %q", got)
+	}
+
+	again, err := Migrate(root, false)
+	if err != nil {
+		t.Fatalf("second Migrate error: %v", err)
+	}
+	if len(again.Changes) != 0 || again.From != CurrentConfigVersion {
+		t.Fatalf("expected no-op second run, got %+v", again)
+	}
+}
+
+func TestMigrateDryRun(t *testing.T) {
+	root := writeMigrationRepo(t)
+	report, err := Migrate(root, true)
+	if err != nil {
+		t.Fatalf("Migrate error: %v", err)
+	}
+	if len(report.Changes) == 0 {
+		t.Fatalf("expected changes to be reported")
+	}
+	if got := readRepoFile(t, root, configRelPath); got != unversionedConfig {
+		t.Fatalf("dry run must not write config")
+	}
+	if _, err := os.Stat(filepath.Join(root, "docs", "agent-layer", "FEATURES.md")); err != nil {
+		t.Fatalf("dry run must not rename files: %v", err)
+	}
+}
+
+func TestMigrateTooNew(t *testing.T) {
+	root := writeMigrationRepo(t)
+	path := filepath.Join(root, filepath.FromSlash(configRelPath))
+	if err := os.WriteFile(path, []byte("config_version = 99\n"), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if _, err := Migrate(root, false); err == nil || !strings.Contains(err.Error(), "newer than this version") {
+		t.Fatalf("expected too-new error, got %v", err)
+	}
+}
+
+func TestMigrateErrors(t *testing.T) {
+	if _, err := Migrate(t.TempDir(), false); err == nil || !strings.Contains(err.Error(), "missing config file") {
+		t.Fatalf("expected missing config error, got %v", err)
+	}
+
+	root := writeMigrationRepo(t)
+	path := filepath.Join(root, filepath.FromSlash(configRelPath))
+	if err := os.WriteFile(path, []byte("[broken"), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if _, err := Migrate(root, false); err == nil {
+		t.Fatalf("expected parse error")
+	}
+
+	root = writeMigrationRepo(t)
+	original := writeMigratedFile
+	writeMigratedFile = func(string, []byte, os.FileMode) error { return errors.New("disk full") }
+	t.Cleanup(func() { writeMigratedFile = original })
+	if _, err := Migrate(root, false); err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Fatalf("expected write error, got %v", err)
+	}
+}
+
+func TestMigrateInlineTables(t *testing.T) {
+	cases := []struct {
+		config string
+		key    string
+	}{
+		{"[agents]\ngemini = { enabled = true }\nclaude = { model = \"opus\" }\n", "agents.claude"},
+		{"[agents.gemini]\nenabled = true\n\n[agents]\ncodex.model = \"gpt\"\n", "agents.codex"},
+		{"agents = { gemini = { enabled = true } }\n", "agents"},
+		{"mcp = { servers = [{ id = \"github\", transport = \"http\", url = \"https://example.com/mcp\" }] }\n", "mcp.servers.github"},
+	}
+	for _, tc := range cases {
+		root := writeMigrationRepo(t)
+		path := filepath.Join(root, filepath.FromSlash(configRelPath))
+		if err := os.WriteFile(path, []byte(tc.config), 0o644); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		_, err := Migrate(root, false)
+		if err == nil || !strings.Contains(err.Error(), tc.key+" is not written with its own [table] header") {
+			t.Fatalf("%s: expected a hand-fix error, got %v", tc.key, err)
+		}
+		if got := readRepoFile(t, root, configRelPath); got != tc.config {
+			t.Fatalf("%s: expected config to be left unchanged, got:\n%s", tc.key, got)
+		}
+	}
+}
+
+func TestMigrateRunsStepsInOrder(t *testing.T) {
+	root := writeMigrationRepo(t)
+	original := migrations
+	t.Cleanup(func() { migrations = original })
+	var order []int
+	failing := errors.New("boom")
+	migrations = []migration{
+		{Version: 1, Apply: func(w *migrationWorkspace) error { order = append(order, 1); return nil }},
+		{Version: 0, Apply: func(w *migrationWorkspace) error { order = append(order, 0); return nil }},
+		{Version: 2, Apply: func(w *migrationWorkspace) error { return failing }},
+	}
+	if _, err := Migrate(root, true); err != nil {
+		t.Fatalf("Migrate error: %v", err)
+	}
+	if len(order) != 1 || order[0] != 1 {
+		t.Fatalf("expected only the pending step to run, got %v", order)
+	}
+
+	migrations = []migration{{Version: 1, Apply: func(w *migrationWorkspace) error { return failing }}}
+	if _, err := Migrate(root, false); !errors.Is(err, failing) || !strings.Contains(err.Error(), "config_version 1") {
+		t.Fatalf("expected wrapped step error, got %v", err)
+	}
+}
+
+func TestCheckConfigVersion(t *testing.T) {
+	if err := checkConfigVersion(CurrentConfigVersion, "config.toml"); err != nil {
+		t.Fatalf("current version should pass: %v", err)
+	}
+	if err := checkConfigVersion(0, "config.toml"); err != nil {
+		t.Fatalf("unversioned should pass: %v", err)
+	}
+	if err := checkConfigVersion(-1, "config.toml"); err == nil || !strings.Contains(err.Error(), "invalid") {
+		t.Fatalf("expected invalid error, got %v", err)
+	}
+	if err := checkConfigVersion(CurrentConfigVersion+1, "config.toml"); err == nil || !strings.Contains(err.Error(), "upgrade al") {
+		t.Fatalf("expected too-new error, got %v", err)
+	}
+}
+
+func TestLoadReportsVersionProblems(t *testing.T) {
+	_, err := ParseConfig([]byte("config_version = 99\n"), "config.toml")
+	if err == nil || !strings.Contains(err.Error(), "config.toml: config_version 99 is newer") {
+		t.Fatalf("expected too-new error, got %v", err)
+	}
+
+	_, err = ParseConfig([]byte(unversionedConfig), "config.toml")
+	if err == nil || !strings.HasSuffix(err.Error(), "run `al migrate` to upgrade configs written by older releases") {
+		t.Fatalf("expected migrate hint, got %v", err)
+	}
+}
@@ -0,0 +1,314 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/pelletier/go-toml/v2/unstable"
+
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+const (
+	configRelPath        = ".agent-layer/config.toml"
+	commandsAllowRelPath = ".agent-layer/commands.allow"
+	memoryRelDir         = "docs/agent-layer"
+	memoryEntriesMarker  = "<!-- ENTRIES START -->"
+)
+
+// migrationAgents lists agents in the order new [agents.*] tables are written.
+var migrationAgents = []string{"gemini", "claude", "codex", "vscode", "antigravity"}
+
+// memoryMarkerFiles are the memory files whose entries are inserted below memoryEntriesMarker.
+var memoryMarkerFiles = []string{"BACKLOG.md", "COMMANDS.md", "DECISIONS.md", "ISSUES.md"}
+
+// migrateToV1 upgrades an unversioned layout: explicit enabled flags, BACKLOG.md, entry markers,
+// and a duplicate-free commands.allow.
+func migrateToV1(w *migrationWorkspace) error {
+	if err := addMissingEnabledFlags(w); err != nil {
+		return err
+	}
+	if err := renameFeaturesMemory(w); err != nil {
+		return err
+	}
+	if err := addMemoryEntryMarkers(w); err != nil {
+		return err
+	}
+	return dedupeCommandsAllow(w)
+}
+
+// addMissingEnabledFlags makes agent and MCP server enablement explicit.
+// Missing agents are added disabled; servers without a flag keep their previous behavior (enabled).
+// Entries written as inline tables or dotted keys fail the migration, naming the key to fix by hand.
+func addMissingEnabledFlags(w *migrationWorkspace) error {
+	content, _, err := w.read(configRelPath)
+	if err != nil {
+		return err
+	}
+	var raw map[string]any
+	if err := toml.Unmarshal([]byte(content), &raw); err != nil {
+		return err
+	}
+	headers := tomlTableHeaders([]byte(content))
+	edits := lineInsertions{}
+	var notes []string
+
+	agents, _ := raw["agents"].(map[string]any)
+	lastAgentLine := 0
+	agentsHeader := false
+	for _, header := range headers {
+		if header.Key == "agents" {
+			agentsHeader = true
+		}
+		if strings.HasPrefix(header.Key, "agents.") {
+			agentsHeader = true
+			lastAgentLine = tableBlockEnd(content, headers, header)
+		}
+	}
+	var missingTables []string
+	for _, name := range migrationAgents {
+		table, ok := agents[name].(map[string]any)
+		if !ok {
+			if agents != nil && !agentsHeader {
+				return fmt.Errorf(messages.ConfigMigrationNotTableFmt, "agents", "agents."+name+".enabled = false")
+			}
+			missingTables = append(missingTables, "", "[agents."+name+"]", "enabled = false")
+			notes = append(notes, fmt.Sprintf(messages.ConfigMigrationAddedFmt, "agents."+name+".enabled = false"))
+			continue
+		}
+		if _, ok := table["enabled"]; ok {
+			continue
+		}
+		header, ok := findTableHeader(headers, "agents."+name, -1)
+		if !ok {
+			return fmt.Errorf(messages.ConfigMigrationNotTableFmt, "agents."+name, "agents."+name+".enabled = false")
+		}
+		edits.add(header.Line, "enabled = false")
+		notes = append(notes, fmt.Sprintf(messages.ConfigMigrationAddedFmt, "agents."+name+".enabled = false"))
+	}
+	if len(missingTables) > 0 {
+		if lastAgentLine == 0 {
+			lastAgentLine = strings.Count(strings.TrimRight(content, "\n"), "\n") + 1
+		}
+		edits.add(lastAgentLine, missingTables...)
+	}
+
+	mcp, _ := raw["mcp"].(map[string]any)
+	servers, _ := mcp["servers"].([]any)
+	for i, item := range servers {
+		server, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		if _, ok := server["enabled"]; ok {
+			continue
+		}
+		id, _ := server["id"].(string)
+		header, ok := findTableHeader(headers, "mcp.servers", i)
+		if !ok {
+			return fmt.Errorf(messages.ConfigMigrationNotTableFmt, serverLabel(i, id), serverLabel(i, id)+".enabled = true")
+		}
+		edits.add(header.Line, "enabled = true")
+		notes = append(notes, fmt.Sprintf(messages.ConfigMigrationAddedFmt, serverLabel(i, id)+".enabled = true"))
+	}
+
+	w.write(configRelPath, edits.apply(content), notes...)
+	return nil
+}
+
+// renameFeaturesMemory renames the pre-0.5.4 FEATURES.md memory file to BACKLOG.md.
+func renameFeaturesMemory(w *migrationWorkspace) error {
+	from := memoryRelDir + "/FEATURES.md"
+	to := memoryRelDir + "/BACKLOG.md"
+	_, fromExists, err := w.read(from)
+	if err != nil || !fromExists {
+		return err
+	}
+	if _, toExists, err := w.read(to); err != nil || toExists {
+		return err
+	}
+	return w.rename(from, to, fmt.Sprintf(messages.ConfigMigrationRenamedFmt, to))
+}
+
+// addMemoryEntryMarkers inserts the entries marker below the last heading of memory files that lack it.
+func addMemoryEntryMarkers(w *migrationWorkspace) error {
+	for _, name := range memoryMarkerFiles {
+		rel := memoryRelDir + "/" + name
+		content, exists, err := w.read(rel)
+		if err != nil {
+			return err
+		}
+		if !exists || strings.Contains(content, memoryEntriesMarker) {
+			continue
+		}
+		w.write(rel, insertEntriesMarker(content), fmt.Sprintf(messages.ConfigMigrationAddedFmt, memoryEntriesMarker))
+	}
+	return nil
+}
+
+// insertEntriesMarker places the marker after the last "## " heading (or at the end when there is none).
+func insertEntriesMarker(content string) string {
+	lines := strings.Split(content, "\n")
+	heading := len(lines) - 1
+	for heading >= 0 && !strings.HasPrefix(lines[heading], "## ") {
+		heading--
+	}
+	if heading < 0 {
+		return strings.TrimRight(content, "\n") + "\n\n" + memoryEntriesMarker + "\n"
+	}
+	rest := lines[heading+1:]
+	for len(rest) > 0 && strings.TrimSpace(rest[0]) == "" {
+		rest = rest[1:]
+	}
+	out := append(append([]string{}, lines[:heading+1]...), "", memoryEntriesMarker, "")
+	return strings.Join(append(out, rest...), "\n")
+}
+
+// dedupeCommandsAllow removes repeated prefixes from commands.allow, keeping the first occurrence and all comments.
+func dedupeCommandsAllow(w *migrationWorkspace) error {
+	content, exists, err := w.read(commandsAllowRelPath)
+	if err != nil || !exists {
+		return err
+	}
+	lines := strings.Split(content, "\n")
+	seen := make(map[string]bool)
+	kept := make([]string, 0, len(lines))
+	removed := 0
+	for _, line := range lines {
+		prefix := strings.TrimSpace(line)
+		if prefix != "" && !strings.HasPrefix(prefix, "#") {
+			if seen[prefix] {
+				removed++
+				continue
+			}
+			seen[prefix] = true
+		}
+		kept = append(kept, line)
+	}
+	if removed == 0 {
+		return nil
+	}
+	w.write(commandsAllowRelPath, strings.Join(kept, "\n"), fmt.Sprintf(messages.ConfigMigrationDedupedFmt, removed))
+	return nil
+}
+
+// setConfigVersion sets the top-level config_version, replacing an existing value or inserting it
+// after any leading editor directives (#:schema).
+func setConfigVersion(content string, version int) string {
+	line := fmt.Sprintf("config_version = %d", version)
+	lines := strings.Split(content, "\n")
+
+	var parser unstable.Parser
+	parser.Reset([]byte(content))
+	for parser.NextExpression() {
+		expr := parser.Expression()
+		if expr.Kind == unstable.Table || expr.Kind == unstable.ArrayTable {
+			break
+		}
+		if expr.Kind != unstable.KeyValue {
+			continue
+		}
+		if key, pos := nodeKey(&parser, expr); key == "config_version" {
+			existing := lines[pos.Line-1]
+			if hash := strings.Index(existing, "#"); hash >= 0 {
+				line += " " + existing[hash:]
+			}
+			lines[pos.Line-1] = line
+			return strings.Join(lines, "\n")
+		}
+	}
+
+	at := 0
+	for at < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[at]), "#:") {
+		at++
+	}
+	insert := []string{line}
+	if at < len(lines) && strings.TrimSpace(lines[at]) != "" {
+		insert = append(insert, "")
+	}
+	out := append([]string{}, lines[:at]...)
+	out = append(out, insert...)
+	out = append(out, lines[at:]...)
+	return strings.Join(out, "\n")
+}
+
+// tomlHeader is a [table] or [[array.table]] header line.
+type tomlHeader struct {
+	Key   string
+	Array bool
+	Line  int
+}
+
+// tomlTableHeaders lists table headers in document order.
+func tomlTableHeaders(data []byte) []tomlHeader {
+	var headers []tomlHeader
+	var parser unstable.Parser
+	parser.Reset(data)
+	for parser.NextExpression() {
+		expr := parser.Expression()
+		if expr.Kind != unstable.Table && expr.Kind != unstable.ArrayTable {
+			continue
+		}
+		key, pos := nodeKey(&parser, expr)
+		headers = append(headers, tomlHeader{Key: key, Array: expr.Kind == unstable.ArrayTable, Line: pos.Line})
+	}
+	return headers
+}
+
+// findTableHeader returns the header for key; index selects the n-th array table entry (-1 for a plain table).
+func findTableHeader(headers []tomlHeader, key string, index int) (tomlHeader, bool) {
+	seen := 0
+	for _, header := range headers {
+		if header.Key != key {
+			continue
+		}
+		if index < 0 && !header.Array {
+			return header, true
+		}
+		if index >= 0 && header.Array {
+			if seen == index {
+				return header, true
+			}
+			seen++
+		}
+	}
+	return tomlHeader{}, false
+}
+
+// tableBlockEnd returns the last non-blank line of header's block (before the next header).
+func tableBlockEnd(content string, headers []tomlHeader, header tomlHeader) int {
+	lines := strings.Split(content, "\n")
+	end := len(lines)
+	for _, next := range headers {
+		if next.Line > header.Line && next.Line-1 < end {
+			end = next.Line - 1
+		}
+	}
+	for end > header.Line && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	return end
+}
+
+// lineInsertions collects lines to insert after 1-based line numbers (0 inserts at the top).
+type lineInsertions map[int][]string
+
+func (l lineInsertions) add(after int, lines ...string) {
+	l[after] = append(l[after], lines...)
+}
+
+// apply returns content with every insertion applied.
+func (l lineInsertions) apply(content string) string {
+	if len(l) == 0 {
+		return content
+	}
+	lines := strings.Split(content, "\n")
+	out := make([]string, 0, len(lines)+len(l))
+	out = append(out, l[0]...)
+	for i, line := range lines {
+		out = append(out, line)
+		out = append(out, l[i+1]...)
+	}
+	return strings.Join(out, "\n")
+}
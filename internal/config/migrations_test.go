@@ -0,0 +1,57 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestSetConfigVersion(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "insert", content: "[approvals]\nmode = \"all\"\n", want: "config_version = 1\n\n[approvals]\nmode = \"all\"\n"},
+		{name: "after directive", content: "#:schema ./config.schema.json\n\n[approvals]\n", want: "#:schema ./config.schema.json\nconfig_version = 1\n\n[approvals]\n"},
+		{name: "replace keeps comment", content: "config_version = 0 # layout\n[approvals]\n", want: "config_version = 1 # layout\n[approvals]\n"},
+		{name: "nested key ignored", content: "[x]\nconfig_version = 3\n", want: "config_version = 1\n\n[x]\nconfig_version = 3\n"},
+	}
+	for _, tc := range cases {
+		if got := setConfigVersion(tc.content, 1); got != tc.want {
+			t.Fatalf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestInsertEntriesMarker(t *testing.T) {
+	if got := insertEntriesMarker("# Title\n\nText\n"); got != "# Title\n\nText\n\n"+memoryEntriesMarker+"\n" {
+		t.Fatalf("unexpected marker without heading: %q", got)
+	}
+	if got := insertEntriesMarker("# T\n\n## A\n\n## B\n- entry\n"); got != "# T\n\n## A\n\n## B\n\n"+memoryEntriesMarker+"\n\n- entry\n" {
+		t.Fatalf("unexpected marker placement: %q", got)
+	}
+}
+
+func TestFindTableHeader(t *testing.T) {
+	headers := tomlTableHeaders([]byte("[a]\n[[s]]\n[[s]]\n[s.env]\n"))
+	if h, ok := findTableHeader(headers, "a", -1); !ok || h.Line != 1 {
+		t.Fatalf("unexpected table header: %+v %v", h, ok)
+	}
+	if h, ok := findTableHeader(headers, "s", 1); !ok || h.Line != 3 {
+		t.Fatalf("unexpected array header: %+v %v", h, ok)
+	}
+	if _, ok := findTableHeader(headers, "s", 2); ok {
+		t.Fatalf("expected no third entry")
+	}
+}
+
+func TestLineInsertions(t *testing.T) {
+	edits := lineInsertions{}
+	if got := edits.apply("a\nb"); got != "a\nb" {
+		t.Fatalf("expected unchanged content")
+	}
+	edits.add(0, "top")
+	edits.add(1, "x", "y")
+	if got := edits.apply("a\nb"); got != "top\na\nx\ny\nb" {
+		t.Fatalf("unexpected insertions: %q", got)
+	}
+}
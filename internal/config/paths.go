@@ -1,15 +1,24 @@
 package config
 
-import "path/filepath"
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mitchellh/go-homedir"
+)
 
 // Paths holds resolved paths for config files and directories.
 type Paths struct {
 	Root             string
 	ConfigPath       string
+	LocalConfigPath  string
+	UserConfigPath   string
 	EnvPath          string
 	InstructionsDir  string
 	SlashCommandsDir string
 	CommandsAllow    string
+	MemoryDir        string
 }
 
 // DefaultPaths returns the default config paths for a repo root.
@@ -17,9 +26,45 @@ func DefaultPaths(root string) Paths {
 	return Paths{
 		Root:             root,
 		ConfigPath:       filepath.Join(root, ".agent-layer", "config.toml"),
+		LocalConfigPath:  filepath.Join(root, ".agent-layer", "config.local.toml"),
+		UserConfigPath:   UserConfigPath(),
 		EnvPath:          filepath.Join(root, ".agent-layer", ".env"),
 		InstructionsDir:  filepath.Join(root, ".agent-layer", "instructions"),
 		SlashCommandsDir: filepath.Join(root, ".agent-layer", "slash-commands"),
 		CommandsAllow:    filepath.Join(root, ".agent-layer", "commands.allow"),
+		MemoryDir:        filepath.Join(root, "docs", "agent-layer"),
+	}
+}
+
+// AgeIdentityEnvVar overrides the age identity file used for .env.age.
+const AgeIdentityEnvVar = "AL_AGE_IDENTITY"
+
+// UserConfigPath returns the user-global config path.
+// It honors XDG_CONFIG_HOME and falls back to ~/.config; returns empty when no home directory is available.
+func UserConfigPath() string {
+	return userConfigFile("config.toml")
+}
+
+// AgeIdentityPath returns the age identity file that decrypts .env.age.
+// It honors AL_AGE_IDENTITY and defaults to age-identity.txt next to the user config.
+func AgeIdentityPath() string {
+	if path := strings.TrimSpace(os.Getenv(AgeIdentityEnvVar)); path != "" {
+		if expanded, err := homedir.Expand(path); err == nil {
+			return expanded
+		}
+		return path
+	}
+	return userConfigFile("age-identity.txt")
+}
+
+// userConfigFile returns name inside the user-global agent-layer config directory.
+func userConfigFile(name string) string {
+	if dir := strings.TrimSpace(os.Getenv("XDG_CONFIG_HOME")); dir != "" {
+		return filepath.Join(dir, "agent-layer", name)
+	}
+	home, err := homedir.Dir()
+	if err != nil || home == "" {
+		return ""
 	}
+	return filepath.Join(home, ".config", "agent-layer", name)
 }
@@ -3,6 +3,8 @@ package config
 import (
 	"path/filepath"
 	"testing"
+
+	"github.com/mitchellh/go-homedir"
 )
 
 func TestDefaultPaths(t *testing.T) {
@@ -15,6 +17,9 @@ func TestDefaultPaths(t *testing.T) {
 	if paths.ConfigPath != filepath.Join(root, ".agent-layer", "config.toml") {
 		t.Fatalf("unexpected config path: %s", paths.ConfigPath)
 	}
+	if paths.LocalConfigPath != filepath.Join(root, ".agent-layer", "config.local.toml") {
+		t.Fatalf("unexpected local config path: %s", paths.LocalConfigPath)
+	}
 	if paths.EnvPath != filepath.Join(root, ".agent-layer", ".env") {
 		t.Fatalf("unexpected env path: %s", paths.EnvPath)
 	}
@@ -28,3 +33,36 @@ func TestDefaultPaths(t *testing.T) {
 		t.Fatalf("unexpected commands allow path: %s", paths.CommandsAllow)
 	}
 }
+
+func TestUserConfigPath(t *testing.T) {
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	if got := UserConfigPath(); got != filepath.Join(xdg, "agent-layer", "config.toml") {
+		t.Fatalf("unexpected user config path with XDG_CONFIG_HOME: %s", got)
+	}
+
+	homedir.DisableCache = true
+	t.Cleanup(func() { homedir.DisableCache = false })
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	if got := UserConfigPath(); got != filepath.Join(home, ".config", "agent-layer", "config.toml") {
+		t.Fatalf("unexpected user config path: %s", got)
+	}
+}
+
+func TestAgeIdentityPath(t *testing.T) {
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	t.Setenv(AgeIdentityEnvVar, "")
+	if got := AgeIdentityPath(); got != filepath.Join(xdg, "agent-layer", "age-identity.txt") {
+		t.Fatalf("unexpected default identity path: %s", got)
+	}
+
+	custom := filepath.Join(t.TempDir(), "key.txt")
+	t.Setenv(AgeIdentityEnvVar, custom)
+	if got := AgeIdentityPath(); got != custom {
+		t.Fatalf("expected AL_AGE_IDENTITY override, got %s", got)
+	}
+}
@@ -0,0 +1,254 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/pelletier/go-toml/v2/unstable"
+
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// Problem is a single config validation failure.
+// Line and Column are 1-based and zero when the position is unknown.
+type Problem struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+// String formats the problem as file:line:col: message (omitting unknown parts).
+func (p Problem) String() string {
+	switch {
+	case p.File != "" && p.Line > 0:
+		return fmt.Sprintf("%s:%d:%d: %s", p.File, p.Line, p.Column, p.Message)
+	case p.File != "":
+		return fmt.Sprintf("%s: %s", p.File, p.Message)
+	default:
+		return p.Message
+	}
+}
+
+// ValidationError reports every problem found while loading a config.
+// Hint, when set, is printed after the problems.
+type ValidationError struct {
+	Problems []Problem
+	Hint     string
+}
+
+// Error lists each problem on its own line.
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Problems)+1)
+	for _, problem := range e.Problems {
+		lines = append(lines, problem.String())
+	}
+	if e.Hint != "" {
+		lines = append(lines, e.Hint)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// position is a 1-based line and column in a TOML document.
+type position struct {
+	Line   int
+	Column int
+}
+
+// keyPositions maps dotted config keys to where they are defined in one file.
+// Array-of-table entries are indexed as key[i]; MCP servers are also aliased by id (mcp.servers.<id>).
+type keyPositions map[string]position
+
+// indexedLayer pairs a layer file with its key positions.
+type indexedLayer struct {
+	Path      string
+	Positions keyPositions
+}
+
+// indexKeyPositions records the position of every key and table header in data.
+// data must already be valid TOML; parse errors stop indexing early.
+func indexKeyPositions(data []byte) keyPositions {
+	positions := make(keyPositions)
+	record := func(key string, pos position) {
+		if _, ok := positions[key]; !ok {
+			positions[key] = pos
+		}
+	}
+
+	var parser unstable.Parser
+	parser.Reset(data)
+	table := ""
+	arrayCounts := make(map[string]int)
+	serverIDs := make(map[string]string)
+	for parser.NextExpression() {
+		expr := parser.Expression()
+		switch expr.Kind {
+		case unstable.Table:
+			key, pos := nodeKey(&parser, expr)
+			table = key
+			record(table, pos)
+		case unstable.ArrayTable:
+			key, pos := nodeKey(&parser, expr)
+			index := arrayCounts[key]
+			arrayCounts[key]++
+			record(key, pos)
+			table = fmt.Sprintf("%s[%d]", key, index)
+			record(table, pos)
+		case unstable.KeyValue:
+			indexKeyValue(&parser, expr, table, record, serverIDs)
+		}
+	}
+
+	// Alias server entries by id so merged-config keys (mcp.servers.<id>...) resolve.
+	// Entries are visited in document order so a duplicated id resolves to its first definition.
+	aliases := make(map[string]position)
+	for i := 0; i < arrayCounts["mcp.servers"]; i++ {
+		entry := fmt.Sprintf("mcp.servers[%d]", i)
+		id, ok := serverIDs[entry]
+		if !ok {
+			continue
+		}
+		for key, pos := range positions {
+			if key != entry && !strings.HasPrefix(key, entry+".") {
+				continue
+			}
+			alias := "mcp.servers." + id + strings.TrimPrefix(key, entry)
+			if _, seen := aliases[alias]; !seen {
+				aliases[alias] = pos
+			}
+		}
+	}
+	for key, pos := range aliases {
+		record(key, pos)
+	}
+	return positions
+}
+
+// indexKeyValue records a key/value expression and, for inline tables, its nested keys.
+func indexKeyValue(parser *unstable.Parser, expr *unstable.Node, table string, record func(string, position), serverIDs map[string]string) {
+	key, pos := nodeKey(parser, expr)
+	full := joinKey(table, key)
+	record(full, pos)
+
+	value := expr.Value()
+	if strings.HasPrefix(table, "mcp.servers[") && key == "id" && value.Kind == unstable.String {
+		serverIDs[table] = string(value.Data)
+	}
+	if value.Kind == unstable.InlineTable {
+		children := value.Children()
+		for children.Next() {
+			child := children.Node()
+			if child.Kind == unstable.KeyValue {
+				indexKeyValue(parser, child, full, record, serverIDs)
+			}
+		}
+	}
+}
+
+// nodeKey returns the dotted key of a table, array table, or key/value node and its start position.
+func nodeKey(parser *unstable.Parser, node *unstable.Node) (string, position) {
+	var parts []string
+	var pos position
+	it := node.Key()
+	for it.Next() {
+		part := it.Node()
+		if len(parts) == 0 && part.Raw.Length > 0 {
+			start := parser.Shape(part.Raw).Start
+			pos = position{Line: start.Line, Column: start.Column}
+		}
+		parts = append(parts, string(part.Data))
+	}
+	return strings.Join(parts, "."), pos
+}
+
+// locate finds the file and position that best explain key: the most specific known key
+// (walking up to parents) in the highest-priority layer that defines it.
+func locate(key string, layers []indexedLayer) (string, position, bool) {
+	for candidate := key; candidate != ""; candidate = parentKey(candidate) {
+		for i := len(layers) - 1; i >= 0; i-- {
+			if pos, ok := layers[i].Positions[candidate]; ok {
+				return layers[i].Path, pos, true
+			}
+		}
+	}
+	return "", position{}, false
+}
+
+// parentKey strips the last .segment or [index] from key.
+func parentKey(key string) string {
+	cut := strings.LastIndexAny(key, ".[")
+	if cut < 0 {
+		return ""
+	}
+	return key[:cut]
+}
+
+// resolveProblems attaches file positions to field problems; unknown keys fall back to source.
+func resolveProblems(fieldProblems []fieldProblem, layers []indexedLayer, source string) []Problem {
+	problems := make([]Problem, 0, len(fieldProblems))
+	for _, fp := range fieldProblems {
+		problem := Problem{File: source, Message: fp.Message}
+		if path, pos, ok := locate(fp.Key, layers); ok {
+			problem.File = path
+			problem.Line = pos.Line
+			problem.Column = pos.Column
+		}
+		problems = append(problems, problem)
+	}
+	return problems
+}
+
+// decodeProblems converts go-toml decode errors into positioned problems.
+// It reports whether the error prevents decoding (syntax or type errors) as opposed to unknown keys.
+func decodeProblems(path string, err error) ([]Problem, bool) {
+	var strict *toml.StrictMissingError
+	if errors.As(err, &strict) {
+		problems := make([]Problem, 0, len(strict.Errors))
+		for _, missing := range strict.Errors {
+			line, column := missing.Position()
+			problems = append(problems, Problem{
+				File:    path,
+				Line:    line,
+				Column:  column,
+				Message: fmt.Sprintf(messages.ConfigUnknownKeyFmt, strings.Join(missing.Key(), ".")),
+			})
+		}
+		return problems, false
+	}
+	var decode *toml.DecodeError
+	if errors.As(err, &decode) {
+		line, column := decode.Position()
+		return []Problem{{File: path, Line: line, Column: column, Message: fmt.Sprintf(messages.ConfigDecodeFailedFmt, decode.Error())}}, true
+	}
+	return []Problem{{File: path, Message: fmt.Sprintf(messages.ConfigDecodeFailedFmt, err.Error())}}, true
+}
+
+// duplicateServerProblems reports [[mcp.servers]] entries in one file that reuse an id.
+func duplicateServerProblems(path string, raw map[string]any, positions keyPositions) []Problem {
+	mcp, _ := raw["mcp"].(map[string]any)
+	servers, _ := mcp["servers"].([]any)
+	first := make(map[string]int)
+	var problems []Problem
+	for i, item := range servers {
+		server, _ := item.(map[string]any)
+		id, _ := server["id"].(string)
+		if id == "" {
+			continue
+		}
+		if prev, ok := first[id]; ok {
+			pos := positions["mcp.servers["+strconv.Itoa(i)+"].id"]
+			problems = append(problems, Problem{
+				File:    path,
+				Line:    pos.Line,
+				Column:  pos.Column,
+				Message: fmt.Sprintf(messages.ConfigMcpServerIDDuplicateFmt, i, id, prev),
+			})
+			continue
+		}
+		first[id] = i
+	}
+	return problems
+}
@@ -0,0 +1,151 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseConfigReportsAllProblemsWithPositions(t *testing.T) {
+	content := `[approvals]
+mode = "sometimes"
+
+[agents.gemini]
+enable = true
+
+[agents.claude]
+enabled = true
+
+[agents.codex]
+enabled = true
+
+[agents.vscode]
+enabled = true
+
+[agents.antigravity]
+enabled = false
+
+[[mcp.servers]]
+id = "github"
+enabled = true
+transport = "http"
+
+[[mcp.servers]]
+id = "github"
+enabled = true
+transport = "stdio"
+command = "gh"
+`
+	_, err := ParseConfig([]byte(content), "config.toml")
+	var validation *ValidationError
+	if !errors.As(err, &validation) {
+		t.Fatalf("expected ValidationError, got %T %v", err, err)
+	}
+	want := []string{
+		"config.toml:5:1: unknown key agents.gemini.enable",
+		`config.toml:25:1: mcp.servers[1].id "github" duplicates mcp.servers[0] in the same file`,
+		"config.toml:2:1: approvals.mode must be one of all, mcp, commands, none",
+		"config.toml:4:2: agents.gemini.enabled is required",
+	}
+	got := err.Error()
+	for _, line := range want {
+		if !strings.Contains(got, line) {
+			t.Fatalf("expected %q in:\n%s", line, got)
+		}
+	}
+	if len(validation.Problems) < len(want) {
+		t.Fatalf("expected at least %d problems, got %d", len(want), len(validation.Problems))
+	}
+}
+
+func TestLayeredProblemsPointAtOwningLayer(t *testing.T) {
+	paths := layerPaths(t)
+	writeLayer(t, paths.ConfigPath, layerBaseConfig)
+	writeLayer(t, paths.LocalConfigPath, `# local overrides
+
+[[mcp.servers]]
+id = "github"
+transport = "stdio"
+`)
+
+	_, err := LoadLayeredConfig(paths, "")
+	if err == nil {
+		t.Fatalf("expected validation error")
+	}
+	got := err.Error()
+	if !strings.Contains(got, paths.LocalConfigPath+":3:3: mcp.servers.github.command is required for stdio transport") {
+		t.Fatalf("expected command problem at local layer, got:\n%s", got)
+	}
+	if !strings.Contains(got, paths.ConfigPath+":") || !strings.Contains(got, "mcp.servers.github.url is not allowed for stdio transport") {
+		t.Fatalf("expected url problem attributed to repo config, got:\n%s", got)
+	}
+}
+
+func TestParseConfigSyntaxError(t *testing.T) {
+	_, err := ParseConfig([]byte("[approvals]\nmode = \n"), "config.toml")
+	if err == nil || !strings.HasPrefix(err.Error(), "config.toml:2:") || !strings.Contains(err.Error(), "invalid config") {
+		t.Fatalf("expected positioned syntax error, got %v", err)
+	}
+}
+
+func TestParseConfigTypeError(t *testing.T) {
+	_, err := ParseConfig([]byte("[agents.gemini]\nenabled = \"yes\"\n"), "config.toml")
+	if err == nil || !strings.HasPrefix(err.Error(), "config.toml:2:") {
+		t.Fatalf("expected positioned type error, got %v", err)
+	}
+}
+
+func TestIndexKeyPositions(t *testing.T) {
+	data := []byte(`top = 1
+[a.b]
+c = { d = 1 }
+
+[[mcp.servers]]
+id = "one"
+url = "x"
+
+[[mcp.servers]]
+  id = "two"
+`)
+	positions := indexKeyPositions(data)
+	cases := map[string]position{
+		"top":                 {Line: 1, Column: 1},
+		"a.b":                 {Line: 2, Column: 2},
+		"a.b.c.d":             {Line: 3, Column: 7},
+		"mcp.servers[0]":      {Line: 5, Column: 3},
+		"mcp.servers.one.url": {Line: 7, Column: 1},
+		"mcp.servers[1].id":   {Line: 10, Column: 3},
+		"mcp.servers.two":     {Line: 9, Column: 3},
+	}
+	for key, want := range cases {
+		if got, ok := positions[key]; !ok || got != want {
+			t.Fatalf("position of %s = %+v (found=%v), want %+v", key, got, ok, want)
+		}
+	}
+}
+
+func TestLocateFallsBackToParent(t *testing.T) {
+	layers := []indexedLayer{
+		{Path: "user.toml", Positions: keyPositions{"agents": {Line: 1, Column: 1}}},
+		{Path: "repo.toml", Positions: keyPositions{"agents.claude": {Line: 3, Column: 2}}},
+	}
+	path, pos, ok := locate("agents.claude.enabled", layers)
+	if !ok || path != "repo.toml" || pos.Line != 3 {
+		t.Fatalf("unexpected location: %s %+v %v", path, pos, ok)
+	}
+	if _, _, ok := locate("warnings.x", layers); ok {
+		t.Fatalf("expected no location")
+	}
+}
+
+func TestProblemString(t *testing.T) {
+	if got := (Problem{File: "f", Line: 1, Column: 2, Message: "m"}).String(); got != "f:1:2: m" {
+		t.Fatalf("unexpected: %s", got)
+	}
+	if got := (Problem{File: "f", Message: "m"}).String(); got != "f: m" {
+		t.Fatalf("unexpected: %s", got)
+	}
+	if got := (Problem{Message: "m"}).String(); got != "m" {
+		t.Fatalf("unexpected: %s", got)
+	}
+}
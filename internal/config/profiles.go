@@ -0,0 +1,111 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// ProfileEnvVar selects a config profile when --profile is not passed.
+const ProfileEnvVar = "AL_PROFILE"
+
+// profileLayerPrefix prefixes the origin layer name of values set by a profile.
+const profileLayerPrefix = "profile:"
+
+// ProfileNames returns the configured profile names in sorted order.
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateProfiles checks every profile overlay, including profiles that are not active.
+func validateProfiles(c *Config) []fieldProblem {
+	var problems []fieldProblem
+	add := func(key string, format string, args ...any) {
+		problems = append(problems, fieldProblem{Key: key, Message: fmt.Sprintf(format, args...)})
+	}
+	serverIDs := make(map[string]struct{}, len(c.MCP.Servers))
+	for _, server := range c.MCP.Servers {
+		serverIDs[server.ID] = struct{}{}
+	}
+	for _, name := range c.ProfileNames() {
+		profile := c.Profiles[name]
+		prefix := "profiles." + name
+		if profile.Approvals.Mode != "" {
+			if _, ok := validApprovals[profile.Approvals.Mode]; !ok {
+				add(prefix+".approvals.mode", messages.ConfigProfileApprovalsModeInvalidFmt, name)
+			}
+		}
+		for i, server := range profile.MCP.Servers {
+			key := fmt.Sprintf("%s.mcp.servers[%d]", prefix, i)
+			if server.ID == "" {
+				add(key+".id", messages.ConfigProfileServerIDRequiredFmt, name, i)
+			} else if _, ok := serverIDs[server.ID]; !ok {
+				add(key+".id", messages.ConfigProfileServerUnknownFmt, name, i, server.ID)
+			}
+			if server.Enabled == nil {
+				add(key+".enabled", messages.ConfigProfileServerEnabledRequiredFmt, name, i)
+			}
+		}
+	}
+	return problems
+}
+
+// applyProfile overlays the named profile onto the merged raw config.
+// Only approvals, agents, and mcp.servers[].enabled are taken from the profile.
+func applyProfile(merged map[string]any, cfg *Config, name string, origins map[string]Origin) error {
+	profiles, _ := merged["profiles"].(map[string]any)
+	raw, ok := profiles[name].(map[string]any)
+	if !ok {
+		available := strings.Join(cfg.ProfileNames(), ", ")
+		if available == "" {
+			available = messages.ConfigProfileNoneDefined
+		}
+		return fmt.Errorf(messages.ConfigProfileUnknownFmt, name, available)
+	}
+
+	overlay := make(map[string]any)
+	for _, key := range []string{"approvals", "agents"} {
+		if value, ok := raw[key]; ok {
+			overlay[key] = value
+		}
+	}
+	if mcp, ok := raw["mcp"].(map[string]any); ok {
+		if servers, ok := mcp["servers"].([]any); ok {
+			toggles := make([]any, 0, len(servers))
+			for _, item := range servers {
+				server, ok := item.(map[string]any)
+				if !ok {
+					continue
+				}
+				toggles = append(toggles, map[string]any{"id": server["id"], "enabled": server["enabled"]})
+			}
+			overlay["mcp"] = map[string]any{"servers": toggles}
+		}
+	}
+
+	origin := Origin{Layer: profileLayerPrefix + name, Path: profileSourcePath(origins, name)}
+	return mergeTable(merged, overlay, "", origin, origins)
+}
+
+// profileSourcePath returns the file that defined the profile, for origin reporting.
+func profileSourcePath(origins map[string]Origin, name string) string {
+	prefix := "profiles." + name + "."
+	keys := make([]string, 0)
+	for key := range origins {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	if len(keys) == 0 {
+		return ""
+	}
+	return origins[keys[0]].Path
+}
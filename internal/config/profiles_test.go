@@ -0,0 +1,152 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+const profileConfig = layerBaseConfig + `
+[[mcp.servers]]
+id = "shell"
+enabled = true
+transport = "stdio"
+command = "shell-mcp"
+
+[profiles.review]
+approvals = { mode = "none" }
+
+[profiles.review.agents.claude]
+model = "haiku"
+
+[[profiles.review.mcp.servers]]
+id = "shell"
+enabled = false
+
+[profiles.build.approvals]
+mode = "all"
+`
+
+func TestLoadLayeredConfigAppliesProfile(t *testing.T) {
+	paths := layerPaths(t)
+	writeLayer(t, paths.ConfigPath, profileConfig)
+
+	layered, err := LoadLayeredConfig(paths, "review")
+	if err != nil {
+		t.Fatalf("LoadLayeredConfig error: %v", err)
+	}
+	cfg := layered.Config
+	if cfg.Approvals.Mode != "none" {
+		t.Fatalf("expected profile approvals, got %q", cfg.Approvals.Mode)
+	}
+	if cfg.Agents.Claude.Model != "haiku" {
+		t.Fatalf("expected profile model, got %q", cfg.Agents.Claude.Model)
+	}
+	if cfg.Agents.Claude.Enabled == nil || !*cfg.Agents.Claude.Enabled {
+		t.Fatalf("expected claude enablement to be inherited")
+	}
+	shell := cfg.MCP.Servers[1]
+	if shell.ID != "shell" || shell.Enabled == nil || *shell.Enabled {
+		t.Fatalf("expected shell server disabled by profile: %+v", shell)
+	}
+	if shell.Command != "shell-mcp" {
+		t.Fatalf("expected shell command inherited, got %q", shell.Command)
+	}
+	entry, ok := layered.Entry("mcp.servers.shell.enabled")
+	if !ok || entry.Origin.Layer != "profile:review" || entry.Origin.Path != paths.ConfigPath {
+		t.Fatalf("unexpected origin: %+v", entry)
+	}
+
+	base, err := LoadLayeredConfig(paths, "")
+	if err != nil {
+		t.Fatalf("LoadLayeredConfig error: %v", err)
+	}
+	if base.Config.Approvals.Mode != "all" || base.Config.Agents.Claude.Model != "sonnet" {
+		t.Fatalf("expected base config without profile: %+v", base.Config.Approvals)
+	}
+}
+
+func TestLoadLayeredConfigProfileFromLocalLayer(t *testing.T) {
+	paths := layerPaths(t)
+	writeLayer(t, paths.ConfigPath, layerBaseConfig)
+	writeLayer(t, paths.LocalConfigPath, `
+[profiles.offline]
+[[profiles.offline.mcp.servers]]
+id = "github"
+enabled = false
+`)
+
+	layered, err := LoadLayeredConfig(paths, "offline")
+	if err != nil {
+		t.Fatalf("LoadLayeredConfig error: %v", err)
+	}
+	if *layered.Config.MCP.Servers[0].Enabled {
+		t.Fatalf("expected github disabled by local profile")
+	}
+}
+
+func TestLoadLayeredConfigUnknownProfile(t *testing.T) {
+	paths := layerPaths(t)
+	writeLayer(t, paths.ConfigPath, profileConfig)
+
+	_, err := LoadLayeredConfig(paths, "missing")
+	if err == nil || !strings.Contains(err.Error(), `unknown profile "missing" (defined profiles: build, review)`) {
+		t.Fatalf("expected unknown profile error, got %v", err)
+	}
+
+	paths = layerPaths(t)
+	writeLayer(t, paths.ConfigPath, layerBaseConfig)
+	_, err = LoadLayeredConfig(paths, "missing")
+	if err == nil || !strings.Contains(err.Error(), "defined profiles: none") {
+		t.Fatalf("expected unknown profile error, got %v", err)
+	}
+}
+
+func TestValidateProfiles(t *testing.T) {
+	cases := []struct {
+		name    string
+		profile string
+		want    string
+	}{
+		{
+			name:    "invalid approvals",
+			profile: "[profiles.bad.approvals]\nmode = \"sometimes\"\n",
+			want:    "profiles.bad.approvals.mode",
+		},
+		{
+			name:    "missing server id",
+			profile: "[[profiles.bad.mcp.servers]]\nenabled = false\n",
+			want:    "profiles.bad.mcp.servers[0].id is required",
+		},
+		{
+			name:    "unknown server",
+			profile: "[[profiles.bad.mcp.servers]]\nid = \"nope\"\nenabled = false\n",
+			want:    `"nope" does not match any mcp.servers entry`,
+		},
+		{
+			name:    "missing enabled",
+			profile: "[[profiles.bad.mcp.servers]]\nid = \"github\"\n",
+			want:    "profiles.bad.mcp.servers[0].enabled is required",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseConfig([]byte(layerBaseConfig+tc.profile), "config.toml")
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected %q error, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestProfileRejectsUnsupportedKeys(t *testing.T) {
+	paths := layerPaths(t)
+	writeLayer(t, paths.ConfigPath, layerBaseConfig+`
+[profiles.quiet.warnings]
+instruction_token_threshold = 5
+`)
+
+	_, err := LoadLayeredConfig(paths, "quiet")
+	if err == nil || !strings.Contains(err.Error(), "unknown key profiles.quiet.warnings") {
+		t.Fatalf("expected unknown key error, got %v", err)
+	}
+}
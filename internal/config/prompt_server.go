@@ -0,0 +1,48 @@
+package config
+
+import (
+	"net"
+	"strconv"
+	"strings"
+)
+
+// PromptServerTokenEnvVar is the .env key holding the bearer token of the HTTP prompt server.
+const PromptServerTokenEnvVar = "AL_PROMPT_SERVER_TOKEN"
+
+// PromptServerPath is the URL path of the HTTP prompt server; /mcp/<client> serves one client.
+const PromptServerPath = "/mcp"
+
+// ValidPromptServerAddress reports whether addr is host:port with a numeric port.
+func ValidPromptServerAddress(addr string) bool {
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	n, err := strconv.Atoi(port)
+	return err == nil && n > 0 && n <= 65535
+}
+
+// IsLoopbackHost reports whether host (without a port) is localhost or a loopback IP.
+func IsLoopbackHost(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(strings.TrimSuffix(strings.TrimPrefix(host, "["), "]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+// PromptServerAddressIsLoopback reports whether binding to addr only accepts local connections.
+// An empty host binds every interface.
+func PromptServerAddressIsLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	return err == nil && IsLoopbackHost(host)
+}
+
+// PromptServerURL returns the URL at which the HTTP prompt server on addr serves client.
+func PromptServerURL(addr string, client string) string {
+	url := "http://" + addr + PromptServerPath
+	if client != "" {
+		url += "/" + client
+	}
+	return url
+}
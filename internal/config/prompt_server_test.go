@@ -0,0 +1,49 @@
+package config
+
+import "testing"
+
+func TestValidPromptServerAddress(t *testing.T) {
+	cases := map[string]bool{
+		"127.0.0.1:7420": true,
+		"localhost:80":   true,
+		"[::1]:7420":     true,
+		"127.0.0.1":      false,
+		"127.0.0.1:0":    false,
+		"127.0.0.1:http": false,
+		":70000":         false,
+	}
+	for addr, want := range cases {
+		if got := ValidPromptServerAddress(addr); got != want {
+			t.Fatalf("%s: expected %v, got %v", addr, want, got)
+		}
+	}
+}
+
+func TestPromptServerAddressIsLoopback(t *testing.T) {
+	cases := map[string]bool{
+		"127.0.0.1:7420":   true,
+		"127.0.0.2:7420":   true,
+		"LocalHost:7420":   true,
+		"[::1]:7420":       true,
+		"0.0.0.0:7420":     false,
+		":7420":            false,
+		"[::]:7420":        false,
+		"192.168.1.5:7420": false,
+		"evil.example:80":  false,
+		"127.0.0.1":        false,
+	}
+	for addr, want := range cases {
+		if got := PromptServerAddressIsLoopback(addr); got != want {
+			t.Fatalf("%s: expected %v, got %v", addr, want, got)
+		}
+	}
+}
+
+func TestPromptServerURL(t *testing.T) {
+	if got := PromptServerURL("127.0.0.1:7420", "claude"); got != "http://127.0.0.1:7420/mcp/claude" {
+		t.Fatalf("unexpected URL %s", got)
+	}
+	if got := PromptServerURL("127.0.0.1:7420", ""); got != "http://127.0.0.1:7420/mcp" {
+		t.Fatalf("unexpected URL %s", got)
+	}
+}
@@ -0,0 +1,225 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// maxIncludeDepth bounds nested {{ include }} calls so a snippet cannot include itself forever.
+const maxIncludeDepth = 10
+
+// TemplateData is the context for rendering instruction fragments and slash command bodies.
+type TemplateData struct {
+	// Client is the client the output is generated for; empty when rendering for every client.
+	Client string
+	// RepoRoot is the absolute repo root.
+	RepoRoot string
+	// Profile is the active profile name, or empty.
+	Profile string
+	// MCPServers lists the ids of MCP servers enabled for Client, in config order.
+	MCPServers []string
+	// CommandsAllow is the commands.allow prefix list.
+	CommandsAllow []string
+	// Env holds the values of the keys listed in [templates] env; other keys are not available.
+	Env map[string]string
+
+	argumentStyle ArgumentStyle
+}
+
+// WithArgumentStyle returns a copy of d that writes {{ arg "name" }} in style.
+func (d TemplateData) WithArgumentStyle(style ArgumentStyle) TemplateData {
+	d.argumentStyle = style
+	return d
+}
+
+// NewTemplateData builds the rendering context for client from a loaded project.
+// Env values come from the process environment first, then .env, matching how clients are launched.
+func NewTemplateData(project *ProjectConfig, client string) TemplateData {
+	data := TemplateData{
+		Client:        client,
+		RepoRoot:      project.Root,
+		Profile:       project.Profile,
+		CommandsAllow: project.CommandsAllow,
+		Env:           make(map[string]string, len(project.Config.Templates.Env)),
+	}
+	for _, server := range project.Config.MCP.Servers {
+		if server.Enabled == nil || !*server.Enabled {
+			continue
+		}
+		if client != "" && !targetsClient(server.Clients, client) {
+			continue
+		}
+		data.MCPServers = append(data.MCPServers, server.ID)
+	}
+	for _, key := range project.Config.Templates.Env {
+		value := os.Getenv(key)
+		if value == "" {
+			value = project.Env[key]
+		}
+		data.Env[key] = value
+	}
+	return data
+}
+
+// RenderInstructions returns copies of files with the content of template files rendered.
+// Files without template: true are copied as is.
+func RenderInstructions(files []InstructionFile, data TemplateData) ([]InstructionFile, error) {
+	rendered := make([]InstructionFile, 0, len(files))
+	for _, file := range files {
+		if !file.Template {
+			rendered = append(rendered, file)
+			continue
+		}
+		content, err := renderTemplate(file.SourcePath, file.Line, file.Content, data)
+		if err != nil {
+			return nil, err
+		}
+		file.Content = content
+		rendered = append(rendered, file)
+	}
+	return rendered, nil
+}
+
+// RenderSlashCommands returns copies of commands with the bodies of template commands rendered.
+// {{ arg "name" }} is written in data's argument style. Other commands are copied as is.
+func RenderSlashCommands(commands []SlashCommand, data TemplateData) ([]SlashCommand, error) {
+	rendered := make([]SlashCommand, 0, len(commands))
+	for _, cmd := range commands {
+		if !cmd.Template {
+			rendered = append(rendered, cmd)
+			continue
+		}
+		r := &templateRenderer{data: data, command: true, args: cmd.Arguments}
+		body, err := r.render(cmd.SourcePath, cmd.Line, cmd.Body, 0)
+		if err != nil {
+			return nil, err
+		}
+		cmd.Body = body
+		rendered = append(rendered, cmd)
+	}
+	return rendered, nil
+}
+
+// renderTemplate renders text, which starts at line of source, with data.
+// Errors are reported as source:line[:col]: detail, pointing into the innermost included file.
+func renderTemplate(source string, line int, text string, data TemplateData) (string, error) {
+	r := &templateRenderer{data: data}
+	return r.render(source, line, text, 0)
+}
+
+// templateFuncNames declares the template functions for parsing without rendering.
+var templateFuncNames = template.FuncMap{
+	"include":    func(string) string { return "" },
+	"mcpEnabled": func(string) bool { return false },
+	"arg":        func(string) string { return "" },
+}
+
+// templateRenderer carries the context through nested includes.
+type templateRenderer struct {
+	data TemplateData
+	// command is set when rendering a slash command body, whose declared arguments are args.
+	command bool
+	args    []SlashCommandArgument
+	// includeErr is the first failure inside an included file; it replaces the
+	// less useful "error calling include" wrapping of every enclosing template.
+	includeErr error
+}
+
+func (r *templateRenderer) render(source string, line int, text string, depth int) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	name := r.displayPath(source)
+	funcs := template.FuncMap{
+		"include": func(path string) (string, error) {
+			return r.include(path, depth+1)
+		},
+		"mcpEnabled": func(id string) bool {
+			return slices.Contains(r.data.MCPServers, id)
+		},
+		"arg": r.arg,
+	}
+	tmpl, err := template.New(name).Funcs(funcs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", templateError(name, line, err)
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, r.data); err != nil {
+		if r.includeErr != nil {
+			return "", r.includeErr
+		}
+		return "", templateError(name, line, err)
+	}
+	return out.String(), nil
+}
+
+// include renders a repo-relative snippet with the same context.
+func (r *templateRenderer) include(path string, depth int) (string, error) {
+	if depth > maxIncludeDepth {
+		return "", fmt.Errorf(messages.ConfigTemplateIncludeDepthFmt, path, maxIncludeDepth)
+	}
+	rel := filepath.FromSlash(path)
+	if !filepath.IsLocal(rel) {
+		return "", fmt.Errorf(messages.ConfigTemplateIncludeOutsideRootFmt, path)
+	}
+	full := filepath.Join(r.data.RepoRoot, rel)
+	data, err := os.ReadFile(full)
+	if err != nil {
+		return "", fmt.Errorf(messages.ConfigTemplateIncludeReadFmt, path, err)
+	}
+	out, err := r.render(full, 1, string(bytes.TrimPrefix(data, utf8BOM)), depth)
+	if err != nil && r.includeErr == nil {
+		r.includeErr = err
+	}
+	return out, err
+}
+
+// arg returns the placeholder for a declared slash command argument.
+func (r *templateRenderer) arg(name string) (string, error) {
+	if !r.command {
+		return "", fmt.Errorf(messages.ConfigTemplateArgOutsideCommand)
+	}
+	for i, arg := range r.args {
+		if arg.Name == name {
+			return r.data.argumentStyle.Placeholder(r.args, i), nil
+		}
+	}
+	return "", fmt.Errorf(messages.ConfigTemplateArgUndeclaredFmt, name)
+}
+
+// displayPath names source relative to the repo root when possible.
+func (r *templateRenderer) displayPath(source string) string {
+	if r.data.RepoRoot != "" {
+		if rel, err := filepath.Rel(r.data.RepoRoot, source); err == nil && filepath.IsLocal(rel) {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return source
+}
+
+// templateError rewrites a text/template error as name:line[:col]: detail, shifting the line
+// by the front matter that precedes the template text in its source file.
+func templateError(name string, line int, err error) error {
+	detail := strings.TrimPrefix(err.Error(), "template: ")
+	rest, ok := strings.CutPrefix(detail, name+":")
+	if !ok {
+		return fmt.Errorf(messages.ConfigTemplateFailedFmt, name, detail)
+	}
+	end := strings.IndexFunc(rest, func(c rune) bool { return c < '0' || c > '9' })
+	if end <= 0 {
+		return fmt.Errorf(messages.ConfigTemplateFailedFmt, name, detail)
+	}
+	n, convErr := strconv.Atoi(rest[:end])
+	if convErr != nil {
+		return fmt.Errorf(messages.ConfigTemplateFailedFmt, name, detail)
+	}
+	return fmt.Errorf(messages.ConfigTemplateErrorFmt, name, n+max(line, 1)-1, rest[end:])
+}
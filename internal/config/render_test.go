@@ -0,0 +1,166 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func boolPtr(v bool) *bool { return &v }
+
+func TestNewTemplateData(t *testing.T) {
+	t.Setenv("DOCS_URL", "")
+	project := &ProjectConfig{
+		Root:          "/repo",
+		Profile:       "ci",
+		CommandsAllow: []string{"git status"},
+		Env:           map[string]string{"DOCS_URL": "https://docs.example", "TOKEN": "secret"},
+		Config: Config{
+			Templates: TemplatesConfig{Env: []string{"DOCS_URL"}},
+			MCP: MCPConfig{Servers: []MCPServer{
+				{ID: "github", Enabled: boolPtr(true)},
+				{ID: "search", Enabled: boolPtr(true), Clients: []string{"vscode"}},
+				{ID: "off", Enabled: boolPtr(false)},
+			}},
+		},
+	}
+
+	data := NewTemplateData(project, "claude")
+	if data.Client != "claude" || data.RepoRoot != "/repo" || data.Profile != "ci" {
+		t.Fatalf("unexpected data: %+v", data)
+	}
+	if strings.Join(data.MCPServers, ",") != "github" {
+		t.Fatalf("unexpected servers: %v", data.MCPServers)
+	}
+	if len(data.Env) != 1 || data.Env["DOCS_URL"] != "https://docs.example" {
+		t.Fatalf("expected only safe env keys, got %v", data.Env)
+	}
+	if got := NewTemplateData(project, "").MCPServers; strings.Join(got, ",") != "github,search" {
+		t.Fatalf("unexpected servers without a client: %v", got)
+	}
+
+	t.Setenv("DOCS_URL", "https://override.example")
+	if got := NewTemplateData(project, "claude").Env["DOCS_URL"]; got != "https://override.example" {
+		t.Fatalf("expected process env to win, got %q", got)
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	data := TemplateData{Client: "claude", MCPServers: []string{"github"}, Env: map[string]string{"TEAM": "core"}}
+	text := "{{ if mcpEnabled \"github\" }}Use the github MCP server.{{ end }}{{ if mcpEnabled \"search\" }}search{{ end }} {{ .Client }}/{{ .Env.TEAM }}"
+	got, err := renderTemplate("x.md", 1, text, data)
+	if err != nil {
+		t.Fatalf("renderTemplate error: %v", err)
+	}
+	if got != "Use the github MCP server. claude/core" {
+		t.Fatalf("unexpected output %q", got)
+	}
+
+	if got, err := renderTemplate("x.md", 1, "no actions here", data); err != nil || got != "no actions here" {
+		t.Fatalf("expected text without actions to pass through, got %q, %v", got, err)
+	}
+}
+
+func TestRenderTemplateErrorsPointAtSource(t *testing.T) {
+	root := t.TempDir()
+	data := TemplateData{RepoRoot: root, Env: map[string]string{}}
+	source := filepath.Join(root, ".agent-layer", "instructions", "00_x.md")
+
+	// Content starting at line 4 of the source (after front matter).
+	_, err := renderTemplate(source, 4, "ok\n{{ if }}", data)
+	if err == nil || !strings.HasPrefix(err.Error(), ".agent-layer/instructions/00_x.md:5:") {
+		t.Fatalf("expected parse error at line 5, got %v", err)
+	}
+
+	_, err = renderTemplate(source, 4, "line\n\nvalue {{ .Env.TOKEN }}", data)
+	if err == nil || !strings.HasPrefix(err.Error(), ".agent-layer/instructions/00_x.md:6:") || !strings.Contains(err.Error(), "TOKEN") {
+		t.Fatalf("expected missing env error at line 6, got %v", err)
+	}
+}
+
+func TestRenderTemplateInclude(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "snippets"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	files := map[string]string{
+		"snippets/outer.md": "outer {{ include \"snippets/inner.md\" }}",
+		"snippets/inner.md": "inner for {{ .Client }}",
+		"snippets/bad.md":   "first\n{{ .Missing }}",
+		"snippets/loop.md":  "{{ include \"snippets/loop.md\" }}",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	data := TemplateData{Client: "codex", RepoRoot: root}
+	source := filepath.Join(root, "AGENTS.src.md")
+
+	got, err := renderTemplate(source, 1, "{{ include \"snippets/outer.md\" }}", data)
+	if err != nil || got != "outer inner for codex" {
+		t.Fatalf("unexpected include output %q, %v", got, err)
+	}
+
+	_, err = renderTemplate(source, 1, "x\n{{ include \"snippets/bad.md\" }}", data)
+	if err == nil || !strings.HasPrefix(err.Error(), "snippets/bad.md:2:") {
+		t.Fatalf("expected error inside the snippet, got %v", err)
+	}
+
+	_, err = renderTemplate(source, 1, "{{ include \"../outside.md\" }}", data)
+	if err == nil || !strings.Contains(err.Error(), "stay inside it") {
+		t.Fatalf("expected outside-root error, got %v", err)
+	}
+
+	_, err = renderTemplate(source, 1, "{{ include \"snippets/loop.md\" }}", data)
+	if err == nil || !strings.Contains(err.Error(), "nested more than") {
+		t.Fatalf("expected include depth error, got %v", err)
+	}
+}
+
+func TestParsedLinesSkipFrontMatter(t *testing.T) {
+	file, err := parseInstruction("x.md", "---\nclients: [claude]\n---\n\nbody")
+	if err != nil || file.Line != 5 {
+		t.Fatalf("expected instruction body at line 5, got %d, %v", file.Line, err)
+	}
+	cmd, err := parseSlashCommand("---\ndescription: d\n---\n\nbody")
+	if err != nil || cmd.Line != 5 {
+		t.Fatalf("expected command body at line 5, got %d, %v", cmd.Line, err)
+	}
+}
+
+func TestTemplatesAreOptIn(t *testing.T) {
+	literal := "Use ${{ secrets.GITHUB_TOKEN }} in {{ .Client }}"
+	file, err := parseInstruction("x.md", "---\nclients: [claude]\n---\n"+literal)
+	if err != nil || file.Template {
+		t.Fatalf("expected a literal fragment, got %+v, %v", file, err)
+	}
+	cmd, err := parseSlashCommand("---\ndescription: d\n---\n" + literal)
+	if err != nil || cmd.Template {
+		t.Fatalf("expected a literal command, got %+v, %v", cmd, err)
+	}
+	data := TemplateData{Client: "claude"}
+	files, err := RenderInstructions([]InstructionFile{file}, data)
+	if err != nil || files[0].Content != literal {
+		t.Fatalf("expected fragment copied as is, got %q, %v", files[0].Content, err)
+	}
+	commands, err := RenderSlashCommands([]SlashCommand{cmd}, data)
+	if err != nil || commands[0].Body != literal {
+		t.Fatalf("expected command copied as is, got %q, %v", commands[0].Body, err)
+	}
+
+	file, err = parseInstruction("x.md", "---\ntemplate: true\n---\nfor {{ .Client }}")
+	if err != nil || !file.Template {
+		t.Fatalf("expected a template fragment, got %+v, %v", file, err)
+	}
+	if files, err := RenderInstructions([]InstructionFile{file}, data); err != nil || files[0].Content != "for claude" {
+		t.Fatalf("unexpected rendered fragment %+v, %v", files, err)
+	}
+	if cmd, err := parseSlashCommand("---\ndescription: d\narguments: [a]\n---\n{{ arg \"a\" }}"); err != nil || !cmd.Template {
+		t.Fatalf("expected arguments to turn templating on, got %+v, %v", cmd, err)
+	}
+	if _, err := parseInstruction("x.md", "---\ntemplate: yes please\n---\nbody"); err == nil || !strings.Contains(err.Error(), "true or false") {
+		t.Fatalf("expected template bool error, got %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const jsonSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// jsonSchema is the subset of JSON Schema (draft-07) used to describe config.toml.
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	AdditionalProperties any                    `json:"additionalProperties,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	Minimum              *int                   `json:"minimum,omitempty"`
+}
+
+// schemaDescriptions documents schema nodes by path.
+// Paths use dotted keys, [] for array items, and * for map values.
+var schemaDescriptions = map[string]string{
+	"config_version":                       "Config layout version; upgrade older configs with al migrate.",
+	"approvals":                            "Auto-approval behavior for shell commands and MCP tools.",
+	"approvals.mode":                       "What clients may run without prompting.",
+	"agents":                               "Per-client enablement and model selection.",
+	"agents.codex.reasoning_effort":        "Codex model_reasoning_effort.",
+	"agents.codex.slash_command_tools":     "Have the agent-layer MCP server offer slash commands as run_command_<name> tools (needs approvals.mode all or mcp).",
+	"agents.vscode.slash_command_tools":    "Add the agent-layer MCP server to .vscode/mcp.json, offering slash commands as run_command_<name> tools.",
+	"agents.claude.slash_commands":         "How slash commands reach Claude: the agent-layer MCP prompt server (mcp, default), .claude/commands/ files (native), or both.",
+	"agents.gemini.slash_commands":         "How slash commands reach Gemini: the agent-layer MCP prompt server (mcp), .gemini/commands/ files (native, default), or both.",
+	"mcp.prompt_server":                    "How clients reach the internal agent-layer MCP server.",
+	"mcp.prompt_server.http":               "host:port of a shared al mcp-prompts --http endpoint; generated client configs point at it instead of starting the server over stdio.",
+	"mcp.servers":                          "External MCP servers projected into client configs. Entries merge across config layers by id.",
+	"mcp.servers[].id":                     "Unique server id (\"agent-layer\" is reserved).",
+	"mcp.servers[].enabled":                "Whether the server is projected into client configs.",
+	"mcp.servers[].clients":                "Restrict the server to these clients; omit for all clients.",
+	"mcp.servers[].transport":              "Server transport.",
+	"mcp.servers[].http_transport":         "HTTP transport mode (http servers only); defaults to sse.",
+	"mcp.servers[].url":                    "Server URL (http transport).",
+	"mcp.servers[].headers":                "HTTP headers; values may reference ${ENV_VAR} placeholders.",
+	"mcp.servers[].command":                "Executable to launch (stdio transport).",
+	"mcp.servers[].args":                   "Command arguments (stdio transport).",
+	"mcp.servers[].env":                    "Environment for the server process (stdio transport).",
+	"secrets.providers":                    "Backends for ${secret:NAME} placeholders, tried in order.",
+	"secrets.providers[].type":             "exec runs a command, file reads one file per secret, keyring uses the OS keyring.",
+	"secrets.providers[].command":          "Command that prints the secret (exec); {name} in args is replaced with the secret name.",
+	"secrets.providers[].dir":              "Directory holding one file per secret (file).",
+	"secrets.providers[].service":          "Keyring service name (keyring); defaults to agent-layer.",
+	"secrets.providers[].file":             "JSON file used instead of the OS keyring (keyring), for tests and headless machines.",
+	"templates":                            "Rendering of instruction fragments and slash command bodies.",
+	"templates.env":                        "Env keys exposed to templates as {{ .Env.NAME }}; their values are written into generated files.",
+	"children":                             "Search for child .agent-layer/ directories in a monorepo.",
+	"children.exclude":                     "Directories not searched for child layers: repo-relative paths or globs, or names matched at any depth.",
+	"warnings":                             "Optional warning thresholds; omit a threshold to disable its warning.",
+	"profiles":                             "Named overlays selected with --profile or AL_PROFILE.",
+	"profiles.*.mcp.servers":               "Toggle existing MCP servers by id.",
+	"profiles.*.mcp.servers[].id":          "Id of an entry in mcp.servers.",
+	"profiles.*.mcp.servers[].enabled":     "Enablement while the profile is active.",
+	"profiles.*.approvals.mode":            "What clients may run without prompting while the profile is active.",
+	"profiles.*.agents":                    "Agent settings applied while the profile is active.",
+	"agents.gemini":                        "Gemini CLI.",
+	"agents.claude":                        "Claude Code CLI.",
+	"agents.codex":                         "Codex CLI.",
+	"agents.vscode":                        "VS Code (Copilot Chat and the Codex extension).",
+	"agents.antigravity":                   "Antigravity.",
+	"warnings.instruction_token_threshold": "Warn when generated instructions exceed this many tokens.",
+	"warnings.tokenizer":                   "How tokens are counted; BPE vocabularies are read from <user config dir>/tokenizers/<name>.tiktoken.",
+	"warnings.clients":                     "Per-client overrides keyed by client name; unset fields inherit the shared [warnings] values.",
+	"warnings.clients.*.tokenizer":         "How tokens are counted for this client.",
+}
+
+// schemaEnums lists allowed string values by path.
+var schemaEnums = map[string][]string{
+	"approvals.mode":                          {"all", "mcp", "commands", "none"},
+	"agents.claude.slash_commands":            {SlashCommandsMCP, SlashCommandsNative, SlashCommandsBoth},
+	"profiles.*.agents.claude.slash_commands": {SlashCommandsMCP, SlashCommandsNative, SlashCommandsBoth},
+	"agents.gemini.slash_commands":            {SlashCommandsMCP, SlashCommandsNative, SlashCommandsBoth},
+	"profiles.*.agents.gemini.slash_commands": {SlashCommandsMCP, SlashCommandsNative, SlashCommandsBoth},
+	"profiles.*.approvals.mode":               {"all", "mcp", "commands", "none"},
+	"mcp.servers[].transport":                 {"http", "stdio"},
+	"mcp.servers[].http_transport":            {"sse", "streamable"},
+	"mcp.servers[].clients[]":                 sortedSetKeys(validClients),
+	"secrets.providers[].type":                {"exec", "file", "keyring"},
+	"warnings.tokenizer":                      sortedSetKeys(validTokenizers),
+	"warnings.clients.*.tokenizer":            sortedSetKeys(validTokenizers),
+}
+
+// schemaRequired lists required properties by object path.
+var schemaRequired = map[string][]string{
+	"mcp.servers[]":            {"id"},
+	"secrets.providers[]":      {"type"},
+	"profiles.*.mcp.servers[]": {"id", "enabled"},
+}
+
+// JSONSchema returns a JSON Schema (draft-07) describing config.toml, generated from the Config types.
+// Every table rejects unknown keys, matching config validation. Required settings are not marked
+// required at the top level because each config layer may be partial.
+func JSONSchema() ([]byte, error) {
+	root := schemaFor(reflect.TypeOf(Config{}), "")
+	root.Schema = jsonSchemaDraft
+	root.Title = "Agent Layer config"
+	root.Description = "Schema for .agent-layer/config.toml, config.local.toml, and the user-global config."
+	data, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// schemaFor builds the schema node for t at path.
+func schemaFor(t reflect.Type, path string) *jsonSchema {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	node := &jsonSchema{
+		Description: schemaDescriptions[path],
+		Enum:        schemaEnums[path],
+		Required:    schemaRequired[path],
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		node.Type = "object"
+		node.AdditionalProperties = false
+		node.Properties = make(map[string]*jsonSchema, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("toml"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+			node.Properties[name] = schemaFor(field.Type, joinKey(path, name))
+		}
+	case reflect.Map:
+		node.Type = "object"
+		node.AdditionalProperties = schemaFor(t.Elem(), path+".*")
+	case reflect.Slice:
+		node.Type = "array"
+		node.Items = schemaFor(t.Elem(), path+"[]")
+	case reflect.String:
+		node.Type = "string"
+	case reflect.Bool:
+		node.Type = "boolean"
+	case reflect.Int, reflect.Int64:
+		node.Type = "integer"
+		if strings.HasPrefix(path, "warnings.") || path == "config_version" {
+			minimum := 1
+			node.Minimum = &minimum
+		}
+	}
+	return node
+}
+
+func sortedSetKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
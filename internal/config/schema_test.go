@@ -0,0 +1,50 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJSONSchema(t *testing.T) {
+	data, err := JSONSchema()
+	if err != nil {
+		t.Fatalf("JSONSchema error: %v", err)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+	if schema["$schema"] != jsonSchemaDraft || schema["additionalProperties"] != false {
+		t.Fatalf("unexpected root schema: %v", schema)
+	}
+
+	props := schema["properties"].(map[string]any)
+	mode := props["approvals"].(map[string]any)["properties"].(map[string]any)["mode"].(map[string]any)
+	if enum := mode["enum"].([]any); len(enum) != 4 || enum[0] != "all" {
+		t.Fatalf("unexpected approvals.mode enum: %v", enum)
+	}
+
+	servers := props["mcp"].(map[string]any)["properties"].(map[string]any)["servers"].(map[string]any)
+	item := servers["items"].(map[string]any)
+	if item["additionalProperties"] != false {
+		t.Fatalf("expected servers to reject unknown keys")
+	}
+	if required := item["required"].([]any); len(required) != 1 || required[0] != "id" {
+		t.Fatalf("unexpected server required: %v", required)
+	}
+	clients := item["properties"].(map[string]any)["clients"].(map[string]any)["items"].(map[string]any)
+	if len(clients["enum"].([]any)) != len(validClients) {
+		t.Fatalf("unexpected clients enum: %v", clients["enum"])
+	}
+
+	threshold := props["warnings"].(map[string]any)["properties"].(map[string]any)["mcp_server_threshold"].(map[string]any)
+	if threshold["type"] != "integer" || threshold["minimum"] != float64(1) {
+		t.Fatalf("unexpected threshold schema: %v", threshold)
+	}
+
+	profiles := props["profiles"].(map[string]any)
+	profile := profiles["additionalProperties"].(map[string]any)
+	if _, ok := profile["properties"].(map[string]any)["warnings"]; ok {
+		t.Fatalf("profiles must not allow warnings")
+	}
+}
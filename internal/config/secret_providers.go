@@ -0,0 +1,101 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/secrets"
+)
+
+// SecretProviders builds the provider chain configured in [[secrets.providers]].
+// Relative and ~ paths are resolved against repoRoot; exec commands run in repoRoot.
+func SecretProviders(cfg *Config, repoRoot string) (secrets.Chain, error) {
+	chain := make(secrets.Chain, 0, len(cfg.Secrets.Providers))
+	for i, provider := range cfg.Secrets.Providers {
+		label := fmt.Sprintf("secrets.providers[%d]", i)
+		switch provider.Type {
+		case "exec":
+			chain = append(chain, secrets.ExecProvider{Command: provider.Command, Args: provider.Args, Dir: repoRoot})
+		case "file":
+			dir, err := ExpandPath(provider.Dir, repoRoot)
+			if err != nil {
+				return nil, fmt.Errorf(messages.ConfigSecretProviderPathFmt, label, err)
+			}
+			chain = append(chain, secrets.FileProvider{Dir: dir})
+		case "keyring":
+			keyring := secrets.KeyringProvider{Service: provider.Service}
+			if provider.File != "" {
+				file, err := ExpandPath(provider.File, repoRoot)
+				if err != nil {
+					return nil, fmt.Errorf(messages.ConfigSecretProviderPathFmt, label, err)
+				}
+				keyring.File = file
+			}
+			chain = append(chain, keyring)
+		default:
+			return nil, fmt.Errorf(messages.ConfigSecretProviderTypeInvalidFmt, label)
+		}
+	}
+	return chain, nil
+}
+
+// ResolveSecrets resolves every ${secret:NAME} referenced by enabled MCP servers (including
+// references inside defaults). Lookup failures are recorded per secret rather than returned, so
+// callers such as doctor can report them; substitution treats them as missing.
+func ResolveSecrets(cfg *Config, repoRoot string) (map[string]secrets.Resolved, error) {
+	names := make(map[string]struct{})
+	for _, server := range cfg.MCP.Servers {
+		if server.Enabled == nil || !*server.Enabled {
+			continue
+		}
+		forEachServerValue(server, func(value string) {
+			for _, name := range ExtractEnvVarNames(value) {
+				if secret, ok := SecretName(name); ok {
+					names[secret] = struct{}{}
+				}
+			}
+		})
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	chain, err := SecretProviders(cfg, repoRoot)
+	if err != nil {
+		return nil, err
+	}
+	resolved := make(map[string]secrets.Resolved, len(names))
+	for _, name := range sortedSet(names) {
+		resolved[name] = chain.Resolve(name)
+	}
+	return resolved, nil
+}
+
+// PlaceholderEnv returns the values available to placeholder substitution: Env plus each
+// resolved secret keyed as secret:NAME.
+func (p *ProjectConfig) PlaceholderEnv() map[string]string {
+	if len(p.Secrets) == 0 {
+		return p.Env
+	}
+	merged := make(map[string]string, len(p.Env)+len(p.Secrets))
+	for key, value := range p.Env {
+		merged[key] = value
+	}
+	for name, secret := range p.Secrets {
+		if secret.Err == nil {
+			merged[SecretPlaceholderPrefix+name] = secret.Value
+		}
+	}
+	return merged
+}
+
+// SecretEnv returns resolved secrets keyed by name, for injection into a launched client's
+// environment (client configs reference them as ${NAME} so values never reach disk).
+func (p *ProjectConfig) SecretEnv() map[string]string {
+	env := make(map[string]string, len(p.Secrets))
+	for name, secret := range p.Secrets {
+		if secret.Err == nil {
+			env[name] = secret.Value
+		}
+	}
+	return env
+}
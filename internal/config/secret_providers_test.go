@@ -0,0 +1,87 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/secrets"
+)
+
+func TestSecretProviders(t *testing.T) {
+	root := t.TempDir()
+	cfg := &Config{Secrets: SecretsConfig{Providers: []SecretProviderConfig{
+		{Type: "exec", Command: "pass", Args: []string{"show", "{name}"}},
+		{Type: "file", Dir: "secrets"},
+		{Type: "keyring", Service: "svc", File: "keyring.json"},
+	}}}
+
+	chain, err := SecretProviders(cfg, root)
+	if err != nil {
+		t.Fatalf("SecretProviders error: %v", err)
+	}
+	want := secrets.Chain{
+		secrets.ExecProvider{Command: "pass", Args: []string{"show", "{name}"}, Dir: root},
+		secrets.FileProvider{Dir: filepath.Join(root, "secrets")},
+		secrets.KeyringProvider{Service: "svc", File: filepath.Join(root, "keyring.json")},
+	}
+	if !reflect.DeepEqual(chain, want) {
+		t.Fatalf("chain = %#v, want %#v", chain, want)
+	}
+
+	if _, err := SecretProviders(&Config{Secrets: SecretsConfig{Providers: []SecretProviderConfig{{Type: "vault"}}}}, root); err == nil {
+		t.Fatalf("expected invalid type error")
+	}
+}
+
+func TestResolveSecrets(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "secrets")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "API_TOKEN"), []byte("abc\n"), 0o600); err != nil {
+		t.Fatalf("write secret: %v", err)
+	}
+	enabled, disabled := true, false
+	cfg := &Config{
+		Secrets: SecretsConfig{Providers: []SecretProviderConfig{{Type: "file", Dir: "secrets"}}},
+		MCP: MCPConfig{Servers: []MCPServer{
+			{ID: "on", Enabled: &enabled, Headers: map[string]string{"Authorization": "Bearer ${secret:API_TOKEN}"}, URL: "https://x/${HOST:-${secret:MISSING}}"},
+			{ID: "off", Enabled: &disabled, URL: "https://x/${secret:IGNORED}"},
+		}},
+	}
+
+	resolved, err := ResolveSecrets(cfg, root)
+	if err != nil {
+		t.Fatalf("ResolveSecrets error: %v", err)
+	}
+	if len(resolved) != 2 {
+		t.Fatalf("expected 2 secrets, got %v", resolved)
+	}
+	if got := resolved["API_TOKEN"]; got.Err != nil || got.Value != "abc" || !strings.HasPrefix(got.Provider, "file") {
+		t.Fatalf("unexpected API_TOKEN: %+v", got)
+	}
+	if got := resolved["MISSING"]; got.Err == nil {
+		t.Fatalf("expected MISSING to be unresolved")
+	}
+
+	project := &ProjectConfig{Env: map[string]string{"HOST": "h"}, Secrets: resolved}
+	env := project.PlaceholderEnv()
+	if env["secret:API_TOKEN"] != "abc" || env["HOST"] != "h" {
+		t.Fatalf("unexpected placeholder env: %v", env)
+	}
+	if _, ok := env["secret:MISSING"]; ok {
+		t.Fatalf("unresolved secrets must not be in placeholder env")
+	}
+	if got := project.SecretEnv(); !reflect.DeepEqual(got, map[string]string{"API_TOKEN": "abc"}) {
+		t.Fatalf("unexpected secret env: %v", got)
+	}
+
+	none, err := ResolveSecrets(&Config{}, root)
+	if err != nil || none != nil {
+		t.Fatalf("expected no secrets, got %v %v", none, err)
+	}
+}
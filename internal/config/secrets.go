@@ -3,51 +3,89 @@ package config
 import "sort"
 
 // RequiredEnvVarsForMCPServer returns required env var names for a single MCP server.
+// Variables referenced only as ${VAR:-default} (or inside a default) are optional and omitted,
+// as are ${secret:NAME} references (see RequiredSecretsForMCPServer).
 // server is the MCP server definition; returns a sorted list of unique names.
 func RequiredEnvVarsForMCPServer(server MCPServer) []string {
+	return requiredNames(server, false)
+}
+
+// RequiredSecretsForMCPServer returns the names of required ${secret:NAME} references for a single
+// MCP server, without the secret: prefix; returns a sorted list of unique names.
+func RequiredSecretsForMCPServer(server MCPServer) []string {
+	return requiredNames(server, true)
+}
+
+// RequiredEnvVarsForMCPServers returns required env var names across all MCP servers.
+// servers is a list of MCP server definitions; returns a sorted list of unique names.
+func RequiredEnvVarsForMCPServers(servers []MCPServer) []string {
+	return unionNames(servers, RequiredEnvVarsForMCPServer)
+}
+
+// RequiredEnvVarServers maps each required env var name to the ids of the MCP servers that need it,
+// in config order.
+func RequiredEnvVarServers(servers []MCPServer) map[string][]string {
+	usage := make(map[string][]string)
+	for _, server := range servers {
+		for _, name := range RequiredEnvVarsForMCPServer(server) {
+			usage[name] = append(usage[name], server.ID)
+		}
+	}
+	return usage
+}
+
+// RequiredSecretsForMCPServers returns required secret names across all MCP servers.
+func RequiredSecretsForMCPServers(servers []MCPServer) []string {
+	return unionNames(servers, RequiredSecretsForMCPServer)
+}
+
+// requiredNames collects required placeholder names from every server field.
+// secrets selects ${secret:NAME} references (returned without the prefix) instead of env vars.
+func requiredNames(server MCPServer, secrets bool) []string {
 	seen := make(map[string]struct{})
 	add := func(value string) {
-		for _, name := range ExtractEnvVarNames(value) {
-			if IsBuiltInEnvVar(name) {
-				continue
+		for _, name := range RequiredEnvVarNames(value) {
+			secret, isSecret := SecretName(name)
+			switch {
+			case isSecret && secrets:
+				seen[secret] = struct{}{}
+			case !isSecret && !secrets && !IsBuiltInEnvVar(name):
+				seen[name] = struct{}{}
 			}
-			seen[name] = struct{}{}
 		}
 	}
+	forEachServerValue(server, add)
+	return sortedSet(seen)
+}
 
-	add(server.URL)
-	add(server.Command)
+// forEachServerValue calls fn with every server field that may contain placeholders.
+func forEachServerValue(server MCPServer, fn func(value string)) {
+	fn(server.URL)
+	fn(server.Command)
 	for _, arg := range server.Args {
-		add(arg)
+		fn(arg)
 	}
 	for _, value := range server.Headers {
-		add(value)
+		fn(value)
 	}
 	for _, value := range server.Env {
-		add(value)
-	}
-
-	if len(seen) == 0 {
-		return nil
-	}
-
-	names := make([]string, 0, len(seen))
-	for name := range seen {
-		names = append(names, name)
+		fn(value)
 	}
-	sort.Strings(names)
-	return names
 }
 
-// RequiredEnvVarsForMCPServers returns required env var names across all MCP servers.
-// servers is a list of MCP server definitions; returns a sorted list of unique names.
-func RequiredEnvVarsForMCPServers(servers []MCPServer) []string {
+// unionNames merges per-server name lists into a sorted list of unique names.
+func unionNames(servers []MCPServer, names func(MCPServer) []string) []string {
 	seen := make(map[string]struct{})
 	for _, server := range servers {
-		for _, name := range RequiredEnvVarsForMCPServer(server) {
+		for _, name := range names(server) {
 			seen[name] = struct{}{}
 		}
 	}
+	return sortedSet(seen)
+}
+
+// sortedSet returns the set's members in sorted order, or nil when empty.
+func sortedSet(seen map[string]struct{}) []string {
 	if len(seen) == 0 {
 		return nil
 	}
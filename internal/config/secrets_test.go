@@ -29,6 +29,15 @@ func TestRequiredEnvVarsForMCPServers(t *testing.T) {
 	assert.Equal(t, want, RequiredEnvVarsForMCPServers(servers))
 }
 
+func TestRequiredEnvVarServers(t *testing.T) {
+	servers := []MCPServer{
+		{ID: "a", URL: "https://example.com?token=${TOKEN}"},
+		{ID: "b", Headers: map[string]string{"X": "${TOKEN}"}, Env: map[string]string{"API_KEY": "${API_KEY:-none}"}},
+	}
+
+	assert.Equal(t, map[string][]string{"TOKEN": {"a", "b"}}, RequiredEnvVarServers(servers))
+}
+
 func TestRequiredEnvVarsForMCPServerEmpty(t *testing.T) {
 	server := MCPServer{
 		URL:     "https://example.com",
@@ -60,3 +69,18 @@ func TestRequiredEnvVarsForMCPServerIgnoresBuiltIns(t *testing.T) {
 	want := []string{"CUSTOM_PATH"}
 	assert.Equal(t, want, RequiredEnvVarsForMCPServer(server))
 }
+
+func TestRequiredSecretsForMCPServers(t *testing.T) {
+	servers := []MCPServer{
+		{
+			URL:     "https://example.com/${secret:URL_SECRET}?env=${ENV_TOKEN}",
+			Headers: map[string]string{"Authorization": "Bearer ${secret:API_TOKEN}"},
+			Env:     map[string]string{"OPTIONAL": "${secret:OPTIONAL:-none}"},
+		},
+		{Args: []string{"--token", "${secret:API_TOKEN}"}},
+	}
+
+	assert.Equal(t, []string{"API_TOKEN", "URL_SECRET"}, RequiredSecretsForMCPServers(servers))
+	assert.Equal(t, []string{"ENV_TOKEN"}, RequiredEnvVarsForMCPServers(servers))
+	assert.Nil(t, RequiredSecretsForMCPServer(MCPServer{URL: "https://example.com"}))
+}
@@ -0,0 +1,40 @@
+package config
+
+// Values of agents.claude.slash_commands and agents.gemini.slash_commands.
+const (
+	// SlashCommandsMCP serves slash commands through the agent-layer MCP prompt server.
+	SlashCommandsMCP = "mcp"
+	// SlashCommandsNative writes them as the client's own command files (.claude/commands/<name>.md,
+	// .gemini/commands/<name>.toml).
+	SlashCommandsNative = "native"
+	// SlashCommandsBoth does both.
+	SlashCommandsBoth = "both"
+)
+
+var validSlashCommandModes = map[string]struct{}{
+	SlashCommandsMCP:    {},
+	SlashCommandsNative: {},
+	SlashCommandsBoth:   {},
+}
+
+// MCPSlashCommands reports whether the agent-layer MCP server serves slash commands to Claude as
+// prompts. Claude gets the server either way, for its resources and memory tools.
+func (c ClaudeConfig) MCPSlashCommands() bool {
+	return c.SlashCommands != SlashCommandsNative
+}
+
+// NativeSlashCommands reports whether sync writes slash commands to .claude/commands/.
+func (c ClaudeConfig) NativeSlashCommands() bool {
+	return c.SlashCommands == SlashCommandsNative || c.SlashCommands == SlashCommandsBoth
+}
+
+// MCPSlashCommands reports whether the agent-layer MCP server serves slash commands to Gemini as
+// prompts. Gemini gets the server either way, for its resources and memory tools.
+func (c GeminiConfig) MCPSlashCommands() bool {
+	return c.SlashCommands == SlashCommandsMCP || c.SlashCommands == SlashCommandsBoth
+}
+
+// NativeSlashCommands reports whether sync writes slash commands to .gemini/commands/.
+func (c GeminiConfig) NativeSlashCommands() bool {
+	return c.SlashCommands != SlashCommandsMCP
+}
@@ -0,0 +1,41 @@
+package config
+
+import "testing"
+
+func TestClaudeSlashCommandModes(t *testing.T) {
+	cases := []struct {
+		mode         string
+		promptServer bool
+		native       bool
+	}{
+		{"", true, false},
+		{SlashCommandsMCP, true, false},
+		{SlashCommandsNative, false, true},
+		{SlashCommandsBoth, true, true},
+	}
+	for _, tc := range cases {
+		cfg := ClaudeConfig{SlashCommands: tc.mode}
+		if cfg.MCPSlashCommands() != tc.promptServer || cfg.NativeSlashCommands() != tc.native {
+			t.Fatalf("mode %q: got prompt server %v, native %v", tc.mode, cfg.MCPSlashCommands(), cfg.NativeSlashCommands())
+		}
+	}
+}
+
+func TestGeminiSlashCommandModes(t *testing.T) {
+	cases := []struct {
+		mode         string
+		promptServer bool
+		native       bool
+	}{
+		{"", false, true},
+		{SlashCommandsMCP, true, false},
+		{SlashCommandsNative, false, true},
+		{SlashCommandsBoth, true, true},
+	}
+	for _, tc := range cases {
+		cfg := GeminiConfig{SlashCommands: tc.mode}
+		if cfg.MCPSlashCommands() != tc.promptServer || cfg.NativeSlashCommands() != tc.native {
+			t.Fatalf("mode %q: got prompt server %v, native %v", tc.mode, cfg.MCPSlashCommands(), cfg.NativeSlashCommands())
+		}
+	}
+}
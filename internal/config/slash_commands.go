@@ -4,64 +4,108 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 
+	"gopkg.in/yaml.v3"
+
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
-// LoadSlashCommands reads .agent-layer/slash-commands/*.md in lexicographic order.
+// LoadSlashCommands reads .agent-layer/slash-commands/**/*.md in lexicographic order. A file in a
+// subdirectory is namespaced by it: git/commit.md is the command git:commit.
 func LoadSlashCommands(dir string) ([]SlashCommand, error) {
-	entries, err := os.ReadDir(dir)
-	if err != nil {
+	if _, err := os.ReadDir(dir); err != nil {
 		return nil, fmt.Errorf(messages.ConfigMissingSlashCommandsDirFmt, dir, err)
 	}
 
 	var names []string
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf(messages.ConfigFailedReadSlashCommandFmt, path, err)
 		}
-		name := entry.Name()
-		if strings.HasSuffix(name, ".md") {
-			names = append(names, name)
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
+			return nil
 		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		names = append(names, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	sort.Strings(names)
 
 	commands := make([]SlashCommand, 0, len(names))
 	for _, name := range names {
-		path := filepath.Join(dir, name)
+		path := filepath.Join(dir, filepath.FromSlash(name))
 		data, err := os.ReadFile(path)
 		if err != nil {
 			return nil, fmt.Errorf(messages.ConfigFailedReadSlashCommandFmt, path, err)
 		}
 		data = bytes.TrimPrefix(data, utf8BOM)
-		description, body, err := parseSlashCommand(string(data))
+		command, err := parseSlashCommand(string(data))
 		if err != nil {
 			return nil, fmt.Errorf(messages.ConfigInvalidSlashCommandFmt, path, err)
 		}
-		commands = append(commands, SlashCommand{
-			Name:        strings.TrimSuffix(name, ".md"),
-			Description: description,
-			Body:        body,
-			SourcePath:  path,
-		})
+		command.Name = strings.ReplaceAll(strings.TrimSuffix(name, ".md"), "/", namespaceSeparator)
+		command.SourcePath = path
+		commands = append(commands, command)
 	}
 
+	if err := checkSlashCommandNames(commands); err != nil {
+		return nil, err
+	}
 	return commands, nil
 }
 
-func parseSlashCommand(content string) (string, string, error) {
+// namespaceSeparator joins a command's subdirectories to its file name in Name.
+const namespaceSeparator = ":"
+
+// FlatName is Name with namespaces joined by "-" (git-commit), for clients without namespaced commands.
+func (c SlashCommand) FlatName() string {
+	return strings.ReplaceAll(c.Name, namespaceSeparator, "-")
+}
+
+// RelPath is the command's path under the slash-commands directory, without .md and with "/"
+// separators (git/commit), for clients that namespace commands by folder.
+func (c SlashCommand) RelPath() string {
+	return strings.ReplaceAll(c.Name, namespaceSeparator, "/")
+}
+
+// checkSlashCommandNames rejects commands whose names collide once namespaces are flattened, such as
+// git/commit.md and git-commit.md.
+func checkSlashCommandNames(commands []SlashCommand) error {
+	seen := make(map[string]SlashCommand, len(commands))
+	for _, cmd := range commands {
+		flat := cmd.FlatName()
+		if existing, ok := seen[flat]; ok {
+			if existing.Name == cmd.Name {
+				return fmt.Errorf(messages.ConfigChildSlashCommandDuplicateFmt, cmd.Name, cmd.SourcePath, existing.SourcePath)
+			}
+			return fmt.Errorf(messages.ConfigSlashCommandFlatNameCollisionFmt, existing.SourcePath, cmd.SourcePath, flat)
+		}
+		seen[flat] = cmd
+	}
+	return nil
+}
+
+// parseSlashCommand parses YAML front matter and body; Name and SourcePath are left empty.
+func parseSlashCommand(content string) (SlashCommand, error) {
 	scanner := bufio.NewScanner(strings.NewReader(content))
 	if !scanner.Scan() {
-		return "", "", fmt.Errorf(messages.ConfigSlashCommandMissingContent)
+		return SlashCommand{}, fmt.Errorf(messages.ConfigSlashCommandMissingContent)
 	}
 	if strings.TrimSpace(scanner.Text()) != "---" {
-		return "", "", fmt.Errorf(messages.ConfigSlashCommandMissingFrontMatter)
+		return SlashCommand{}, fmt.Errorf(messages.ConfigSlashCommandMissingFrontMatter)
 	}
 
 	var fmLines []string
@@ -75,7 +119,7 @@ func parseSlashCommand(content string) (string, string, error) {
 		fmLines = append(fmLines, line)
 	}
 	if !foundEnd {
-		return "", "", fmt.Errorf(messages.ConfigSlashCommandUnterminatedFrontMatter)
+		return SlashCommand{}, fmt.Errorf(messages.ConfigSlashCommandUnterminatedFrontMatter)
 	}
 
 	var bodyBuilder strings.Builder
@@ -84,47 +128,167 @@ func parseSlashCommand(content string) (string, string, error) {
 		bodyBuilder.WriteString("\n")
 	}
 	if err := scanner.Err(); err != nil {
-		return "", "", fmt.Errorf(messages.ConfigSlashCommandFailedReadContentFmt, err)
+		return SlashCommand{}, fmt.Errorf(messages.ConfigSlashCommandFailedReadContentFmt, err)
 	}
 
-	body := strings.TrimPrefix(bodyBuilder.String(), "\n")
+	body := bodyBuilder.String()
+	line := len(fmLines) + 3
+	if strings.HasPrefix(body, "\n") {
+		body = body[1:]
+		line++
+	}
 	body = strings.TrimRight(body, "\n")
 
-	description, err := parseDescription(fmLines)
+	cmd := SlashCommand{Body: body, Line: line}
+	if err := parseSlashCommandFrontMatter(fmLines, &cmd); err != nil {
+		return SlashCommand{}, err
+	}
+	if !cmd.Template {
+		return cmd, nil
+	}
+	if err := checkArgumentReferences(body, line, cmd.Arguments); err != nil {
+		return SlashCommand{}, err
+	}
+	return cmd, nil
+}
+
+// parseSlashCommandFrontMatter decodes the YAML front matter lines into cmd. Keys that no generated
+// format uses are recorded in cmd.UnknownKeys instead of failing, so they can be reported as warnings.
+func parseSlashCommandFrontMatter(lines []string, cmd *SlashCommand) error {
+	mapping, err := decodeFrontMatterMapping(quoteLegacyDescription(lines))
 	if err != nil {
-		return "", "", err
+		return err
+	}
+	if mapping == nil {
+		return fmt.Errorf(messages.ConfigSlashCommandMissingDescription)
+	}
+
+	seen := make(map[string]bool, len(mapping.Content)/2)
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key, value := mapping.Content[i].Value, mapping.Content[i+1]
+		if seen[key] {
+			return fmt.Errorf(messages.ConfigFrontMatterDuplicateKeyFmt, key)
+		}
+		seen[key] = true
+		switch key {
+		case "name":
+			// Accepted for compatibility; the filename is the command name.
+		case "description":
+			cmd.Description, err = decodeFrontMatterString(key, value)
+		case "clients":
+			if cmd.Clients, err = decodeFrontMatterList(key, value); err == nil {
+				err = validateClients(cmd.Clients)
+			}
+		case "arguments":
+			cmd.Arguments, err = decodeArguments(value)
+		case "model":
+			cmd.Model, err = decodeFrontMatterString(key, value)
+		case "allowed-tools":
+			if value.Kind == yaml.ScalarNode {
+				var tools string
+				tools, err = decodeFrontMatterString(key, value)
+				cmd.AllowedTools = []string{tools}
+			} else {
+				cmd.AllowedTools, err = decodeFrontMatterList(key, value)
+			}
+		case "argument-hint":
+			cmd.ArgumentHint, err = decodeFrontMatterString(key, value)
+		case "mode", "agent":
+			cmd.Agent, err = decodeFrontMatterString(key, value)
+		case "tools":
+			cmd.Tools, err = decodeFrontMatterList(key, value)
+		case "disable-model-invocation":
+			cmd.DisableModelInvocation, err = decodeFrontMatterBool(key, value)
+		case "template":
+			cmd.Template, err = decodeFrontMatterBool(key, value)
+		default:
+			cmd.UnknownKeys = append(cmd.UnknownKeys, key)
+		}
+		if err != nil {
+			return err
+		}
 	}
 
-	return description, body, nil
+	if !seen["description"] {
+		return fmt.Errorf(messages.ConfigSlashCommandMissingDescription)
+	}
+	if strings.TrimSpace(cmd.Description) == "" {
+		return fmt.Errorf(messages.ConfigSlashCommandDescriptionEmpty)
+	}
+	cmd.Description = strings.TrimSpace(cmd.Description)
+	// {{ arg "name" }} is template syntax, so declaring arguments turns templating on.
+	cmd.Template = cmd.Template || len(cmd.Arguments) > 0
+	return validateArguments(cmd.Arguments)
 }
 
-func parseDescription(lines []string) (string, error) {
-	for i := 0; i < len(lines); i++ {
-		line := strings.TrimSpace(lines[i])
-		if !strings.HasPrefix(line, "description:") {
+// quoteLegacyDescription double-quotes a plain description value that YAML would reject or cut
+// short, such as "description: Cleanup pass: remove dead code", which earlier releases accepted.
+func quoteLegacyDescription(lines []string) []string {
+	out := make([]string, len(lines))
+	copy(out, lines)
+	for i, line := range lines {
+		value, ok := strings.CutPrefix(line, "description:")
+		if !ok {
 			continue
 		}
-		value := strings.TrimSpace(strings.TrimPrefix(line, "description:"))
-		if value == "" {
-			return "", fmt.Errorf(messages.ConfigSlashCommandDescriptionEmpty)
+		value = strings.TrimSpace(value)
+		if value == "" || strings.ContainsAny(value[:1], "\"'[{|>&*!%@`#") {
+			continue
 		}
-		if value == ">-" || value == ">" || value == "|" || value == "|+" || value == "|-" {
-			var parts []string
-			for j := i + 1; j < len(lines); j++ {
-				if strings.HasPrefix(lines[j], "  ") {
-					parts = append(parts, strings.TrimSpace(strings.TrimPrefix(lines[j], "  ")))
-					continue
-				}
-				break
-			}
-			description := strings.TrimSpace(strings.Join(parts, " "))
-			if description == "" {
-				return "", fmt.Errorf(messages.ConfigSlashCommandDescriptionEmpty)
-			}
-			return description, nil
+		if strings.Contains(value, ": ") || strings.Contains(value, " #") || strings.HasSuffix(value, ":") {
+			out[i] = "description: " + strconv.Quote(value)
 		}
-		return strings.Trim(value, "\""), nil
 	}
+	return out
+}
 
-	return "", fmt.Errorf(messages.ConfigSlashCommandMissingDescription)
+// decodeFrontMatterMapping parses front matter lines as a YAML mapping; empty front matter returns nil.
+func decodeFrontMatterMapping(lines []string) (*yaml.Node, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(strings.Join(lines, "\n")), &root); err != nil {
+		return nil, fmt.Errorf(messages.ConfigFrontMatterInvalidFmt, strings.TrimPrefix(err.Error(), "yaml: "))
+	}
+	if len(root.Content) == 0 {
+		return nil, nil
+	}
+	if root.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf(messages.ConfigFrontMatterNotMapping)
+	}
+	return root.Content[0], nil
+}
+
+// decodeFrontMatterString reads a scalar front matter value; null reads as empty.
+func decodeFrontMatterString(key string, node *yaml.Node) (string, error) {
+	if node.Kind != yaml.ScalarNode {
+		return "", fmt.Errorf(messages.ConfigFrontMatterStringInvalidFmt, key)
+	}
+	if node.Tag == "!!null" {
+		return "", nil
+	}
+	return node.Value, nil
+}
+
+// decodeFrontMatterBool reads a true or false front matter value.
+func decodeFrontMatterBool(key string, node *yaml.Node) (bool, error) {
+	var value bool
+	if node.Kind != yaml.ScalarNode || node.Tag != "!!bool" {
+		return false, fmt.Errorf(messages.ConfigFrontMatterBoolInvalidFmt, key)
+	}
+	err := node.Decode(&value)
+	return value, err
+}
+
+// decodeFrontMatterList reads a list of scalars, written inline ([a, b]) or as a block.
+func decodeFrontMatterList(key string, node *yaml.Node) ([]string, error) {
+	if node.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf(messages.ConfigFrontMatterListInvalidFmt, key)
+	}
+	values := make([]string, 0, len(node.Content))
+	for _, item := range node.Content {
+		if item.Kind != yaml.ScalarNode {
+			return nil, fmt.Errorf(messages.ConfigFrontMatterListInvalidFmt, key)
+		}
+		values = append(values, item.Value)
+	}
+	return values, nil
 }
@@ -46,7 +46,7 @@ func TestLoadSlashCommands_ParseError(t *testing.T) {
 }
 
 func TestParseDescription_EmptyValue(t *testing.T) {
-	_, err := parseDescription([]string{"description:"})
+	_, err := frontMatterDescription([]string{"description:"})
 	if err == nil {
 		t.Fatalf("expected error for empty description")
 	}
@@ -56,7 +56,7 @@ func TestParseDescription_EmptyValue(t *testing.T) {
 }
 
 func TestParseDescription_EmptyBlock(t *testing.T) {
-	_, err := parseDescription([]string{"description: >-", "  "})
+	_, err := frontMatterDescription([]string{"description: >-", "  "})
 	if err == nil {
 		t.Fatalf("expected error for empty block")
 	}
@@ -66,7 +66,7 @@ func TestParseDescription_EmptyBlock(t *testing.T) {
 }
 
 func TestParseDescription_MissingDescription(t *testing.T) {
-	_, err := parseDescription([]string{"name: test", "version: 1"})
+	_, err := frontMatterDescription([]string{"name: test", "version: 1"})
 	if err == nil {
 		t.Fatalf("expected error for missing description")
 	}
@@ -76,7 +76,7 @@ func TestParseDescription_MissingDescription(t *testing.T) {
 }
 
 func TestParseDescription_EmptyLines(t *testing.T) {
-	_, err := parseDescription([]string{})
+	_, err := frontMatterDescription([]string{})
 	if err == nil {
 		t.Fatalf("expected error for empty lines")
 	}
@@ -93,7 +93,7 @@ func TestParseDescription_MultiLineBlockTerminates(t *testing.T) {
 		"  Second line of description",
 		"another_field: value",
 	}
-	desc, err := parseDescription(lines)
+	desc, err := frontMatterDescription(lines)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
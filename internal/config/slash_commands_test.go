@@ -47,30 +47,82 @@ func TestLoadSlashCommands(t *testing.T) {
 	}
 }
 
+func TestLoadSlashCommandsNamespaced(t *testing.T) {
+	dir := t.TempDir()
+	write := func(rel string) {
+		t.Helper()
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(slashCommandContent), 0o644); err != nil {
+			t.Fatalf("write %s: %v", rel, err)
+		}
+	}
+	write("review.md")
+	write("git/commit.md")
+	write("git/pr/open.md")
+	write("git/notes.txt")
+
+	commands, err := LoadSlashCommands(dir)
+	if err != nil {
+		t.Fatalf("LoadSlashCommands error: %v", err)
+	}
+	var names []string
+	for _, cmd := range commands {
+		names = append(names, cmd.Name+"="+cmd.FlatName()+"="+cmd.RelPath())
+	}
+	want := "git:commit=git-commit=git/commit,git:pr:open=git-pr-open=git/pr/open,review=review=review"
+	if strings.Join(names, ",") != want {
+		t.Fatalf("unexpected commands: %v", names)
+	}
+
+	write("git-commit.md")
+	_, err = LoadSlashCommands(dir)
+	if err == nil || !strings.Contains(err.Error(), `are both named "git-commit"`) {
+		t.Fatalf("expected flattened name collision, got %v", err)
+	}
+}
+
+func TestParseSlashCommandClients(t *testing.T) {
+	cmd, err := parseSlashCommand("---\ndescription: Review\nclients:\n  - vscode\n  - \"claude\"\n---\nBody")
+	if err != nil {
+		t.Fatalf("parseSlashCommand error: %v", err)
+	}
+	if strings.Join(cmd.Clients, ",") != "vscode,claude" || cmd.Description != "Review" || cmd.Body != "Body" {
+		t.Fatalf("unexpected command: %+v", cmd)
+	}
+
+	_, err = parseSlashCommand("---\ndescription: Review\nclients: claude\n---\nBody")
+	if err == nil || !strings.Contains(err.Error(), "clients must be a list") {
+		t.Fatalf("expected list error, got %v", err)
+	}
+}
+
 func TestParseSlashCommandErrors(t *testing.T) {
-	_, _, err := parseSlashCommand("")
+	_, err := parseSlashCommand("")
 	if err == nil || !strings.Contains(err.Error(), "missing content") {
 		t.Fatalf("expected missing content error, got %v", err)
 	}
 
-	_, _, err = parseSlashCommand("no front matter")
+	_, err = parseSlashCommand("no front matter")
 	if err == nil || !strings.Contains(err.Error(), "missing front matter") {
 		t.Fatalf("expected front matter error, got %v", err)
 	}
 
-	_, _, err = parseSlashCommand("---\nname: alpha\n")
+	_, err = parseSlashCommand("---\nname: alpha\n")
 	if err == nil || !strings.Contains(err.Error(), "unterminated front matter") {
 		t.Fatalf("expected unterminated front matter error, got %v", err)
 	}
 
-	_, _, err = parseSlashCommand("---\nname: alpha\n---\n")
+	_, err = parseSlashCommand("---\nname: alpha\n---\n")
 	if err == nil || !strings.Contains(err.Error(), "missing description") {
 		t.Fatalf("expected missing description error, got %v", err)
 	}
 }
 
 func TestParseDescriptionScalar(t *testing.T) {
-	desc, err := parseDescription([]string{"description: \"hello\""})
+	desc, err := frontMatterDescription([]string{"description: \"hello\""})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -80,8 +132,71 @@ func TestParseDescriptionScalar(t *testing.T) {
 }
 
 func TestParseDescriptionBlockEmpty(t *testing.T) {
-	_, err := parseDescription([]string{"description: >-"})
+	_, err := frontMatterDescription([]string{"description: >-"})
 	if err == nil {
 		t.Fatalf("expected error")
 	}
 }
+
+// frontMatterDescription parses front matter lines and returns the description.
+func frontMatterDescription(lines []string) (string, error) {
+	var cmd SlashCommand
+	err := parseSlashCommandFrontMatter(lines, &cmd)
+	return cmd.Description, err
+}
+
+func TestParseSlashCommandFrontMatterFields(t *testing.T) {
+	content := `---
+description: Cleanup pass: remove dead code # not a comment
+model: claude-sonnet-4-5
+allowed-tools: Bash(git add:*), Bash(git status:*)
+argument-hint: "[message]"
+mode: agent
+tools: ['search', fetch]
+disable-model-invocation: true
+owner: platform
+x-extra:
+  nested: true
+---
+Body
+`
+	cmd, err := parseSlashCommand(content)
+	if err != nil {
+		t.Fatalf("parseSlashCommand error: %v", err)
+	}
+	if cmd.Description != "Cleanup pass: remove dead code # not a comment" {
+		t.Fatalf("unexpected description %q", cmd.Description)
+	}
+	if cmd.Model != "claude-sonnet-4-5" || cmd.ArgumentHint != "[message]" || cmd.Agent != "agent" || !cmd.DisableModelInvocation {
+		t.Fatalf("unexpected scalar fields: %+v", cmd)
+	}
+	if strings.Join(cmd.AllowedTools, "|") != "Bash(git add:*), Bash(git status:*)" || strings.Join(cmd.Tools, "|") != "search|fetch" {
+		t.Fatalf("unexpected tool fields: %q %q", cmd.AllowedTools, cmd.Tools)
+	}
+	if strings.Join(cmd.UnknownKeys, ",") != "owner,x-extra" {
+		t.Fatalf("unexpected unknown keys %v", cmd.UnknownKeys)
+	}
+
+	cmd, err = parseSlashCommand("---\ndescription: d\nallowed-tools:\n  - Read\n  - Bash(ls:*)\n---\nBody")
+	if err != nil || strings.Join(cmd.AllowedTools, "|") != "Read|Bash(ls:*)" {
+		t.Fatalf("unexpected allowed-tools list %q, %v", cmd.AllowedTools, err)
+	}
+}
+
+func TestParseSlashCommandFrontMatterErrors(t *testing.T) {
+	cases := map[string]string{
+		"description: [a]":                              "description must be a string",
+		"description: d\nmodel: [a]":                    "model must be a string",
+		"description: d\ntools: search":                 "tools must be a list",
+		"description: d\nclients: [nope]":               `unknown client "nope"`,
+		"description: d\ndisable-model-invocation: yes": "disable-model-invocation must be true or false",
+		"description: d\n  bad: indent":                 "not valid YAML",
+		"- just\n- a list":                              "must be a mapping",
+		"description: d\ndescription: e":                "description is set more than once",
+	}
+	for frontMatter, want := range cases {
+		if _, err := parseSlashCommand("---\n" + frontMatter + "\n---\nBody"); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("%q: expected error containing %q, got %v", frontMatter, want, err)
+		}
+	}
+}
@@ -1,11 +1,38 @@
 package config
 
+import "github.com/conn-castle/agent-layer/internal/secrets"
+
 // Config is the root configuration loaded from .agent-layer/config.toml.
 type Config struct {
-	Approvals ApprovalsConfig `toml:"approvals"`
-	Agents    AgentsConfig    `toml:"agents"`
-	MCP       MCPConfig       `toml:"mcp"`
-	Warnings  WarningsConfig  `toml:"warnings"`
+	// ConfigVersion is the layout version; see CurrentConfigVersion and al migrate.
+	ConfigVersion int                      `toml:"config_version"`
+	Approvals     ApprovalsConfig          `toml:"approvals"`
+	Agents        AgentsConfig             `toml:"agents"`
+	MCP           MCPConfig                `toml:"mcp"`
+	Warnings      WarningsConfig           `toml:"warnings"`
+	Secrets       SecretsConfig            `toml:"secrets"`
+	Templates     TemplatesConfig          `toml:"templates"`
+	Children      ChildrenConfig           `toml:"children"`
+	Profiles      map[string]ProfileConfig `toml:"profiles"`
+}
+
+// ProfileConfig is a named overlay selected with --profile or AL_PROFILE.
+// Only approvals, agents, and MCP server enablement can be overridden.
+type ProfileConfig struct {
+	Approvals ApprovalsConfig  `toml:"approvals"`
+	Agents    AgentsConfig     `toml:"agents"`
+	MCP       ProfileMCPConfig `toml:"mcp"`
+}
+
+// ProfileMCPConfig overrides MCP server enablement by id.
+type ProfileMCPConfig struct {
+	Servers []ProfileMCPServer `toml:"servers"`
+}
+
+// ProfileMCPServer toggles an existing MCP server within a profile.
+type ProfileMCPServer struct {
+	ID      string `toml:"id"`
+	Enabled *bool  `toml:"enabled"`
 }
 
 // ApprovalsConfig controls auto-approval behavior per client.
@@ -15,11 +42,11 @@ type ApprovalsConfig struct {
 
 // AgentsConfig holds per-client enablement and model selection.
 type AgentsConfig struct {
-	Gemini      AgentConfig `toml:"gemini"`
-	Claude      AgentConfig `toml:"claude"`
-	Codex       CodexConfig `toml:"codex"`
-	VSCode      AgentConfig `toml:"vscode"`
-	Antigravity AgentConfig `toml:"antigravity"`
+	Gemini      GeminiConfig `toml:"gemini"`
+	Claude      ClaudeConfig `toml:"claude"`
+	Codex       CodexConfig  `toml:"codex"`
+	VSCode      VSCodeConfig `toml:"vscode"`
+	Antigravity AgentConfig  `toml:"antigravity"`
 }
 
 // AgentConfig is shared by agents that only need enablement and model selection.
@@ -28,16 +55,51 @@ type AgentConfig struct {
 	Model   string `toml:"model"`
 }
 
+// GeminiConfig extends AgentConfig with Gemini-specific settings.
+type GeminiConfig struct {
+	Enabled *bool  `toml:"enabled"`
+	Model   string `toml:"model"`
+	// SlashCommands selects how slash commands reach Gemini: mcp, native, or both. Empty means native.
+	SlashCommands string `toml:"slash_commands"`
+}
+
+// ClaudeConfig extends AgentConfig with Claude-specific settings.
+type ClaudeConfig struct {
+	Enabled *bool  `toml:"enabled"`
+	Model   string `toml:"model"`
+	// SlashCommands selects how slash commands reach Claude: mcp, native, or both. Empty means mcp.
+	SlashCommands string `toml:"slash_commands"`
+}
+
 // CodexConfig extends AgentConfig with Codex-specific settings.
 type CodexConfig struct {
 	Enabled         *bool  `toml:"enabled"`
 	Model           string `toml:"model"`
 	ReasoningEffort string `toml:"reasoning_effort"`
+	// SlashCommandTools has the agent-layer MCP server offer slash commands as tools.
+	SlashCommandTools bool `toml:"slash_command_tools"`
+}
+
+// VSCodeConfig extends AgentConfig with VS Code-specific settings.
+type VSCodeConfig struct {
+	Enabled *bool  `toml:"enabled"`
+	Model   string `toml:"model"`
+	// SlashCommandTools adds the agent-layer MCP server to .vscode/mcp.json, offering slash commands as tools.
+	SlashCommandTools bool `toml:"slash_command_tools"`
 }
 
 // MCPConfig contains the external MCP servers configuration.
 type MCPConfig struct {
 	Servers []MCPServer `toml:"servers"`
+	// PromptServer configures how clients reach the internal agent-layer MCP server.
+	PromptServer PromptServerConfig `toml:"prompt_server"`
+}
+
+// PromptServerConfig configures the internal agent-layer MCP server.
+type PromptServerConfig struct {
+	// HTTP is the host:port of a shared `al mcp-prompts --http` endpoint. Empty means each client
+	// session starts its own server over stdio.
+	HTTP string `toml:"http"`
 }
 
 // WarningsConfig configures optional warning thresholds. Nil disables warnings.
@@ -48,6 +110,49 @@ type WarningsConfig struct {
 	MCPServerToolsThreshold        *int `toml:"mcp_server_tools_threshold"`
 	MCPSchemaTokensTotalThreshold  *int `toml:"mcp_schema_tokens_total_threshold"`
 	MCPSchemaTokensServerThreshold *int `toml:"mcp_schema_tokens_server_threshold"`
+	// Tokenizer selects how tokens are counted; empty means heuristic.
+	Tokenizer string `toml:"tokenizer"`
+	// Clients overrides the thresholds and tokenizer for individual clients.
+	Clients map[string]ClientWarningsConfig `toml:"clients"`
+}
+
+// ClientWarningsConfig is a [warnings.clients.<client>] table. Set fields replace the shared
+// [warnings] values for that client; unset fields inherit them.
+type ClientWarningsConfig struct {
+	InstructionTokenThreshold      *int   `toml:"instruction_token_threshold"`
+	MCPServerThreshold             *int   `toml:"mcp_server_threshold"`
+	MCPToolsTotalThreshold         *int   `toml:"mcp_tools_total_threshold"`
+	MCPServerToolsThreshold        *int   `toml:"mcp_server_tools_threshold"`
+	MCPSchemaTokensTotalThreshold  *int   `toml:"mcp_schema_tokens_total_threshold"`
+	MCPSchemaTokensServerThreshold *int   `toml:"mcp_schema_tokens_server_threshold"`
+	Tokenizer                      string `toml:"tokenizer"`
+}
+
+// SecretsConfig configures the providers that resolve ${secret:NAME} placeholders.
+type SecretsConfig struct {
+	Providers []SecretProviderConfig `toml:"providers"`
+}
+
+// TemplatesConfig configures rendering of instruction fragments and slash command bodies.
+type TemplatesConfig struct {
+	// Env lists the env keys exposed to templates as {{ .Env.NAME }}; values are written into generated files.
+	Env []string `toml:"env"`
+}
+
+// ChildrenConfig configures the search for child .agent-layer/ directories in a monorepo.
+type ChildrenConfig struct {
+	// Exclude lists directories that are not searched, as repo-relative paths or names (see ExcludesDir).
+	Exclude []string `toml:"exclude"`
+}
+
+// SecretProviderConfig is one secret backend, tried in order; Type selects which fields apply.
+type SecretProviderConfig struct {
+	Type    string   `toml:"type"`
+	Command string   `toml:"command"`
+	Args    []string `toml:"args"`
+	Dir     string   `toml:"dir"`
+	Service string   `toml:"service"`
+	File    string   `toml:"file"`
 }
 
 // MCPServer defines a single MCP server entry.
@@ -65,25 +170,80 @@ type MCPServer struct {
 }
 
 // InstructionFile holds a single instruction fragment.
+// Clients limits the fragment to those clients (from a clients: front matter list); empty means all.
+// AppliesTo limits it to repo-relative path globs (from applies_to:); empty means repo-wide.
+// Line is the line of SourcePath where Content starts, after any front matter.
 type InstructionFile struct {
-	Name    string
-	Content string
+	Name       string
+	Content    string
+	Clients    []string
+	AppliesTo  []string
+	SourcePath string
+	Line       int
+	// Template is set by template: true front matter; only then is Content rendered as a template.
+	Template bool
+	// UnknownKeys lists front matter keys other than clients, applies_to, and template, in file order.
+	UnknownKeys []string
 }
 
 // SlashCommand represents a parsed slash command with metadata and body.
+// Clients limits the command to those clients; empty means all.
+// Arguments are the parameters the body references with {{ arg "name" }}, in declaration order.
+// Line is the line of SourcePath where Body starts.
 type SlashCommand struct {
 	Name        string
 	Description string
 	Body        string
 	SourcePath  string
+	Clients     []string
+	Arguments   []SlashCommandArgument
+	Line        int
+	// Template is set by template: true front matter or by declaring arguments; only then is Body
+	// rendered as a template.
+	Template bool
+
+	// Client-specific front matter, copied into the generated formats that support it.
+	Model                  string
+	AllowedTools           []string
+	ArgumentHint           string
+	Agent                  string
+	Tools                  []string
+	DisableModelInvocation bool
+	// UnknownKeys lists front matter keys that no generated format uses, in file order.
+	UnknownKeys []string
+}
+
+// SlashCommandArgument is one entry of a slash command's arguments: front matter list.
+type SlashCommandArgument struct {
+	Name        string
+	Description string
+	Required    bool
+	// Default is used when the argument is omitted; only optional arguments may have one.
+	Default string
+}
+
+// ChildLayer is a nested .agent-layer/ directory (without its own config.toml) in a monorepo.
+// It inherits the root config and adds instructions for its subtree plus extra slash commands.
+type ChildLayer struct {
+	// Dir is the child directory relative to the repo root, using forward slashes.
+	Dir string
+	// Path is the absolute child directory.
+	Path          string
+	Instructions  []InstructionFile
+	SlashCommands []SlashCommand
 }
 
 // ProjectConfig is the fully loaded configuration state for sync and launch.
+// SlashCommands includes commands from child layers; Children keeps their instructions.
+// Secrets holds the ${secret:NAME} references of enabled MCP servers, resolved by name.
 type ProjectConfig struct {
 	Config        Config
 	Env           map[string]string
+	Secrets       map[string]secrets.Resolved
 	Instructions  []InstructionFile
 	SlashCommands []SlashCommand
 	CommandsAllow []string
 	Root          string
+	Profile       string
+	Children      []ChildLayer
 }
@@ -2,6 +2,9 @@ package config
 
 import (
 	"fmt"
+	"path"
+	"sort"
+	"strings"
 
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
@@ -26,103 +29,253 @@ var validHTTPTransports = map[string]struct{}{
 	"streamable": {},
 }
 
+// fieldProblem is a validation failure tied to a dotted config key (used to locate it in a file).
+type fieldProblem struct {
+	Key     string
+	Message string
+}
+
 // Validate ensures the config is complete and consistent.
+// Every problem is reported; the returned error is a *ValidationError attributed to path.
 func (c *Config) Validate(path string) error {
+	fieldProblems := c.validationProblems()
+	if len(fieldProblems) == 0 {
+		return nil
+	}
+	problems := make([]Problem, 0, len(fieldProblems))
+	for _, problem := range fieldProblems {
+		problems = append(problems, Problem{File: path, Message: problem.Message})
+	}
+	return &ValidationError{Problems: problems}
+}
+
+// validationProblems collects every validation failure in a single pass.
+func (c *Config) validationProblems() []fieldProblem {
+	var problems []fieldProblem
+	add := func(key string, format string, args ...any) {
+		problems = append(problems, fieldProblem{Key: key, Message: fmt.Sprintf(format, args...)})
+	}
+
 	if _, ok := validApprovals[c.Approvals.Mode]; !ok {
-		return fmt.Errorf(messages.ConfigApprovalsModeInvalidFmt, path)
+		add("approvals.mode", messages.ConfigApprovalsModeInvalid)
 	}
 
-	if c.Agents.Gemini.Enabled == nil {
-		return fmt.Errorf(messages.ConfigGeminiEnabledRequiredFmt, path)
+	agents := []struct {
+		name    string
+		enabled *bool
+	}{
+		{"gemini", c.Agents.Gemini.Enabled},
+		{"claude", c.Agents.Claude.Enabled},
+		{"codex", c.Agents.Codex.Enabled},
+		{"vscode", c.Agents.VSCode.Enabled},
+		{"antigravity", c.Agents.Antigravity.Enabled},
 	}
-	if c.Agents.Claude.Enabled == nil {
-		return fmt.Errorf(messages.ConfigClaudeEnabledRequiredFmt, path)
+	for _, agent := range agents {
+		if agent.enabled == nil {
+			add("agents."+agent.name+".enabled", messages.ConfigAgentEnabledRequiredFmt, agent.name)
+		}
 	}
-	if c.Agents.Codex.Enabled == nil {
-		return fmt.Errorf(messages.ConfigCodexEnabledRequiredFmt, path)
+	if mode := c.Agents.Claude.SlashCommands; mode != "" {
+		if _, ok := validSlashCommandModes[mode]; !ok {
+			add("agents.claude.slash_commands", messages.ConfigClaudeSlashCommandsInvalid)
+		}
 	}
-	if c.Agents.VSCode.Enabled == nil {
-		return fmt.Errorf(messages.ConfigVSCodeEnabledRequiredFmt, path)
+	if mode := c.Agents.Gemini.SlashCommands; mode != "" {
+		if _, ok := validSlashCommandModes[mode]; !ok {
+			add("agents.gemini.slash_commands", messages.ConfigGeminiSlashCommandsInvalid)
+		}
 	}
-	if c.Agents.Antigravity.Enabled == nil {
-		return fmt.Errorf(messages.ConfigAntigravityEnabledRequiredFmt, path)
+
+	if addr := c.MCP.PromptServer.HTTP; addr != "" && !ValidPromptServerAddress(addr) {
+		add("mcp.prompt_server.http", messages.ConfigPromptServerHTTPInvalidFmt, addr)
 	}
 
 	for i, server := range c.MCP.Servers {
+		label := serverLabel(i, server.ID)
 		if server.ID == "" {
-			return fmt.Errorf(messages.ConfigMcpServerIDRequiredFmt, path, i)
+			add(label+".id", messages.ConfigMcpServerIDRequiredFmt, label)
 		}
 		if server.ID == "agent-layer" {
-			return fmt.Errorf(messages.ConfigMcpServerIDReservedFmt, path, i)
+			add(label+".id", messages.ConfigMcpServerIDReservedFmt, label)
 		}
 		if server.Enabled == nil {
-			return fmt.Errorf(messages.ConfigMcpServerEnabledRequiredFmt, path, i)
+			add(label+".enabled", messages.ConfigMcpServerEnabledRequiredFmt, label)
 		}
 		switch server.Transport {
 		case "http":
 			if server.URL == "" {
-				return fmt.Errorf(messages.ConfigMcpServerURLRequiredFmt, path, i)
+				add(label+".url", messages.ConfigMcpServerURLRequiredFmt, label)
 			}
 			if server.HTTPTransport != "" {
 				if _, ok := validHTTPTransports[server.HTTPTransport]; !ok {
-					return fmt.Errorf(messages.ConfigMcpServerHTTPTransportInvalidFmt, path, i)
+					add(label+".http_transport", messages.ConfigMcpServerHTTPTransportInvalidFmt, label)
 				}
 			}
 			if server.Command != "" || len(server.Args) > 0 {
-				return fmt.Errorf(messages.ConfigMcpServerCommandNotAllowedFmt, path, i)
+				add(label+".command", messages.ConfigMcpServerCommandNotAllowedFmt, label)
 			}
 			if len(server.Env) > 0 {
-				return fmt.Errorf(messages.ConfigMcpServerEnvNotAllowedFmt, path, i)
+				add(label+".env", messages.ConfigMcpServerEnvNotAllowedFmt, label)
 			}
 		case "stdio":
 			if server.HTTPTransport != "" {
-				return fmt.Errorf(messages.ConfigMcpServerHTTPTransportNotAllowedFmt, path, i)
+				add(label+".http_transport", messages.ConfigMcpServerHTTPTransportNotAllowedFmt, label)
 			}
 			if server.Command == "" {
-				return fmt.Errorf(messages.ConfigMcpServerCommandRequiredFmt, path, i)
+				add(label+".command", messages.ConfigMcpServerCommandRequiredFmt, label)
 			}
 			if server.URL != "" {
-				return fmt.Errorf(messages.ConfigMcpServerURLNotAllowedFmt, path, i)
+				add(label+".url", messages.ConfigMcpServerURLNotAllowedFmt, label)
 			}
 			if len(server.Headers) > 0 {
-				return fmt.Errorf(messages.ConfigMcpServerHeadersNotAllowedFmt, path, i)
+				add(label+".headers", messages.ConfigMcpServerHeadersNotAllowedFmt, label)
 			}
 		default:
-			return fmt.Errorf(messages.ConfigMcpServerTransportInvalidFmt, path, i)
+			add(label+".transport", messages.ConfigMcpServerTransportInvalidFmt, label)
 		}
 
 		for _, client := range server.Clients {
 			if _, ok := validClients[client]; !ok {
-				return fmt.Errorf(messages.ConfigMcpServerClientInvalidFmt, path, i, client)
+				add(label+".clients", messages.ConfigMcpServerClientInvalidFmt, label, client)
 			}
 		}
 	}
 
-	if err := validateWarnings(path, c.Warnings); err != nil {
-		return err
+	problems = append(problems, validateWarnings(c.Warnings)...)
+	problems = append(problems, validateSecretProviders(c.Secrets)...)
+	for _, key := range c.Templates.Env {
+		if !IsValidEnvVarName(key) {
+			add("templates.env", messages.ConfigTemplateEnvInvalidFmt, key)
+		}
 	}
+	for _, pattern := range c.Children.Exclude {
+		if _, err := path.Match(strings.TrimSuffix(pattern, "/"), ""); err != nil || pattern == "" || strings.HasPrefix(pattern, "/") || strings.Contains(pattern, "\\") {
+			add("children.exclude", messages.ConfigChildrenExcludeInvalidFmt, pattern)
+		}
+	}
+	problems = append(problems, validateProfiles(c)...)
+	return problems
+}
 
-	return nil
+// serverLabel names an MCP server in messages and position lookups: by id when set, else by index.
+func serverLabel(index int, id string) string {
+	if id == "" {
+		return fmt.Sprintf("mcp.servers[%d]", index)
+	}
+	return "mcp.servers." + id
 }
 
-// validateWarnings validates optional warning thresholds.
-// path is used for error context; warnings carries the thresholds; returns an error when a threshold is non-positive.
-func validateWarnings(path string, warnings WarningsConfig) error {
-	thresholds := []struct {
-		name  string
-		value *int
-	}{
-		{"warnings.instruction_token_threshold", warnings.InstructionTokenThreshold},
-		{"warnings.mcp_server_threshold", warnings.MCPServerThreshold},
-		{"warnings.mcp_tools_total_threshold", warnings.MCPToolsTotalThreshold},
-		{"warnings.mcp_server_tools_threshold", warnings.MCPServerToolsThreshold},
-		{"warnings.mcp_schema_tokens_total_threshold", warnings.MCPSchemaTokensTotalThreshold},
-		{"warnings.mcp_schema_tokens_server_threshold", warnings.MCPSchemaTokensServerThreshold},
+// validateWarnings validates optional warning thresholds and tokenizers.
+// warnings carries the settings; returns a problem for each non-positive threshold, unknown
+// tokenizer, and unknown client table.
+func validateWarnings(warnings WarningsConfig) []fieldProblem {
+	problems := validateWarningFields("warnings", warnings.Tokenizer, warnings.InstructionTokenThreshold,
+		warnings.MCPServerThreshold, warnings.MCPToolsTotalThreshold, warnings.MCPServerToolsThreshold,
+		warnings.MCPSchemaTokensTotalThreshold, warnings.MCPSchemaTokensServerThreshold)
+	clients := make([]string, 0, len(warnings.Clients))
+	for client := range warnings.Clients {
+		clients = append(clients, client)
 	}
-	for _, threshold := range thresholds {
-		if threshold.value != nil && *threshold.value <= 0 {
-			return fmt.Errorf(messages.ConfigWarningThresholdInvalidFmt, path, threshold.name)
+	sort.Strings(clients)
+	for _, client := range clients {
+		prefix := "warnings.clients." + client
+		if _, ok := validClients[client]; !ok {
+			problems = append(problems, fieldProblem{
+				Key:     prefix,
+				Message: fmt.Sprintf(messages.ConfigWarningClientInvalidFmt, client, strings.Join(sortedSetKeys(validClients), ", ")),
+			})
+			continue
+		}
+		c := warnings.Clients[client]
+		problems = append(problems, validateWarningFields(prefix, c.Tokenizer, c.InstructionTokenThreshold,
+			c.MCPServerThreshold, c.MCPToolsTotalThreshold, c.MCPServerToolsThreshold,
+			c.MCPSchemaTokensTotalThreshold, c.MCPSchemaTokensServerThreshold)...)
+	}
+	return problems
+}
+
+// validateWarningFields checks one [warnings] or [warnings.clients.<client>] table under prefix.
+// thresholds are in WarningsConfig field order.
+func validateWarningFields(prefix string, tokenizer string, thresholds ...*int) []fieldProblem {
+	names := []string{
+		"instruction_token_threshold",
+		"mcp_server_threshold",
+		"mcp_tools_total_threshold",
+		"mcp_server_tools_threshold",
+		"mcp_schema_tokens_total_threshold",
+		"mcp_schema_tokens_server_threshold",
+	}
+	var problems []fieldProblem
+	for i, threshold := range thresholds {
+		if threshold != nil && *threshold <= 0 {
+			name := prefix + "." + names[i]
+			problems = append(problems, fieldProblem{
+				Key:     name,
+				Message: fmt.Sprintf(messages.ConfigWarningThresholdInvalidFmt, name),
+			})
+		}
+	}
+	if _, ok := validTokenizers[tokenizer]; tokenizer != "" && !ok {
+		name := prefix + ".tokenizer"
+		problems = append(problems, fieldProblem{
+			Key:     name,
+			Message: fmt.Sprintf(messages.ConfigWarningTokenizerInvalidFmt, name, strings.Join(sortedSetKeys(validTokenizers), ", ")),
+		})
+	}
+	return problems
+}
+
+// secretProviderFields lists the fields each secret provider type uses, required ones first.
+var secretProviderFields = map[string]struct {
+	required []string
+	optional []string
+}{
+	"exec":    {required: []string{"command"}, optional: []string{"args"}},
+	"file":    {required: []string{"dir"}},
+	"keyring": {optional: []string{"service", "file"}},
+}
+
+// validateSecretProviders checks each provider's type and that it sets only the fields its type uses.
+func validateSecretProviders(cfg SecretsConfig) []fieldProblem {
+	var problems []fieldProblem
+	for i, provider := range cfg.Providers {
+		label := fmt.Sprintf("secrets.providers[%d]", i)
+		fields, ok := secretProviderFields[provider.Type]
+		if !ok {
+			problems = append(problems, fieldProblem{
+				Key:     label + ".type",
+				Message: fmt.Sprintf(messages.ConfigSecretProviderTypeInvalidFmt, label),
+			})
+			continue
+		}
+		set := map[string]bool{
+			"command": provider.Command != "",
+			"args":    len(provider.Args) > 0,
+			"dir":     provider.Dir != "",
+			"service": provider.Service != "",
+			"file":    provider.File != "",
+		}
+		used := make(map[string]bool)
+		for _, field := range fields.required {
+			used[field] = true
+			if !set[field] {
+				problems = append(problems, fieldProblem{
+					Key:     label + "." + field,
+					Message: fmt.Sprintf(messages.ConfigSecretProviderFieldRequiredFmt, label, field, provider.Type),
+				})
+			}
+		}
+		for _, field := range fields.optional {
+			used[field] = true
+		}
+		for _, field := range []string{"command", "args", "dir", "service", "file"} {
+			if set[field] && !used[field] {
+				problems = append(problems, fieldProblem{
+					Key:     label + "." + field,
+					Message: fmt.Sprintf(messages.ConfigSecretProviderFieldNotAllowedFmt, label, field, provider.Type),
+				})
+			}
 		}
 	}
-	return nil
+	return problems
 }
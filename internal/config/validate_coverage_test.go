@@ -10,10 +10,10 @@ func TestValidate_TopLevelErrors(t *testing.T) {
 	valid := Config{
 		Approvals: ApprovalsConfig{Mode: "all"},
 		Agents: AgentsConfig{
-			Gemini:      AgentConfig{Enabled: &enabled},
-			Claude:      AgentConfig{Enabled: &enabled},
+			Gemini:      GeminiConfig{Enabled: &enabled},
+			Claude:      ClaudeConfig{Enabled: &enabled},
 			Codex:       CodexConfig{Enabled: &enabled},
-			VSCode:      AgentConfig{Enabled: &enabled},
+			VSCode:      VSCodeConfig{Enabled: &enabled},
 			Antigravity: AgentConfig{Enabled: &enabled},
 		},
 	}
@@ -89,10 +89,10 @@ func TestValidate_MCPServerErrors(t *testing.T) {
 	baseConfig := Config{
 		Approvals: ApprovalsConfig{Mode: "all"},
 		Agents: AgentsConfig{
-			Gemini:      AgentConfig{Enabled: &enabled},
-			Claude:      AgentConfig{Enabled: &enabled},
+			Gemini:      GeminiConfig{Enabled: &enabled},
+			Claude:      ClaudeConfig{Enabled: &enabled},
 			Codex:       CodexConfig{Enabled: &enabled},
-			VSCode:      AgentConfig{Enabled: &enabled},
+			VSCode:      VSCodeConfig{Enabled: &enabled},
 			Antigravity: AgentConfig{Enabled: &enabled},
 		},
 	}
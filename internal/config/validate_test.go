@@ -11,10 +11,10 @@ func TestValidateConfigErrors(t *testing.T) {
 	valid := Config{
 		Approvals: ApprovalsConfig{Mode: "all"},
 		Agents: AgentsConfig{
-			Gemini:      AgentConfig{Enabled: &trueVal},
-			Claude:      AgentConfig{Enabled: &trueVal},
+			Gemini:      GeminiConfig{Enabled: &trueVal},
+			Claude:      ClaudeConfig{Enabled: &trueVal},
 			Codex:       CodexConfig{Enabled: &trueVal},
-			VSCode:      AgentConfig{Enabled: &trueVal},
+			VSCode:      VSCodeConfig{Enabled: &trueVal},
 			Antigravity: AgentConfig{Enabled: &falseVal},
 		},
 		MCP: MCPConfig{},
@@ -42,6 +42,11 @@ func TestValidateConfigErrors(t *testing.T) {
 			}),
 			wantErr: "mcp.servers[0].id",
 		},
+		{
+			name:    "invalid prompt server address",
+			cfg:     withPromptServerHTTP(valid, "localhost"),
+			wantErr: "mcp.prompt_server.http must be host:port",
+		},
 		{
 			name: "reserved server id",
 			cfg: withServers(valid, []MCPServer{
@@ -126,6 +131,21 @@ func TestValidateConfigErrors(t *testing.T) {
 			}),
 			wantErr: "invalid client",
 		},
+		{
+			name:    "invalid template env key",
+			cfg:     withTemplateEnv(valid, []string{"NOT-A-KEY"}),
+			wantErr: "templates.env: invalid env var name",
+		},
+		{
+			name:    "absolute children exclude",
+			cfg:     withChildrenExclude(valid, []string{"/abs"}),
+			wantErr: "children.exclude: \"/abs\" must be a repo-relative",
+		},
+		{
+			name:    "malformed children exclude",
+			cfg:     withChildrenExclude(valid, []string{"web/[a"}),
+			wantErr: "children.exclude: \"web/[a\"",
+		},
 	}
 
 	for _, tc := range cases {
@@ -137,6 +157,11 @@ func TestValidateConfigErrors(t *testing.T) {
 	}
 }
 
+func withPromptServerHTTP(cfg Config, addr string) Config {
+	cfg.MCP.PromptServer.HTTP = addr
+	return cfg
+}
+
 func withApprovals(cfg Config, mode string) Config {
 	cfg.Approvals.Mode = mode
 	return cfg
@@ -152,15 +177,25 @@ func withServers(cfg Config, servers []MCPServer) Config {
 	return cfg
 }
 
+func withTemplateEnv(cfg Config, keys []string) Config {
+	cfg.Templates.Env = keys
+	return cfg
+}
+
+func withChildrenExclude(cfg Config, exclude []string) Config {
+	cfg.Children.Exclude = exclude
+	return cfg
+}
+
 func TestValidateWarningsThresholds(t *testing.T) {
 	enabled := true
 	base := Config{
 		Approvals: ApprovalsConfig{Mode: "all"},
 		Agents: AgentsConfig{
-			Gemini:      AgentConfig{Enabled: &enabled},
-			Claude:      AgentConfig{Enabled: &enabled},
+			Gemini:      GeminiConfig{Enabled: &enabled},
+			Claude:      ClaudeConfig{Enabled: &enabled},
 			Codex:       CodexConfig{Enabled: &enabled},
-			VSCode:      AgentConfig{Enabled: &enabled},
+			VSCode:      VSCodeConfig{Enabled: &enabled},
 			Antigravity: AgentConfig{Enabled: &enabled},
 		},
 	}
@@ -214,6 +249,48 @@ func TestValidateWarningsThresholds(t *testing.T) {
 			},
 			errContains: "warnings.mcp_schema_tokens_server_threshold",
 		},
+		{
+			name: "tokenizer",
+			set: func(cfg *Config) {
+				cfg.Warnings.Tokenizer = "gpt2"
+			},
+			errContains: "warnings.tokenizer must be one of cl100k_base, heuristic, o200k_base",
+		},
+		{
+			name: "client threshold",
+			set: func(cfg *Config) {
+				cfg.Warnings.Clients = map[string]ClientWarningsConfig{"claude": {MCPServerThreshold: intPtr(0)}}
+			},
+			errContains: "warnings.clients.claude.mcp_server_threshold",
+		},
+		{
+			name: "client tokenizer",
+			set: func(cfg *Config) {
+				cfg.Warnings.Clients = map[string]ClientWarningsConfig{"codex": {Tokenizer: "gpt2"}}
+			},
+			errContains: "warnings.clients.codex.tokenizer",
+		},
+		{
+			name: "unknown client",
+			set: func(cfg *Config) {
+				cfg.Warnings.Clients = map[string]ClientWarningsConfig{"cursor": {}}
+			},
+			errContains: "warnings.clients.cursor is not a client",
+		},
+		{
+			name: "claude slash commands",
+			set: func(cfg *Config) {
+				cfg.Agents.Claude.SlashCommands = "skills"
+			},
+			errContains: "agents.claude.slash_commands must be one of mcp, native, both",
+		},
+		{
+			name: "gemini slash commands",
+			set: func(cfg *Config) {
+				cfg.Agents.Gemini.SlashCommands = "skills"
+			},
+			errContains: "agents.gemini.slash_commands must be one of mcp, native, both",
+		},
 	}
 
 	for _, tc := range tests {
@@ -227,3 +304,50 @@ func TestValidateWarningsThresholds(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateSecretProviders(t *testing.T) {
+	enabled := true
+	base := Config{
+		Approvals: ApprovalsConfig{Mode: "all"},
+		Agents: AgentsConfig{
+			Gemini:      GeminiConfig{Enabled: &enabled},
+			Claude:      ClaudeConfig{Enabled: &enabled},
+			Codex:       CodexConfig{Enabled: &enabled},
+			VSCode:      VSCodeConfig{Enabled: &enabled},
+			Antigravity: AgentConfig{Enabled: &enabled},
+		},
+	}
+
+	valid := base
+	valid.Secrets.Providers = []SecretProviderConfig{
+		{Type: "exec", Command: "pass", Args: []string{"show", "{name}"}},
+		{Type: "file", Dir: "~/.secrets"},
+		{Type: "keyring"},
+		{Type: "keyring", Service: "svc", File: "keyring.json"},
+	}
+	if err := valid.Validate("config.toml"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	invalid := base
+	invalid.Secrets.Providers = []SecretProviderConfig{
+		{Type: "vault"},
+		{Type: "exec"},
+		{Type: "file", Dir: "d", Command: "x"},
+		{Type: "keyring", Args: []string{"a"}},
+	}
+	err := invalid.Validate("config.toml")
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	for _, want := range []string{
+		"secrets.providers[0].type must be one of exec, file, keyring",
+		"secrets.providers[1].command is required for exec providers",
+		"secrets.providers[2].command is not used by file providers",
+		"secrets.providers[3].args is not used by keyring providers",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in %v", want, err)
+		}
+	}
+}
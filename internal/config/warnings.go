@@ -0,0 +1,48 @@
+package config
+
+// HeuristicTokenizer is the default [warnings] tokenizer: a byte and rune count estimate that needs no vocabulary.
+const HeuristicTokenizer = "heuristic"
+
+// validTokenizers lists the [warnings] tokenizer values.
+var validTokenizers = map[string]struct{}{
+	HeuristicTokenizer: {},
+	"cl100k_base":      {},
+	"o200k_base":       {},
+}
+
+// ForClient returns the warnings settings that apply to client: its [warnings.clients.<client>]
+// values over the shared ones. The result has no Clients and always names a tokenizer.
+func (w WarningsConfig) ForClient(client string) WarningsConfig {
+	resolved := w
+	resolved.Clients = nil
+	if override, ok := w.Clients[client]; ok {
+		for _, field := range []struct {
+			dst **int
+			src *int
+		}{
+			{&resolved.InstructionTokenThreshold, override.InstructionTokenThreshold},
+			{&resolved.MCPServerThreshold, override.MCPServerThreshold},
+			{&resolved.MCPToolsTotalThreshold, override.MCPToolsTotalThreshold},
+			{&resolved.MCPServerToolsThreshold, override.MCPServerToolsThreshold},
+			{&resolved.MCPSchemaTokensTotalThreshold, override.MCPSchemaTokensTotalThreshold},
+			{&resolved.MCPSchemaTokensServerThreshold, override.MCPSchemaTokensServerThreshold},
+		} {
+			if field.src != nil {
+				*field.dst = field.src
+			}
+		}
+		if override.Tokenizer != "" {
+			resolved.Tokenizer = override.Tokenizer
+		}
+	}
+	if resolved.Tokenizer == "" {
+		resolved.Tokenizer = HeuristicTokenizer
+	}
+	return resolved
+}
+
+// TokenizerDir returns the directory holding BPE vocabularies (<name>.tiktoken) next to the user config.
+// It is empty when no home directory is available.
+func TokenizerDir() string {
+	return userConfigFile("tokenizers")
+}
@@ -0,0 +1,62 @@
+package config
+
+import "testing"
+
+func TestWarningsForClient(t *testing.T) {
+	shared, tools, claudeShared := 10, 20, 5
+	warnings := WarningsConfig{
+		InstructionTokenThreshold: &shared,
+		MCPToolsTotalThreshold:    &tools,
+		Tokenizer:                 "o200k_base",
+		Clients: map[string]ClientWarningsConfig{
+			"claude": {InstructionTokenThreshold: &claudeShared, Tokenizer: "cl100k_base"},
+		},
+	}
+
+	claude := warnings.ForClient("claude")
+	if *claude.InstructionTokenThreshold != 5 || *claude.MCPToolsTotalThreshold != 20 || claude.Tokenizer != "cl100k_base" || claude.Clients != nil {
+		t.Fatalf("unexpected claude settings: %+v", claude)
+	}
+	codex := warnings.ForClient("codex")
+	if *codex.InstructionTokenThreshold != 10 || codex.Tokenizer != "o200k_base" || codex.MCPServerThreshold != nil {
+		t.Fatalf("unexpected codex settings: %+v", codex)
+	}
+	if got := (WarningsConfig{}).ForClient("").Tokenizer; got != HeuristicTokenizer {
+		t.Fatalf("expected heuristic default, got %q", got)
+	}
+}
+
+func TestWarningsClientsFromTOML(t *testing.T) {
+	cfg, err := ParseConfig([]byte(`
+[approvals]
+mode = "all"
+
+[agents.gemini]
+enabled = false
+
+[agents.claude]
+enabled = true
+
+[agents.codex]
+enabled = false
+
+[agents.vscode]
+enabled = false
+
+[agents.antigravity]
+enabled = false
+
+[warnings]
+instruction_token_threshold = 10000
+tokenizer = "o200k_base"
+
+[warnings.clients.claude]
+instruction_token_threshold = 8000
+`), "config.toml")
+	if err != nil {
+		t.Fatalf("ParseConfig error: %v", err)
+	}
+	if got := *cfg.Warnings.ForClient("claude").InstructionTokenThreshold; got != 8000 {
+		t.Fatalf("expected claude override, got %d", got)
+	}
+}
@@ -44,10 +44,10 @@ func CheckStructure(root string) []Result {
 	return results
 }
 
-// CheckConfig validates that the configuration file can be loaded and parsed.
-func CheckConfig(root string) ([]Result, *config.ProjectConfig) {
+// CheckConfig validates that the configuration can be loaded and parsed with opts applied.
+func CheckConfig(root string, opts config.LoadOptions) ([]Result, *config.ProjectConfig) {
 	var results []Result
-	cfg, err := config.LoadProjectConfig(root)
+	cfg, err := config.LoadProjectConfigWithOptions(root, opts)
 	if err != nil {
 		results = append(results, Result{
 			Status:         StatusFail,
@@ -66,7 +66,7 @@ func CheckConfig(root string) ([]Result, *config.ProjectConfig) {
 	return results, cfg
 }
 
-// CheckSecrets scans the configuration for missing environment variables.
+// CheckSecrets scans the configuration for missing environment variables and unresolved ${secret:NAME} references.
 func CheckSecrets(cfg *config.ProjectConfig) []Result {
 	var results []Result
 	required := config.RequiredEnvVarsForMCPServers(cfg.Config.MCP.Servers)
@@ -99,7 +99,37 @@ func CheckSecrets(cfg *config.ProjectConfig) []Result {
 		}
 	}
 
-	if len(required) == 0 {
+	// Secret providers are only consulted for enabled servers.
+	var enabled []config.MCPServer
+	for _, server := range cfg.Config.MCP.Servers {
+		if server.Enabled != nil && *server.Enabled {
+			enabled = append(enabled, server)
+		}
+	}
+	requiredSecrets := config.RequiredSecretsForMCPServers(enabled)
+	for _, name := range requiredSecrets {
+		secret, ok := cfg.Secrets[name]
+		if !ok || secret.Err != nil {
+			message := fmt.Sprintf(messages.DoctorMissingSecretFmt, name)
+			if ok {
+				message = fmt.Sprintf(messages.DoctorSecretUnresolvedFmt, secret.Err)
+			}
+			results = append(results, Result{
+				Status:         StatusFail,
+				CheckName:      messages.DoctorCheckNameSecrets,
+				Message:        message,
+				Recommendation: fmt.Sprintf(messages.DoctorSecretUnresolvedRecommendFmt, name),
+			})
+			continue
+		}
+		results = append(results, Result{
+			Status:    StatusOK,
+			CheckName: messages.DoctorCheckNameSecrets,
+			Message:   fmt.Sprintf(messages.DoctorSecretResolvedFmt, name, secret.Provider),
+		})
+	}
+
+	if len(required) == 0 && len(requiredSecrets) == 0 {
 		results = append(results, Result{
 			Status:    StatusOK,
 			CheckName: messages.DoctorCheckNameSecrets,
@@ -1,13 +1,16 @@
 package doctor
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/conn-castle/agent-layer/internal/config"
 	"github.com/conn-castle/agent-layer/internal/messages"
+	"github.com/conn-castle/agent-layer/internal/secrets"
 )
 
 func TestCheckStructure(t *testing.T) {
@@ -115,11 +118,50 @@ func TestCheckSecretsUsesRequiredEnvVars(t *testing.T) {
 	}
 }
 
+func TestCheckSecretsReportsSecretProviders(t *testing.T) {
+	enabled := true
+	disabled := false
+	cfg := &config.ProjectConfig{
+		Config: config.Config{
+			MCP: config.MCPConfig{
+				Servers: []config.MCPServer{
+					{
+						ID:      "demo",
+						Enabled: &enabled,
+						Headers: map[string]string{"Authorization": "Bearer ${secret:API_TOKEN}"},
+						Env:     map[string]string{"A": "${secret:BROKEN}", "B": "${secret:OPTIONAL:-none}"},
+					},
+					{
+						ID:      "off",
+						Enabled: &disabled,
+						Env:     map[string]string{"C": "${secret:IGNORED}"},
+					},
+				},
+			},
+		},
+		Secrets: map[string]secrets.Resolved{
+			"API_TOKEN": {Value: "abc", Provider: "keyring (agent-layer)"},
+			"BROKEN":    {Err: errors.New("secret BROKEN not found in providers: file (/tmp)")},
+		},
+	}
+
+	results := CheckSecrets(cfg)
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %+v", results)
+	}
+	if results[0].Status != StatusOK || results[0].Message != fmt.Sprintf(messages.DoctorSecretResolvedFmt, "API_TOKEN", "keyring (agent-layer)") {
+		t.Fatalf("unexpected resolved result: %+v", results[0])
+	}
+	if results[1].Status != StatusFail || !strings.Contains(results[1].Message, "not found in providers") || results[1].Recommendation == "" {
+		t.Fatalf("unexpected unresolved result: %+v", results[1])
+	}
+}
+
 func TestCheckConfig(t *testing.T) {
 	tmpDir := t.TempDir()
 
 	// Missing config
-	results, cfg := CheckConfig(tmpDir)
+	results, cfg := CheckConfig(tmpDir, config.LoadOptions{})
 	if cfg != nil {
 		t.Error("Expected nil config for missing file")
 	}
@@ -135,7 +177,7 @@ func TestCheckConfig(t *testing.T) {
 	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte("invalid"), 0644); err != nil {
 		t.Fatal(err)
 	}
-	results, cfg = CheckConfig(tmpDir)
+	results, cfg = CheckConfig(tmpDir, config.LoadOptions{})
 	if cfg != nil {
 		t.Error("Expected nil config for invalid file")
 	}
@@ -179,7 +221,7 @@ enabled = false
 		t.Fatal(err)
 	}
 
-	results, cfg = CheckConfig(tmpDir)
+	results, cfg = CheckConfig(tmpDir, config.LoadOptions{})
 	if cfg == nil {
 		t.Error("Expected valid config")
 	}
@@ -217,10 +259,10 @@ func TestCheckAgents(t *testing.T) {
 	cfg := &config.ProjectConfig{
 		Config: config.Config{
 			Agents: config.AgentsConfig{
-				Gemini:      config.AgentConfig{Enabled: &tBool},
-				Claude:      config.AgentConfig{Enabled: &fBool},
+				Gemini:      config.GeminiConfig{Enabled: &tBool},
+				Claude:      config.ClaudeConfig{Enabled: &fBool},
 				Codex:       config.CodexConfig{Enabled: nil},
-				VSCode:      config.AgentConfig{Enabled: &tBool},
+				VSCode:      config.VSCodeConfig{Enabled: &tBool},
 				Antigravity: config.AgentConfig{Enabled: &fBool},
 			},
 		},
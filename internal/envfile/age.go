@@ -0,0 +1,55 @@
+package envfile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// AgeSuffix marks an age-encrypted env file (for example .agent-layer/.env.age).
+const AgeSuffix = ".age"
+
+var execCommand = exec.Command
+
+// Decrypt returns the plaintext of age ciphertext using the identity file at identity.
+// The plaintext is only ever held in memory.
+func Decrypt(ciphertext []byte, identity string) ([]byte, error) {
+	return runAge("--decrypt", ciphertext, identity)
+}
+
+// Encrypt encrypts plaintext to the recipient of the identity file at identity,
+// so the same identity can decrypt it again. The output is ASCII-armored.
+func Encrypt(plaintext []byte, identity string) ([]byte, error) {
+	return runAge("--encrypt", plaintext, identity, "--armor")
+}
+
+// runAge pipes input through `age <mode> --identity identity` and returns stdout.
+func runAge(mode string, input []byte, identity string, extra ...string) ([]byte, error) {
+	if _, err := os.Stat(identity); err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf(messages.EnvfileAgeIdentityMissingFmt, identity, identity)
+		}
+		return nil, err
+	}
+	args := append([]string{mode, "--identity", identity}, extra...)
+	cmd := execCommand("age", args...)
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, errors.New(messages.EnvfileAgeNotInstalled)
+		}
+		if detail := strings.TrimSpace(stderr.String()); detail != "" {
+			return nil, fmt.Errorf(messages.EnvfileAgeFailedOutputFmt, mode, err, detail)
+		}
+		return nil, fmt.Errorf(messages.EnvfileAgeFailedFmt, mode, err)
+	}
+	return stdout.Bytes(), nil
+}
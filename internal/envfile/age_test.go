@@ -0,0 +1,68 @@
+package envfile
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAge installs an `age` stand-in on PATH that "encrypts" by prefixing AGE: and fails on bad input.
+func fakeAge(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+	dir := t.TempDir()
+	script := `#!/bin/sh
+case "$1" in
+--encrypt) printf 'AGE:'; cat ;;
+--decrypt)
+	input=$(mktemp); cat > "$input"
+	if [ "$(head -c 4 "$input")" != "AGE:" ]; then echo "age: error: no identity matched any of the recipients" >&2; exit 1; fi
+	tail -c +5 "$input" ;;
+esac
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "age"), []byte(script), 0o755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	identity := filepath.Join(dir, "identity.txt")
+	require.NoError(t, os.WriteFile(identity, []byte("AGE-SECRET-KEY-TEST\n"), 0o600))
+	return identity
+}
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	identity := fakeAge(t)
+	ciphertext, err := Encrypt([]byte("TOKEN=abc\n"), identity)
+	require.NoError(t, err)
+	assert.Equal(t, "AGE:TOKEN=abc\n", string(ciphertext))
+
+	plaintext, err := Decrypt(ciphertext, identity)
+	require.NoError(t, err)
+	assert.Equal(t, "TOKEN=abc\n", string(plaintext))
+}
+
+func TestDecryptFailureIncludesStderr(t *testing.T) {
+	identity := fakeAge(t)
+	_, err := Decrypt([]byte("garbage"), identity)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no identity matched")
+}
+
+func TestAgeMissingIdentity(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "identity.txt")
+	_, err := Decrypt([]byte("AGE:"), missing)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "age-keygen -o "+missing)
+}
+
+func TestAgeNotInstalled(t *testing.T) {
+	identity := filepath.Join(t.TempDir(), "identity.txt")
+	require.NoError(t, os.WriteFile(identity, []byte("key"), 0o600))
+	t.Setenv("PATH", t.TempDir())
+	_, err := Encrypt([]byte("A=1"), identity)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "age is not installed")
+}
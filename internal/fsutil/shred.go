@@ -0,0 +1,34 @@
+package fsutil
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// ShredFile overwrites the file at path with zeros, syncs it, and removes it.
+// It is best effort on copy-on-write and journaling filesystems; a missing file is not an error.
+func ShredFile(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf(messages.FsutilShredFileFmt, path, err)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf(messages.FsutilShredFileFmt, path, err)
+	}
+	_, writeErr := file.Write(make([]byte, info.Size()))
+	syncErr := file.Sync()
+	closeErr := file.Close()
+	removeErr := os.Remove(path)
+	for _, err := range []error{writeErr, syncErr, closeErr, removeErr} {
+		if err != nil {
+			return fmt.Errorf(messages.FsutilShredFileFmt, path, err)
+		}
+	}
+	return nil
+}
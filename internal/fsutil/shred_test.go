@@ -0,0 +1,22 @@
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShredFileRemovesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secret.env")
+	require.NoError(t, os.WriteFile(path, []byte("TOKEN=abc\n"), 0o600))
+
+	require.NoError(t, ShredFile(path))
+	_, err := os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	// Missing files are ignored.
+	assert.NoError(t, ShredFile(path))
+}
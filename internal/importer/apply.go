@@ -0,0 +1,189 @@
+package importer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/envfile"
+	"github.com/conn-castle/agent-layer/internal/fsutil"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// Apply writes plan into .agent-layer under root: instruction fragments, [[mcp.servers]] entries
+// appended to config.toml (keeping its comments), commands.allow lines, and .env secrets.
+// Every output is prepared and the new config validated before the first file is written.
+func Apply(root string, plan *Plan) error {
+	paths := config.DefaultPaths(root)
+	type output struct {
+		path string
+		data []byte
+		perm os.FileMode
+	}
+	var outputs []output
+	for _, file := range plan.Instructions {
+		outputs = append(outputs, output{
+			path: filepath.Join(paths.InstructionsDir, file.Name),
+			data: []byte(renderInstruction(file)),
+			perm: 0o644,
+		})
+	}
+
+	if len(plan.Servers) > 0 {
+		data, err := os.ReadFile(paths.ConfigPath)
+		if err != nil {
+			return fmt.Errorf(messages.ImportReadFailedFmt, paths.ConfigPath, err)
+		}
+		updated := appendBlock(string(data), renderServers(plan.Servers))
+		if _, err := config.ParseConfig([]byte(updated), paths.ConfigPath); err != nil {
+			return fmt.Errorf(messages.ImportInvalidConfigFmt, paths.ConfigPath, err)
+		}
+		outputs = append(outputs, output{path: paths.ConfigPath, data: []byte(updated), perm: 0o644})
+	}
+
+	if len(plan.Commands) > 0 {
+		data, err := os.ReadFile(paths.CommandsAllow)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf(messages.ImportReadFailedFmt, paths.CommandsAllow, err)
+		}
+		var lines strings.Builder
+		for _, cmd := range plan.Commands {
+			lines.WriteString(cmd.Prefix + "\n")
+		}
+		outputs = append(outputs, output{path: paths.CommandsAllow, data: []byte(appendBlock(string(data), lines.String())), perm: 0o644})
+	}
+
+	if len(plan.Secrets) > 0 {
+		envPath := plan.EnvPath
+		perm := os.FileMode(0o600)
+		data, err := os.ReadFile(envPath)
+		switch {
+		case err == nil:
+			if info, statErr := os.Stat(envPath); statErr == nil {
+				perm = info.Mode().Perm()
+			}
+			if data, err = config.DecodeEnvFile(envPath, data); err != nil {
+				return err
+			}
+		case !os.IsNotExist(err):
+			return fmt.Errorf(messages.ImportReadFailedFmt, envPath, err)
+		}
+		updates := make(map[string]string, len(plan.Secrets))
+		for _, secret := range plan.Secrets {
+			updates[secret.Key] = secret.Value
+		}
+		patched := envfile.Patch(string(data), updates)
+		if !strings.HasSuffix(patched, "\n") {
+			patched += "\n"
+		}
+		encoded, err := config.EncodeEnvFile(envPath, []byte(patched))
+		if err != nil {
+			return err
+		}
+		outputs = append(outputs, output{path: envPath, data: encoded, perm: perm})
+	}
+
+	if len(plan.Instructions) > 0 {
+		if err := os.MkdirAll(paths.InstructionsDir, 0o755); err != nil {
+			return fmt.Errorf(messages.ImportWriteFailedFmt, paths.InstructionsDir, err)
+		}
+	}
+	for _, out := range outputs {
+		if err := fsutil.WriteFileAtomic(out.path, out.data, out.perm); err != nil {
+			return fmt.Errorf(messages.ImportWriteFailedFmt, out.path, err)
+		}
+	}
+	return nil
+}
+
+// renderInstruction returns the fragment file, with clients front matter when it is client-specific.
+func renderInstruction(file Instruction) string {
+	if len(file.Clients) == 0 {
+		return file.Content
+	}
+	return "---\nclients: [" + strings.Join(file.Clients, ", ") + "]\n---\n\n" + file.Content
+}
+
+// renderServers returns [[mcp.servers]] tables for servers in the config template's layout.
+func renderServers(servers []Server) string {
+	var builder strings.Builder
+	for i, entry := range servers {
+		server := entry.Server
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+		builder.WriteString("[[mcp.servers]]\n")
+		builder.WriteString(fmt.Sprintf("# Imported from %s.\n", strings.Join(entry.From, ", ")))
+		builder.WriteString(fmt.Sprintf("id = %q\n", server.ID))
+		builder.WriteString(fmt.Sprintf("enabled = %t\n", server.Enabled != nil && *server.Enabled))
+		if len(server.Clients) > 0 {
+			builder.WriteString(fmt.Sprintf("clients = %s\n", tomlArray(server.Clients)))
+		}
+		builder.WriteString(fmt.Sprintf("transport = %q\n", server.Transport))
+		if server.HTTPTransport != "" {
+			builder.WriteString(fmt.Sprintf("http_transport = %q\n", server.HTTPTransport))
+		}
+		if server.URL != "" {
+			builder.WriteString(fmt.Sprintf("url = %q\n", server.URL))
+		}
+		if len(server.Headers) > 0 {
+			builder.WriteString(fmt.Sprintf("headers = %s\n", tomlInlineTable(server.Headers)))
+		}
+		if server.Command != "" {
+			builder.WriteString(fmt.Sprintf("command = %q\n", server.Command))
+		}
+		if len(server.Args) > 0 {
+			builder.WriteString(fmt.Sprintf("args = %s\n", tomlArray(server.Args)))
+		}
+		if len(server.Env) > 0 {
+			builder.WriteString(fmt.Sprintf("env = %s\n", tomlInlineTable(server.Env)))
+		}
+	}
+	return builder.String()
+}
+
+func tomlArray(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = fmt.Sprintf("%q", value)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+func tomlInlineTable(values map[string]string) string {
+	pairs := make([]string, 0, len(values))
+	for _, key := range sortedKeys(values) {
+		name := key
+		if !isBareKey(key) {
+			name = fmt.Sprintf("%q", key)
+		}
+		pairs = append(pairs, fmt.Sprintf("%s = %q", name, values[key]))
+	}
+	return "{ " + strings.Join(pairs, ", ") + " }"
+}
+
+func isBareKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, r := range key {
+		if !(r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// appendBlock appends block to content, separated by a blank line.
+func appendBlock(content string, block string) string {
+	if content == "" {
+		return block
+	}
+	content = strings.TrimRight(content, "\n") + "\n"
+	if strings.TrimSpace(content) == "" {
+		return block
+	}
+	return content + "\n" + block
+}
@@ -0,0 +1,474 @@
+// Package importer adopts hand-written instruction files and client configs into .agent-layer.
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/envfile"
+	"github.com/conn-castle/agent-layer/internal/messages"
+	alsync "github.com/conn-castle/agent-layer/internal/sync"
+)
+
+// Plan lists what an import adds to .agent-layer. Scan builds it; Apply writes it.
+type Plan struct {
+	// Instructions are new fragments for .agent-layer/instructions.
+	Instructions []Instruction
+	// Servers are new [[mcp.servers]] entries, with literal secrets replaced by placeholders.
+	Servers []Server
+	// Secrets are the literal values moved into .env.
+	Secrets []Secret
+	// Commands are new commands.allow prefixes.
+	Commands []Command
+	// Notes describe settings that were not imported and need a manual look.
+	Notes []Note
+	// Replaced lists the repo-relative hand-written files that sync regenerates after the import.
+	Replaced []string
+	// EnvPath is the env file secrets are written to (.env, or .env.age when it exists).
+	EnvPath string
+}
+
+// Instruction is an imported instruction fragment.
+type Instruction struct {
+	Name    string
+	Content string
+	// Clients is set when sources with different content are imported side by side.
+	Clients []string
+	From    []string
+}
+
+// Server is an imported MCP server and the files that declared it.
+type Server struct {
+	Server config.MCPServer
+	From   []string
+}
+
+// Secret is a literal value moved out of a client config into .env.
+type Secret struct {
+	Key   string
+	Value string
+	From  string
+}
+
+// Command is an imported commands.allow prefix.
+type Command struct {
+	Prefix string
+	From   string
+}
+
+// Note is a setting in Source that import cannot carry over.
+type Note struct {
+	Source string
+	Detail string
+}
+
+// Empty reports whether there is nothing to import or replace.
+func (p *Plan) Empty() bool {
+	return len(p.Instructions) == 0 && len(p.Servers) == 0 && len(p.Commands) == 0 && len(p.Replaced) == 0
+}
+
+// instructionSource is a root instruction file and the clients that read it.
+type instructionSource struct {
+	path    string
+	name    string
+	clients []string
+}
+
+var instructionSources = []instructionSource{
+	{path: "AGENTS.md", name: "agents", clients: []string{"codex"}},
+	{path: "CLAUDE.md", name: "claude", clients: []string{"claude"}},
+	{path: "GEMINI.md", name: "gemini", clients: []string{"gemini", "antigravity"}},
+	{path: ".github/copilot-instructions.md", name: "copilot", clients: []string{"vscode"}},
+}
+
+// Scan reads the hand-written files under root and plans their import. Files that sync generated
+// are ignored, as are servers and commands .agent-layer already has.
+func Scan(root string) (*Plan, error) {
+	paths := config.DefaultPaths(root)
+	if _, err := os.Stat(paths.ConfigPath); err != nil {
+		return nil, fmt.Errorf(messages.ImportMissingConfigFmt, paths.ConfigPath)
+	}
+	cfg, err := config.LoadConfigLayer(paths.ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	s := &scanner{
+		root:     root,
+		cfg:      cfg,
+		plan:     &Plan{EnvPath: config.EnvFilePath(paths.EnvPath)},
+		ids:      make(map[string]struct{}),
+		allowed:  make(map[string]struct{}),
+		names:    make(map[string]struct{}),
+		envSaved: make(map[string]string),
+	}
+	if err := s.loadExisting(paths); err != nil {
+		return nil, err
+	}
+	if err := s.scanInstructions(); err != nil {
+		return nil, err
+	}
+	if err := s.scanClientSources(); err != nil {
+		return nil, err
+	}
+	return s.plan, nil
+}
+
+// scanner carries the existing .agent-layer state while a plan is built.
+type scanner struct {
+	root string
+	cfg  *config.Config
+	plan *Plan
+	// ids, allowed, and names hold existing server ids, commands.allow lines, and instruction file names.
+	ids     map[string]struct{}
+	allowed map[string]struct{}
+	names   map[string]struct{}
+	// envSaved holds the current .env values.
+	envSaved map[string]string
+}
+
+func (s *scanner) loadExisting(paths config.Paths) error {
+	for _, server := range s.cfg.MCP.Servers {
+		s.ids[server.ID] = struct{}{}
+	}
+	if _, err := os.Stat(paths.LocalConfigPath); err == nil {
+		local, err := config.LoadConfigLayer(paths.LocalConfigPath)
+		if err != nil {
+			return err
+		}
+		for _, server := range local.MCP.Servers {
+			s.ids[server.ID] = struct{}{}
+		}
+	}
+	if _, err := os.Stat(paths.CommandsAllow); err == nil {
+		commands, err := config.LoadCommandsAllow(paths.CommandsAllow)
+		if err != nil {
+			return err
+		}
+		for _, cmd := range commands {
+			s.allowed[cmd] = struct{}{}
+		}
+	}
+	entries, err := os.ReadDir(paths.InstructionsDir)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf(messages.ImportReadFailedFmt, paths.InstructionsDir, err)
+	}
+	for _, entry := range entries {
+		s.names[entry.Name()] = struct{}{}
+	}
+	data, err := os.ReadFile(s.plan.EnvPath)
+	switch {
+	case err == nil:
+		if data, err = config.DecodeEnvFile(s.plan.EnvPath, data); err != nil {
+			return err
+		}
+		env, err := envfile.Parse(string(data))
+		if err != nil {
+			return fmt.Errorf(messages.ConfigInvalidEnvFileFmt, s.plan.EnvPath, err)
+		}
+		s.envSaved = env
+	case !os.IsNotExist(err):
+		return fmt.Errorf(messages.ImportReadFailedFmt, s.plan.EnvPath, err)
+	}
+	return nil
+}
+
+// readUserFile returns the content of the repo-relative file rel when it exists and sync did not generate it.
+func (s *scanner) readUserFile(rel string) ([]byte, bool, error) {
+	path := filepath.Join(s.root, filepath.FromSlash(rel))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf(messages.ImportReadFailedFmt, path, err)
+	}
+	if alsync.IsGeneratedOutput(s.root, path, data) {
+		return nil, false, nil
+	}
+	return bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n")), true, nil
+}
+
+// scanInstructions plans one fragment per distinct instruction file content. Identical files are
+// imported once for every client; differing ones keep their clients through front matter.
+func (s *scanner) scanInstructions() error {
+	type group struct {
+		source  instructionSource
+		content string
+		clients []string
+		from    []string
+	}
+	var groups []*group
+	for _, source := range instructionSources {
+		data, ok, err := s.readUserFile(source.path)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		s.plan.Replaced = append(s.plan.Replaced, source.path)
+		content := strings.TrimSpace(string(bytes.TrimPrefix(data, []byte("\ufeff"))))
+		idx := slices.IndexFunc(groups, func(g *group) bool { return g.content == content })
+		if idx < 0 {
+			groups = append(groups, &group{source: source, content: content})
+			idx = len(groups) - 1
+		}
+		groups[idx].clients = append(groups[idx].clients, source.clients...)
+		groups[idx].from = append(groups[idx].from, source.path)
+	}
+	for _, g := range groups {
+		file := Instruction{
+			Name:    s.instructionName(g.source.name),
+			Content: g.content + "\n",
+			From:    g.from,
+		}
+		if len(groups) > 1 {
+			file.Clients = g.clients
+		}
+		s.plan.Instructions = append(s.plan.Instructions, file)
+	}
+	return nil
+}
+
+// instructionName returns an unused file name for a fragment imported from a source.
+func (s *scanner) instructionName(source string) string {
+	name := fmt.Sprintf("50_imported-%s.md", source)
+	for i := 2; ; i++ {
+		if _, taken := s.names[name]; !taken {
+			s.names[name] = struct{}{}
+			return name
+		}
+		name = fmt.Sprintf("50_imported-%s-%d.md", source, i)
+	}
+}
+
+// scanClientSources plans servers, secrets, and commands from the client config files.
+func (s *scanner) scanClientSources() error {
+	type merged struct {
+		server  config.MCPServer
+		from    []string
+		clients []string
+	}
+	var order []string
+	servers := make(map[string]*merged)
+	mcpSources := 0
+	for _, source := range clientSources {
+		data, ok, err := s.readUserFile(source.path)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if agentEnabled(s.cfg, source.client) {
+			s.plan.Replaced = append(s.plan.Replaced, source.path)
+		}
+		content, err := source.parse(data)
+		if err != nil {
+			return fmt.Errorf(messages.ImportParseFailedFmt, source.path, err)
+		}
+		for _, note := range content.notes {
+			s.plan.Notes = append(s.plan.Notes, Note{Source: source.path, Detail: note})
+		}
+		for _, cmd := range content.commands {
+			if _, ok := s.allowed[cmd]; ok {
+				continue
+			}
+			s.allowed[cmd] = struct{}{}
+			s.plan.Commands = append(s.plan.Commands, Command{Prefix: cmd, From: source.path})
+		}
+		found := false
+		for _, server := range content.servers {
+			if server.ID == "agent-layer" {
+				continue
+			}
+			found = true
+			if _, exists := s.ids[server.ID]; exists {
+				continue
+			}
+			entry, seen := servers[server.ID]
+			if !seen {
+				entry = &merged{server: server}
+				servers[server.ID] = entry
+				order = append(order, server.ID)
+			}
+			entry.from = append(entry.from, source.path)
+			if !slices.Contains(entry.clients, source.client) {
+				entry.clients = append(entry.clients, source.client)
+			}
+		}
+		if found {
+			mcpSources++
+		}
+	}
+
+	for _, id := range order {
+		entry := servers[id]
+		server := entry.server
+		enabled := true
+		server.Enabled = &enabled
+		// A server every client config declares stays available to all clients, including ones
+		// that had no config yet; otherwise it keeps the clients it was found in.
+		if len(entry.clients) < mcpSources {
+			server.Clients = entry.clients
+		}
+		s.extractSecrets(&server, entry.from[0])
+		s.plan.Servers = append(s.plan.Servers, Server{Server: server, From: entry.from})
+	}
+	for _, server := range s.plan.Servers {
+		s.noteMissingEnv(server)
+	}
+	return nil
+}
+
+// extractSecrets replaces literal env and header values with placeholders and plans their .env entries.
+func (s *scanner) extractSecrets(server *config.MCPServer, from string) {
+	if len(server.Env) > 0 {
+		env := make(map[string]string, len(server.Env))
+		for _, name := range sortedKeys(server.Env) {
+			value := server.Env[name]
+			if !isLiteral(value) {
+				env[name] = value
+				continue
+			}
+			key := s.secretKey(value, from, envName(name), envName(server.ID+"_"+name))
+			env[name] = "${" + key + "}"
+		}
+		server.Env = env
+	}
+	if len(server.Headers) > 0 {
+		headers := make(map[string]string, len(server.Headers))
+		for _, name := range sortedKeys(server.Headers) {
+			value := server.Headers[name]
+			if !isLiteral(value) {
+				headers[name] = value
+				continue
+			}
+			if token, ok := strings.CutPrefix(value, "Bearer "); ok && strings.EqualFold(name, "Authorization") {
+				headers[name] = "Bearer ${" + s.secretKey(token, from, envName(server.ID+"_TOKEN")) + "}"
+				continue
+			}
+			headers[name] = "${" + s.secretKey(value, from, envName(server.ID+"_"+name)) + "}"
+		}
+		server.Headers = headers
+	}
+}
+
+// secretKey returns the .env key that holds value, reusing a key that already has it and
+// otherwise taking the first free candidate, then numbered variants of the last one.
+func (s *scanner) secretKey(value string, from string, candidates ...string) string {
+	last := candidates[len(candidates)-1]
+	for i := 2; ; i++ {
+		for _, key := range candidates {
+			if current, ok := s.envValue(key); ok {
+				if current == value {
+					return key
+				}
+				continue
+			}
+			if config.IsBuiltInEnvVar(key) {
+				continue
+			}
+			s.plan.Secrets = append(s.plan.Secrets, Secret{Key: key, Value: value, From: from})
+			return key
+		}
+		candidates = []string{fmt.Sprintf("%s_%d", last, i)}
+	}
+}
+
+// envValue returns the value key will have in .env after the import.
+func (s *scanner) envValue(key string) (string, bool) {
+	for _, secret := range s.plan.Secrets {
+		if secret.Key == key {
+			return secret.Value, true
+		}
+	}
+	value, ok := s.envSaved[key]
+	return value, ok
+}
+
+// noteMissingEnv flags placeholders in server that neither .env, the import, nor the environment provides.
+func (s *scanner) noteMissingEnv(server Server) {
+	values := []string{server.Server.Command, server.Server.URL}
+	values = append(values, server.Server.Args...)
+	for _, name := range sortedKeys(server.Server.Env) {
+		values = append(values, server.Server.Env[name])
+	}
+	for _, name := range sortedKeys(server.Server.Headers) {
+		values = append(values, server.Server.Headers[name])
+	}
+	seen := make(map[string]struct{})
+	for _, value := range values {
+		for _, name := range config.RequiredEnvVarNames(value) {
+			if _, done := seen[name]; done {
+				continue
+			}
+			seen[name] = struct{}{}
+			if _, isSecret := config.SecretName(name); isSecret || config.IsBuiltInEnvVar(name) {
+				continue
+			}
+			if _, ok := s.envValue(name); ok {
+				continue
+			}
+			if _, ok := os.LookupEnv(name); ok {
+				continue
+			}
+			s.plan.Notes = append(s.plan.Notes, Note{
+				Source: server.From[0],
+				Detail: fmt.Sprintf(messages.ImportNoteMissingEnvFmt, name, name),
+			})
+		}
+	}
+}
+
+// isLiteral reports whether value is a hard-coded value rather than a placeholder reference.
+func isLiteral(value string) bool {
+	return value != "" && !strings.Contains(value, "${")
+}
+
+// envName turns text into an upper-case env var name.
+func envName(text string) string {
+	var builder strings.Builder
+	for _, r := range strings.ToUpper(text) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			builder.WriteRune(r)
+		} else {
+			builder.WriteRune('_')
+		}
+	}
+	name := builder.String()
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
+
+func agentEnabled(cfg *config.Config, client string) bool {
+	var enabled *bool
+	switch client {
+	case "claude":
+		enabled = cfg.Agents.Claude.Enabled
+	case "codex":
+		enabled = cfg.Agents.Codex.Enabled
+	case "gemini":
+		enabled = cfg.Agents.Gemini.Enabled
+	case "vscode":
+		enabled = cfg.Agents.VSCode.Enabled
+	}
+	return enabled != nil && *enabled
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,210 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+const testConfig = `# Team config.
+[approvals]
+mode = "all"
+
+[agents.gemini]
+enabled = false
+
+[agents.claude]
+enabled = true
+
+[agents.codex]
+enabled = false
+
+[agents.vscode]
+enabled = true
+
+[agents.antigravity]
+enabled = false
+
+[[mcp.servers]]
+id = "existing"
+enabled = false
+transport = "stdio"
+command = "existing-mcp"
+`
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", rel, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", rel, err)
+		}
+	}
+}
+
+func TestScanAndApply(t *testing.T) {
+	t.Setenv("SEARCH_KEY", "")
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		".agent-layer/config.toml":             testConfig,
+		".agent-layer/.env":                    "EXISTING=1\n",
+		".agent-layer/commands.allow":          "git status\n",
+		".agent-layer/instructions/00_base.md": "base\n",
+		".agent-layer/slash-commands/.gitkeep": "",
+		"CLAUDE.md":                            "# Rules\r\nUse {{ braces }}.\r\n",
+		"AGENTS.md":                            "# Rules\nUse {{ braces }}.\n",
+		"GEMINI.md":                            "# Gemini only\n",
+		".mcp.json": `{"mcpServers": {
+			"github": {"command": "npx", "args": ["-y", "gh-mcp"], "env": {"GITHUB_TOKEN": "ghp_secret"}},
+			"existing": {"command": "other"},
+			"agent-layer": {"command": "al"}
+		}}`,
+		".vscode/mcp.json": `{
+			// VS Code allows comments.
+			"servers": {
+				"github": {"command": "npx", "args": ["-y", "gh-mcp"]},
+				"search": {"type": "http", "url": "https://search.example/mcp", "headers": {"Authorization": "Bearer tok-123", "X-Key": "${input:search-key}"}},
+			},
+		}`,
+		".claude/settings.json": `{"permissions": {"allow": ["Bash(npm run test:*)", "Bash(git status:*)", "Read(*)", "mcp__github__search"], "deny": ["Bash(rm:*)"]}, "hooks": {}}`,
+	})
+
+	plan, err := Scan(root)
+	if err != nil {
+		t.Fatalf("Scan error: %v", err)
+	}
+
+	if len(plan.Instructions) != 2 {
+		t.Fatalf("expected 2 instruction groups, got %+v", plan.Instructions)
+	}
+	shared := plan.Instructions[0]
+	if shared.Name != "50_imported-agents.md" || strings.Join(shared.From, ",") != "AGENTS.md,CLAUDE.md" || strings.Join(shared.Clients, ",") != "codex,claude" {
+		t.Fatalf("unexpected shared instruction: %+v", shared)
+	}
+	if shared.Content != "# Rules\nUse {{ braces }}.\n" {
+		t.Fatalf("expected braces copied as is, got %q", shared.Content)
+	}
+	if got := plan.Instructions[1]; got.Name != "50_imported-gemini.md" || strings.Join(got.Clients, ",") != "gemini,antigravity" {
+		t.Fatalf("unexpected gemini instruction: %+v", got)
+	}
+
+	if len(plan.Servers) != 2 {
+		t.Fatalf("expected github and search, got %+v", plan.Servers)
+	}
+	github := plan.Servers[0].Server
+	if github.ID != "github" || len(github.Clients) != 0 || github.Env["GITHUB_TOKEN"] != "${GITHUB_TOKEN}" {
+		t.Fatalf("unexpected github server: %+v", github)
+	}
+	search := plan.Servers[1].Server
+	if search.ID != "search" || strings.Join(search.Clients, ",") != "vscode" || search.HTTPTransport != "streamable" {
+		t.Fatalf("unexpected search server: %+v", search)
+	}
+	if search.Headers["Authorization"] != "Bearer ${SEARCH_TOKEN}" || search.Headers["X-Key"] != "${SEARCH_KEY}" {
+		t.Fatalf("unexpected search headers: %v", search.Headers)
+	}
+
+	var secrets []string
+	for _, secret := range plan.Secrets {
+		secrets = append(secrets, secret.Key+"="+secret.Value)
+	}
+	if strings.Join(secrets, ",") != "GITHUB_TOKEN=ghp_secret,SEARCH_TOKEN=tok-123" {
+		t.Fatalf("unexpected secrets: %v", secrets)
+	}
+	if len(plan.Commands) != 1 || plan.Commands[0].Prefix != "npm run test" {
+		t.Fatalf("expected only the new command, got %+v", plan.Commands)
+	}
+
+	var notes []string
+	for _, note := range plan.Notes {
+		notes = append(notes, note.Source+": "+note.Detail)
+	}
+	for _, want := range []string{
+		".claude/settings.json: hooks is not managed by Agent Layer",
+		".claude/settings.json: permissions.deny is not managed by Agent Layer",
+		".claude/settings.json: permissions.allow Read(*) has no commands.allow equivalent",
+		".vscode/mcp.json: VS Code input search-key became ${SEARCH_KEY}",
+	} {
+		if !strings.Contains(strings.Join(notes, "\n"), want) {
+			t.Fatalf("missing note %q in:\n%s", want, strings.Join(notes, "\n"))
+		}
+	}
+	if strings.Join(plan.Replaced, ",") != "AGENTS.md,CLAUDE.md,GEMINI.md,.mcp.json,.claude/settings.json,.vscode/mcp.json" {
+		t.Fatalf("unexpected replaced files: %v", plan.Replaced)
+	}
+
+	if err := Apply(root, plan); err != nil {
+		t.Fatalf("Apply error: %v", err)
+	}
+	t.Setenv("SEARCH_KEY", "set")
+	project, err := config.LoadProjectConfig(root)
+	if err != nil {
+		t.Fatalf("imported config does not load: %v", err)
+	}
+	if len(project.Config.MCP.Servers) != 3 || len(project.Instructions) != 3 {
+		t.Fatalf("unexpected project after import: %d servers, %d instructions", len(project.Config.MCP.Servers), len(project.Instructions))
+	}
+	if project.Env["GITHUB_TOKEN"] != "ghp_secret" || project.Env["EXISTING"] != "1" {
+		t.Fatalf("unexpected env: %v", project.Env)
+	}
+	if strings.Join(project.CommandsAllow, ",") != "git status,npm run test" {
+		t.Fatalf("unexpected commands.allow: %v", project.CommandsAllow)
+	}
+	env, err := os.ReadFile(filepath.Join(root, ".agent-layer", ".env"))
+	if err != nil {
+		t.Fatalf("read env: %v", err)
+	}
+	if !strings.HasPrefix(string(env), "EXISTING=1\n") || !strings.HasSuffix(string(env), "\n") {
+		t.Fatalf("expected secrets appended with a trailing newline, got %q", env)
+	}
+	data, err := os.ReadFile(filepath.Join(root, ".agent-layer", "config.toml"))
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "# Team config.\n") || !strings.Contains(string(data), "# Imported from .mcp.json, .vscode/mcp.json.\nid = \"github\"") {
+		t.Fatalf("expected servers appended to the commented config, got:\n%s", data)
+	}
+}
+
+func TestScanRequiresInit(t *testing.T) {
+	if _, err := Scan(t.TempDir()); err == nil || !strings.Contains(err.Error(), "al init") {
+		t.Fatalf("expected al init hint, got %v", err)
+	}
+}
+
+func TestScanSkipsGeneratedFiles(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		".agent-layer/config.toml": testConfig,
+		"AGENTS.md":                "<!--\n  GENERATED FILE\n-->\nbase\n",
+		".mcp.json":                "{\n  \"mcpServers\": {\n    \"agent-layer\": {\n      \"type\": \"stdio\",\n      \"command\": \"al\"\n    }\n  }\n}\n",
+	})
+	plan, err := Scan(root)
+	if err != nil {
+		t.Fatalf("Scan error: %v", err)
+	}
+	if !plan.Empty() {
+		t.Fatalf("expected nothing to import, got %+v", plan)
+	}
+}
+
+func TestSecretKeyAvoidsConflicts(t *testing.T) {
+	s := &scanner{plan: &Plan{}, envSaved: map[string]string{"TOKEN": "other", "SAME": "v"}}
+	if got := s.secretKey("v", "x", "SAME"); got != "SAME" || len(s.plan.Secrets) != 0 {
+		t.Fatalf("expected existing key reuse, got %q %v", got, s.plan.Secrets)
+	}
+	if got := s.secretKey("v", "x", "TOKEN", "API_TOKEN"); got != "API_TOKEN" {
+		t.Fatalf("expected fallback key, got %q", got)
+	}
+	if got := s.secretKey("w", "x", "TOKEN", "API_TOKEN"); got != "API_TOKEN_2" {
+		t.Fatalf("expected numbered key, got %q", got)
+	}
+	if got := envName("my-server.key"); got != "MY_SERVER_KEY" {
+		t.Fatalf("unexpected env name %q", got)
+	}
+}
@@ -0,0 +1,364 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// clientSource is a client config file that import reads.
+type clientSource struct {
+	path string
+	// client is the client that reads the file; sync only regenerates it while that agent is enabled.
+	client string
+	parse  func(data []byte) (*sourceContent, error)
+}
+
+// sourceContent is what one client config holds, in Agent Layer terms.
+type sourceContent struct {
+	servers  []config.MCPServer
+	commands []string
+	notes    []string
+}
+
+var clientSources = []clientSource{
+	{path: ".mcp.json", client: "claude", parse: parseClaudeMCP},
+	{path: ".claude/settings.json", client: "claude", parse: parseClaudeSettings},
+	{path: ".vscode/mcp.json", client: "vscode", parse: parseVSCodeMCP},
+	{path: ".gemini/settings.json", client: "gemini", parse: parseGeminiSettings},
+	{path: ".codex/config.toml", client: "codex", parse: parseCodexConfig},
+}
+
+// jsonServer holds the server fields shared by the JSON client configs.
+type jsonServer struct {
+	Type    string            `json:"type"`
+	Command string            `json:"command"`
+	Args    []string          `json:"args"`
+	Env     map[string]string `json:"env"`
+	URL     string            `json:"url"`
+	HTTPURL string            `json:"httpUrl"`
+	Headers map[string]string `json:"headers"`
+}
+
+// toServer converts a JSON server entry. Claude and VS Code mark SSE servers with type "sse";
+// Gemini uses url for SSE and httpUrl for streamable HTTP.
+func (s jsonServer) toServer(id string) config.MCPServer {
+	server := config.MCPServer{ID: id, Command: s.Command, Args: s.Args, Env: s.Env, Headers: s.Headers}
+	if s.Command != "" {
+		server.Transport = "stdio"
+		server.Headers = nil
+		return server
+	}
+	server.Transport = "http"
+	server.Env = nil
+	switch {
+	case s.HTTPURL != "":
+		server.URL = s.HTTPURL
+		server.HTTPTransport = "streamable"
+	case s.Type == "sse" || (s.Type == "" && s.URL != ""):
+		server.URL = s.URL
+		server.HTTPTransport = "sse"
+	default:
+		server.URL = s.URL
+		server.HTTPTransport = "streamable"
+	}
+	return server
+}
+
+// parseClaudeMCP reads Claude Code's .mcp.json.
+func parseClaudeMCP(data []byte) (*sourceContent, error) {
+	var file struct {
+		MCPServers map[string]jsonServer `json:"mcpServers"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	content := &sourceContent{notes: unsupportedKeys(data, "", "mcpServers")}
+	for _, id := range sortedKeys(file.MCPServers) {
+		server := file.MCPServers[id]
+		if server.Type == "" && server.URL != "" {
+			server.Type = "http"
+		}
+		content.servers = append(content.servers, server.toServer(id))
+	}
+	return content, nil
+}
+
+// parseClaudeSettings reads the Bash permissions from .claude/settings.json.
+func parseClaudeSettings(data []byte) (*sourceContent, error) {
+	var file struct {
+		Permissions struct {
+			Allow []string `json:"allow"`
+		} `json:"permissions"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	content := &sourceContent{notes: unsupportedKeys(data, "", "permissions")}
+	content.notes = append(content.notes, unsupportedKeys(data, "permissions", "allow")...)
+	for _, rule := range file.Permissions.Allow {
+		if strings.HasPrefix(rule, "mcp__") {
+			// MCP tool approvals come from [approvals] in config.toml.
+			continue
+		}
+		inner, ok := cutCall(rule, "Bash")
+		if cmd := commandPrefix(inner); ok && cmd != "" {
+			content.commands = append(content.commands, cmd)
+			continue
+		}
+		content.notes = append(content.notes, fmt.Sprintf(messages.ImportNoteUnsupportedPerm, "permissions.allow "+rule))
+	}
+	return content, nil
+}
+
+// parseVSCodeMCP reads .vscode/mcp.json, which may contain comments and trailing commas.
+func parseVSCodeMCP(data []byte) (*sourceContent, error) {
+	data = stripJSONC(data)
+	var file struct {
+		Servers map[string]jsonServer `json:"servers"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	content := &sourceContent{notes: unsupportedKeys(data, "", "servers", "inputs")}
+	for _, id := range sortedKeys(file.Servers) {
+		server := file.Servers[id]
+		if server.Type == "" && server.URL != "" {
+			server.Type = "http"
+		}
+		converted := server.toServer(id)
+		convert := func(value string) string {
+			value, inputs := convertVSCodeVars(value)
+			for _, input := range inputs {
+				name := envName(input)
+				content.notes = append(content.notes, fmt.Sprintf(messages.ImportNoteVSCodeInputFmt, input, name, name))
+			}
+			return value
+		}
+		converted.Command = convert(converted.Command)
+		converted.URL = convert(converted.URL)
+		for i, arg := range converted.Args {
+			converted.Args[i] = convert(arg)
+		}
+		for key, value := range converted.Env {
+			converted.Env[key] = convert(value)
+		}
+		for key, value := range converted.Headers {
+			converted.Headers[key] = convert(value)
+		}
+		content.servers = append(content.servers, converted)
+	}
+	return content, nil
+}
+
+var vscodeVarPattern = regexp.MustCompile(`\$\{(env|input):([^}]+)\}|\$\{workspaceFolder\}`)
+
+// convertVSCodeVars rewrites ${env:NAME} to ${NAME}, ${workspaceFolder} to ${AL_REPO_ROOT}, and
+// ${input:id} to an env placeholder, returning the input ids it replaced.
+func convertVSCodeVars(value string) (string, []string) {
+	var inputs []string
+	value = vscodeVarPattern.ReplaceAllStringFunc(value, func(match string) string {
+		groups := vscodeVarPattern.FindStringSubmatch(match)
+		switch groups[1] {
+		case "env":
+			return "${" + groups[2] + "}"
+		case "input":
+			inputs = append(inputs, groups[2])
+			return "${" + envName(groups[2]) + "}"
+		}
+		return "${" + config.BuiltinRepoRootEnvVar + "}"
+	})
+	return value, inputs
+}
+
+// parseGeminiSettings reads MCP servers and run_shell_command allowances from .gemini/settings.json.
+func parseGeminiSettings(data []byte) (*sourceContent, error) {
+	var file struct {
+		MCPServers map[string]jsonServer `json:"mcpServers"`
+		Tools      struct {
+			Allowed []string `json:"allowed"`
+		} `json:"tools"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	content := &sourceContent{notes: unsupportedKeys(data, "", "mcpServers", "tools")}
+	content.notes = append(content.notes, unsupportedKeys(data, "tools", "allowed")...)
+	for _, id := range sortedKeys(file.MCPServers) {
+		server := file.MCPServers[id].toServer(id)
+		for key, value := range server.Env {
+			server.Env[key] = bareEnvPattern.ReplaceAllString(value, "$${$1}")
+		}
+		content.servers = append(content.servers, server)
+	}
+	for _, tool := range file.Tools.Allowed {
+		inner, ok := cutCall(tool, "run_shell_command")
+		if cmd := strings.TrimSpace(inner); ok && cmd != "" {
+			content.commands = append(content.commands, cmd)
+			continue
+		}
+		content.notes = append(content.notes, fmt.Sprintf(messages.ImportNoteUnsupportedPerm, "tools.allowed "+tool))
+	}
+	return content, nil
+}
+
+// bareEnvPattern matches the $NAME references Gemini expands in env values.
+var bareEnvPattern = regexp.MustCompile(`\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// parseCodexConfig reads [mcp_servers.<id>] tables from .codex/config.toml.
+func parseCodexConfig(data []byte) (*sourceContent, error) {
+	var file struct {
+		MCPServers map[string]struct {
+			Command           string            `toml:"command"`
+			Args              []string          `toml:"args"`
+			Env               map[string]string `toml:"env"`
+			URL               string            `toml:"url"`
+			BearerTokenEnvVar string            `toml:"bearer_token_env_var"`
+			HTTPHeaders       map[string]string `toml:"http_headers"`
+			EnvHTTPHeaders    map[string]string `toml:"env_http_headers"`
+		} `toml:"mcp_servers"`
+	}
+	if err := toml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	var raw map[string]any
+	if err := toml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	content := &sourceContent{}
+	for _, key := range sortedKeys(raw) {
+		if key != "mcp_servers" {
+			content.notes = append(content.notes, fmt.Sprintf(messages.ImportNoteUnsupportedKeyFmt, key))
+		}
+	}
+	for _, id := range sortedKeys(file.MCPServers) {
+		entry := file.MCPServers[id]
+		server := config.MCPServer{ID: id, Command: entry.Command, Args: entry.Args, Env: entry.Env}
+		if entry.Command != "" {
+			server.Transport = "stdio"
+			content.servers = append(content.servers, server)
+			continue
+		}
+		server.Env = nil
+		server.Transport = "http"
+		server.HTTPTransport = "streamable"
+		server.URL = entry.URL
+		headers := make(map[string]string)
+		for key, value := range entry.HTTPHeaders {
+			headers[key] = value
+		}
+		for key, name := range entry.EnvHTTPHeaders {
+			headers[key] = "${" + name + "}"
+		}
+		if entry.BearerTokenEnvVar != "" {
+			headers["Authorization"] = "Bearer ${" + entry.BearerTokenEnvVar + "}"
+		}
+		if len(headers) > 0 {
+			server.Headers = headers
+		}
+		content.servers = append(content.servers, server)
+	}
+	return content, nil
+}
+
+// cutCall returns the argument of a name(arg) permission rule.
+func cutCall(rule string, name string) (string, bool) {
+	inner, ok := strings.CutPrefix(rule, name+"(")
+	if !ok || !strings.HasSuffix(inner, ")") {
+		return "", false
+	}
+	return strings.TrimSuffix(inner, ")"), true
+}
+
+// commandPrefix converts a Claude Bash rule argument ("npm run test:*", "git status *", "make")
+// into a commands.allow prefix, or "" when it uses wildcards a prefix cannot express.
+func commandPrefix(rule string) string {
+	rule = strings.TrimSpace(rule)
+	for _, suffix := range []string{":*", " *"} {
+		rule = strings.TrimSpace(strings.TrimSuffix(rule, suffix))
+	}
+	if strings.Contains(rule, "*") {
+		return ""
+	}
+	return rule
+}
+
+// unsupportedKeys lists the keys of the object at parent (or the top level) outside known.
+func unsupportedKeys(data []byte, parent string, known ...string) []string {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil
+	}
+	if parent != "" {
+		nested, ok := object[parent]
+		object = nil
+		if !ok || json.Unmarshal(nested, &object) != nil {
+			return nil
+		}
+	}
+	var notes []string
+	for _, key := range sortedKeys(object) {
+		if slices.Contains(known, key) {
+			continue
+		}
+		if parent != "" {
+			key = parent + "." + key
+		}
+		notes = append(notes, fmt.Sprintf(messages.ImportNoteUnsupportedKeyFmt, key))
+	}
+	return notes
+}
+
+// stripJSONC removes // and /* */ comments and trailing commas outside of strings.
+func stripJSONC(data []byte) []byte {
+	var out bytes.Buffer
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case inString:
+			out.WriteByte(c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				out.WriteByte(data[i])
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+			out.WriteByte(c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				out.WriteByte('\n')
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				return out.Bytes()
+			}
+			i += end + 3
+		case c == ']' || c == '}':
+			written := out.Bytes()
+			if comma := len(bytes.TrimRight(written, " \t\r\n")) - 1; comma >= 0 && written[comma] == ',' {
+				rest := append([]byte(nil), written[comma+1:]...)
+				out.Truncate(comma)
+				out.Write(rest)
+			}
+			out.WriteByte(c)
+		default:
+			out.WriteByte(c)
+		}
+	}
+	return out.Bytes()
+}
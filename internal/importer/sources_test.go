@@ -0,0 +1,99 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseCodexConfig(t *testing.T) {
+	data := []byte(`model = "o3"
+
+[mcp_servers.docs]
+command = "docs-mcp"
+args = ["--stdio"]
+env = { DOCS_TOKEN = "abc" }
+
+[mcp_servers.remote]
+url = "https://remote.example/mcp"
+bearer_token_env_var = "REMOTE_TOKEN"
+env_http_headers = { "X-Team" = "TEAM_ID" }
+`)
+	content, err := parseCodexConfig(data)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if len(content.servers) != 2 {
+		t.Fatalf("expected 2 servers, got %+v", content.servers)
+	}
+	docs, remote := content.servers[0], content.servers[1]
+	if docs.Transport != "stdio" || docs.Command != "docs-mcp" || docs.Env["DOCS_TOKEN"] != "abc" {
+		t.Fatalf("unexpected stdio server: %+v", docs)
+	}
+	if remote.Transport != "http" || remote.HTTPTransport != "streamable" || remote.Headers["Authorization"] != "Bearer ${REMOTE_TOKEN}" || remote.Headers["X-Team"] != "${TEAM_ID}" {
+		t.Fatalf("unexpected http server: %+v", remote)
+	}
+	if strings.Join(content.notes, ",") != "model is not managed by Agent Layer" {
+		t.Fatalf("unexpected notes: %v", content.notes)
+	}
+}
+
+func TestParseGeminiSettings(t *testing.T) {
+	data := []byte(`{
+  "theme": "dark",
+  "mcpServers": {
+    "sse": {"url": "https://sse.example/sse"},
+    "stream": {"httpUrl": "https://stream.example/mcp"},
+    "local": {"command": "local-mcp", "env": {"API_KEY": "$API_KEY"}}
+  },
+  "tools": {"allowed": ["run_shell_command(go test)", "read_file"], "exclude": ["web_fetch"]}
+}`)
+	content, err := parseGeminiSettings(data)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	byID := make(map[string]string)
+	for _, server := range content.servers {
+		byID[server.ID] = server.Transport + "/" + server.HTTPTransport + "/" + server.Env["API_KEY"]
+	}
+	if byID["sse"] != "http/sse/" || byID["stream"] != "http/streamable/" || byID["local"] != "stdio//${API_KEY}" {
+		t.Fatalf("unexpected servers: %v", byID)
+	}
+	if strings.Join(content.commands, ",") != "go test" {
+		t.Fatalf("unexpected commands: %v", content.commands)
+	}
+	want := "theme is not managed by Agent Layer,tools.exclude is not managed by Agent Layer,tools.allowed read_file has no commands.allow equivalent"
+	if strings.Join(content.notes, ",") != want {
+		t.Fatalf("unexpected notes: %v", content.notes)
+	}
+}
+
+func TestCommandPrefix(t *testing.T) {
+	cases := map[string]string{
+		"npm run test:*": "npm run test",
+		"git status *":   "git status",
+		"make":           "make",
+		"*":              "",
+		"rm -rf */tmp":   "",
+	}
+	for rule, want := range cases {
+		if got := commandPrefix(rule); got != want {
+			t.Fatalf("commandPrefix(%q) = %q, want %q", rule, got, want)
+		}
+	}
+}
+
+func TestStripJSONC(t *testing.T) {
+	input := "{\n  // comment\n  \"url\": \"http://x/*not a comment*/\", /* block */\n  \"list\": [1, 2,],\n}\n"
+	got := string(stripJSONC([]byte(input)))
+	want := "{\n  \n  \"url\": \"http://x/*not a comment*/\", \n  \"list\": [1, 2]\n}\n"
+	if got != want {
+		t.Fatalf("stripJSONC = %q, want %q", got, want)
+	}
+}
+
+func TestConvertVSCodeVars(t *testing.T) {
+	got, inputs := convertVSCodeVars("${env:HOME}/x ${workspaceFolder} ${input:api-key}")
+	if got != "${HOME}/x ${AL_REPO_ROOT} ${API_KEY}" || strings.Join(inputs, ",") != "api-key" {
+		t.Fatalf("unexpected conversion %q %v", got, inputs)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/conn-castle/agent-layer/internal/envfile"
 	"github.com/conn-castle/agent-layer/internal/fsutil"
 	"github.com/conn-castle/agent-layer/internal/launchers"
 	"github.com/conn-castle/agent-layer/internal/messages"
@@ -149,6 +150,12 @@ func (inst *installer) writeTemplateFiles() error {
 			}
 			continue
 		}
+		if file.template == "env" {
+			// An encrypted .env.age replaces the plaintext .env.
+			if _, err := os.Stat(file.path + envfile.AgeSuffix); err == nil {
+				continue
+			}
+		}
 		if err := writeTemplateFile(file.path, file.template, file.perm, inst.shouldOverwrite, inst.recordDiff); err != nil {
 			return err
 		}
@@ -438,8 +445,12 @@ func (inst *installer) buildKnownPaths() (map[string]struct{}, error) {
 
 	// Root-level managed files.
 	add(filepath.Join(root, ".agent-layer", "config.toml"))
+	add(filepath.Join(root, ".agent-layer", "config.local.toml"))
+	add(filepath.Join(root, ".agent-layer", "config.schema.json"))
 	add(filepath.Join(root, ".agent-layer", "commands.allow"))
 	add(filepath.Join(root, ".agent-layer", ".env"))
+	add(filepath.Join(root, ".agent-layer", ".env"+envfile.AgeSuffix))
+	add(filepath.Join(root, ".agent-layer", ".env.example"))
 	add(filepath.Join(root, ".agent-layer", ".gitignore"))
 	add(filepath.Join(root, ".agent-layer", "gitignore.block"))
 	add(filepath.Join(root, ".agent-layer", "al.version"))
@@ -1118,6 +1118,7 @@ func TestBuildKnownPaths_Success(t *testing.T) {
 	expectedPaths := []string{
 		filepath.Join(root, ".agent-layer"),
 		filepath.Join(root, ".agent-layer", "config.toml"),
+		filepath.Join(root, ".agent-layer", "config.local.toml"),
 		filepath.Join(root, ".agent-layer", "instructions"),
 	}
 	for _, p := range expectedPaths {
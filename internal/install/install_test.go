@@ -43,6 +43,23 @@ func TestRunCreatesStructure(t *testing.T) {
 	}
 }
 
+func TestRunSkipsPlaintextEnvWhenEncrypted(t *testing.T) {
+	root := t.TempDir()
+	encrypted := filepath.Join(root, ".agent-layer", ".env.age")
+	if err := os.MkdirAll(filepath.Dir(encrypted), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(encrypted, []byte("ciphertext"), 0o600); err != nil {
+		t.Fatalf("write env.age: %v", err)
+	}
+	if err := Run(root, Options{}); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".agent-layer", ".env")); !os.IsNotExist(err) {
+		t.Fatalf("expected no plaintext .env next to .env.age, got %v", err)
+	}
+}
+
 func TestRunWritesPinVersion(t *testing.T) {
 	root := t.TempDir()
 	if err := Run(root, Options{PinVersion: "0.5.0"}); err != nil {
@@ -0,0 +1,124 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+const commandToolPrefix = "run_command_"
+
+// CommandToolName returns the tool name for cmd: run_command_ followed by its flattened name, with
+// characters other than letters, digits, and underscores replaced by underscores.
+func CommandToolName(cmd config.SlashCommand) string {
+	name := strings.Map(func(r rune) rune {
+		if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, cmd.FlatName())
+	return commandToolPrefix + name
+}
+
+// commandToolSet tracks the slash commands registered as tools on server, for clients that do not
+// surface MCP prompts.
+type commandToolSet struct {
+	server     *mcp.Server
+	registered map[string]config.SlashCommand
+}
+
+// update registers commands as tools, replacing changed ones and removing ones that are gone.
+// A command whose tool name is already taken by an earlier command is skipped with a log message.
+func (s *commandToolSet) update(commands []config.SlashCommand) {
+	wanted := make(map[string]config.SlashCommand, len(commands))
+	for _, cmd := range commands {
+		name := CommandToolName(cmd)
+		if existing, ok := wanted[name]; ok {
+			_, _ = fmt.Fprintf(logOutput, messages.McpCommandToolCollisionFmt, cmd.Name, existing.Name, name)
+			continue
+		}
+		wanted[name] = cmd
+		if existing, ok := s.registered[name]; ok && reflect.DeepEqual(existing, cmd) {
+			continue
+		}
+		s.registered[name] = cmd
+		s.server.AddTool(newCommandTool(name, cmd), commandToolHandler(cmd))
+	}
+	var removed []string
+	for name := range s.registered {
+		if _, ok := wanted[name]; !ok {
+			removed = append(removed, name)
+			delete(s.registered, name)
+		}
+	}
+	if len(removed) > 0 {
+		s.server.RemoveTools(removed...)
+	}
+}
+
+// newCommandTool describes cmd as a tool whose input schema lists its arguments as strings.
+func newCommandTool(name string, cmd config.SlashCommand) *mcp.Tool {
+	properties := make(map[string]any, len(cmd.Arguments))
+	required := []string{}
+	for _, arg := range cmd.Arguments {
+		property := map[string]any{"type": "string"}
+		if arg.Description != "" {
+			property["description"] = arg.Description
+		}
+		if arg.Default != "" {
+			property["default"] = arg.Default
+		}
+		properties[arg.Name] = property
+		if arg.Required {
+			required = append(required, arg.Name)
+		}
+	}
+	return &mcp.Tool{
+		Name:        name,
+		Description: fmt.Sprintf(messages.McpCommandToolDescriptionFmt, cmd.Description, cmd.Name),
+		InputSchema: map[string]any{
+			"type":                 "object",
+			"properties":           properties,
+			"required":             required,
+			"additionalProperties": false,
+		},
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}
+}
+
+// commandToolHandler returns cmd's body, with the call's arguments substituted, as the tool result.
+func commandToolHandler(cmd config.SlashCommand) mcp.ToolHandler {
+	return func(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var raw map[string]any
+		if req.Params != nil && len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &raw); err != nil {
+				return toolError(fmt.Errorf(messages.McpCommandToolArgumentsInvalidFmt, err)), nil
+			}
+		}
+		values := make(map[string]string, len(raw))
+		for name, value := range raw {
+			if text, ok := value.(string); ok {
+				values[name] = text
+			} else if value != nil {
+				values[name] = fmt.Sprint(value)
+			}
+		}
+		body, err := substituteArguments(cmd, values)
+		if err != nil {
+			return toolError(err), nil
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: body}}}, nil
+	}
+}
+
+// toolError reports err to the model as a failed tool call rather than a protocol error.
+func toolError(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}}}
+}
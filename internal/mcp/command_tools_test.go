@@ -0,0 +1,165 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+func TestCommandToolName(t *testing.T) {
+	cases := map[string]string{
+		"finish-task":   "run_command_finish_task",
+		"git:commit":    "run_command_git_commit",
+		"review.v2":     "run_command_review_v2",
+		"Fix_Bug":       "run_command_Fix_Bug",
+		"docs:to do/x!": "run_command_docs_to_do_x_",
+	}
+	for name, want := range cases {
+		if got := CommandToolName(config.SlashCommand{Name: name}); got != want {
+			t.Fatalf("%s: expected %s, got %s", name, want, got)
+		}
+	}
+}
+
+func TestRunPromptServerCommandTools(t *testing.T) {
+	originalRun := runServer
+	t.Cleanup(func() { runServer = originalRun })
+
+	source := PromptSource{
+		CommandTools: true,
+		Commands: []config.SlashCommand{
+			{Name: "plain", Description: "Plain", Body: "Just do it."},
+			{
+				Name:        "git:fix",
+				Description: "Fix an issue",
+				Body:        "Fix " + config.PromptArgumentPlaceholder("issue") + " on " + config.PromptArgumentPlaceholder("branch") + ".",
+				Arguments: []config.SlashCommandArgument{
+					{Name: "issue", Description: "Issue id", Required: true},
+					{Name: "branch", Default: "main"},
+				},
+			},
+		},
+	}
+
+	runServer = func(ctx context.Context, server *mcp.Server) error {
+		clientTransport, serverTransport := mcp.NewInMemoryTransports()
+		serverSession, err := server.Connect(ctx, serverTransport, nil)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = serverSession.Close() }()
+		session, err := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "v1"}, nil).Connect(ctx, clientTransport, nil)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = session.Close() }()
+
+		list, err := session.ListTools(ctx, nil)
+		if err != nil {
+			t.Fatalf("ListTools error: %v", err)
+		}
+		tools := map[string]*mcp.Tool{}
+		var names []string
+		for _, tool := range list.Tools {
+			tools[tool.Name] = tool
+			names = append(names, tool.Name)
+		}
+		sort.Strings(names)
+		if strings.Join(names, ",") != "run_command_git_fix,run_command_plain" {
+			t.Fatalf("unexpected tools %v", names)
+		}
+		fix := tools["run_command_git_fix"]
+		if !strings.Contains(fix.Description, "Fix an issue") || !strings.Contains(fix.Description, "/git:fix") {
+			t.Fatalf("unexpected description %q", fix.Description)
+		}
+		schema, _ := json.Marshal(fix.InputSchema)
+		var parsed struct {
+			Properties map[string]map[string]string `json:"properties"`
+			Required   []string                     `json:"required"`
+		}
+		if err := json.Unmarshal(schema, &parsed); err != nil {
+			t.Fatalf("unmarshal schema: %v", err)
+		}
+		if parsed.Properties["issue"]["description"] != "Issue id" || parsed.Properties["branch"]["default"] != "main" || strings.Join(parsed.Required, ",") != "issue" {
+			t.Fatalf("unexpected input schema %s", schema)
+		}
+
+		text := func(result *mcp.CallToolResult) string {
+			return result.Content[0].(*mcp.TextContent).Text
+		}
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "run_command_git_fix", Arguments: map[string]any{"issue": "#12"}})
+		if err != nil || result.IsError || text(result) != "Fix #12 on main." {
+			t.Fatalf("unexpected result %+v, %v", result, err)
+		}
+		result, err = session.CallTool(ctx, &mcp.CallToolParams{Name: "run_command_git_fix", Arguments: map[string]any{"issue": 12, "branch": "dev"}})
+		if err != nil || text(result) != "Fix 12 on dev." {
+			t.Fatalf("expected non-string values to be formatted, got %+v, %v", result, err)
+		}
+		result, err = session.CallTool(ctx, &mcp.CallToolParams{Name: "run_command_git_fix", Arguments: map[string]any{}})
+		if err != nil || !result.IsError || !strings.Contains(text(result), `missing required argument "issue"`) {
+			t.Fatalf("expected missing argument error, got %+v, %v", result, err)
+		}
+		result, err = session.CallTool(ctx, &mcp.CallToolParams{Name: "run_command_plain"})
+		if err != nil || text(result) != "Just do it." {
+			t.Fatalf("unexpected result %+v, %v", result, err)
+		}
+		return nil
+	}
+
+	if err := RunPromptServer(context.Background(), "v1", source); err != nil {
+		t.Fatalf("RunPromptServer error: %v", err)
+	}
+}
+
+func TestCommandToolSetUpdate(t *testing.T) {
+	originalLog := logOutput
+	logs := make(lineWriter, 10)
+	logOutput = logs
+	t.Cleanup(func() { logOutput = originalLog })
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "v1"}, nil)
+	set := &commandToolSet{server: server, registered: map[string]config.SlashCommand{}}
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("Connect error: %v", err)
+	}
+	defer func() { _ = serverSession.Close() }()
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "v1"}, nil).Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("Connect error: %v", err)
+	}
+	defer func() { _ = session.Close() }()
+	names := func() string {
+		list, err := session.ListTools(ctx, nil)
+		if err != nil {
+			t.Fatalf("ListTools error: %v", err)
+		}
+		var names []string
+		for _, tool := range list.Tools {
+			names = append(names, tool.Name)
+		}
+		sort.Strings(names)
+		return strings.Join(names, ",")
+	}
+
+	set.update([]config.SlashCommand{{Name: "a_b", Description: "A"}, {Name: "a.b", Description: "Taken"}, {Name: "c", Description: "C"}})
+	if got := names(); got != "run_command_a_b,run_command_c" {
+		t.Fatalf("unexpected tools %q", got)
+	}
+	if line := <-logs; !strings.Contains(line, "not serving a.b as a tool: a_b already uses run_command_a_b") {
+		t.Fatalf("unexpected log %q", line)
+	}
+
+	set.update([]config.SlashCommand{{Name: "c", Description: "C"}})
+	if got := names(); got != "run_command_c" {
+		t.Fatalf("expected removed command tool to be dropped, got %q", got)
+	}
+}
@@ -0,0 +1,166 @@
+package mcp
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+var listen = net.Listen
+
+// ServePromptsHTTP serves the prompt server over streamable HTTP on addr until ctx is done.
+// config.PromptServerPath serves every slash command and PromptServerPath/<client> serves the ones
+// for that client; load supplies each client's source the first time it is requested. When token
+// is set, requests must carry it as an Authorization: Bearer header. Without a token, addr must be
+// a loopback address.
+func ServePromptsHTTP(ctx context.Context, version string, addr string, token string, load func(client string) (PromptSource, error)) error {
+	if token == "" && !config.PromptServerAddressIsLoopback(addr) {
+		return fmt.Errorf(messages.McpPromptsHTTPTokenRequiredFmt, addr, config.PromptServerTokenEnvVar)
+	}
+	listener, err := listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf(messages.McpRunPromptServerFailedFmt, err)
+	}
+	handler := newHTTPHandler(ctx, version, token, load)
+	defer handler.close()
+
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+	_, _ = fmt.Fprintf(logOutput, messages.McpPromptsHTTPListeningFmt, config.PromptServerURL(listener.Addr().String(), ""))
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf(messages.McpRunPromptServerFailedFmt, err)
+	}
+	return nil
+}
+
+// httpHandler routes requests to one prompt server per client, built on first use.
+type httpHandler struct {
+	ctx     context.Context
+	version string
+	token   string
+	load    func(client string) (PromptSource, error)
+
+	mu      sync.Mutex
+	clients map[string]*httpClient
+}
+
+// httpClient is the server for one client and the function that stops watching its files.
+type httpClient struct {
+	handler http.Handler
+	stop    func()
+}
+
+// newHTTPHandler returns a handler whose servers live, and watch their files, until ctx is done.
+func newHTTPHandler(ctx context.Context, version string, token string, load func(client string) (PromptSource, error)) *httpHandler {
+	return &httpHandler{ctx: ctx, version: version, token: token, load: load, clients: map[string]*httpClient{}}
+}
+
+// ServeHTTP checks where r comes from and its bearer token, then hands r to the server for the
+// client in its path. Browsers send an Origin, so a non-loopback one is a web page trying to reach
+// the server and is refused. Without a token, a non-loopback Host is refused too, so a DNS
+// rebinding page cannot address the server by its own name.
+func (h *httpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if origin := r.Header.Get("Origin"); origin != "" && !loopbackOrigin(origin) {
+		http.Error(w, messages.McpPromptsHTTPForbiddenOrigin, http.StatusForbidden)
+		return
+	}
+	if h.token == "" && !loopbackHost(r.Host) {
+		http.Error(w, messages.McpPromptsHTTPForbiddenHost, http.StatusForbidden)
+		return
+	}
+	if !h.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="agent-layer"`)
+		http.Error(w, messages.McpPromptsHTTPUnauthorized, http.StatusUnauthorized)
+		return
+	}
+	client, ok := clientFromPath(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	handler, err := h.clientHandler(client)
+	if err != nil {
+		_, _ = fmt.Fprintf(logOutput, messages.McpPromptsHTTPLoadFailedFmt, client, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	handler.ServeHTTP(w, r)
+}
+
+// authorized reports whether r carries the bearer token, or no token is required.
+func (h *httpHandler) authorized(r *http.Request) bool {
+	if h.token == "" {
+		return true
+	}
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(got), []byte(h.token)) == 1
+}
+
+// loopbackOrigin reports whether the Origin header value names a loopback host.
+func loopbackOrigin(origin string) bool {
+	u, err := url.Parse(origin)
+	return err == nil && u.Host != "" && config.IsLoopbackHost(u.Hostname())
+}
+
+// loopbackHost reports whether the Host header value, with or without a port, is loopback.
+func loopbackHost(hostport string) bool {
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host = hostport
+	}
+	return config.IsLoopbackHost(host)
+}
+
+// clientHandler returns the streamable HTTP handler for client, loading its source on first use.
+// A failed load is not cached, so the next request tries again.
+func (h *httpHandler) clientHandler(client string) (http.Handler, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if existing, ok := h.clients[client]; ok {
+		return existing.handler, nil
+	}
+	source, err := h.load(client)
+	if err != nil {
+		return nil, err
+	}
+	server, stop := newPromptServer(h.ctx, h.version, source)
+	handler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil)
+	h.clients[client] = &httpClient{handler: handler, stop: stop}
+	return handler, nil
+}
+
+// close stops watching files for every client served so far.
+func (h *httpHandler) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, client := range h.clients {
+		client.stop()
+	}
+}
+
+// clientFromPath maps config.PromptServerPath to all clients and PromptServerPath/<client> to one.
+func clientFromPath(path string) (string, bool) {
+	if path == config.PromptServerPath {
+		return "", true
+	}
+	client, ok := strings.CutPrefix(path, config.PromptServerPath+"/")
+	if !ok || !config.IsValidClient(client) {
+		return "", false
+	}
+	return client, true
+}
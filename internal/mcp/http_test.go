@@ -0,0 +1,218 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+// bearerTransport adds an Authorization header to every request.
+type bearerTransport struct {
+	token string
+}
+
+func (b bearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+b.token)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestHTTPHandler(t *testing.T) {
+	originalLog := logOutput
+	logOutput = io.Discard
+	t.Cleanup(func() { logOutput = originalLog })
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var loads []string
+	handler := newHTTPHandler(ctx, "v1", "secret", func(client string) (PromptSource, error) {
+		loads = append(loads, client)
+		if client == "gemini" {
+			return PromptSource{}, errors.New("broken config")
+		}
+		return PromptSource{
+			CommandTools: client == "codex",
+			Commands:     []config.SlashCommand{{Name: "review-" + client, Description: "Review"}},
+		}, nil
+	})
+	defer handler.close()
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	connect := func(path string) (*mcp.ClientSession, error) {
+		transport := &mcp.StreamableClientTransport{
+			Endpoint:   server.URL + path,
+			HTTPClient: &http.Client{Transport: bearerTransport{token: "secret"}},
+			MaxRetries: -1,
+		}
+		return mcp.NewClient(&mcp.Implementation{Name: "test", Version: "v1"}, nil).Connect(ctx, transport, nil)
+	}
+
+	session, err := connect("/mcp/codex")
+	if err != nil {
+		t.Fatalf("Connect error: %v", err)
+	}
+	prompts, err := session.ListPrompts(ctx, nil)
+	if err != nil || len(prompts.Prompts) != 1 || prompts.Prompts[0].Name != "review-codex" {
+		t.Fatalf("unexpected prompts %+v, %v", prompts, err)
+	}
+	tools, err := session.ListTools(ctx, nil)
+	if err != nil || len(tools.Tools) != 1 || tools.Tools[0].Name != "run_command_review_codex" {
+		t.Fatalf("unexpected tools %+v, %v", tools, err)
+	}
+	_ = session.Close()
+
+	second, err := connect("/mcp/codex")
+	if err != nil {
+		t.Fatalf("Connect error: %v", err)
+	}
+	_ = second.Close()
+	all, err := connect("/mcp")
+	if err != nil {
+		t.Fatalf("Connect error: %v", err)
+	}
+	_ = all.Close()
+	if strings.Join(loads, ",") != "codex," {
+		t.Fatalf("expected each client to load once, got %q", loads)
+	}
+
+	if _, err := connect("/mcp/gemini"); err == nil {
+		t.Fatalf("expected load failure to fail the connection")
+	}
+
+	status := func(path string, header string) int {
+		req, _ := http.NewRequest(http.MethodPost, server.URL+path, strings.NewReader("{}"))
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request error: %v", err)
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+	if got := status("/mcp", ""); got != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without a token, got %d", got)
+	}
+	if got := status("/mcp", "Bearer wrong"); got != http.StatusUnauthorized {
+		t.Fatalf("expected 401 with a wrong token, got %d", got)
+	}
+	if got := status("/mcp/notepad", "Bearer secret"); got != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown client, got %d", got)
+	}
+	if got := status("/other", "Bearer secret"); got != http.StatusNotFound {
+		t.Fatalf("expected 404 outside /mcp, got %d", got)
+	}
+}
+
+func TestHTTPHandlerWithoutToken(t *testing.T) {
+	handler := newHTTPHandler(context.Background(), "v1", "", nil)
+	req := httptest.NewRequest(http.MethodGet, "/mcp/unknown", nil)
+	req.Host = "127.0.0.1:7420"
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected requests without a token to be routed, got %d", rec.Code)
+	}
+}
+
+func TestHTTPHandlerRejectsRebinding(t *testing.T) {
+	cases := []struct {
+		name   string
+		token  string
+		host   string
+		origin string
+		want   int
+	}{
+		{"rebinding origin", "", "127.0.0.1:7420", "http://evil.example", http.StatusForbidden},
+		{"rebinding origin with token", "secret", "127.0.0.1:7420", "http://evil.example:7420", http.StatusForbidden},
+		{"null origin", "", "localhost:7420", "null", http.StatusForbidden},
+		{"rebinding host", "", "evil.example:7420", "", http.StatusForbidden},
+		{"loopback origin", "", "localhost:7420", "http://localhost:3000", http.StatusNotFound},
+		{"ipv6 loopback", "", "[::1]:7420", "http://[::1]:7420", http.StatusNotFound},
+		{"remote host with token", "secret", "192.168.1.5:7420", "", http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		handler := newHTTPHandler(context.Background(), "v1", tc.token, nil)
+		req := httptest.NewRequest(http.MethodPost, "/mcp/unknown", strings.NewReader("{}"))
+		req.Host = tc.host
+		if tc.origin != "" {
+			req.Header.Set("Origin", tc.origin)
+		}
+		if tc.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Fatalf("%s: expected %d, got %d", tc.name, tc.want, rec.Code)
+		}
+	}
+}
+
+func TestServePromptsHTTP(t *testing.T) {
+	originalListen := listen
+	originalLog := logOutput
+	logs := make(lineWriter, 10)
+	logOutput = logs
+	t.Cleanup(func() {
+		listen = originalListen
+		logOutput = originalLog
+	})
+
+	listen = func(network, addr string) (net.Listener, error) {
+		return nil, errors.New("address in use")
+	}
+	if err := ServePromptsHTTP(context.Background(), "v1", "0.0.0.0:7420", "secret", nil); err == nil || !strings.Contains(err.Error(), "address in use") {
+		t.Fatalf("expected listen error, got %v", err)
+	}
+
+	listen = func(network, addr string) (net.Listener, error) {
+		t.Fatalf("expected no listener for %s", addr)
+		return nil, nil
+	}
+	err := ServePromptsHTTP(context.Background(), "v1", "0.0.0.0:7420", "", nil)
+	if err == nil || !strings.Contains(err.Error(), config.PromptServerTokenEnvVar) {
+		t.Fatalf("expected a non-loopback bind without a token to be refused, got %v", err)
+	}
+	if err := ServePromptsHTTP(context.Background(), "v1", ":7420", "", nil); err == nil {
+		t.Fatalf("expected an all-interfaces bind without a token to be refused")
+	}
+
+	listened := make(chan string, 1)
+	listen = func(network, addr string) (net.Listener, error) {
+		listener, err := net.Listen(network, "127.0.0.1:0")
+		if err == nil {
+			listened <- listener.Addr().String()
+		}
+		return listener, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- ServePromptsHTTP(ctx, "v1", "127.0.0.1:7420", "", func(string) (PromptSource, error) { return PromptSource{}, nil })
+	}()
+	addr := <-listened
+	if line := <-logs; !strings.Contains(line, "serving MCP over HTTP at http://"+addr+"/mcp") {
+		t.Fatalf("unexpected log %q", line)
+	}
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "v1"}, nil).Connect(ctx, &mcp.StreamableClientTransport{Endpoint: "http://" + addr + "/mcp", MaxRetries: -1}, nil)
+	if err != nil {
+		t.Fatalf("Connect error: %v", err)
+	}
+	_ = session.Close()
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("ServePromptsHTTP error: %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/conn-castle/agent-layer/internal/memory"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+type addIssueInput struct {
+	Title       string `json:"title" jsonschema:"short title, one line"`
+	Priority    string `json:"priority" jsonschema:"Critical, High, Medium, or Low"`
+	Area        string `json:"area" jsonschema:"affected area, for example sync or mcp"`
+	Description string `json:"description" jsonschema:"observed problem or risk"`
+	NextStep    string `json:"next_step" jsonschema:"smallest concrete next action"`
+	Notes       string `json:"notes,omitempty" jsonschema:"optional dependencies or constraints"`
+}
+
+type addBacklogInput struct {
+	Title              string `json:"title" jsonschema:"short title, one line"`
+	Priority           string `json:"priority" jsonschema:"Critical, High, Medium, or Low"`
+	Area               string `json:"area" jsonschema:"affected area, for example sync or mcp"`
+	Description        string `json:"description" jsonschema:"what the user should be able to do"`
+	AcceptanceCriteria string `json:"acceptance_criteria" jsonschema:"clear condition to consider it done"`
+	Notes              string `json:"notes,omitempty" jsonschema:"optional dependencies or constraints"`
+}
+
+type addDecisionInput struct {
+	Title     string `json:"title" jsonschema:"short title, one line"`
+	Decision  string `json:"decision" jsonschema:"what was chosen"`
+	Reason    string `json:"reason" jsonschema:"why it was chosen"`
+	Tradeoffs string `json:"tradeoffs" jsonschema:"what is gained and what is lost"`
+}
+
+type searchInput struct {
+	Query string `json:"query" jsonschema:"words every matching entry contains, ignoring case"`
+	Kind  string `json:"kind,omitempty" jsonschema:"optional: issue, backlog, or decision"`
+}
+
+type resolveInput struct {
+	ID string `json:"id" jsonschema:"id of the issue or backlog entry, for example a1b2c3"`
+}
+
+type entryOutput struct {
+	ID    string `json:"id"`
+	File  string `json:"file"`
+	Entry string `json:"entry"`
+}
+
+type searchOutput struct {
+	Entries []entryOutput `json:"entries"`
+}
+
+// addMemoryTools registers tools that add, search, and resolve entries in the memory files in dir.
+// Clients ask before calling them unless approvals.mode auto-approves MCP tools.
+func addMemoryTools(server *mcp.Server, dir string) {
+	notDestructive, destructive := false, true
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "memory_add_issue",
+		Description: messages.MemoryAddIssueDescription,
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: &notDestructive},
+	}, func(_ context.Context, _ *mcp.CallToolRequest, in addIssueInput) (*mcp.CallToolResult, entryOutput, error) {
+		return entryResult(memory.AddIssue(dir, memory.Issue(in)))
+	})
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "memory_add_backlog",
+		Description: messages.MemoryAddBacklogDesc,
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: &notDestructive},
+	}, func(_ context.Context, _ *mcp.CallToolRequest, in addBacklogInput) (*mcp.CallToolResult, entryOutput, error) {
+		return entryResult(memory.AddBacklog(dir, memory.Backlog(in)))
+	})
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "memory_add_decision",
+		Description: messages.MemoryAddDecisionDesc,
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: &notDestructive},
+	}, func(_ context.Context, _ *mcp.CallToolRequest, in addDecisionInput) (*mcp.CallToolResult, entryOutput, error) {
+		return entryResult(memory.AddDecision(dir, memory.Decision(in)))
+	})
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "memory_search",
+		Description: messages.MemorySearchDescription,
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(_ context.Context, _ *mcp.CallToolRequest, in searchInput) (*mcp.CallToolResult, searchOutput, error) {
+		var kinds []memory.Kind
+		if in.Kind != "" {
+			kind, err := memory.ParseKind(in.Kind)
+			if err != nil {
+				return nil, searchOutput{}, err
+			}
+			kinds = append(kinds, kind)
+		}
+		entries, err := memory.Search(dir, in.Query, kinds...)
+		if err != nil {
+			return nil, searchOutput{}, err
+		}
+		out := searchOutput{Entries: []entryOutput{}}
+		for _, entry := range entries {
+			out.Entries = append(out.Entries, newEntryOutput(entry))
+		}
+		return nil, out, nil
+	})
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "memory_resolve",
+		Description: messages.MemoryResolveDescription,
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: &destructive},
+	}, func(_ context.Context, _ *mcp.CallToolRequest, in resolveInput) (*mcp.CallToolResult, entryOutput, error) {
+		return entryResult(memory.Resolve(dir, in.ID))
+	})
+}
+
+func entryResult(entry memory.Entry, err error) (*mcp.CallToolResult, entryOutput, error) {
+	if err != nil {
+		return nil, entryOutput{}, err
+	}
+	return nil, newEntryOutput(entry), nil
+}
+
+func newEntryOutput(entry memory.Entry) entryOutput {
+	return entryOutput{ID: entry.ID, File: entry.File, Entry: entry.Text}
+}
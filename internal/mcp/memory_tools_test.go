@@ -0,0 +1,137 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestRunPromptServerMemoryTools(t *testing.T) {
+	originalRun := runServer
+	t.Cleanup(func() { runServer = originalRun })
+
+	dir := t.TempDir()
+	issues := filepath.Join(dir, "ISSUES.md")
+	if err := os.WriteFile(issues, []byte("# Issues\n\n<!-- ENTRIES START -->\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	runServer = func(ctx context.Context, server *mcp.Server) error {
+		clientTransport, serverTransport := mcp.NewInMemoryTransports()
+		serverSession, err := server.Connect(ctx, serverTransport, nil)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = serverSession.Close() }()
+		client := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "v1"}, nil)
+		session, err := client.Connect(ctx, clientTransport, nil)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = session.Close() }()
+
+		list, err := session.ListTools(ctx, nil)
+		if err != nil {
+			t.Fatalf("ListTools error: %v", err)
+		}
+		var names []string
+		for _, tool := range list.Tools {
+			names = append(names, tool.Name)
+			if tool.Name == "memory_search" && !tool.Annotations.ReadOnlyHint {
+				t.Fatalf("expected memory_search to be read-only")
+			}
+		}
+		sort.Strings(names)
+		if strings.Join(names, ",") != "memory_add_backlog,memory_add_decision,memory_add_issue,memory_resolve,memory_search" {
+			t.Fatalf("unexpected tools %v", names)
+		}
+
+		call := func(name string, args map[string]any) (*mcp.CallToolResult, map[string]any) {
+			t.Helper()
+			result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: args})
+			if err != nil {
+				t.Fatalf("CallTool %s error: %v", name, err)
+			}
+			var out map[string]any
+			if !result.IsError {
+				data, _ := json.Marshal(result.StructuredContent)
+				_ = json.Unmarshal(data, &out)
+			}
+			return result, out
+		}
+
+		issue := map[string]any{"title": "Watcher leaks", "priority": "high", "area": "mcp", "description": "d", "next_step": "n"}
+		_, added := call("memory_add_issue", issue)
+		id, _ := added["id"].(string)
+		if id == "" || added["file"] != "ISSUES.md" || !strings.Contains(added["entry"].(string), "Priority: High. Area: mcp") {
+			t.Fatalf("unexpected memory_add_issue output %+v", added)
+		}
+		if result, _ := call("memory_add_issue", issue); !result.IsError {
+			t.Fatalf("expected duplicate issue to fail")
+		}
+		if _, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "memory_add_issue", Arguments: map[string]any{"title": "x"}}); err == nil {
+			t.Fatalf("expected missing fields to fail schema validation")
+		}
+		invalid := map[string]any{"title": "x", "priority": "urgent", "area": "a", "description": "d", "next_step": "n"}
+		if result, _ := call("memory_add_issue", invalid); !result.IsError {
+			t.Fatalf("expected invalid priority to fail")
+		}
+
+		_, found := call("memory_search", map[string]any{"query": "watcher", "kind": "issue"})
+		if entries, _ := found["entries"].([]any); len(entries) != 1 {
+			t.Fatalf("unexpected memory_search output %+v", found)
+		}
+		if result, _ := call("memory_search", map[string]any{"query": "watcher", "kind": "roadmap"}); !result.IsError {
+			t.Fatalf("expected unknown kind to fail")
+		}
+		if result, _ := call("memory_add_decision", map[string]any{"title": "t", "decision": "d", "reason": "r", "tradeoffs": "t"}); !result.IsError {
+			t.Fatalf("expected missing DECISIONS.md to fail")
+		}
+
+		if _, resolved := call("memory_resolve", map[string]any{"id": id}); resolved["id"] != id {
+			t.Fatalf("unexpected memory_resolve output %+v", resolved)
+		}
+		data, err := os.ReadFile(issues)
+		if err != nil || string(data) != "# Issues\n\n<!-- ENTRIES START -->\n" {
+			t.Fatalf("expected resolved issue to be removed, got %q, %v", data, err)
+		}
+		return nil
+	}
+
+	if err := RunPromptServer(context.Background(), "v1", PromptSource{MemoryDir: dir}); err != nil {
+		t.Fatalf("RunPromptServer error: %v", err)
+	}
+}
+
+func TestRunPromptServerWithoutMemoryDir(t *testing.T) {
+	originalRun := runServer
+	t.Cleanup(func() { runServer = originalRun })
+
+	runServer = func(ctx context.Context, server *mcp.Server) error {
+		clientTransport, serverTransport := mcp.NewInMemoryTransports()
+		serverSession, err := server.Connect(ctx, serverTransport, nil)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = serverSession.Close() }()
+		session, err := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "v1"}, nil).Connect(ctx, clientTransport, nil)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = session.Close() }()
+		if caps := session.InitializeResult().Capabilities.Tools; caps != nil {
+			t.Fatalf("expected no tools capability without a memory dir, got %+v", caps)
+		}
+		return nil
+	}
+
+	if err := RunPromptServer(context.Background(), "v1", PromptSource{}); err != nil {
+		t.Fatalf("RunPromptServer error: %v", err)
+	}
+}
@@ -3,6 +3,8 @@ package mcp
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"strings"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
@@ -14,39 +16,174 @@ var runServer = func(ctx context.Context, server *mcp.Server) error {
 	return server.Run(ctx, &mcp.StdioTransport{})
 }
 
-// RunPromptServer starts an MCP prompt server over stdio.
-func RunPromptServer(ctx context.Context, version string, commands []config.SlashCommand) error {
+// PromptSource supplies what the prompt server serves: slash commands as prompts and project files
+// as resources.
+type PromptSource struct {
+	// Commands are served at startup, rendered with config.ArgumentsPromptServer.
+	Commands []config.SlashCommand
+	// Resources are served at startup; see LoadResources.
+	Resources []Resource
+	// Reload loads the commands and resources again, the same way, after a change under WatchDirs.
+	Reload func() ([]config.SlashCommand, []Resource, error)
+	// WatchDirs are the directories to watch; empty disables reloading.
+	WatchDirs []string
+	// MemoryDir is docs/agent-layer/; when set, the memory tools edit the memory files in it.
+	MemoryDir string
+	// CommandTools also registers each command as a run_command_<name> tool, for clients that do not
+	// surface MCP prompts.
+	CommandTools bool
+}
+
+// RunPromptServer starts an MCP prompt server over stdio. When source.WatchDirs is set, edited,
+// added, and removed files are applied to the running server, which notifies clients with
+// notifications/prompts/list_changed, notifications/resources/list_changed, and
+// notifications/resources/updated for subscribed resources. A reload that fails is logged and the
+// previous prompts and resources are kept. When source.MemoryDir is set, the server also offers the
+// memory_* tools, which add, search, and resolve memory file entries. When source.CommandTools is set,
+// each slash command is also a run_command_<name> tool that returns its body.
+func RunPromptServer(ctx context.Context, version string, source PromptSource) error {
+	server, stop := newPromptServer(ctx, version, source)
+	defer stop()
+
+	if err := runServer(ctx, server); err != nil {
+		return fmt.Errorf(messages.McpRunPromptServerFailedFmt, err)
+	}
+
+	return nil
+}
+
+// newPromptServer builds the server for source and starts watching source.WatchDirs; stop ends the
+// watch.
+func newPromptServer(ctx context.Context, version string, source PromptSource) (*mcp.Server, func()) {
 	server := mcp.NewServer(&mcp.Implementation{
 		Name:    "agent-layer",
 		Version: version,
-	}, nil)
+	}, &mcp.ServerOptions{
+		HasPrompts:         true,
+		HasResources:       true,
+		SubscribeHandler:   subscribe,
+		UnsubscribeHandler: unsubscribe,
+	})
 
-	for _, cmd := range commands {
-		cmd := cmd
-		prompt := &mcp.Prompt{
-			Name:        cmd.Name,
-			Description: cmd.Description,
+	prompts := &promptSet{server: server, registered: map[string]config.SlashCommand{}}
+	prompts.update(source.Commands)
+	var tools *commandToolSet
+	if source.CommandTools {
+		tools = &commandToolSet{server: server, registered: map[string]config.SlashCommand{}}
+		tools.update(source.Commands)
+	}
+	resources := &resourceSet{server: server, byURI: map[string]Resource{}}
+	addResourceTemplates(server, resources)
+	resources.update(ctx, source.Resources)
+	if source.MemoryDir != "" {
+		addMemoryTools(server, source.MemoryDir)
+	}
+
+	stop := func() {}
+	if len(source.WatchDirs) > 0 && source.Reload != nil {
+		stopWatch, err := watchDirs(ctx, source.WatchDirs, func() {
+			commands, files, err := source.Reload()
+			if err != nil {
+				_, _ = fmt.Fprintf(logOutput, messages.McpPromptsReloadFailedFmt, err)
+				return
+			}
+			prompts.update(commands)
+			if tools != nil {
+				tools.update(commands)
+			}
+			resources.update(ctx, files)
+		})
+		if err != nil {
+			_, _ = fmt.Fprintf(logOutput, messages.McpPromptsWatchFailedFmt, err)
+		} else {
+			stop = stopWatch
 		}
-		server.AddPrompt(prompt, promptHandler(cmd))
 	}
 
-	if err := runServer(ctx, server); err != nil {
-		return fmt.Errorf(messages.McpRunPromptServerFailedFmt, err)
+	return server, stop
+}
+
+// promptSet tracks the prompts registered on server so reloads only touch the ones that changed.
+type promptSet struct {
+	server     *mcp.Server
+	registered map[string]config.SlashCommand
+}
+
+// update registers commands on the server, replacing changed prompts and removing ones that are gone.
+func (p *promptSet) update(commands []config.SlashCommand) {
+	wanted := make(map[string]struct{}, len(commands))
+	for _, cmd := range commands {
+		name := cmd.FlatName()
+		wanted[name] = struct{}{}
+		if existing, ok := p.registered[name]; ok && reflect.DeepEqual(existing, cmd) {
+			continue
+		}
+		p.registered[name] = cmd
+		p.server.AddPrompt(newPrompt(cmd), promptHandler(cmd))
 	}
+	var removed []string
+	for name := range p.registered {
+		if _, ok := wanted[name]; !ok {
+			removed = append(removed, name)
+			delete(p.registered, name)
+		}
+	}
+	if len(removed) > 0 {
+		p.server.RemovePrompts(removed...)
+	}
+}
 
-	return nil
+// newPrompt describes cmd as an MCP prompt under its flattened name.
+func newPrompt(cmd config.SlashCommand) *mcp.Prompt {
+	prompt := &mcp.Prompt{
+		Name:        cmd.FlatName(),
+		Description: cmd.Description,
+	}
+	for _, arg := range cmd.Arguments {
+		prompt.Arguments = append(prompt.Arguments, &mcp.PromptArgument{
+			Name:        arg.Name,
+			Description: arg.Description,
+			Required:    arg.Required,
+		})
+	}
+	return prompt
 }
 
+// promptHandler serves cmd, whose body was rendered with config.ArgumentsPromptServer placeholders.
 func promptHandler(cmd config.SlashCommand) func(context.Context, *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
 	return func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		var values map[string]string
+		if req != nil && req.Params != nil {
+			values = req.Params.Arguments
+		}
+		body, err := substituteArguments(cmd, values)
+		if err != nil {
+			return nil, err
+		}
 		return &mcp.GetPromptResult{
 			Description: cmd.Description,
 			Messages: []*mcp.PromptMessage{
 				{
 					Role:    "user",
-					Content: &mcp.TextContent{Text: cmd.Body},
+					Content: &mcp.TextContent{Text: body},
 				},
 			},
 		}, nil
 	}
 }
+
+// substituteArguments fills cmd's argument placeholders from values, falling back to defaults.
+func substituteArguments(cmd config.SlashCommand, values map[string]string) (string, error) {
+	body := cmd.Body
+	for _, arg := range cmd.Arguments {
+		value := values[arg.Name]
+		if value == "" {
+			if arg.Required {
+				return "", fmt.Errorf(messages.McpPromptArgumentRequiredFmt, arg.Name)
+			}
+			value = arg.Default
+		}
+		body = strings.ReplaceAll(body, config.PromptArgumentPlaceholder(arg.Name), value)
+	}
+	return body, nil
+}
@@ -3,8 +3,13 @@ package mcp
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
@@ -24,7 +29,7 @@ func TestRunPromptServerInvokesRunner(t *testing.T) {
 	commands := []config.SlashCommand{
 		{Name: "alpha", Description: "desc", Body: "body"},
 	}
-	if err := RunPromptServer(context.Background(), "v1", commands); err != nil {
+	if err := RunPromptServer(context.Background(), "v1", PromptSource{Commands: commands}); err != nil {
 		t.Fatalf("RunPromptServer error: %v", err)
 	}
 	if !called {
@@ -40,7 +45,7 @@ func TestRunPromptServerPropagatesError(t *testing.T) {
 		return errors.New("boom")
 	}
 
-	err := RunPromptServer(context.Background(), "v1", nil)
+	err := RunPromptServer(context.Background(), "v1", PromptSource{})
 	if err == nil || !strings.Contains(err.Error(), "failed to run MCP prompt server") {
 		t.Fatalf("expected wrapped error, got %v", err)
 	}
@@ -67,3 +72,205 @@ func TestPromptHandler(t *testing.T) {
 		t.Fatalf("unexpected message content: %#v", result.Messages[0].Content)
 	}
 }
+
+func TestPromptHandlerSubstitutesArguments(t *testing.T) {
+	cmd := config.SlashCommand{
+		Name:        "fix",
+		Description: "desc",
+		Body:        "Fix ${arg:issue} on ${arg:branch}${arg:note}.",
+		Arguments: []config.SlashCommandArgument{
+			{Name: "issue", Required: true},
+			{Name: "branch", Default: "main"},
+			{Name: "note"},
+		},
+	}
+	handler := promptHandler(cmd)
+
+	req := &mcp.GetPromptRequest{Params: &mcp.GetPromptParams{Name: "fix", Arguments: map[string]string{"issue": "#42"}}}
+	result, err := handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text := result.Messages[0].Content.(*mcp.TextContent).Text; text != "Fix #42 on main." {
+		t.Fatalf("unexpected body: %q", text)
+	}
+
+	req.Params.Arguments = map[string]string{"issue": "#7", "branch": "dev", "note": " quickly"}
+	result, err = handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text := result.Messages[0].Content.(*mcp.TextContent).Text; text != "Fix #7 on dev quickly." {
+		t.Fatalf("unexpected body: %q", text)
+	}
+
+	if _, err := handler(context.Background(), &mcp.GetPromptRequest{Params: &mcp.GetPromptParams{Name: "fix"}}); err == nil || !strings.Contains(err.Error(), `missing required argument "issue"`) {
+		t.Fatalf("expected missing argument error, got %v", err)
+	}
+}
+
+func TestRunPromptServerRegistersArguments(t *testing.T) {
+	original := runServer
+	t.Cleanup(func() { runServer = original })
+
+	var prompts []*mcp.Prompt
+	runServer = func(ctx context.Context, server *mcp.Server) error {
+		clientTransport, serverTransport := mcp.NewInMemoryTransports()
+		serverSession, err := server.Connect(ctx, serverTransport, nil)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = serverSession.Close() }()
+		client := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "v1"}, nil)
+		session, err := client.Connect(ctx, clientTransport, nil)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = session.Close() }()
+		result, err := session.ListPrompts(ctx, nil)
+		if err != nil {
+			return err
+		}
+		prompts = result.Prompts
+		return nil
+	}
+
+	commands := []config.SlashCommand{{
+		Name:        "issues:fix",
+		Description: "desc",
+		Body:        "Fix ${arg:issue}",
+		Arguments:   []config.SlashCommandArgument{{Name: "issue", Description: "Issue number", Required: true}},
+	}}
+	if err := RunPromptServer(context.Background(), "v1", PromptSource{Commands: commands}); err != nil {
+		t.Fatalf("RunPromptServer error: %v", err)
+	}
+	if len(prompts) != 1 || len(prompts[0].Arguments) != 1 {
+		t.Fatalf("expected one prompt with one argument, got %#v", prompts)
+	}
+	if prompts[0].Name != "issues-fix" {
+		t.Fatalf("expected flattened prompt name, got %q", prompts[0].Name)
+	}
+	if arg := prompts[0].Arguments[0]; arg.Name != "issue" || arg.Description != "Issue number" || !arg.Required {
+		t.Fatalf("unexpected argument: %#v", arg)
+	}
+}
+
+type lineWriter chan string
+
+func (w lineWriter) Write(p []byte) (int, error) {
+	w <- string(p)
+	return len(p), nil
+}
+
+func TestRunPromptServerReload(t *testing.T) {
+	setReloadDelay(t, 20*time.Millisecond)
+	originalLog := logOutput
+	logs := make(lineWriter, 10)
+	logOutput = logs
+	originalRun := runServer
+	t.Cleanup(func() {
+		runServer = originalRun
+		logOutput = originalLog
+	})
+
+	dir := t.TempDir()
+	var mu sync.Mutex
+	var next []config.SlashCommand
+	var nextErr error
+	setNext := func(commands []config.SlashCommand, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		next, nextErr = commands, err
+	}
+	touch := func() {
+		if err := os.WriteFile(filepath.Join(dir, "cmd.md"), []byte(time.Now().String()), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	alpha := config.SlashCommand{Name: "alpha", Description: "Alpha", Body: "a"}
+	source := PromptSource{
+		Commands: []config.SlashCommand{alpha},
+		Reload: func() ([]config.SlashCommand, []Resource, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			return next, nil, nextErr
+		},
+		WatchDirs: []string{dir},
+	}
+
+	runServer = func(ctx context.Context, server *mcp.Server) error {
+		clientTransport, serverTransport := mcp.NewInMemoryTransports()
+		serverSession, err := server.Connect(ctx, serverTransport, nil)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = serverSession.Close() }()
+		changed := make(chan struct{}, 10)
+		client := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "v1"}, &mcp.ClientOptions{
+			PromptListChangedHandler: func(context.Context, *mcp.PromptListChangedRequest) { changed <- struct{}{} },
+		})
+		session, err := client.Connect(ctx, clientTransport, nil)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = session.Close() }()
+		names := func() string {
+			result, err := session.ListPrompts(ctx, nil)
+			if err != nil {
+				t.Fatalf("ListPrompts error: %v", err)
+			}
+			var names []string
+			for _, prompt := range result.Prompts {
+				names = append(names, prompt.Name+"="+prompt.Description)
+			}
+			sort.Strings(names)
+			return strings.Join(names, ",")
+		}
+		// waitPrompts waits for list_changed notifications until the prompt list is want.
+		waitPrompts := func(want string) {
+			timeout := time.After(5 * time.Second)
+			for {
+				select {
+				case <-changed:
+					if names() == want {
+						return
+					}
+				case <-timeout:
+					t.Fatalf("timed out waiting for prompts %q, have %q", want, names())
+				}
+			}
+		}
+
+		if got := names(); got != "alpha=Alpha" {
+			t.Fatalf("unexpected initial prompts %q", got)
+		}
+
+		setNext([]config.SlashCommand{{Name: "alpha", Description: "Alpha v2", Body: "a"}, {Name: "git:commit", Description: "Commit", Body: "c"}}, nil)
+		touch()
+		waitPrompts("alpha=Alpha v2,git-commit=Commit")
+
+		setNext(nil, errors.New("bad front matter"))
+		touch()
+		select {
+		case line := <-logs:
+			if !strings.Contains(line, "keeping the previous slash commands and resources: bad front matter") {
+				t.Fatalf("unexpected log %q", line)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for reload failure to be logged")
+		}
+		if got := names(); got != "alpha=Alpha v2,git-commit=Commit" {
+			t.Fatalf("expected last good prompts after failed reload, got %q", got)
+		}
+
+		setNext([]config.SlashCommand{{Name: "git:commit", Description: "Commit", Body: "c"}}, nil)
+		touch()
+		waitPrompts("git-commit=Commit")
+		return nil
+	}
+
+	if err := RunPromptServer(context.Background(), "v1", source); err != nil {
+		t.Fatalf("RunPromptServer error: %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// Resource URIs are stable: agent-layer://memory/<file> for docs/agent-layer/<file> and
+// agent-layer://instructions/<file> for .agent-layer/instructions/<file>.
+const (
+	resourceScheme            = "agent-layer://"
+	memoryResourcePrefix      = resourceScheme + "memory/"
+	instructionResourcePrefix = resourceScheme + "instructions/"
+	resourceMIMEType          = "text/markdown"
+)
+
+// memoryDescriptions describes the memory files agent-layer templates create.
+var memoryDescriptions = map[string]string{
+	"ISSUES.md":    messages.McpResourceIssuesDescription,
+	"BACKLOG.md":   messages.McpResourceBacklogDescription,
+	"ROADMAP.md":   messages.McpResourceRoadmapDescription,
+	"DECISIONS.md": messages.McpResourceDecisionsDescription,
+	"COMMANDS.md":  messages.McpResourceCommandsDescription,
+}
+
+// Resource is a project file served by the agent-layer MCP server.
+type Resource struct {
+	URI         string
+	Name        string
+	Description string
+	Content     string
+}
+
+// MemoryResourceURI returns the URI of docs/agent-layer/<file>.
+func MemoryResourceURI(file string) string {
+	return memoryResourcePrefix + file
+}
+
+// InstructionResourceURI returns the URI of the instruction fragment <file>.
+func InstructionResourceURI(file string) string {
+	return instructionResourcePrefix + file
+}
+
+// LoadResources returns the memory files in docs/agent-layer/ and the root instruction fragments that
+// target client, rendered for it. An empty client gets every fragment.
+func LoadResources(project *config.ProjectConfig, client string) ([]Resource, error) {
+	var resources []Resource
+	memoryDir := config.DefaultPaths(project.Root).MemoryDir
+	entries, err := os.ReadDir(memoryDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf(messages.McpResourceReadFailedFmt, memoryDir, err)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".md") {
+			continue
+		}
+		path := filepath.Join(memoryDir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf(messages.McpResourceReadFailedFmt, path, err)
+		}
+		description, ok := memoryDescriptions[name]
+		if !ok {
+			description = fmt.Sprintf(messages.McpResourceMemoryDescriptionFmt, name)
+		}
+		resources = append(resources, Resource{URI: MemoryResourceURI(name), Name: name, Description: description, Content: string(data)})
+	}
+
+	files := project.Instructions
+	if client != "" {
+		files = config.InstructionsForClients(files, client)
+	}
+	rendered, err := config.RenderInstructions(files, config.NewTemplateData(project, client))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range rendered {
+		resources = append(resources, Resource{
+			URI:         InstructionResourceURI(file.Name),
+			Name:        file.Name,
+			Description: fmt.Sprintf(messages.McpResourceInstructionDescriptionFmt, file.Name),
+			Content:     file.Content,
+		})
+	}
+	return resources, nil
+}
+
+// resourceSet tracks the resources registered on server. Reloads register new resources, remove
+// deleted ones, and send notifications/resources/updated to subscribers of resources whose content changed.
+type resourceSet struct {
+	server *mcp.Server
+
+	mu    sync.Mutex
+	byURI map[string]Resource
+}
+
+// addResourceTemplates registers URI templates so clients can address memory files and fragments by name.
+func addResourceTemplates(server *mcp.Server, set *resourceSet) {
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "memory",
+		URITemplate: memoryResourcePrefix + "{file}",
+		Description: messages.McpResourceMemoryTemplateDescription,
+		MIMEType:    resourceMIMEType,
+	}, set.read)
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "instructions",
+		URITemplate: instructionResourcePrefix + "{file}",
+		Description: messages.McpResourceInstructionTemplateDesc,
+		MIMEType:    resourceMIMEType,
+	}, set.read)
+}
+
+// update applies resources to the server. Subscribers are notified of created, edited, and deleted files.
+func (s *resourceSet) update(ctx context.Context, resources []Resource) {
+	s.mu.Lock()
+	wanted := make(map[string]struct{}, len(resources))
+	var added []Resource
+	var updated, removed []string
+	for _, resource := range resources {
+		wanted[resource.URI] = struct{}{}
+		existing, ok := s.byURI[resource.URI]
+		s.byURI[resource.URI] = resource
+		if !ok || existing.Name != resource.Name || existing.Description != resource.Description {
+			added = append(added, resource)
+		}
+		if !ok || existing.Content != resource.Content {
+			updated = append(updated, resource.URI)
+		}
+	}
+	for uri := range s.byURI {
+		if _, ok := wanted[uri]; !ok {
+			removed = append(removed, uri)
+			delete(s.byURI, uri)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, resource := range added {
+		s.server.AddResource(&mcp.Resource{
+			URI:         resource.URI,
+			Name:        resource.Name,
+			Description: resource.Description,
+			MIMEType:    resourceMIMEType,
+		}, s.read)
+	}
+	if len(removed) > 0 {
+		sort.Strings(removed)
+		s.server.RemoveResources(removed...)
+	}
+	for _, uri := range append(updated, removed...) {
+		_ = s.server.ResourceUpdated(ctx, &mcp.ResourceUpdatedNotificationParams{URI: uri})
+	}
+}
+
+// read serves the current content of a registered resource.
+func (s *resourceSet) read(_ context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	s.mu.Lock()
+	resource, ok := s.byURI[req.Params.URI]
+	s.mu.Unlock()
+	if !ok {
+		return nil, mcp.ResourceNotFoundError(req.Params.URI)
+	}
+	return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{
+		URI:      resource.URI,
+		MIMEType: resourceMIMEType,
+		Text:     resource.Content,
+	}}}, nil
+}
+
+// subscribe accepts subscriptions to agent-layer:// URIs, including files that do not exist yet.
+func subscribe(_ context.Context, req *mcp.SubscribeRequest) error {
+	if !strings.HasPrefix(req.Params.URI, resourceScheme) {
+		return fmt.Errorf(messages.McpResourceSubscribeUnknownFmt, req.Params.URI)
+	}
+	return nil
+}
+
+func unsubscribe(context.Context, *mcp.UnsubscribeRequest) error {
+	return nil
+}
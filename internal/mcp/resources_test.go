@@ -0,0 +1,182 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+func TestLoadResources(t *testing.T) {
+	root := t.TempDir()
+	memoryDir := filepath.Join(root, "docs", "agent-layer")
+	if err := os.MkdirAll(filepath.Join(memoryDir, "archive"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	for name, content := range map[string]string{"ISSUES.md": "issues", "NOTES.md": "notes", "notes.txt": "skip"} {
+		if err := os.WriteFile(filepath.Join(memoryDir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	project := &config.ProjectConfig{
+		Root: root,
+		Instructions: []config.InstructionFile{
+			{Name: "00_base.md", Content: "Hello {{ .Client }}", Template: true},
+			{Name: "10_claude.md", Content: "Claude only", Clients: []string{"claude"}},
+		},
+	}
+
+	resources, err := LoadResources(project, "gemini")
+	if err != nil {
+		t.Fatalf("LoadResources error: %v", err)
+	}
+	byURI := make(map[string]Resource, len(resources))
+	for _, resource := range resources {
+		byURI[resource.URI] = resource
+	}
+	if len(byURI) != 3 {
+		t.Fatalf("unexpected resources %+v", resources)
+	}
+	if r := byURI["agent-layer://memory/ISSUES.md"]; r.Content != "issues" || r.Description != messages.McpResourceIssuesDescription {
+		t.Fatalf("unexpected ISSUES.md resource %+v", r)
+	}
+	if r := byURI["agent-layer://memory/NOTES.md"]; !strings.Contains(r.Description, "docs/agent-layer/NOTES.md") {
+		t.Fatalf("unexpected NOTES.md resource %+v", r)
+	}
+	if r := byURI["agent-layer://instructions/00_base.md"]; r.Content != "Hello gemini" {
+		t.Fatalf("expected fragment rendered for gemini, got %+v", r)
+	}
+
+	resources, err = LoadResources(project, "")
+	if err != nil || len(resources) != 4 {
+		t.Fatalf("expected every fragment without a client, got %+v, %v", resources, err)
+	}
+
+	if resources, err := LoadResources(&config.ProjectConfig{Root: t.TempDir()}, ""); err != nil || len(resources) != 0 {
+		t.Fatalf("expected no resources without memory files, got %+v, %v", resources, err)
+	}
+	bad := &config.ProjectConfig{Root: root, Instructions: []config.InstructionFile{{Name: "bad.md", Content: "{{ .Missing ", Template: true}}}
+	if _, err := LoadResources(bad, ""); err == nil {
+		t.Fatalf("expected render error")
+	}
+}
+
+func TestRunPromptServerResources(t *testing.T) {
+	setReloadDelay(t, 20*time.Millisecond)
+	originalRun := runServer
+	t.Cleanup(func() { runServer = originalRun })
+
+	dir := t.TempDir()
+	var mu sync.Mutex
+	next := []Resource{{URI: MemoryResourceURI("ISSUES.md"), Name: "ISSUES.md", Content: "v1"}}
+	source := PromptSource{
+		Resources: next,
+		Reload: func() ([]config.SlashCommand, []Resource, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			return nil, next, nil
+		},
+		WatchDirs: []string{dir},
+	}
+	setNext := func(resources []Resource) {
+		mu.Lock()
+		defer mu.Unlock()
+		next = resources
+		if err := os.WriteFile(filepath.Join(dir, "ISSUES.md"), []byte(time.Now().String()), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	runServer = func(ctx context.Context, server *mcp.Server) error {
+		clientTransport, serverTransport := mcp.NewInMemoryTransports()
+		serverSession, err := server.Connect(ctx, serverTransport, nil)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = serverSession.Close() }()
+		updated := make(chan string, 10)
+		client := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "v1"}, &mcp.ClientOptions{
+			ResourceUpdatedHandler: func(_ context.Context, req *mcp.ResourceUpdatedNotificationRequest) {
+				updated <- req.Params.URI
+			},
+		})
+		session, err := client.Connect(ctx, clientTransport, nil)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = session.Close() }()
+
+		if caps := session.InitializeResult().Capabilities.Resources; caps == nil || !caps.Subscribe || !caps.ListChanged {
+			t.Fatalf("expected resource subscribe and list_changed capabilities, got %+v", caps)
+		}
+		list, err := session.ListResources(ctx, nil)
+		if err != nil || len(list.Resources) != 1 || list.Resources[0].URI != "agent-layer://memory/ISSUES.md" {
+			t.Fatalf("unexpected resources %+v, %v", list, err)
+		}
+		templates, err := session.ListResourceTemplates(ctx, nil)
+		if err != nil {
+			t.Fatalf("ListResourceTemplates error: %v", err)
+		}
+		var uriTemplates []string
+		for _, tmpl := range templates.ResourceTemplates {
+			uriTemplates = append(uriTemplates, tmpl.URITemplate)
+		}
+		sort.Strings(uriTemplates)
+		if strings.Join(uriTemplates, ",") != "agent-layer://instructions/{file},agent-layer://memory/{file}" {
+			t.Fatalf("unexpected resource templates %v", uriTemplates)
+		}
+		read := func(uri string) (string, error) {
+			result, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: uri})
+			if err != nil {
+				return "", err
+			}
+			return result.Contents[0].Text, nil
+		}
+		if text, err := read("agent-layer://memory/ISSUES.md"); err != nil || text != "v1" {
+			t.Fatalf("unexpected ISSUES.md content %q, %v", text, err)
+		}
+		if _, err := read("agent-layer://memory/ROADMAP.md"); err == nil {
+			t.Fatalf("expected missing memory file to be not found")
+		}
+
+		if err := session.Subscribe(ctx, &mcp.SubscribeParams{URI: "agent-layer://memory/ISSUES.md"}); err != nil {
+			t.Fatalf("Subscribe error: %v", err)
+		}
+		if err := session.Subscribe(ctx, &mcp.SubscribeParams{URI: "file:///etc/passwd"}); err == nil {
+			t.Fatalf("expected subscription outside agent-layer:// to fail")
+		}
+
+		setNext([]Resource{
+			{URI: MemoryResourceURI("ISSUES.md"), Name: "ISSUES.md", Content: "v2"},
+			{URI: MemoryResourceURI("ROADMAP.md"), Name: "ROADMAP.md", Content: "roadmap"},
+		})
+		select {
+		case uri := <-updated:
+			if uri != "agent-layer://memory/ISSUES.md" {
+				t.Fatalf("unexpected updated resource %q", uri)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for resources/updated")
+		}
+		if text, err := read("agent-layer://memory/ISSUES.md"); err != nil || text != "v2" {
+			t.Fatalf("unexpected ISSUES.md content after reload %q, %v", text, err)
+		}
+		if text, err := read("agent-layer://memory/ROADMAP.md"); err != nil || text != "roadmap" {
+			t.Fatalf("expected new memory file to be readable, got %q, %v", text, err)
+		}
+		return nil
+	}
+
+	if err := RunPromptServer(context.Background(), "v1", source); err != nil {
+		t.Fatalf("RunPromptServer error: %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDelay is how long to wait after the last change before reloading, so an editor's
+// write-rename-chmod sequence or a git checkout triggers one reload. A package var for tests.
+var reloadDelay = 250 * time.Millisecond
+
+// logOutput receives reload problems; stdout carries the MCP protocol.
+var logOutput io.Writer = os.Stderr
+
+// watchDirs calls reload once changes under dirs (including subdirectories created later) have
+// settled for reloadDelay. The returned stop function ends the watch and waits for it to finish.
+func watchDirs(ctx context.Context, dirs []string, reload func()) (func(), error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range dirs {
+		if err := addTree(watcher, dir); err != nil {
+			_ = watcher.Close()
+			return nil, err
+		}
+	}
+
+	delay := reloadDelay
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		timer := time.NewTimer(delay)
+		timer.Stop()
+		defer timer.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if event.Has(fsnotify.Create) {
+					if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+						_ = addTree(watcher, event.Name)
+					}
+				}
+				timer.Reset(delay)
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				// Events may have been dropped; reload to resynchronize.
+				timer.Reset(delay)
+			case <-timer.C:
+				reload()
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		_ = watcher.Close()
+		<-done
+	}, nil
+}
+
+// addTree watches dir and every directory below it. A missing dir is skipped.
+func addTree(watcher *fsnotify.Watcher, dir string) error {
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return watcher.Add(path)
+		}
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,74 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func setReloadDelay(t *testing.T, delay time.Duration) {
+	t.Helper()
+	original := reloadDelay
+	t.Cleanup(func() { reloadDelay = original })
+	reloadDelay = delay
+}
+
+func waitReload(t *testing.T, reloads <-chan struct{}, what string) {
+	t.Helper()
+	select {
+	case <-reloads:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for reload after %s", what)
+	}
+}
+
+func TestWatchDirs(t *testing.T) {
+	setReloadDelay(t, 20*time.Millisecond)
+	dir := t.TempDir()
+	reloads := make(chan struct{}, 10)
+	stop, err := watchDirs(context.Background(), []string{dir, filepath.Join(dir, "missing")}, func() { reloads <- struct{}{} })
+	if err != nil {
+		t.Fatalf("watchDirs error: %v", err)
+	}
+	defer stop()
+
+	for i := 0; i < 3; i++ {
+		if err := os.WriteFile(filepath.Join(dir, "a.md"), []byte{byte('a' + i)}, 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	waitReload(t, reloads, "writes")
+	select {
+	case <-reloads:
+		t.Fatalf("expected writes in quick succession to reload once")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	sub := filepath.Join(dir, "git")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	waitReload(t, reloads, "mkdir")
+	if err := os.WriteFile(filepath.Join(sub, "commit.md"), []byte("x"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	waitReload(t, reloads, "write in new subdirectory")
+}
+
+func TestWatchDirsStop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stop, err := watchDirs(ctx, []string{t.TempDir()}, func() {})
+	if err != nil {
+		t.Fatalf("watchDirs error: %v", err)
+	}
+	cancel()
+	stop()
+
+	stop, err = watchDirs(context.Background(), []string{filepath.Join(t.TempDir(), "missing", "x")}, func() {})
+	if err != nil {
+		t.Fatalf("expected missing directory to be skipped, got %v", err)
+	}
+	stop()
+}
@@ -0,0 +1,445 @@
+// Package memory reads and edits the entries of the project memory files in docs/agent-layer/.
+package memory
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/conn-castle/agent-layer/internal/fsutil"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// EntriesMarker marks where entries start in a memory file.
+const EntriesMarker = "<!-- ENTRIES START -->"
+
+// Kind is the kind of a memory entry, as written at the start of its first line.
+type Kind string
+
+const (
+	KindIssue    Kind = "Issue"
+	KindBacklog  Kind = "Backlog"
+	KindDecision Kind = "Decision"
+)
+
+// kinds lists every kind in search order.
+var kinds = []Kind{KindIssue, KindBacklog, KindDecision}
+
+// File returns the memory file that holds entries of kind k.
+func (k Kind) File() string {
+	switch k {
+	case KindIssue:
+		return "ISSUES.md"
+	case KindBacklog:
+		return "BACKLOG.md"
+	default:
+		return "DECISIONS.md"
+	}
+}
+
+// ParseKind returns the kind named s (issue, backlog, or decision; any case).
+func ParseKind(s string) (Kind, error) {
+	for _, kind := range kinds {
+		if strings.EqualFold(s, string(kind)) {
+			return kind, nil
+		}
+	}
+	return "", fmt.Errorf(messages.MemoryKindInvalidFmt, s)
+}
+
+// Entry is one entry of a memory file.
+type Entry struct {
+	Kind  Kind
+	Date  string
+	ID    string
+	Title string
+	// File is the memory file name, for example ISSUES.md.
+	File string
+	// Text is the entry as written, first line included.
+	Text string
+}
+
+// Issue is a new ISSUES.md entry.
+type Issue struct {
+	Title       string
+	Priority    string
+	Area        string
+	Description string
+	NextStep    string
+	Notes       string
+}
+
+// Backlog is a new BACKLOG.md entry.
+type Backlog struct {
+	Title              string
+	Priority           string
+	Area               string
+	Description        string
+	AcceptanceCriteria string
+	Notes              string
+}
+
+// Decision is a new DECISIONS.md entry.
+type Decision struct {
+	Title     string
+	Decision  string
+	Reason    string
+	Tradeoffs string
+}
+
+var (
+	now = time.Now
+	// newID returns a random entry id.
+	newID = func() (string, error) {
+		buf := make([]byte, 3)
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(buf), nil
+	}
+	// fileMu serializes read-modify-write cycles so concurrent tool calls do not drop entries.
+	fileMu sync.Mutex
+)
+
+var entryHeaderPattern = regexp.MustCompile(`^- (Issue|Backlog|Decision) (\d{4}-\d{2}-\d{2}) ([0-9A-Za-z]+): (.*)$`)
+
+var priorities = []string{"Critical", "High", "Medium", "Low"}
+
+// AddIssue validates issue and inserts it at the top of ISSUES.md in dir.
+func AddIssue(dir string, issue Issue) (Entry, error) {
+	priority, err := normalizePriority(issue.Priority)
+	if err != nil {
+		return Entry{}, err
+	}
+	if err := requireFields(
+		field{"title", issue.Title, true},
+		field{"area", issue.Area, true},
+		field{"description", issue.Description, true},
+		field{"next_step", issue.NextStep, true},
+		field{"notes", issue.Notes, false},
+	); err != nil {
+		return Entry{}, err
+	}
+	lines := []string{
+		fmt.Sprintf("Priority: %s. Area: %s", priority, clean(issue.Area)),
+		"Description: " + clean(issue.Description),
+		"Next step: " + clean(issue.NextStep),
+	}
+	if notes := clean(issue.Notes); notes != "" {
+		lines = append(lines, "Notes: "+notes)
+	}
+	return add(dir, KindIssue, clean(issue.Title), lines)
+}
+
+// AddBacklog validates item and inserts it at the top of BACKLOG.md in dir.
+func AddBacklog(dir string, item Backlog) (Entry, error) {
+	priority, err := normalizePriority(item.Priority)
+	if err != nil {
+		return Entry{}, err
+	}
+	if err := requireFields(
+		field{"title", item.Title, true},
+		field{"area", item.Area, true},
+		field{"description", item.Description, true},
+		field{"acceptance_criteria", item.AcceptanceCriteria, true},
+		field{"notes", item.Notes, false},
+	); err != nil {
+		return Entry{}, err
+	}
+	lines := []string{
+		fmt.Sprintf("Priority: %s. Area: %s", priority, clean(item.Area)),
+		"Description: " + clean(item.Description),
+		"Acceptance criteria: " + clean(item.AcceptanceCriteria),
+	}
+	if notes := clean(item.Notes); notes != "" {
+		lines = append(lines, "Notes: "+notes)
+	}
+	return add(dir, KindBacklog, clean(item.Title), lines)
+}
+
+// AddDecision validates decision and appends it to DECISIONS.md in dir, which keeps the oldest first.
+func AddDecision(dir string, decision Decision) (Entry, error) {
+	if err := requireFields(
+		field{"title", decision.Title, true},
+		field{"decision", decision.Decision, true},
+		field{"reason", decision.Reason, true},
+		field{"tradeoffs", decision.Tradeoffs, true},
+	); err != nil {
+		return Entry{}, err
+	}
+	return add(dir, KindDecision, clean(decision.Title), []string{
+		"Decision: " + clean(decision.Decision),
+		"Reason: " + clean(decision.Reason),
+		"Tradeoffs: " + clean(decision.Tradeoffs),
+	})
+}
+
+// Search returns the entries in dir that contain every word of query, ignoring case.
+// only limits the search to those kinds; by default issues, backlog, and decisions are searched. Missing files are skipped.
+func Search(dir string, query string, only ...Kind) ([]Entry, error) {
+	words := strings.Fields(strings.ToLower(query))
+	if len(words) == 0 {
+		return nil, errors.New(messages.MemoryQueryRequired)
+	}
+	if len(only) == 0 {
+		only = kinds
+	}
+	fileMu.Lock()
+	defer fileMu.Unlock()
+	var matches []Entry
+	for _, kind := range only {
+		lines, err := readLines(filepath.Join(dir, kind.File()))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		for _, span := range parseEntries(lines, 0, len(lines)) {
+			entry := span.entry(kind.File(), lines)
+			text := strings.ToLower(entry.Text)
+			if containsAll(text, words) {
+				matches = append(matches, entry)
+			}
+		}
+	}
+	return matches, nil
+}
+
+// Resolve removes the issue or backlog entry with id from dir and returns it.
+func Resolve(dir string, id string) (Entry, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return Entry{}, fmt.Errorf(messages.MemoryFieldRequiredFmt, "id")
+	}
+	fileMu.Lock()
+	defer fileMu.Unlock()
+	for _, kind := range kinds {
+		path := filepath.Join(dir, kind.File())
+		lines, err := readLines(path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return Entry{}, err
+		}
+		for _, span := range parseEntries(lines, 0, len(lines)) {
+			if span.id != id {
+				continue
+			}
+			if kind == KindDecision {
+				return Entry{}, fmt.Errorf(messages.MemoryResolveDecisionFmt, id)
+			}
+			entry := span.entry(kind.File(), lines)
+			start, end := span.start, span.end
+			if end < len(lines) && strings.TrimSpace(lines[end]) == "" {
+				end++
+			} else if start > 0 && strings.TrimSpace(lines[start-1]) == "" {
+				start--
+			}
+			lines = append(lines[:start:start], lines[end:]...)
+			if err := writeLines(path, lines); err != nil {
+				return Entry{}, err
+			}
+			return entry, nil
+		}
+	}
+	return Entry{}, fmt.Errorf(messages.MemoryEntryNotFoundFmt, id)
+}
+
+// add writes a new entry of kind below the entries marker: first for issues and backlog, last for decisions.
+func add(dir string, kind Kind, title string, body []string) (Entry, error) {
+	fileMu.Lock()
+	defer fileMu.Unlock()
+	path := filepath.Join(dir, kind.File())
+	lines, err := readLines(path)
+	if err != nil {
+		return Entry{}, err
+	}
+	marker := -1
+	for i, line := range lines {
+		if strings.TrimSpace(line) == EntriesMarker {
+			marker = i
+			break
+		}
+	}
+	if marker < 0 {
+		return Entry{}, fmt.Errorf(messages.MemoryMarkerMissingFmt, kind.File(), EntriesMarker)
+	}
+	end := sectionEnd(lines, marker+1)
+	spans := parseEntries(lines, marker+1, end)
+
+	ids := make(map[string]bool, len(spans))
+	for _, span := range spans {
+		ids[span.id] = true
+		if normalizeTitle(span.title) == normalizeTitle(title) {
+			return Entry{}, fmt.Errorf(messages.MemoryDuplicateFmt, kind.File(), span.title, span.id)
+		}
+	}
+	id, err := uniqueID(ids)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	date := now().Format("2006-01-02")
+	entry := []string{fmt.Sprintf("- %s %s %s: %s", kind, date, id, title)}
+	for _, line := range body {
+		entry = append(entry, "    "+line)
+	}
+
+	// at is the line the entry is inserted before; the entry is wrapped in blank lines as needed.
+	at := marker + 1
+	if kind == KindDecision && len(spans) > 0 {
+		at = spans[len(spans)-1].end
+	}
+	insert := append([]string{""}, entry...)
+	if at < len(lines) && strings.TrimSpace(lines[at]) != "" {
+		insert = append(insert, "")
+	}
+	lines = append(lines[:at:at], append(insert, lines[at:]...)...)
+	if err := writeLines(path, lines); err != nil {
+		return Entry{}, err
+	}
+	return Entry{
+		Kind:  kind,
+		Date:  date,
+		ID:    id,
+		Title: title,
+		File:  kind.File(),
+		Text:  strings.Join(entry, "\n"),
+	}, nil
+}
+
+// entrySpan locates an entry: lines[start] is its first line and lines[end] is the first line after it.
+type entrySpan struct {
+	kind, date, id, title string
+	start, end            int
+}
+
+func (s entrySpan) entry(file string, lines []string) Entry {
+	return Entry{
+		Kind:  Kind(s.kind),
+		Date:  s.date,
+		ID:    s.id,
+		Title: s.title,
+		File:  file,
+		Text:  strings.Join(lines[s.start:s.end], "\n"),
+	}
+}
+
+// parseEntries finds the entries in lines[from:to]. Indented lines after a first line belong to its entry.
+func parseEntries(lines []string, from, to int) []entrySpan {
+	var spans []entrySpan
+	for i := from; i < to; i++ {
+		match := entryHeaderPattern.FindStringSubmatch(lines[i])
+		if match == nil {
+			continue
+		}
+		span := entrySpan{kind: match[1], date: match[2], id: match[3], title: strings.TrimSpace(match[4]), start: i}
+		end := i + 1
+		for end < to && strings.TrimSpace(lines[end]) != "" && (strings.HasPrefix(lines[end], " ") || strings.HasPrefix(lines[end], "\t")) {
+			end++
+		}
+		span.end = end
+		spans = append(spans, span)
+		i = end - 1
+	}
+	return spans
+}
+
+// sectionEnd returns the index of the first heading at or after from, or len(lines).
+func sectionEnd(lines []string, from int) int {
+	for i := from; i < len(lines); i++ {
+		if strings.HasPrefix(lines[i], "#") {
+			return i
+		}
+	}
+	return len(lines)
+}
+
+func uniqueID(existing map[string]bool) (string, error) {
+	for {
+		id, err := newID()
+		if err != nil {
+			return "", fmt.Errorf(messages.MemoryIDGenerateFailedFmt, err)
+		}
+		if !existing[id] {
+			return id, nil
+		}
+	}
+}
+
+func readLines(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf(messages.MemoryReadFailedFmt, path, err)
+	}
+	content := strings.ReplaceAll(string(data), "\r\n", "\n")
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n"), nil
+}
+
+func writeLines(path string, lines []string) error {
+	content := strings.TrimRight(strings.Join(lines, "\n"), "\n") + "\n"
+	if err := fsutil.WriteFileAtomic(path, []byte(content), 0o644); err != nil {
+		return fmt.Errorf(messages.MemoryWriteFailedFmt, path, err)
+	}
+	return nil
+}
+
+type field struct {
+	name     string
+	value    string
+	required bool
+}
+
+// requireFields rejects missing required fields and values that would break the one-line-per-key format.
+func requireFields(fields ...field) error {
+	for _, f := range fields {
+		value := strings.TrimSpace(f.value)
+		if value == "" {
+			if f.required {
+				return fmt.Errorf(messages.MemoryFieldRequiredFmt, f.name)
+			}
+			continue
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf(messages.MemoryFieldMultilineFmt, f.name)
+		}
+	}
+	return nil
+}
+
+func normalizePriority(value string) (string, error) {
+	for _, priority := range priorities {
+		if strings.EqualFold(strings.TrimSpace(value), priority) {
+			return priority, nil
+		}
+	}
+	return "", fmt.Errorf(messages.MemoryPriorityInvalidFmt, value)
+}
+
+// clean trims the whitespace around a field value.
+func clean(s string) string {
+	return strings.TrimSpace(s)
+}
+
+// normalizeTitle compares titles ignoring case, spacing, and trailing punctuation.
+func normalizeTitle(title string) string {
+	return strings.TrimRight(strings.Join(strings.Fields(strings.ToLower(title)), " "), ".!?")
+}
+
+func containsAll(text string, words []string) bool {
+	for _, word := range words {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,255 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const issuesFile = `# Issues
+
+## Format
+` + "```text" + `
+- Issue YYYY-MM-DD abcdef: Short title
+    Priority: Critical | High | Medium | Low. Area: <area>
+` + "```" + `
+
+## Open issues
+
+<!-- ENTRIES START -->
+
+- Issue 2026-01-27 j7k8l9: Sync engine bypasses System
+    Priority: High. Area: sync.
+    Description: Direct os calls.
+    Next step: Refactor.
+`
+
+const decisionsFile = `# Decisions
+
+## Decision Log
+
+<!-- ENTRIES START -->
+
+- Decision 2026-01-22 f1e2d3: Distribution model
+    Decision: Global CLI.
+    Reason: One entrypoint.
+`
+
+// fixedIDs makes newID return ids in order and now return a fixed date.
+func fixedIDs(t *testing.T, ids ...string) {
+	t.Helper()
+	originalID, originalNow := newID, now
+	t.Cleanup(func() { newID, now = originalID, originalNow })
+	now = func() time.Time { return time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC) }
+	newID = func() (string, error) {
+		if len(ids) == 0 {
+			return "", errors.New("out of ids")
+		}
+		id := ids[0]
+		ids = ids[1:]
+		return id, nil
+	}
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+}
+
+func readFile(t *testing.T, dir, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestAddIssue(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "ISSUES.md", issuesFile)
+	fixedIDs(t, "j7k8l9", "a1b2c3")
+
+	entry, err := AddIssue(dir, Issue{
+		Title:       "  Watcher leaks goroutines ",
+		Priority:    "medium",
+		Area:        "mcp",
+		Description: "Stop is never called.",
+		NextStep:    "Call stop on shutdown.",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "a1b2c3", entry.ID, "an id already in the file is not reused")
+	assert.Equal(t, "2026-10-17", entry.Date)
+	assert.Equal(t, "ISSUES.md", entry.File)
+
+	want := `<!-- ENTRIES START -->
+
+- Issue 2026-10-17 a1b2c3: Watcher leaks goroutines
+    Priority: Medium. Area: mcp
+    Description: Stop is never called.
+    Next step: Call stop on shutdown.
+
+- Issue 2026-01-27 j7k8l9: Sync engine bypasses System
+`
+	assert.Contains(t, readFile(t, dir, "ISSUES.md"), want)
+	assert.Equal(t, strings.SplitAfter(want, "\n\n")[1], entry.Text+"\n\n")
+}
+
+func TestAddIssueValidation(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "ISSUES.md", issuesFile)
+	fixedIDs(t, "a1b2c3")
+	valid := Issue{Title: "t", Priority: "High", Area: "a", Description: "d", NextStep: "n"}
+
+	for name, tc := range map[string]struct {
+		edit func(*Issue)
+		err  string
+	}{
+		"priority":  {func(i *Issue) { i.Priority = "urgent" }, "priority must be"},
+		"title":     {func(i *Issue) { i.Title = " " }, "title is required"},
+		"next step": {func(i *Issue) { i.NextStep = "" }, "next_step is required"},
+		"multiline": {func(i *Issue) { i.Notes = "a\nb" }, "notes must be a single line"},
+		"duplicate": {func(i *Issue) { i.Title = "sync engine  bypasses system." }, "(j7k8l9)"},
+	} {
+		issue := valid
+		tc.edit(&issue)
+		_, err := AddIssue(dir, issue)
+		require.Error(t, err, name)
+		assert.Contains(t, err.Error(), tc.err, name)
+	}
+	assert.Equal(t, issuesFile, readFile(t, dir, "ISSUES.md"), "rejected entries leave the file untouched")
+}
+
+func TestAddBacklogEmptyFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "BACKLOG.md", "# Backlog\n\n<!-- ENTRIES START -->\n")
+	fixedIDs(t, "a1b2c3", "d4e5f6")
+
+	_, err := AddBacklog(dir, Backlog{Title: "First", Priority: "Low", Area: "cli", Description: "d", AcceptanceCriteria: "ac", Notes: "n"})
+	require.NoError(t, err)
+	_, err = AddBacklog(dir, Backlog{Title: "Second", Priority: "Low", Area: "cli", Description: "d", AcceptanceCriteria: "ac"})
+	require.NoError(t, err)
+
+	assert.Equal(t, `# Backlog
+
+<!-- ENTRIES START -->
+
+- Backlog 2026-10-17 d4e5f6: Second
+    Priority: Low. Area: cli
+    Description: d
+    Acceptance criteria: ac
+
+- Backlog 2026-10-17 a1b2c3: First
+    Priority: Low. Area: cli
+    Description: d
+    Acceptance criteria: ac
+    Notes: n
+`, readFile(t, dir, "BACKLOG.md"))
+}
+
+func TestAddDecisionAppends(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "DECISIONS.md", decisionsFile)
+	fixedIDs(t, "a1b2c3")
+
+	_, err := AddDecision(dir, Decision{Title: "Memory tools", Decision: "Add tools.", Reason: "Well-formed files.", Tradeoffs: "More surface."})
+	require.NoError(t, err)
+	assert.Equal(t, decisionsFile+`
+- Decision 2026-10-17 a1b2c3: Memory tools
+    Decision: Add tools.
+    Reason: Well-formed files.
+    Tradeoffs: More surface.
+`, readFile(t, dir, "DECISIONS.md"))
+
+	_, err = AddDecision(dir, Decision{Title: "x", Decision: "d", Reason: "r"})
+	assert.EqualError(t, err, "tradeoffs is required")
+}
+
+func TestAddMissingMarker(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "DECISIONS.md", "# Decisions\n")
+	_, err := AddDecision(dir, Decision{Title: "t", Decision: "d", Reason: "r", Tradeoffs: "t"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "al migrate")
+
+	_, err = AddIssue(t.TempDir(), Issue{Title: "t", Priority: "High", Area: "a", Description: "d", NextStep: "n"})
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestAddIDError(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "DECISIONS.md", decisionsFile)
+	fixedIDs(t)
+	_, err := AddDecision(dir, Decision{Title: "t", Decision: "d", Reason: "r", Tradeoffs: "t"})
+	assert.ErrorContains(t, err, "out of ids")
+}
+
+func TestSearch(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "ISSUES.md", issuesFile)
+	writeFile(t, dir, "DECISIONS.md", decisionsFile)
+
+	entries, err := Search(dir, "REFACTOR sync")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, Entry{
+		Kind:  KindIssue,
+		Date:  "2026-01-27",
+		ID:    "j7k8l9",
+		Title: "Sync engine bypasses System",
+		File:  "ISSUES.md",
+		Text:  "- Issue 2026-01-27 j7k8l9: Sync engine bypasses System\n    Priority: High. Area: sync.\n    Description: Direct os calls.\n    Next step: Refactor.",
+	}, entries[0])
+
+	entries, err = Search(dir, "cli")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "f1e2d3", entries[0].ID)
+
+	entries, err = Search(dir, "cli", KindIssue)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	_, err = Search(dir, "  ")
+	assert.EqualError(t, err, "query is required")
+}
+
+func TestResolve(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "ISSUES.md", issuesFile)
+	writeFile(t, dir, "DECISIONS.md", decisionsFile)
+	fixedIDs(t, "a1b2c3")
+	_, err := AddIssue(dir, Issue{Title: "New", Priority: "Low", Area: "a", Description: "d", NextStep: "n"})
+	require.NoError(t, err)
+
+	entry, err := Resolve(dir, "a1b2c3")
+	require.NoError(t, err)
+	assert.Equal(t, "New", entry.Title)
+	assert.Equal(t, issuesFile, readFile(t, dir, "ISSUES.md"))
+
+	_, err = Resolve(dir, "j7k8l9")
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(readFile(t, dir, "ISSUES.md"), "<!-- ENTRIES START -->\n"))
+
+	_, err = Resolve(dir, "f1e2d3")
+	assert.ErrorContains(t, err, "decisions are history")
+	_, err = Resolve(dir, "zzzzzz")
+	assert.EqualError(t, err, `no issue or backlog entry with id "zzzzzz"`)
+	_, err = Resolve(dir, "")
+	assert.EqualError(t, err, "id is required")
+}
+
+func TestParseKind(t *testing.T) {
+	for _, kind := range kinds {
+		parsed, err := ParseKind(strings.ToLower(string(kind)))
+		require.NoError(t, err)
+		assert.Equal(t, kind, parsed)
+	}
+	_, err := ParseKind("roadmap")
+	assert.EqualError(t, err, fmt.Sprintf("kind must be issue, backlog, or decision, got %q", "roadmap"))
+}
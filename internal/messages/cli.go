@@ -27,6 +27,9 @@ const (
 	InitDeleteUnknownAllPrompt    = "Delete all unknown files under .agent-layer?"
 	InitDeleteUnknownPromptFmt    = "Delete %s?"
 	InitRunWizardPrompt           = "Run the setup wizard now? (recommended)"
+	InitImportPrompt              = "Import these into .agent-layer now? (sync will not overwrite them otherwise)"
+	InitImportScanFailedFmt       = "Warning: could not check for files to import: %v\n"
+	InitImportHint                = "Found hand-written instruction files or client configs; run `al import` to adopt them (sync will not overwrite them until you do).\n"
 
 	InitFlagOverwrite = "Prompt before overwriting existing template files"
 	InitFlagForce     = "Overwrite existing template files and delete unknown files under .agent-layer without prompting (implies --overwrite)"
@@ -93,7 +96,142 @@ const (
 	StubShortFmt          = "%s (not implemented yet)"
 	StubNotImplementedFmt = "%s is not implemented in this phase"
 
+	// ProfileFlag describes the --profile flag shared by sync, doctor, config, and client commands.
+	ProfileFlag = "Apply a [profiles.<name>] overlay from config (defaults to $AL_PROFILE)"
+
+	// ConfigUse is the config command name.
+	ConfigUse            = "config"
+	ConfigShort          = "Inspect and edit Agent Layer configuration"
+	ConfigShowUse        = "show"
+	ConfigShowShort      = "Print the effective config after merging user, repo, and local layers"
+	ConfigShowFlagOrigin = "Annotate each value with the config layer that set it"
+	ConfigShowOriginFmt  = "%s (%s)"
+	ConfigSchemaUse      = "schema"
+	ConfigSchemaShort    = "Print the JSON Schema for config.toml"
+	ConfigSchemaLong     = "Print a JSON Schema (draft-07) for config.toml so TOML editors can autocomplete and validate it.\n\nExample: al config schema > .agent-layer/config.schema.json, then add `#:schema ./config.schema.json` as the first line of config.toml."
+	ConfigGetUse         = "get <key>"
+	ConfigGetShort       = "Print the effective value of a config key"
+	ConfigGetLong        = "Print the effective value of a dotted config key after merging all layers (and --profile).\n\nStrings print unquoted; other values print as TOML. A table prints one key = value line per setting. MCP servers are addressed by id, for example mcp.servers.github.enabled."
+	ConfigSetUse         = "set <key> <value> [<key> <value>...]"
+	ConfigSetShort       = "Set a config key, validating the result before writing"
+	ConfigSetLong        = "Set a dotted config key in .agent-layer/config.toml (or --local/--global) without the interactive wizard.\n\nThe value is parsed as a TOML literal when possible (true, 5, [\"a\", \"b\"]) and used as a string otherwise. MCP servers are addressed by id (mcp.servers.<id>.<field>); an unknown id adds a new server. Several pairs are applied together, so a new server can be added in one validated step. The merged config is validated before anything is written. Run al sync afterwards to regenerate client files."
+	ConfigUnsetUse       = "unset <key>"
+	ConfigUnsetShort     = "Remove a config key, validating the result before writing"
+	ConfigUnsetLong      = "Remove a dotted config key from .agent-layer/config.toml (or --local/--global). Unsetting mcp.servers.<id> removes the whole server entry. The merged config is validated before anything is written."
+	ConfigFlagLocal      = "Edit .agent-layer/config.local.toml (personal overrides)"
+	ConfigFlagGlobal     = "Edit the user-global config (~/.config/agent-layer/config.toml)"
+	ConfigSetArgsPairs   = "expected one or more <key> <value> pairs"
+	ConfigKeyNotSetFmt   = "%s is not set"
+	ConfigEditInvalidFmt = "not writing %s: %w"
+	ConfigEditNoUserPath = "user-global config path is unavailable (no home directory)"
+	ConfigUpdatedFmt     = "Updated %s\n"
+
+	// EnvUse is the env command name.
+	EnvUse                     = "env"
+	EnvShort                   = "Manage the .agent-layer/.env file"
+	EnvEditUse                 = "edit"
+	EnvEditShort               = "Edit the encrypted .agent-layer/.env.age in $EDITOR"
+	EnvEditLong                = "Decrypt .agent-layer/.env.age to a private temp file, open it in $VISUAL or $EDITOR, then re-encrypt it and shred the temp file.\n\nThe file is encrypted with the age CLI (https://age-encryption.org) to the identity in $AL_AGE_IDENTITY or ~/.config/agent-layer/age-identity.txt (create one with age-keygen -o). When .env.age does not exist yet, editing starts from the plaintext .env and creates .env.age; from then on .env.age is used instead of .env."
+	EnvEditInvalidFmt          = "invalid env file: %v\nReopening the editor; save it unchanged to abort.\n"
+	EnvEditAbortedFmt          = "not writing %s: %w"
+	EnvEditEditorFailedFmt     = "editor %s failed: %w"
+	EnvEditNoChangesFmt        = "No changes; %s left untouched.\n"
+	EnvEditSavedFmt            = "Encrypted %s\n"
+	EnvEditPlaintextRemainsFmt = "%s is now ignored in favor of %s; delete it once you have checked the encrypted file.\n"
+	EnvListUse                 = "list"
+	EnvListShort               = "Show .env keys with masked values and the MCP servers that need them"
+	EnvListHeader              = "KEY\tVALUE\tSOURCE\tUSED BY"
+	EnvNotSet                  = "(not set)"
+	EnvSourceEnvFile           = ".env"
+	EnvSourceProcess           = "environment"
+	EnvSourceNone              = "-"
+	EnvUnused                  = "-"
+	EnvDisabledServerFmt       = "%s (disabled)"
+	EnvSetUse                  = "set <KEY>"
+	EnvSetShort                = "Set a .env value from a hidden prompt (or one line of stdin)"
+	EnvSetPromptFmt            = "Value for %s"
+	EnvSetInvalidKeyFmt        = "invalid env key %q (use letters, digits, and underscores)"
+	EnvSetEmptyValueFmt        = "no value given for %s"
+	EnvSetUpdatedFmt           = "Set %s in %s\n"
+	EnvCheckUse                = "check"
+	EnvCheckShort              = "Exit non-zero when a key required by an enabled MCP server is missing"
+	EnvCheckOK                 = "All required environment variables are set."
+	EnvCheckMissingFmt         = "missing %s (required by %s)\n"
+	EnvCheckFailedFmt          = "%d required environment variable(s) missing; set them with `al env set <KEY>`"
+	EnvExampleUse              = "example"
+	EnvExampleShort            = "Write .agent-layer/.env.example listing every required key"
+	EnvExampleHeader           = "# Generated by `al env example`. Copy to .env (or run `al env set <KEY>`) and fill in the values.\n"
+	EnvExampleUsedByFmt        = "# Required by: %s\n"
+	EnvExampleWrittenFmt       = "Wrote %s (%d keys)\n"
+
+	// ImportUse is the import command name.
+	ImportUse              = "import"
+	ImportShort            = "Adopt existing instruction files and client configs into .agent-layer"
+	ImportLong             = "Find hand-written AGENTS.md, CLAUDE.md, GEMINI.md, and .github/copilot-instructions.md files, MCP servers in .mcp.json, .vscode/mcp.json, .gemini/settings.json, and .codex/config.toml, and allowed shell commands in .claude/settings.json and .gemini/settings.json. They are converted into .agent-layer/instructions/, [[mcp.servers]] entries (with literal secrets moved into .env), and commands.allow lines, then sync regenerates the client files.\n\nA preview is printed and confirmed before anything is written."
+	ImportFlagDryRun       = "Print the preview without writing any files"
+	ImportFlagYes          = "Import without asking for confirmation"
+	ImportNothing          = "Nothing to import: no hand-written instruction files or client configs found.\n"
+	ImportConfirmPrompt    = "Import these into .agent-layer and regenerate the files above?"
+	ImportNeedsYes         = "al import needs confirmation; re-run with --yes in non-interactive shells"
+	ImportCanceled         = "Import canceled; nothing was written.\n"
+	ImportDone             = "Imported into .agent-layer and regenerated client files.\n"
+	ImportInstructionsHead = "Instructions:\n"
+	ImportInstructionFmt   = "  + %s (from %s)\n"
+	ImportServersHead      = "MCP servers (appended to .agent-layer/config.toml):\n"
+	ImportServerFmt        = "  + %s: %s (from %s)\n"
+	ImportSecretsHeadFmt   = "Secrets (written to %s):\n"
+	ImportSecretFmt        = "  + %s=%s (from %s)\n"
+	ImportCommandsHead     = "commands.allow:\n"
+	ImportCommandFmt       = "  + %s (from %s)\n"
+	ImportNotesHead        = "Needs attention (not imported):\n"
+	ImportNoteFmt          = "  - %s: %s\n"
+	ImportRegeneratedHead  = "Replaced by generated files after import:\n"
+	ImportFileFmt          = "  - %s\n"
+
+	// StatsUse is the stats command name.
+	StatsUse               = "stats"
+	StatsShort             = "Show the context budget: tokens per instruction fragment, slash command, MCP server, tool, and client"
+	StatsLong              = "Count the tokens of each instruction fragment and slash command, list every enabled MCP server's tools with the size of their definitions, and show what each enabled client loads: its generated instruction file plus the tool definitions of its MCP servers.\n\nTokens are counted with the [warnings] tokenizer (per client with [warnings.clients.<client>] tokenizer). MCP servers are started to list their tools; use --no-mcp to skip that."
+	StatsFlagNoMCP         = "Skip MCP tool discovery"
+	StatsDiscoveringFmt    = "Listing tools of %d MCP servers...\n"
+	StatsDiscoverFailedFmt = "MCP discovery failed: %w (re-run with --no-mcp to skip it)"
+	StatsTokenizerFmt      = "Tokenizer: %s\n"
+	StatsNoteFmt           = "Note: %s; counting with the heuristic instead.\n"
+	StatsFragmentsHead     = "\nInstruction fragments\n"
+	StatsFragmentsHeader   = "FRAGMENT\tTOKENS\tCLIENTS"
+	StatsCommandsHead      = "\nSlash commands\n"
+	StatsCommandsHeader    = "COMMAND\tTOKENS\tCLIENTS"
+	StatsServersHead       = "\nMCP servers\n"
+	StatsServersHeader     = "SERVER\tTOOLS\tSCHEMA TOKENS\tCLIENTS"
+	StatsToolsHead         = "\nMCP tools\n"
+	StatsToolsHeader       = "SERVER\tTOOL\tTOKENS"
+	StatsClientsHead       = "\nClients\n"
+	StatsClientsHeader     = "CLIENT\tTOKENIZER\tINSTRUCTIONS\tTOKENS\tSERVERS\tTOOLS\tSCHEMA TOKENS\tTOTAL"
+	StatsTotalLabel        = "TOTAL"
+	StatsAllClients        = "all"
+	StatsNone              = "  (none)\n"
+	StatsUnreachableFmt    = "unreachable: %v"
+	StatsNotGenerated      = "not generated"
+	StatsSkipped           = "-"
+
+	// MigrateUse is the migrate command name.
+	MigrateUse             = "migrate"
+	MigrateShort           = "Upgrade config.toml, commands.allow, and memory files to the current layout"
+	MigrateLong            = "Upgrade .agent-layer/config.toml, commands.allow, and the docs/agent-layer memory files written by older releases to the current config_version, one step at a time. Files are edited in place so comments and customizations are kept, and a summary of every change is printed."
+	MigrateFlagDryRun      = "Print the changes without writing any files"
+	MigrateUpToDateFmt     = "Already at config_version %d; nothing to migrate.\n"
+	MigrateHeaderFmt       = "Migrated from config_version %s to %d:\n"
+	MigrateDryRunHeaderFmt = "Would migrate from config_version %s to %d:\n"
+	MigrateChangeFmt       = "  %s: %s\n"
+	MigrateUnversioned     = "unversioned"
+
 	// McpPromptsUse is the mcp-prompts command name.
-	McpPromptsUse   = "mcp-prompts"
-	McpPromptsShort = "Run the internal MCP prompt server over stdio"
+	McpPromptsUse              = "mcp-prompts"
+	McpPromptsShort            = "Run the internal MCP prompt server over stdio or HTTP"
+	McpPromptsFlagClient       = "Serve only the slash commands that target this client (clients: front matter)"
+	McpPromptsClientInvalidFmt = "unknown client %q"
+	McpPromptsFlagCommandTools = "Also serve each slash command as a run_command_<name> tool"
+	McpPromptsFlagHTTP         = "Serve streamable HTTP on this host:port instead of stdio; /mcp/<client> serves one client"
+	McpPromptsHTTPInvalidFmt   = "--http must be host:port, got %q"
+	McpPromptsHTTPClientFlag   = "--client cannot be combined with --http; clients select themselves with /mcp/<client>"
 )
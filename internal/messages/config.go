@@ -7,45 +7,111 @@ const (
 	ConfigFailedReadTemplateFmt = "failed to read template config.toml: %w"
 	ConfigMissingEnvFileFmt     = "missing env file %s: %w"
 	ConfigInvalidEnvFileFmt     = "invalid env file %s: %w"
+	ConfigDecryptEnvFileFmt     = "failed to decrypt env file %s: %w"
+	ConfigEncryptEnvFileFmt     = "failed to encrypt env file %s: %w"
 	ConfigInvalidConfigFmt      = "invalid config %s: %w"
 
+	ConfigLayerServersNotArrayFmt = "%s must be an array of tables ([[mcp.servers]])"
+
 	ConfigMissingCommandsAllowlistFmt    = "missing commands allowlist %s: %w"
 	ConfigFailedReadCommandsAllowlistFmt = "failed to read commands allowlist %s: %w"
 
-	ConfigApprovalsModeInvalidFmt             = "%s: approvals.mode must be one of all, mcp, commands, none"
-	ConfigGeminiEnabledRequiredFmt            = "%s: agents.gemini.enabled is required"
-	ConfigClaudeEnabledRequiredFmt            = "%s: agents.claude.enabled is required"
-	ConfigCodexEnabledRequiredFmt             = "%s: agents.codex.enabled is required"
-	ConfigVSCodeEnabledRequiredFmt            = "%s: agents.vscode.enabled is required"
-	ConfigAntigravityEnabledRequiredFmt       = "%s: agents.antigravity.enabled is required"
-	ConfigMcpServerIDRequiredFmt              = "%s: mcp.servers[%d].id is required"
-	ConfigMcpServerIDReservedFmt              = "%s: mcp.servers[%d].id is reserved for the internal prompt server"
-	ConfigMcpServerEnabledRequiredFmt         = "%s: mcp.servers[%d].enabled is required"
-	ConfigMcpServerURLRequiredFmt             = "%s: mcp.servers[%d].url is required for http transport"
-	ConfigMcpServerCommandNotAllowedFmt       = "%s: mcp.servers[%d].command/args are not allowed for http transport"
-	ConfigMcpServerEnvNotAllowedFmt           = "%s: mcp.servers[%d].env is not allowed for http transport"
-	ConfigMcpServerHTTPTransportInvalidFmt    = "%s: mcp.servers[%d].http_transport must be sse or streamable"
-	ConfigMcpServerHTTPTransportNotAllowedFmt = "%s: mcp.servers[%d].http_transport is only valid for http transport"
-	ConfigMcpServerCommandRequiredFmt         = "%s: mcp.servers[%d].command is required for stdio transport"
-	ConfigMcpServerURLNotAllowedFmt           = "%s: mcp.servers[%d].url is not allowed for stdio transport"
-	ConfigMcpServerHeadersNotAllowedFmt       = "%s: mcp.servers[%d].headers are not allowed for stdio transport"
-	ConfigMcpServerTransportInvalidFmt        = "%s: mcp.servers[%d].transport must be http or stdio"
-	ConfigMcpServerClientInvalidFmt           = "%s: mcp.servers[%d].clients contains invalid client %q"
-	ConfigWarningThresholdInvalidFmt          = "%s: %s must be greater than zero"
-
-	ConfigMissingSlashCommandsDirFmt          = "missing slash commands directory %s: %w"
-	ConfigFailedReadSlashCommandFmt           = "failed to read slash command %s: %w"
-	ConfigInvalidSlashCommandFmt              = "invalid slash command %s: %w"
-	ConfigSlashCommandMissingContent          = "missing content"
-	ConfigSlashCommandMissingFrontMatter      = "missing front matter"
-	ConfigSlashCommandUnterminatedFrontMatter = "unterminated front matter"
-	ConfigSlashCommandFailedReadContentFmt    = "failed to read content: %w"
-	ConfigSlashCommandDescriptionEmpty        = "description is empty"
-	ConfigSlashCommandMissingDescription      = "missing description in front matter"
+	ConfigApprovalsModeInvalid                = "approvals.mode must be one of all, mcp, commands, none"
+	ConfigAgentEnabledRequiredFmt             = "agents.%s.enabled is required"
+	ConfigClaudeSlashCommandsInvalid          = "agents.claude.slash_commands must be one of mcp, native, both"
+	ConfigGeminiSlashCommandsInvalid          = "agents.gemini.slash_commands must be one of mcp, native, both"
+	ConfigMcpServerIDRequiredFmt              = "%s.id is required"
+	ConfigPromptServerHTTPInvalidFmt          = "mcp.prompt_server.http must be host:port, got %q"
+	ConfigMcpServerIDReservedFmt              = "%s.id is reserved for the internal prompt server"
+	ConfigMcpServerIDDuplicateFmt             = "mcp.servers[%d].id %q duplicates mcp.servers[%d] in the same file"
+	ConfigMcpServerEnabledRequiredFmt         = "%s.enabled is required"
+	ConfigMcpServerURLRequiredFmt             = "%s.url is required for http transport"
+	ConfigMcpServerCommandNotAllowedFmt       = "%s.command/args are not allowed for http transport"
+	ConfigMcpServerEnvNotAllowedFmt           = "%s.env is not allowed for http transport"
+	ConfigMcpServerHTTPTransportInvalidFmt    = "%s.http_transport must be sse or streamable"
+	ConfigMcpServerHTTPTransportNotAllowedFmt = "%s.http_transport is only valid for http transport"
+	ConfigMcpServerCommandRequiredFmt         = "%s.command is required for stdio transport"
+	ConfigMcpServerURLNotAllowedFmt           = "%s.url is not allowed for stdio transport"
+	ConfigMcpServerHeadersNotAllowedFmt       = "%s.headers are not allowed for stdio transport"
+	ConfigMcpServerTransportInvalidFmt        = "%s.transport must be http or stdio"
+	ConfigMcpServerClientInvalidFmt           = "%s.clients contains invalid client %q"
+	ConfigWarningThresholdInvalidFmt          = "%s must be greater than zero"
+	ConfigWarningTokenizerInvalidFmt          = "%s must be one of %s"
+	ConfigWarningClientInvalidFmt             = "warnings.clients.%s is not a client (valid: %s)"
+	ConfigUnknownKeyFmt                       = "unknown key %s"
+	ConfigDecodeFailedFmt                     = "invalid config: %s"
+
+	ConfigProfileApprovalsModeInvalidFmt  = "profiles.%s.approvals.mode must be one of all, mcp, commands, none"
+	ConfigProfileServerIDRequiredFmt      = "profiles.%s.mcp.servers[%d].id is required"
+	ConfigProfileServerUnknownFmt         = "profiles.%s.mcp.servers[%d].id %q does not match any mcp.servers entry"
+	ConfigProfileServerEnabledRequiredFmt = "profiles.%s.mcp.servers[%d].enabled is required"
+	ConfigProfileUnknownFmt               = "unknown profile %q (defined profiles: %s)"
+	ConfigProfileNoneDefined              = "none"
+
+	ConfigMissingSlashCommandsDirFmt             = "missing slash commands directory %s: %w"
+	ConfigFailedReadSlashCommandFmt              = "failed to read slash command %s: %w"
+	ConfigInvalidSlashCommandFmt                 = "invalid slash command %s: %w"
+	ConfigSlashCommandMissingContent             = "missing content"
+	ConfigSlashCommandMissingFrontMatter         = "missing front matter"
+	ConfigSlashCommandUnterminatedFrontMatter    = "unterminated front matter"
+	ConfigSlashCommandFailedReadContentFmt       = "failed to read content: %w"
+	ConfigSlashCommandDescriptionEmpty           = "description is empty"
+	ConfigSlashCommandMissingDescription         = "missing description in front matter"
+	ConfigFrontMatterListInvalidFmt              = "%s must be a list such as [a, b]"
+	ConfigFrontMatterStringInvalidFmt            = "%s must be a string"
+	ConfigFrontMatterDuplicateKeyFmt             = "%s is set more than once"
+	ConfigFrontMatterBoolInvalidFmt              = "%s must be true or false"
+	ConfigFrontMatterInvalidFmt                  = "front matter is not valid YAML: %s"
+	ConfigFrontMatterNotMapping                  = "front matter must be a mapping of keys to values"
+	ConfigSlashCommandArgumentsInvalid           = "arguments must be a list of names or of entries with name, description, required, and default"
+	ConfigSlashCommandArgumentKeyUnknownFmt      = "arguments: unknown key %q (valid: name, description, required, default)"
+	ConfigSlashCommandArgumentRequiredInvalidFmt = "arguments: %s: required must be true or false"
+	ConfigSlashCommandArgumentNameInvalidFmt     = "arguments: invalid name %q (use letters, digits, _ and -, starting with a letter)"
+	ConfigSlashCommandArgumentDuplicateFmt       = "arguments: %q is declared more than once"
+	ConfigSlashCommandArgumentDefaultRequiredFmt = "arguments: %s: a required argument cannot have a default"
+	ConfigSlashCommandArgumentUndeclaredFmt      = "line %d: argument %q is not declared in arguments"
+	ConfigFrontMatterClientInvalidFmt            = "clients: unknown client %q (valid: %s)"
+	ConfigInvalidInstructionFmt                  = "invalid instruction %s: %w"
+	ConfigFrontMatterAppliesToInvalidFmt         = "applies_to: %q must be a glob relative to the repo root, using forward slashes"
+	ConfigChildAppliesToUnsupportedFmt           = "invalid instruction %s: applies_to is only supported in the root .agent-layer/instructions/"
+
+	ConfigTemplateErrorFmt              = "%s:%d%s"
+	ConfigTemplateFailedFmt             = "%s: %s"
+	ConfigTemplateIncludeDepthFmt       = "include %q: includes nested more than %d deep"
+	ConfigTemplateIncludeOutsideRootFmt = "include %q: path must be relative to the repo root and stay inside it"
+	ConfigTemplateIncludeReadFmt        = "include %q: %v"
+	ConfigTemplateEnvInvalidFmt         = "templates.env: invalid env var name %q"
+	ConfigChildrenExcludeInvalidFmt     = "children.exclude: %q must be a repo-relative directory path or glob, using forward slashes"
+	ConfigTemplateArgUndeclaredFmt      = "arg %q: not declared in the command's arguments"
+	ConfigTemplateArgOutsideCommand     = "arg is only available in slash command bodies"
 
 	ConfigMissingInstructionsDirFmt = "missing instructions directory %s: %w"
 	ConfigNoInstructionFilesFmt     = "no instruction files found in %s"
 	ConfigFailedReadInstructionFmt  = "failed to read instruction %s: %w"
 
 	ConfigMissingEnvVarsFmt = "missing environment variables: %s"
+	ConfigRequiredEnvVarFmt = "%s: %s"
+	ConfigMissingSecretsFmt = "missing secrets: %s (run `al doctor` for provider details)"
+
+	ConfigSecretProviderTypeInvalidFmt     = "%s.type must be one of exec, file, keyring"
+	ConfigSecretProviderFieldRequiredFmt   = "%s.%s is required for %s providers"
+	ConfigSecretProviderFieldNotAllowedFmt = "%s.%s is not used by %s providers"
+	ConfigSecretProviderPathFmt            = "secret provider %s: %w"
+
+	ConfigVersionTooNewFmt        = "%s: config_version %d is newer than this version of al supports (%d); upgrade al"
+	ConfigVersionInvalidFmt       = "%s: config_version %d is invalid"
+	ConfigVersionOutdatedFmt      = "%s: config_version %d is outdated (current is %d); run `al migrate` to upgrade it"
+	ConfigUnversionedHint         = "config has no config_version; run `al migrate` to upgrade configs written by older releases"
+	ConfigMigrationFailedFmt      = "migration to config_version %d failed: %w"
+	ConfigMigrationReadFailedFmt  = "failed to read %s: %w"
+	ConfigMigrationWriteFailedFmt = "failed to write %s: %w"
+	ConfigMigrationSetVersionFmt  = "set config_version = %d"
+	ConfigMigrationAddedFmt       = "added %s"
+	ConfigMigrationRenamedFmt     = "renamed to %s"
+	ConfigMigrationDedupedFmt     = "removed %d duplicate command prefix(es)"
+	ConfigMigrationNotTableFmt    = "%s is not written with its own [table] header, so %q cannot be added automatically; add it by hand and run al migrate again"
+
+	ConfigChildLayerReadFailedFmt          = "failed to read child layer %s: %w"
+	ConfigChildSlashCommandDuplicateFmt    = "duplicate slash command %q in %s (already defined in %s)"
+	ConfigSlashCommandFlatNameCollisionFmt = "slash commands %s and %s are both named %q in clients without namespaces; rename one of them"
 )
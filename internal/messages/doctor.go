@@ -30,6 +30,10 @@ const (
 	DoctorSecretFoundEnvFileFmt     = "Secret found in .agent-layer/.env: %s"
 	DoctorNoRequiredSecrets         = "No required secrets found in configuration."
 
+	DoctorSecretResolvedFmt            = "Secret %s resolved from %s"
+	DoctorSecretUnresolvedFmt          = "Unresolved secret: %v"
+	DoctorSecretUnresolvedRecommendFmt = "Store %s in a provider listed in [[secrets.providers]], or fix the failing provider."
+
 	DoctorAgentEnabledFmt  = "Agent enabled: %s"
 	DoctorAgentDisabledFmt = "Agent disabled: %s"
 
@@ -0,0 +1,14 @@
+package messages
+
+// Importer messages for adopting existing client files.
+const (
+	ImportMissingConfigFmt      = "%s not found; run `al init` before `al import`"
+	ImportReadFailedFmt         = "failed to read %s: %w"
+	ImportParseFailedFmt        = "failed to parse %s: %w"
+	ImportWriteFailedFmt        = "failed to write %s: %w"
+	ImportInvalidConfigFmt      = "imported servers would make %s invalid: %w"
+	ImportNoteUnsupportedKeyFmt = "%s is not managed by Agent Layer"
+	ImportNoteUnsupportedPerm   = "%s has no commands.allow equivalent"
+	ImportNoteVSCodeInputFmt    = "VS Code input %s became ${%s}; set it with `al env set %s`"
+	ImportNoteMissingEnvFmt     = "${%s} is not set in .agent-layer/.env; set it with `al env set %s`"
+)
@@ -0,0 +1,18 @@
+package messages
+
+// Secrets messages for secret providers.
+const (
+	SecretsNotFound                = "secret not found"
+	SecretsNotFoundInProvidersFmt  = "secret %s not found in providers: %s"
+	SecretsNoProvidersFmt          = "secret %s: no secret providers configured (add [[secrets.providers]] to config.toml)"
+	SecretsProviderFailedFmt       = "secret %s: %s provider: %w"
+	SecretsInvalidNameFmt          = "invalid secret name %q"
+	SecretsExecFailedFmt           = "%s failed: %w"
+	SecretsExecFailedOutputFmt     = "%s failed: %w: %s"
+	SecretsFileReadFailedFmt       = "failed to read %s: %w"
+	SecretsFileWriteFailedFmt      = "failed to write %s: %w"
+	SecretsKeyringUnsupportedFmt   = "the OS keyring is not supported on %s; set file in the keyring provider"
+	SecretsKeyringFileInvalidFmt   = "invalid keyring file %s: %w"
+	SecretsKeyringCommandFailedFmt = "keyring %s failed: %w"
+	SecretsKeyringMultilineValue   = "the macOS keychain cannot store a value with line breaks"
+)
@@ -6,6 +6,9 @@ const (
 	SyncUse                                      = "sync"
 	SyncShort                                    = "Regenerate client outputs from .agent-layer"
 	SyncCompletedWithWarnings                    = "sync completed with warnings"
+	SyncFlagForce                                = "Overwrite existing files that Agent Layer did not generate"
+	SyncUserFilesConflictFmt                     = "refusing to overwrite files that Agent Layer did not generate:\n%sRun `al import` to adopt them into .agent-layer/, or `al sync --force` to overwrite them"
+	SyncUserFileLineFmt                          = "  - %s\n"
 	SyncAgentEnabledFlagMissingFmt               = "agent %s is missing enabled flag in config"
 	SyncAgentDisabledFmt                         = "agent %s is disabled in config"
 	SyncMarshalMCPConfigFailedFmt                = "failed to marshal mcp config: %w"
@@ -28,6 +31,7 @@ const (
 	SyncCodexUnsupportedHeaderFmt                = "unsupported header %s for codex http server"
 	SyncCodexAuthorizationBearerRequired         = "authorization header must use Bearer token"
 	SyncCodexAuthorizationEnvPlaceholderRequired = "authorization header must use env var placeholder"
+	SyncCodexSecretNotSupportedFmt               = "secret %s would be written to .codex/config.toml; codex only reads secrets from the Authorization header"
 
 	MCPServerResolveFmt              = "mcp server %s: %w"
 	MCPServerURLFmt                  = "mcp server %s url: %w"
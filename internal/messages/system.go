@@ -55,6 +55,12 @@ const (
 	EnvfileReadFailedFmt    = "failed to read env content: %w"
 	EnvfileExpectedKeyValue = "expected KEY=VALUE"
 
+	// EnvfileAgeNotInstalled indicates the age CLI is missing for .env.age files.
+	EnvfileAgeNotInstalled       = "age is not installed; install it from https://age-encryption.org to use .env.age"
+	EnvfileAgeIdentityMissingFmt = "age identity %s not found; create one with `age-keygen -o %s` or set AL_AGE_IDENTITY"
+	EnvfileAgeFailedFmt          = "age %s failed: %w"
+	EnvfileAgeFailedOutputFmt    = "age %s failed: %w: %s"
+
 	// FsutilCreateTempFileFmt formats temp file creation errors.
 	FsutilCreateTempFileFmt = "create temp file for %s: %w"
 	FsutilSetPermissionsFmt = "set permissions for %s: %w"
@@ -64,25 +70,32 @@ const (
 	FsutilRenameTempFileFmt = "rename temp file for %s: %w"
 	FsutilOpenDirFmt        = "open dir %s: %w"
 	FsutilSyncDirFmt        = "sync dir %s: %w"
+	FsutilShredFileFmt      = "shred %s: %w"
 
 	// WarningsResolveConfigFailedFmt formats config resolution failures.
-	WarningsResolveConfigFailedFmt   = "Failed to resolve configuration: %v"
-	WarningsResolveConfigFix         = "Correct URL/command/auth or environment variables."
-	WarningsTooManyServersFmt        = "enabled server count > %d (%d > %d)"
-	WarningsTooManyServersFix        = "disable rarely used servers; consolidate."
-	WarningsMCPConnectFailedFmt      = "cannot connect, initialize, or list tools: %v"
-	WarningsMCPConnectFix            = "correct URL/command/auth; or disable the server."
-	WarningsMCPServerTooManyToolsFmt = "server has > %d tools (%d > %d)"
-	WarningsMCPServerTooManyToolsFix = "split the server by domain or reduce exported tools."
-	WarningsMCPSchemaBloatServerFmt  = "estimated tokens for tool definitions > %d (%d > %d)"
-	WarningsMCPSchemaBloatFix        = "reduce schema verbosity; shorten descriptions; remove huge enums/oneOf; reduce tools."
-	WarningsMCPTooManyToolsTotalFmt  = "total discovered tools > %d (%d > %d)"
-	WarningsMCPTooManyToolsTotalFix  = "disable servers; reduce tool surface."
-	WarningsMCPSchemaBloatTotalFmt   = "estimated tokens for all tool definitions > %d (%d > %d)"
-	WarningsMCPToolNameCollisionFmt  = "same tool name appears in more than one server: %v"
-	WarningsMCPToolNameCollisionFix  = "namespace tool names per server (recommended pattern: <server>__<action>)."
-	WarningsInstructionsTooLargeFmt  = "estimated tokens of the combined instruction payload > %d (%d > %d)"
-	WarningsInstructionsTooLargeFix  = "reduce always-on instructions; move reference material into docs/ and link to it; remove repetition."
+	WarningsResolveConfigFailedFmt     = "Failed to resolve configuration: %v"
+	WarningsResolveConfigFix           = "Correct URL/command/auth or environment variables."
+	WarningsTooManyServersFmt          = "enabled server count > %d (%d > %d)"
+	WarningsTooManyServersFix          = "disable rarely used servers; consolidate."
+	WarningsMCPConnectFailedFmt        = "cannot connect, initialize, or list tools: %v"
+	WarningsMCPConnectFix              = "correct URL/command/auth; or disable the server."
+	WarningsMCPServerTooManyToolsFmt   = "server has > %d tools (%d > %d)"
+	WarningsMCPServerTooManyToolsFix   = "split the server by domain or reduce exported tools."
+	WarningsMCPSchemaBloatServerFmt    = "estimated tokens for tool definitions > %d (%d > %d)"
+	WarningsMCPSchemaBloatFix          = "reduce schema verbosity; shorten descriptions; remove huge enums/oneOf; reduce tools."
+	WarningsMCPTooManyToolsTotalFmt    = "total discovered tools > %d (%d > %d)"
+	WarningsMCPTooManyToolsTotalFix    = "disable servers; reduce tool surface."
+	WarningsMCPSchemaBloatTotalFmt     = "estimated tokens for all tool definitions > %d (%d > %d)"
+	WarningsMCPToolNameCollisionFmt    = "same tool name appears in more than one server: %v"
+	WarningsMCPToolNameCollisionFix    = "namespace tool names per server (recommended pattern: <server>__<action>)."
+	WarningsInstructionsTooLargeFmt    = "estimated tokens of the combined instruction payload > %d (%d > %d)"
+	WarningsInstructionsTooLargeFix    = "reduce always-on instructions; move reference material into docs/ and link to it; remove repetition."
+	WarningsSlashCommandUnknownKeysFmt = "front matter has keys agent-layer does not recognize and will not pass to any client: %s"
+	WarningsSlashCommandUnknownKeysFix = "check the spelling, or remove the keys; supported keys are listed in the README under Slash commands."
+	WarningsInstructionUnknownKeysFmt  = "front matter has keys agent-layer does not recognize: %s"
+	WarningsInstructionUnknownKeysFix  = "check the spelling, or remove the keys; instruction front matter supports clients, applies_to, and template."
+	WarningsTokenizerUnavailableFmt    = "%v; token counts use the heuristic estimate instead"
+	WarningsTokenizerUnavailableFix    = "download the vocabulary as described in the README under Warning thresholds, or set the tokenizer to \"heuristic\"."
 
 	WarningsUnsupportedTransportFmt     = "unsupported transport: %s"
 	WarningsUnsupportedHTTPTransportFmt = "unsupported http transport: %s"
@@ -90,6 +103,15 @@ const (
 	WarningsListToolsFailedFmt          = "list tools failed: %w"
 	WarningsTooManyTools                = "too many tools or infinite loop"
 
+	WarningsTokenizerUnknownFmt     = "unknown tokenizer %q"
+	WarningsTokenizerNoDirFmt       = "tokenizer %s needs a vocabulary file, but no home directory is available to look in"
+	WarningsTokenizerMissingFmt     = "tokenizer %s: vocabulary %s not found (see the README to download it)"
+	WarningsTokenizerLoadFailedFmt  = "invalid tokenizer vocabulary %s: %w"
+	WarningsTokenizerInvalidLineFmt = "line %d is not \"<base64 token> <rank>\""
+	WarningsTokenizerEmpty          = "vocabulary is empty"
+	WarningsClientsDetailPrefix     = "clients: "
+	WarningsTokenizerDetailPrefix   = "tokenizer: "
+
 	// CoverReportProfileFlagUsage describes the profile flag.
 	CoverReportProfileFlagUsage      = "path to coverage profile"
 	CoverReportThresholdFlagUsage    = "required coverage threshold (optional)"
@@ -120,4 +142,61 @@ const (
 
 	// McpRunPromptServerFailedFmt formats MCP prompt server failures.
 	McpRunPromptServerFailedFmt = "failed to run MCP prompt server: %w"
+	// McpPromptArgumentRequiredFmt formats a prompt request that omits a required argument.
+	McpPromptArgumentRequiredFmt = "missing required argument %q"
+	// McpPromptsReloadFailedFmt reports slash commands or resources that failed to reload; the previous ones stay registered.
+	McpPromptsReloadFailedFmt = "agent-layer prompts: keeping the previous slash commands and resources: %v\n"
+	// McpPromptsWatchFailedFmt reports that slash command changes will not be picked up until restart.
+	McpPromptsWatchFailedFmt = "agent-layer prompts: not watching slash commands for changes: %v\n"
+	// McpPromptsHTTPListeningFmt reports the URL the HTTP prompt server listens on.
+	McpPromptsHTTPListeningFmt = "agent-layer prompts: serving MCP over HTTP at %s\n"
+	// McpPromptsHTTPUnauthorized is the response body for requests without the bearer token.
+	McpPromptsHTTPUnauthorized = "missing or invalid bearer token"
+	// McpPromptsHTTPForbiddenOrigin is the response body for requests from a non-loopback Origin.
+	McpPromptsHTTPForbiddenOrigin = "requests from other origins are not allowed"
+	// McpPromptsHTTPForbiddenHost is the response body for requests whose Host is not loopback.
+	McpPromptsHTTPForbiddenHost = "requests must be addressed to localhost unless a token is set"
+	// McpPromptsHTTPTokenRequiredFmt reports a non-loopback bind address without a token: address, env var.
+	McpPromptsHTTPTokenRequiredFmt = "refusing to serve on %s, which is reachable from other machines, without a token; set %s in .agent-layer/.env or bind to 127.0.0.1"
+	// McpPromptsHTTPLoadFailedFmt reports a client whose prompts could not be loaded for an HTTP request.
+	McpPromptsHTTPLoadFailedFmt = "agent-layer prompts: failed to load prompts for client %q: %v\n"
+	// McpCommandToolCollisionFmt reports a slash command left out because its tool name is taken.
+	McpCommandToolCollisionFmt = "agent-layer prompts: not serving %s as a tool: %s already uses %s\n"
+	// McpCommandToolArgumentsInvalidFmt formats tool arguments that are not a JSON object.
+	McpCommandToolArgumentsInvalidFmt = "arguments must be a JSON object: %v"
+	// McpCommandToolDescriptionFmt describes a slash command tool: description, then command name.
+	McpCommandToolDescriptionFmt = "%s (slash command /%s; returns its instructions for you to follow)"
+	// McpResourceReadFailedFmt formats a project file that could not be read for a resource.
+	McpResourceReadFailedFmt = "failed to read %s: %w"
+	// McpResourceSubscribeUnknownFmt rejects subscriptions to URIs the server does not serve.
+	McpResourceSubscribeUnknownFmt = "cannot subscribe to %s: not an agent-layer resource"
+
+	McpResourceMemoryDescriptionFmt      = "Project memory file docs/agent-layer/%s"
+	McpResourceIssuesDescription         = "Project memory: deferred defects, maintainability refactors, technical debt, and risks (docs/agent-layer/ISSUES.md)"
+	McpResourceBacklogDescription        = "Project memory: unscheduled user-visible features and tasks (docs/agent-layer/BACKLOG.md)"
+	McpResourceRoadmapDescription        = "Project memory: numbered phases that guide architecture and sequencing (docs/agent-layer/ROADMAP.md)"
+	McpResourceDecisionsDescription      = "Project memory: log of important, non-obvious decisions (docs/agent-layer/DECISIONS.md)"
+	McpResourceCommandsDescription       = "Project memory: canonical development workflow commands (docs/agent-layer/COMMANDS.md)"
+	McpResourceInstructionDescriptionFmt = "Instruction fragment .agent-layer/instructions/%s, rendered for this client"
+	McpResourceMemoryTemplateDescription = "A project memory file in docs/agent-layer/, by file name (for example ISSUES.md)"
+	McpResourceInstructionTemplateDesc   = "An instruction fragment in .agent-layer/instructions/, by file name"
+
+	// Memory file tool messages.
+	MemoryReadFailedFmt       = "failed to read %s: %w"
+	MemoryWriteFailedFmt      = "failed to write %s: %w"
+	MemoryFieldRequiredFmt    = "%s is required"
+	MemoryFieldMultilineFmt   = "%s must be a single line"
+	MemoryPriorityInvalidFmt  = "priority must be Critical, High, Medium, or Low, got %q"
+	MemoryMarkerMissingFmt    = "%s has no %s marker; run al migrate to add it"
+	MemoryDuplicateFmt        = "%s already has an entry titled %q (%s); update that entry instead"
+	MemoryEntryNotFoundFmt    = "no issue or backlog entry with id %q"
+	MemoryResolveDecisionFmt  = "%s is a decision; decisions are history and cannot be resolved"
+	MemoryIDGenerateFailedFmt = "failed to generate entry id: %w"
+	MemoryQueryRequired       = "query is required"
+	MemoryKindInvalidFmt      = "kind must be issue, backlog, or decision, got %q"
+	MemoryAddIssueDescription = "Record a deferred defect, refactor, or risk in docs/agent-layer/ISSUES.md. Rejects duplicate titles."
+	MemoryAddBacklogDesc      = "Record an unscheduled feature or task in docs/agent-layer/BACKLOG.md. Rejects duplicate titles."
+	MemoryAddDecisionDesc     = "Append an important, non-obvious decision to docs/agent-layer/DECISIONS.md. Rejects duplicate titles."
+	MemorySearchDescription   = "Search issue, backlog, and decision entries in docs/agent-layer/ for entries containing every word of query."
+	MemoryResolveDescription  = "Remove a fixed issue or implemented backlog entry by id. Decisions are history and cannot be resolved."
 )
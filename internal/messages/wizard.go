@@ -26,6 +26,9 @@ const (
 	WizardEnvSecretFoundPromptFmt             = "Found %s in your environment. Write it to .agent-layer/.env?"
 	WizardSecretInputPromptFmt                = "Enter %s (leave blank to skip)"
 	WizardSecretMissingDisablePromptFmt       = "No value provided for %s. Disable MCP server %s?"
+	WizardSecretProviderFoundPromptFmt        = "Found %s in secret provider %s. Reference it as ${secret:%s} instead of writing it to .agent-layer/.env?"
+	WizardProviderSecretInputPromptFmt        = "Enter %s to store in secret provider %s (leave blank to skip)"
+	WizardSecretNoWritableProviderFmt         = "%s is referenced as ${secret:%s} but no configured secret provider has it or can store it. Disable MCP server %s?"
 	WizardEnableWarningsPrompt                = "Enable warnings for performance and usage issues?"
 	WizardInstructionTokenThresholdTitle      = "Instruction token threshold"
 	WizardMCPServerThresholdTitle             = "MCP server threshold"
@@ -42,6 +45,7 @@ const (
 	WizardBackupEnvFailedFmt    = "failed to backup .env: %w"
 	WizardWriteConfigFailedFmt  = "failed to write config: %w"
 	WizardWriteEnvFailedFmt     = "failed to write .env: %w"
+	WizardStoreSecretFailedFmt  = "failed to store %s in secret provider %s: %w"
 	WizardRunningSync           = "Running sync..."
 	WizardWarningFmt            = "Warning: %s\n"
 
@@ -55,6 +59,8 @@ const (
 	WizardSummaryRestoredMCPServersHeader        = "\nRestored Default MCP Servers:\n"
 	WizardSummaryDisabledMCPServersHeader        = "\nDisabled MCP Servers (missing secrets):\n"
 	WizardSummarySecretsHeader                   = "\nSecrets to Update:\n"
+	WizardSummaryProviderSecretFmt               = "- %s (stored in %s)\n"
+	WizardSummarySecretReferenceFmt              = "- %s (referenced as ${secret:%s})\n"
 	WizardSummaryWarningsHeader                  = "\nWarnings:\n"
 	WizardSummaryWarningsDisabled                = "(disabled)\n"
 	WizardSummaryListItemFmt                     = "- %s\n"
@@ -95,6 +101,11 @@ const (
 
 	WizardTOMLUnterminatedMultiline = "unterminated multiline string in TOML output"
 
+	WizardConfigKeyInvalidFmt       = "invalid config key %q"
+	WizardConfigKeyNotTableFmt      = "cannot set %s: %s is not a table"
+	WizardConfigKeyNotSetFmt        = "%s is not set"
+	WizardConfigServerKeyInvalidFmt = "invalid config key %q: address MCP servers as %s.<id>.<field>"
+
 	WizardMultiSelectDescription = "Arrow keys to navigate, Space to toggle, Enter to continue, Esc to cancel."
 
 	WizardApprovalAllDescription      = "Auto-approve shell commands and MCP tool calls (where supported)."
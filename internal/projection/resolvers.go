@@ -22,12 +22,16 @@ func (e *MCPServerResolveError) Unwrap() error {
 
 // ClientPlaceholderResolver returns a resolver that preserves placeholders in client-specific syntax.
 // clientSyntax is a format string like "${%s}" or "${env:%s}" where %s is the env var name.
-// Built-in placeholders (like AL_REPO_ROOT) are resolved to their actual values.
+// Built-in placeholders (like AL_REPO_ROOT) are resolved to their actual values. ${secret:NAME}
+// becomes a reference to NAME, which is injected into the client environment at launch.
 func ClientPlaceholderResolver(clientSyntax string) EnvVarResolver {
 	return func(name, value string) string {
 		if config.IsBuiltInEnvVar(name) {
 			return value
 		}
+		if secret, ok := config.SecretName(name); ok {
+			name = secret
+		}
 		return fmt.Sprintf(clientSyntax, name)
 	}
 }
@@ -22,6 +22,13 @@ func TestClientPlaceholderResolver(t *testing.T) {
 	}
 }
 
+func TestClientPlaceholderResolverSecret(t *testing.T) {
+	resolver := ClientPlaceholderResolver("${env:%s}")
+	if result := resolver("secret:API_TOKEN", "secret123"); result != "${env:API_TOKEN}" {
+		t.Fatalf("expected ${env:API_TOKEN}, got %s", result)
+	}
+}
+
 func TestClientPlaceholderResolverVSCode(t *testing.T) {
 	resolver := ClientPlaceholderResolver("${env:%s}")
 
@@ -31,6 +38,43 @@ func TestClientPlaceholderResolverVSCode(t *testing.T) {
 	}
 }
 
+func TestResolveMCPServersPlaceholderForms(t *testing.T) {
+	enabled := true
+	servers := []config.MCPServer{{
+		ID:        "api",
+		Enabled:   &enabled,
+		Transport: "http",
+		URL:       "https://${HOST:-api.example.com}/mcp?v=$${version}",
+		Headers:   map[string]string{"Authorization": "Bearer ${TOKEN:?set TOKEN in .agent-layer/.env}"},
+	}}
+	env := map[string]string{"TOKEN": "secret"}
+
+	resolved, err := ResolveMCPServers(servers, env, "vscode", ClientPlaceholderResolver("${env:%s}"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// VS Code has no default syntax, so the default is inlined; set variables stay client placeholders.
+	if resolved[0].URL != "https://api.example.com/mcp?v=${version}" {
+		t.Fatalf("unexpected url: %s", resolved[0].URL)
+	}
+	if resolved[0].Headers["Authorization"] != "Bearer ${env:TOKEN}" {
+		t.Fatalf("unexpected header: %s", resolved[0].Headers["Authorization"])
+	}
+
+	env["HOST"] = "custom.example.com"
+	resolved, err = ResolveMCPServers(servers, env, "gemini", ClientPlaceholderResolver("${%s}"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resolved[0].URL != "https://${HOST}/mcp?v=${version}" {
+		t.Fatalf("unexpected url: %s", resolved[0].URL)
+	}
+
+	if _, err := ResolveMCPServers(servers, map[string]string{}, "gemini", ClientPlaceholderResolver("${%s}")); err == nil {
+		t.Fatalf("expected required placeholder error")
+	}
+}
+
 func TestFullValueResolver(t *testing.T) {
 	env := map[string]string{"TOKEN": "from-env"}
 
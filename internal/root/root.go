@@ -11,10 +11,14 @@ import (
 
 const (
 	agentLayerDir = ".agent-layer"
+	configFile    = "config.toml"
 	gitDir        = ".git"
 )
 
 // FindAgentLayerRoot walks upward from start until it finds a directory containing .agent-layer/.
+// Nested .agent-layer/ directories without a config.toml are child layers of a monorepo, so the walk
+// continues past them to the nearest ancestor whose .agent-layer/ has a config.toml; when none exists,
+// the nearest .agent-layer/ is returned.
 // It returns the root path, whether it was found, and any error encountered.
 func FindAgentLayerRoot(start string) (string, bool, error) {
 	if start == "" {
@@ -25,6 +29,7 @@ func FindAgentLayerRoot(start string) (string, bool, error) {
 		return "", false, fmt.Errorf(messages.RootResolvePathFmt, start, err)
 	}
 
+	nearest := ""
 	dir := abs
 	for {
 		candidate := filepath.Join(dir, agentLayerDir)
@@ -33,7 +38,16 @@ func FindAgentLayerRoot(start string) (string, bool, error) {
 			if !info.IsDir() {
 				return "", false, fmt.Errorf(messages.RootPathNotDirFmt, candidate)
 			}
-			return dir, true, nil
+			hasConfig, err := exists(filepath.Join(candidate, configFile))
+			if err != nil {
+				return "", false, err
+			}
+			if hasConfig {
+				return dir, true, nil
+			}
+			if nearest == "" {
+				nearest = dir
+			}
 		}
 		if err != nil && !errors.Is(err, os.ErrNotExist) {
 			return "", false, fmt.Errorf(messages.RootCheckPathFmt, candidate, err)
@@ -41,12 +55,24 @@ func FindAgentLayerRoot(start string) (string, bool, error) {
 
 		parent := filepath.Dir(dir)
 		if parent == dir {
-			return "", false, nil
+			return nearest, nearest != "", nil
 		}
 		dir = parent
 	}
 }
 
+// exists reports whether path exists.
+func exists(path string) (bool, error) {
+	_, err := os.Stat(path)
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return false, fmt.Errorf(messages.RootCheckPathFmt, path, err)
+}
+
 // FindRepoRoot returns the repo root for initialization.
 // It prefers an existing .agent-layer directory, then a .git directory or file, and falls back to start.
 func FindRepoRoot(start string) (string, error) {
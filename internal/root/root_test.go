@@ -28,6 +28,40 @@ func TestFindAgentLayerRootFound(t *testing.T) {
 	}
 }
 
+func TestFindAgentLayerRootSkipsChildLayer(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".agent-layer"), 0o755); err != nil {
+		t.Fatalf("mkdir .agent-layer: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".agent-layer", "config.toml"), []byte(""), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	child := filepath.Join(root, "services", "api")
+	if err := os.MkdirAll(filepath.Join(child, ".agent-layer", "instructions"), 0o755); err != nil {
+		t.Fatalf("mkdir child layer: %v", err)
+	}
+
+	got, found, err := FindAgentLayerRoot(filepath.Join(child, "src"))
+	if err != nil {
+		t.Fatalf("FindAgentLayerRoot error: %v", err)
+	}
+	if !found || got != root {
+		t.Fatalf("expected parent root %s, got %s (found=%v)", root, got, found)
+	}
+
+	// A nested .agent-layer with its own config.toml is an independent root.
+	if err := os.WriteFile(filepath.Join(child, ".agent-layer", "config.toml"), []byte(""), 0o644); err != nil {
+		t.Fatalf("write child config: %v", err)
+	}
+	got, _, err = FindAgentLayerRoot(child)
+	if err != nil {
+		t.Fatalf("FindAgentLayerRoot error: %v", err)
+	}
+	if got != child {
+		t.Fatalf("expected independent root %s, got %s", child, got)
+	}
+}
+
 func TestFindAgentLayerRootMissing(t *testing.T) {
 	root := t.TempDir()
 	got, found, err := FindAgentLayerRoot(root)
@@ -0,0 +1,63 @@
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+var execCommand = exec.Command
+
+// NamePlaceholder in exec provider args is replaced with the requested secret name.
+const NamePlaceholder = "{name}"
+
+// ExecProvider runs a command (for example `pass show agent-layer/{name}`) and reads the secret
+// from stdout. The secret name is also exported to the command as AL_SECRET_NAME.
+// Empty output means the secret is not available; a non-zero exit is an error.
+type ExecProvider struct {
+	Command string
+	Args    []string
+	// Dir is the working directory for the command (the repo root).
+	Dir string
+}
+
+// Name implements Provider.
+func (p ExecProvider) Name() string {
+	return "exec (" + p.Command + ")"
+}
+
+// Lookup implements Provider.
+func (p ExecProvider) Lookup(name string) (string, error) {
+	if err := validateName(name); err != nil {
+		return "", err
+	}
+	args := make([]string, len(p.Args))
+	for i, arg := range p.Args {
+		args[i] = strings.ReplaceAll(arg, NamePlaceholder, name)
+	}
+	cmd := execCommand(p.Command, args...)
+	cmd.Dir = p.Dir
+	cmd.Env = append(os.Environ(), "AL_SECRET_NAME="+name)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			if detail := strings.TrimSpace(stderr.String()); detail != "" {
+				return "", fmt.Errorf(messages.SecretsExecFailedOutputFmt, p.Command, err, detail)
+			}
+		}
+		return "", fmt.Errorf(messages.SecretsExecFailedFmt, p.Command, err)
+	}
+	value := trimValue(stdout.String())
+	if value == "" {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
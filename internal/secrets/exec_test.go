@@ -0,0 +1,69 @@
+package secrets
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func requireShell(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+}
+
+func TestExecProviderLookup(t *testing.T) {
+	requireShell(t)
+	provider := ExecProvider{
+		Command: "sh",
+		Args:    []string{"-c", `printf '%s-%s\n' "$1" "$AL_SECRET_NAME"`, "sh", "{name}"},
+		Dir:     t.TempDir(),
+	}
+	value, err := provider.Lookup("TOKEN")
+	if err != nil {
+		t.Fatalf("Lookup error: %v", err)
+	}
+	if value != "TOKEN-TOKEN" {
+		t.Fatalf("unexpected value %q", value)
+	}
+	if provider.Name() != "exec (sh)" {
+		t.Fatalf("unexpected name %q", provider.Name())
+	}
+}
+
+func TestExecProviderEmptyOutputIsNotFound(t *testing.T) {
+	requireShell(t)
+	provider := ExecProvider{Command: "sh", Args: []string{"-c", "true"}}
+	if _, err := provider.Lookup("TOKEN"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestExecProviderFailure(t *testing.T) {
+	requireShell(t)
+	provider := ExecProvider{Command: "sh", Args: []string{"-c", "echo 'is not in the password store' >&2; exit 1"}}
+	_, err := provider.Lookup("TOKEN")
+	if err == nil || errors.Is(err, ErrNotFound) || !strings.Contains(err.Error(), "is not in the password store") {
+		t.Fatalf("expected failure with stderr, got %v", err)
+	}
+
+	missing := ExecProvider{Command: "al-missing-secret-command"}
+	if _, err := missing.Lookup("TOKEN"); err == nil {
+		t.Fatalf("expected missing command error")
+	}
+}
+
+func TestExecProviderRejectsInvalidName(t *testing.T) {
+	original := execCommand
+	t.Cleanup(func() { execCommand = original })
+	execCommand = func(name string, args ...string) *exec.Cmd {
+		t.Fatalf("command should not run for an invalid name")
+		return nil
+	}
+	if _, err := (ExecProvider{Command: "pass"}).Lookup("../x"); err == nil {
+		t.Fatalf("expected invalid name error")
+	}
+}
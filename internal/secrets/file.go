@@ -0,0 +1,56 @@
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/conn-castle/agent-layer/internal/fsutil"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// FileProvider stores one secret per file in Dir; the file name is the secret name.
+type FileProvider struct {
+	Dir string
+}
+
+// Name implements Provider.
+func (p FileProvider) Name() string {
+	return "file (" + p.Dir + ")"
+}
+
+// Lookup implements Provider.
+func (p FileProvider) Lookup(name string) (string, error) {
+	if err := validateName(name); err != nil {
+		return "", err
+	}
+	path := filepath.Join(p.Dir, name)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf(messages.SecretsFileReadFailedFmt, path, err)
+	}
+	value := trimValue(string(data))
+	if value == "" {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+// Store implements Writer. Secret files are readable only by the owner.
+func (p FileProvider) Store(name string, value string) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+	path := filepath.Join(p.Dir, name)
+	if err := os.MkdirAll(p.Dir, 0o700); err != nil {
+		return fmt.Errorf(messages.SecretsFileWriteFailedFmt, path, err)
+	}
+	if err := fsutil.WriteFileAtomic(path, []byte(value+"\n"), 0o600); err != nil {
+		return fmt.Errorf(messages.SecretsFileWriteFailedFmt, path, err)
+	}
+	return nil
+}
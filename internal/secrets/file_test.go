@@ -0,0 +1,57 @@
+package secrets
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestFileProviderLookupAndStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "secrets")
+	provider := FileProvider{Dir: dir}
+
+	if _, err := provider.Lookup("TOKEN"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if err := provider.Store("TOKEN", "abc"); err != nil {
+		t.Fatalf("Store error: %v", err)
+	}
+	value, err := provider.Lookup("TOKEN")
+	if err != nil || value != "abc" {
+		t.Fatalf("unexpected lookup: %q %v", value, err)
+	}
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(filepath.Join(dir, "TOKEN"))
+		if err != nil {
+			t.Fatalf("stat: %v", err)
+		}
+		if info.Mode().Perm() != 0o600 {
+			t.Fatalf("expected 0600, got %v", info.Mode().Perm())
+		}
+	}
+}
+
+func TestFileProviderEmptyAndInvalid(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "EMPTY"), []byte("\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	provider := FileProvider{Dir: dir}
+	if _, err := provider.Lookup("EMPTY"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for empty file, got %v", err)
+	}
+	if _, err := provider.Lookup("../EMPTY"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected invalid name error, got %v", err)
+	}
+	if err := provider.Store("a/b", "x"); err == nil {
+		t.Fatalf("expected invalid name error on store")
+	}
+	if err := os.Mkdir(filepath.Join(dir, "DIR"), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if _, err := provider.Lookup("DIR"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected read error, got %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/fsutil"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+var goos = runtime.GOOS
+
+// DefaultKeyringService is the keyring service used when none is configured.
+const DefaultKeyringService = "agent-layer"
+
+// macOSItemNotFound is the exit status of `security find-generic-password` for a missing item.
+const macOSItemNotFound = 44
+
+// KeyringProvider reads secrets from the OS keyring (macOS Keychain via `security`, or the
+// Secret Service via `secret-tool` elsewhere), using Service as the service and the secret name
+// as the account. When File is set, a JSON file of service -> name -> value stands in for the OS
+// keyring, for tests and headless machines.
+type KeyringProvider struct {
+	Service string
+	File    string
+}
+
+// Name implements Provider.
+func (p KeyringProvider) Name() string {
+	if p.File != "" {
+		return "keyring (" + p.File + ")"
+	}
+	return "keyring (" + p.service() + ")"
+}
+
+func (p KeyringProvider) service() string {
+	if p.Service == "" {
+		return DefaultKeyringService
+	}
+	return p.Service
+}
+
+// Lookup implements Provider.
+func (p KeyringProvider) Lookup(name string) (string, error) {
+	if err := validateName(name); err != nil {
+		return "", err
+	}
+	if p.File != "" {
+		items, err := readKeyringFile(p.File)
+		if err != nil {
+			return "", err
+		}
+		if value := items[p.service()][name]; value != "" {
+			return value, nil
+		}
+		return "", ErrNotFound
+	}
+	return osKeyringGet(p.service(), name)
+}
+
+// Store implements Writer.
+func (p KeyringProvider) Store(name string, value string) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+	if p.File == "" {
+		return osKeyringSet(p.service(), name, value)
+	}
+	items, err := readKeyringFile(p.File)
+	if err != nil {
+		return err
+	}
+	if items[p.service()] == nil {
+		items[p.service()] = make(map[string]string)
+	}
+	items[p.service()][name] = value
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return fmt.Errorf(messages.SecretsFileWriteFailedFmt, p.File, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(p.File), 0o700); err != nil {
+		return fmt.Errorf(messages.SecretsFileWriteFailedFmt, p.File, err)
+	}
+	if err := fsutil.WriteFileAtomic(p.File, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf(messages.SecretsFileWriteFailedFmt, p.File, err)
+	}
+	return nil
+}
+
+// readKeyringFile loads a file-backed keyring; a missing file is an empty keyring.
+func readKeyringFile(path string) (map[string]map[string]string, error) {
+	items := make(map[string]map[string]string)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return items, nil
+		}
+		return nil, fmt.Errorf(messages.SecretsFileReadFailedFmt, path, err)
+	}
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf(messages.SecretsKeyringFileInvalidFmt, path, err)
+	}
+	return items, nil
+}
+
+// osKeyringGet reads an item from the platform keyring.
+func osKeyringGet(service string, name string) (string, error) {
+	var cmd *exec.Cmd
+	switch goos {
+	case "darwin":
+		cmd = execCommand("security", "find-generic-password", "-s", service, "-a", name, "-w")
+	case "linux", "freebsd", "openbsd", "netbsd":
+		cmd = execCommand("secret-tool", "lookup", "service", service, "account", name)
+	default:
+		return "", fmt.Errorf(messages.SecretsKeyringUnsupportedFmt, goos)
+	}
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			// secret-tool exits 1 without output for a missing item.
+			if (goos == "darwin" && exitErr.ExitCode() == macOSItemNotFound) || (goos != "darwin" && stdout.Len() == 0) {
+				return "", ErrNotFound
+			}
+		}
+		return "", fmt.Errorf(messages.SecretsKeyringCommandFailedFmt, "lookup", err)
+	}
+	value := trimValue(stdout.String())
+	if value == "" {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+// osKeyringSet stores an item in the platform keyring. The value is passed on stdin, never in argv,
+// where other local users could read it with ps.
+func osKeyringSet(service string, name string, value string) error {
+	var cmd *exec.Cmd
+	switch goos {
+	case "darwin":
+		// security -i reads one command per line, so the value cannot contain a line break.
+		if strings.ContainsAny(value, "\r\n") {
+			return errors.New(messages.SecretsKeyringMultilineValue)
+		}
+		cmd = execCommand("security", "-i")
+		cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n", securityQuote(service), securityQuote(name), securityQuote(value)))
+	case "linux", "freebsd", "openbsd", "netbsd":
+		cmd = execCommand("secret-tool", "store", "--label", service+" "+name, "service", service, "account", name)
+		cmd.Stdin = strings.NewReader(value)
+	default:
+		return fmt.Errorf(messages.SecretsKeyringUnsupportedFmt, goos)
+	}
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf(messages.SecretsKeyringCommandFailedFmt, "store", err)
+	}
+	return nil
+}
+
+// securityQuote quotes an argument for a `security -i` command line.
+func securityQuote(arg string) string {
+	return "'" + strings.ReplaceAll(arg, "'", `'"'"'`) + "'"
+}
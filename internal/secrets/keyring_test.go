@@ -0,0 +1,121 @@
+package secrets
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestKeyringProviderFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keyring.json")
+	provider := KeyringProvider{File: path}
+
+	if _, err := provider.Lookup("TOKEN"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if err := provider.Store("TOKEN", "abc"); err != nil {
+		t.Fatalf("Store error: %v", err)
+	}
+	if err := (KeyringProvider{Service: "other", File: path}).Store("TOKEN", "xyz"); err != nil {
+		t.Fatalf("Store error: %v", err)
+	}
+	value, err := provider.Lookup("TOKEN")
+	if err != nil || value != "abc" {
+		t.Fatalf("unexpected lookup: %q %v", value, err)
+	}
+	if provider.Name() != "keyring ("+path+")" {
+		t.Fatalf("unexpected name %q", provider.Name())
+	}
+
+	if err := os.WriteFile(path, []byte("not json"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := provider.Lookup("TOKEN"); err == nil || !strings.Contains(err.Error(), "invalid keyring file") {
+		t.Fatalf("expected invalid file error, got %v", err)
+	}
+}
+
+// fakeCommands replaces execCommand with a shell script and records invocations.
+func fakeCommands(t *testing.T, platform string, script string) *[][]string {
+	t.Helper()
+	requireShell(t)
+	originalCommand, originalOS := execCommand, goos
+	t.Cleanup(func() { execCommand, goos = originalCommand, originalOS })
+	goos = platform
+	var calls [][]string
+	execCommand = func(name string, args ...string) *exec.Cmd {
+		calls = append(calls, append([]string{name}, args...))
+		return exec.Command("sh", "-c", script)
+	}
+	return &calls
+}
+
+func TestKeyringProviderOSLinux(t *testing.T) {
+	calls := fakeCommands(t, "linux", "printf 'secret\\n'")
+	value, err := KeyringProvider{}.Lookup("TOKEN")
+	if err != nil || value != "secret" {
+		t.Fatalf("unexpected lookup: %q %v", value, err)
+	}
+	want := []string{"secret-tool", "lookup", "service", "agent-layer", "account", "TOKEN"}
+	if !reflect.DeepEqual((*calls)[0], want) {
+		t.Fatalf("unexpected command %v", (*calls)[0])
+	}
+
+	fakeCommands(t, "linux", "exit 1")
+	if _, err := (KeyringProvider{}).Lookup("TOKEN"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	calls = fakeCommands(t, "linux", "cat >/dev/null")
+	if err := (KeyringProvider{Service: "svc"}).Store("TOKEN", "v"); err != nil {
+		t.Fatalf("Store error: %v", err)
+	}
+	if (*calls)[0][0] != "secret-tool" || (*calls)[0][1] != "store" {
+		t.Fatalf("unexpected command %v", (*calls)[0])
+	}
+}
+
+func TestKeyringProviderOSDarwin(t *testing.T) {
+	calls := fakeCommands(t, "darwin", "exit 44")
+	if _, err := (KeyringProvider{}).Lookup("TOKEN"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if (*calls)[0][0] != "security" {
+		t.Fatalf("unexpected command %v", (*calls)[0])
+	}
+
+	fakeCommands(t, "darwin", "exit 1")
+	if _, err := (KeyringProvider{}).Lookup("TOKEN"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected keyring failure, got %v", err)
+	}
+
+	stdin := filepath.Join(t.TempDir(), "stdin")
+	calls = fakeCommands(t, "darwin", "cat > "+stdin)
+	if err := (KeyringProvider{}).Store("TOKEN", "it's secret"); err != nil {
+		t.Fatalf("Store error: %v", err)
+	}
+	if !reflect.DeepEqual((*calls)[0], []string{"security", "-i"}) {
+		t.Fatalf("expected the value to stay out of argv, got %v", (*calls)[0])
+	}
+	data, err := os.ReadFile(stdin)
+	if err != nil || string(data) != "add-generic-password -U -s 'agent-layer' -a 'TOKEN' -w 'it'\"'\"'s secret'\n" {
+		t.Fatalf("unexpected security input %q, %v", data, err)
+	}
+	if err := (KeyringProvider{}).Store("TOKEN", "a\nb"); err == nil {
+		t.Fatalf("expected multi-line value to be rejected")
+	}
+}
+
+func TestKeyringProviderUnsupportedOS(t *testing.T) {
+	fakeCommands(t, "windows", "true")
+	if _, err := (KeyringProvider{}).Lookup("TOKEN"); err == nil || !strings.Contains(err.Error(), "not supported on windows") {
+		t.Fatalf("expected unsupported error, got %v", err)
+	}
+	if err := (KeyringProvider{}).Store("TOKEN", "v"); err == nil {
+		t.Fatalf("expected unsupported store error")
+	}
+}
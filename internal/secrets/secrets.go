@@ -0,0 +1,96 @@
+// Package secrets resolves ${secret:NAME} placeholders through pluggable providers.
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// ErrNotFound reports that a provider has no value for a secret.
+var ErrNotFound = errors.New(messages.SecretsNotFound)
+
+// Provider resolves named secrets from a backend.
+type Provider interface {
+	// Name identifies the provider in diagnostics.
+	Name() string
+	// Lookup returns the value of name, or ErrNotFound when the provider has none.
+	Lookup(name string) (string, error)
+}
+
+// Writer is implemented by providers that can store secrets.
+type Writer interface {
+	Store(name string, value string) error
+}
+
+// WritableProvider is a Provider that can also store secrets.
+type WritableProvider interface {
+	Provider
+	Writer
+}
+
+// Resolved is the outcome of resolving one secret through a Chain.
+type Resolved struct {
+	Value string
+	// Provider names the provider that supplied Value.
+	Provider string
+	// Err is set when no provider supplied a value or a provider failed.
+	Err error
+}
+
+// Chain tries providers in order; the first provider with a value wins.
+type Chain []Provider
+
+// Resolve looks up name in each provider. A provider failure (other than ErrNotFound) stops
+// the lookup so a broken backend is never silently skipped.
+func (c Chain) Resolve(name string) Resolved {
+	if len(c) == 0 {
+		return Resolved{Err: fmt.Errorf(messages.SecretsNoProvidersFmt, name)}
+	}
+	names := make([]string, 0, len(c))
+	for _, provider := range c {
+		value, err := provider.Lookup(name)
+		if err == nil && value != "" {
+			return Resolved{Value: value, Provider: provider.Name()}
+		}
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return Resolved{Err: fmt.Errorf(messages.SecretsProviderFailedFmt, name, provider.Name(), err)}
+		}
+		names = append(names, provider.Name())
+	}
+	return Resolved{Err: fmt.Errorf(messages.SecretsNotFoundInProvidersFmt, name, strings.Join(names, ", "))}
+}
+
+// Writer returns the first provider that can store secrets, if any.
+func (c Chain) Writer() (WritableProvider, bool) {
+	for _, provider := range c {
+		if writer, ok := provider.(WritableProvider); ok {
+			return writer, true
+		}
+	}
+	return nil, false
+}
+
+// validateName rejects names that could escape a provider's namespace (for example a path).
+func validateName(name string) error {
+	if name == "" {
+		return fmt.Errorf(messages.SecretsInvalidNameFmt, name)
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c != '_' && c != '-' && c != '.' && (c < 'A' || c > 'Z') && (c < 'a' || c > 'z') && (c < '0' || c > '9') {
+			return fmt.Errorf(messages.SecretsInvalidNameFmt, name)
+		}
+	}
+	if name == "." || name == ".." {
+		return fmt.Errorf(messages.SecretsInvalidNameFmt, name)
+	}
+	return nil
+}
+
+// trimValue drops the trailing newline that commands and editors append to secrets.
+func trimValue(value string) string {
+	return strings.TrimRight(value, "\r\n")
+}
@@ -0,0 +1,87 @@
+package secrets
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type stubProvider struct {
+	name   string
+	values map[string]string
+	err    error
+}
+
+func (p stubProvider) Name() string { return p.name }
+
+func (p stubProvider) Lookup(name string) (string, error) {
+	if p.err != nil {
+		return "", p.err
+	}
+	if value, ok := p.values[name]; ok {
+		return value, nil
+	}
+	return "", ErrNotFound
+}
+
+func TestChainResolve(t *testing.T) {
+	chain := Chain{
+		stubProvider{name: "first", values: map[string]string{"A": "from-first"}},
+		stubProvider{name: "second", values: map[string]string{"A": "shadowed", "B": "from-second"}},
+	}
+
+	got := chain.Resolve("A")
+	if got.Err != nil || got.Value != "from-first" || got.Provider != "first" {
+		t.Fatalf("unexpected A: %+v", got)
+	}
+	got = chain.Resolve("B")
+	if got.Err != nil || got.Value != "from-second" || got.Provider != "second" {
+		t.Fatalf("unexpected B: %+v", got)
+	}
+	got = chain.Resolve("C")
+	if got.Err == nil || !strings.Contains(got.Err.Error(), "secret C not found in providers: first, second") {
+		t.Fatalf("expected not found error, got %+v", got)
+	}
+}
+
+func TestChainResolveStopsOnProviderFailure(t *testing.T) {
+	chain := Chain{
+		stubProvider{name: "broken", err: errors.New("boom")},
+		stubProvider{name: "fallback", values: map[string]string{"A": "value"}},
+	}
+	got := chain.Resolve("A")
+	if got.Err == nil || !strings.Contains(got.Err.Error(), "broken provider: boom") {
+		t.Fatalf("expected provider failure, got %+v", got)
+	}
+}
+
+func TestChainResolveNoProviders(t *testing.T) {
+	got := Chain{}.Resolve("A")
+	if got.Err == nil || !strings.Contains(got.Err.Error(), "no secret providers configured") {
+		t.Fatalf("expected no providers error, got %+v", got)
+	}
+}
+
+func TestChainWriter(t *testing.T) {
+	if _, ok := (Chain{stubProvider{name: "ro"}}).Writer(); ok {
+		t.Fatalf("expected no writer")
+	}
+	chain := Chain{ExecProvider{Command: "pass"}, FileProvider{Dir: t.TempDir()}}
+	writer, ok := chain.Writer()
+	if !ok || !strings.HasPrefix(writer.Name(), "file") {
+		t.Fatalf("expected file writer, got %v", writer)
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	for _, name := range []string{"TOKEN", "github-token", "a.b_c9"} {
+		if err := validateName(name); err != nil {
+			t.Fatalf("expected %q to be valid: %v", name, err)
+		}
+	}
+	for _, name := range []string{"", ".", "..", "a/b", `a\b`, "a b"} {
+		if err := validateName(name); err == nil {
+			t.Fatalf("expected %q to be invalid", name)
+		}
+	}
+}
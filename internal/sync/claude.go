@@ -55,8 +55,7 @@ func buildClaudeSettings(project *config.ProjectConfig) (*claudeSettings, error)
 	}
 
 	if approvals.AllowMCP {
-		ids := projection.EnabledServerIDs(project.Config.MCP.Servers, "claude")
-		ids = append(ids, "agent-layer")
+		ids := append(projection.EnabledServerIDs(project.Config.MCP.Servers, "claude"), "agent-layer")
 		sort.Strings(ids)
 		for _, id := range ids {
 			allow = append(allow, fmt.Sprintf("mcp__%s__*", id))
@@ -3,6 +3,7 @@ package sync
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/conn-castle/agent-layer/internal/config"
@@ -32,6 +33,24 @@ func TestBuildClaudeSettings(t *testing.T) {
 	}
 }
 
+func TestBuildClaudeSettingsNativeSlashCommands(t *testing.T) {
+	t.Parallel()
+	project := &config.ProjectConfig{
+		Config: config.Config{
+			Approvals: config.ApprovalsConfig{Mode: "mcp"},
+			Agents:    config.AgentsConfig{Claude: config.ClaudeConfig{SlashCommands: config.SlashCommandsNative}},
+		},
+	}
+
+	settings, err := buildClaudeSettings(project)
+	if err != nil {
+		t.Fatalf("buildClaudeSettings error: %v", err)
+	}
+	if settings.Permissions == nil || strings.Join(settings.Permissions.Allow, ",") != "mcp__agent-layer__*" {
+		t.Fatalf("expected the mcp__agent-layer__* permission, got %+v", settings.Permissions)
+	}
+}
+
 func TestWriteClaudeSettings(t *testing.T) {
 	t.Parallel()
 	root := t.TempDir()
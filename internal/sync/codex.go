@@ -20,7 +20,7 @@ const codexHeader = `# GENERATED FILE — MAY CONTAIN SECRETS
 
 // WriteCodexConfig generates .codex/config.toml.
 func WriteCodexConfig(sys System, root string, project *config.ProjectConfig) error {
-	content, err := buildCodexConfig(project)
+	content, err := buildCodexConfig(sys, project)
 	if err != nil {
 		return err
 	}
@@ -52,7 +52,10 @@ func WriteCodexRules(sys System, root string, project *config.ProjectConfig) err
 	return nil
 }
 
-func buildCodexConfig(project *config.ProjectConfig) (string, error) {
+// buildCodexConfig returns .codex/config.toml. Codex has no per-server trust setting, so the internal
+// agent-layer server, whose memory tools edit docs/agent-layer/, is only added when approvals.mode
+// auto-approves MCP tools.
+func buildCodexConfig(sys System, project *config.ProjectConfig) (string, error) {
 	var builder strings.Builder
 	if project.Config.Agents.Codex.Model != "" {
 		builder.WriteString(fmt.Sprintf("model = %q\n", project.Config.Agents.Codex.Model))
@@ -62,31 +65,68 @@ func buildCodexConfig(project *config.ProjectConfig) (string, error) {
 	}
 	builder.WriteString(codexHeader)
 
-	// Use placeholder syntax for initial resolution (needed for bearer_token_env_var extraction).
+	env := project.PlaceholderEnv()
+	// Use placeholder syntax for header resolution (needed for bearer_token_env_var extraction).
 	resolved, err := projection.ResolveMCPServers(
 		project.Config.MCP.Servers,
-		project.Env,
+		env,
 		"codex",
 		projection.ClientPlaceholderResolver("${%s}"),
 	)
 	if err != nil {
 		return "", err
 	}
+	// Codex doesn't support ${VAR} placeholders elsewhere, so everything else is fully resolved in a
+	// single pass (re-substituting placeholder output would expand escaped $${...} literals).
+	values, err := projection.ResolveMCPServers(
+		project.Config.MCP.Servers,
+		env,
+		"codex",
+		projection.FullValueResolver(env),
+	)
+	if err != nil {
+		return "", err
+	}
+	if err := checkCodexSecrets(project.Config.MCP.Servers, resolved); err != nil {
+		return "", err
+	}
 
+	wrote := false
+	// Codex runs every tool of a listed server without asking. Outside all and mcp modes the server
+	// is only listed for slash_command_tools, and mcp-prompts then leaves out the memory tools that write.
+	if projection.BuildApprovals(project.Config, project.CommandsAllow).AllowMCP || project.Config.Agents.Codex.SlashCommandTools {
+		prompt, err := resolvePromptServer(sys, project, "codex")
+		if err != nil {
+			return "", err
+		}
+		builder.WriteString("[mcp_servers.agent-layer]\n")
+		if prompt.URL != "" {
+			// The HTTP server reads slash_command_tools itself.
+			server := projection.ResolvedMCPServer{ID: "agent-layer", Headers: prompt.headers("${%s}"), URL: prompt.URL}
+			if err := writeCodexHTTPServer(&builder, server, server); err != nil {
+				return "", err
+			}
+		} else {
+			if project.Config.Agents.Codex.SlashCommandTools {
+				prompt.Args = append(prompt.Args, commandToolsFlag)
+			}
+			writeCodexStdioServer(&builder, projection.ResolvedMCPServer{Command: prompt.Command, Args: prompt.Args})
+		}
+		wrote = true
+	}
 	for i, server := range resolved {
-		if i > 0 {
+		if wrote {
 			builder.WriteString("\n")
 		}
+		wrote = true
 		builder.WriteString(fmt.Sprintf("[mcp_servers.%s]\n", server.ID))
 		switch server.Transport {
 		case "http":
-			if err := writeCodexHTTPServer(&builder, server, project.Env); err != nil {
+			if err := writeCodexHTTPServer(&builder, server, values[i]); err != nil {
 				return "", err
 			}
 		case "stdio":
-			if err := writeCodexStdioServer(&builder, server, project.Env); err != nil {
-				return "", err
-			}
+			writeCodexStdioServer(&builder, values[i])
 		default:
 			return "", fmt.Errorf(messages.MCPServerUnsupportedTransportFmt, server.ID, server.Transport)
 		}
@@ -95,7 +135,7 @@ func buildCodexConfig(project *config.ProjectConfig) (string, error) {
 	return builder.String(), nil
 }
 
-func writeCodexHTTPServer(builder *strings.Builder, server projection.ResolvedMCPServer, env map[string]string) error {
+func writeCodexHTTPServer(builder *strings.Builder, server projection.ResolvedMCPServer, values projection.ResolvedMCPServer) error {
 	if len(server.Headers) > 0 {
 		bearerEnv, err := extractBearerEnvVar(server.Headers)
 		if err != nil {
@@ -105,48 +145,46 @@ func writeCodexHTTPServer(builder *strings.Builder, server projection.ResolvedMC
 			builder.WriteString(fmt.Sprintf("bearer_token_env_var = %q\n", bearerEnv))
 		}
 	}
-	// Resolve actual values in the URL (Codex doesn't support ${VAR} placeholders in URLs).
-	resolvedURL, err := config.SubstituteEnvVars(server.URL, env)
-	if err != nil {
-		return fmt.Errorf(messages.MCPServerURLFmt, server.ID, err)
-	}
-	builder.WriteString(fmt.Sprintf("url = %q\n", resolvedURL))
+	builder.WriteString(fmt.Sprintf("url = %q\n", values.URL))
 	return nil
 }
 
-func writeCodexStdioServer(builder *strings.Builder, server projection.ResolvedMCPServer, env map[string]string) error {
-	// Resolve actual values in command (Codex doesn't support ${VAR} placeholders).
-	resolvedCommand, err := config.SubstituteEnvVars(server.Command, env)
-	if err != nil {
-		return fmt.Errorf(messages.MCPServerCommandFmt, server.ID, err)
+func writeCodexStdioServer(builder *strings.Builder, values projection.ResolvedMCPServer) {
+	builder.WriteString(fmt.Sprintf("command = %q\n", values.Command))
+	if len(values.Args) > 0 {
+		builder.WriteString(fmt.Sprintf("args = %s\n", tomlStringArray(values.Args)))
 	}
-	builder.WriteString(fmt.Sprintf("command = %q\n", resolvedCommand))
-
-	if len(server.Args) > 0 {
-		resolvedArgs := make([]string, 0, len(server.Args))
-		for _, arg := range server.Args {
-			resolvedArg, err := config.SubstituteEnvVars(arg, env)
-			if err != nil {
-				return fmt.Errorf(messages.SyncMCPServerArgFailedFmt, server.ID, err)
-			}
-			resolvedArgs = append(resolvedArgs, resolvedArg)
-		}
-		builder.WriteString(fmt.Sprintf("args = %s\n", tomlStringArray(resolvedArgs)))
+	if len(values.Env) > 0 {
+		builder.WriteString(fmt.Sprintf("env = %s\n", tomlInlineTable(values.Env)))
 	}
+}
 
-	if len(server.Env) > 0 {
-		// Resolve actual values in env vars (Codex doesn't support ${VAR} placeholders).
-		resolvedEnv := make(map[string]string, len(server.Env))
-		for key, value := range server.Env {
-			resolvedValue, err := config.SubstituteEnvVars(value, env)
-			if err != nil {
-				return fmt.Errorf(messages.MCPServerEnvFmt, server.ID, key, err)
+// checkCodexSecrets rejects ${secret:NAME} references that Codex would receive as literal values.
+// Secrets are only injected into the launch environment, which Codex reads for the bearer token.
+func checkCodexSecrets(servers []config.MCPServer, resolved []projection.ResolvedMCPServer) error {
+	projected := make(map[string]bool, len(resolved))
+	for _, server := range resolved {
+		projected[server.ID] = true
+	}
+	for _, server := range servers {
+		if !projected[server.ID] {
+			continue
+		}
+		values := append([]string{server.URL, server.Command}, server.Args...)
+		var envValues []string
+		for _, value := range server.Env {
+			envValues = append(envValues, value)
+		}
+		sort.Strings(envValues)
+		values = append(values, envValues...)
+		for _, value := range values {
+			for _, name := range config.ExtractEnvVarNames(value) {
+				if secret, ok := config.SecretName(name); ok {
+					return fmt.Errorf(messages.SyncMCPServerErrorFmt, server.ID, fmt.Errorf(messages.SyncCodexSecretNotSupportedFmt, secret))
+				}
 			}
-			resolvedEnv[key] = resolvedValue
 		}
-		builder.WriteString(fmt.Sprintf("env = %s\n", tomlInlineTable(resolvedEnv)))
 	}
-
 	return nil
 }
 
@@ -29,7 +29,7 @@ func TestBuildCodexConfig_UnsupportedTransport(t *testing.T) {
 		Env: map[string]string{},
 	}
 
-	_, err := buildCodexConfig(project)
+	_, err := buildCodexConfig(alOnPathSystem(), project)
 	if err == nil {
 		t.Fatalf("expected error for unsupported transport")
 	}
@@ -76,7 +76,7 @@ func TestBuildCodexConfig_ModelSettings(t *testing.T) {
 		Env: map[string]string{},
 	}
 
-	output, err := buildCodexConfig(project)
+	output, err := buildCodexConfig(alOnPathSystem(), project)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -7,8 +7,79 @@ import (
 	"testing"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/secrets"
 )
 
+// alOnPathSystem resolves the internal prompt server to "al mcp-prompts".
+func alOnPathSystem() *MockSystem {
+	return &MockSystem{
+		LookPathFunc: func(file string) (string, error) {
+			if file == "al" {
+				return "/usr/local/bin/al", nil
+			}
+			return "", os.ErrNotExist
+		},
+	}
+}
+
+func TestBuildCodexConfigPromptServer(t *testing.T) {
+	enabled := true
+	project := &config.ProjectConfig{
+		Config: config.Config{
+			Approvals: config.ApprovalsConfig{Mode: "mcp"},
+			MCP: config.MCPConfig{Servers: []config.MCPServer{
+				{ID: "local", Enabled: &enabled, Transport: "stdio", Command: "tool"},
+			}},
+		},
+		Env: map[string]string{},
+	}
+
+	output, err := buildCodexConfig(alOnPathSystem(), project)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "[mcp_servers.agent-layer]\ncommand = \"al\"\nargs = [\"mcp-prompts\", \"--client\", \"codex\"]\n\n[mcp_servers.local]\n"
+	if !strings.Contains(output, want) {
+		t.Fatalf("expected agent-layer server before external servers, got:\n%s", output)
+	}
+
+	for _, mode := range []string{"commands", "none"} {
+		project.Config.Approvals.Mode = mode
+		output, err := buildCodexConfig(&MockSystem{}, project)
+		if err != nil {
+			t.Fatalf("mode %s: unexpected error: %v", mode, err)
+		}
+		if strings.Contains(output, "agent-layer]") || !strings.Contains(output, codexHeader+"[mcp_servers.local]") {
+			t.Fatalf("mode %s: expected no agent-layer server, got:\n%s", mode, output)
+		}
+	}
+
+	project.Config.Agents.Codex.SlashCommandTools = true
+	for _, mode := range []string{"all", "commands", "none"} {
+		project.Config.Approvals.Mode = mode
+		output, err = buildCodexConfig(alOnPathSystem(), project)
+		if err != nil || !strings.Contains(output, `args = ["mcp-prompts", "--client", "codex", "--command-tools"]`) {
+			t.Fatalf("mode %s: expected --command-tools with slash_command_tools, got %v:\n%s", mode, err, output)
+		}
+	}
+	if _, err := buildCodexConfig(&MockSystem{}, project); err == nil {
+		t.Fatalf("expected error when the prompt server cannot be resolved")
+	}
+
+	project.Config.MCP.PromptServer.HTTP = "127.0.0.1:7420"
+	output, err = buildCodexConfig(&MockSystem{}, project)
+	want = "[mcp_servers.agent-layer]\nurl = \"http://127.0.0.1:7420/mcp/codex\"\n\n"
+	if err != nil || !strings.Contains(output, want) {
+		t.Fatalf("expected HTTP agent-layer server, got %v:\n%s", err, output)
+	}
+	project.Env[config.PromptServerTokenEnvVar] = "secret"
+	output, err = buildCodexConfig(&MockSystem{}, project)
+	want = "[mcp_servers.agent-layer]\nbearer_token_env_var = \"AL_PROMPT_SERVER_TOKEN\"\nurl = \"http://127.0.0.1:7420/mcp/codex\"\n"
+	if err != nil || !strings.Contains(output, want) {
+		t.Fatalf("expected bearer token env var, got %v:\n%s", err, output)
+	}
+}
+
 func TestExtractBearerEnvVar(t *testing.T) {
 	headers := map[string]string{
 		"Authorization": "Bearer ${TOKEN}",
@@ -61,7 +132,7 @@ func TestBuildCodexConfigHTTP(t *testing.T) {
 		Env: map[string]string{"TOKEN": "abc"},
 	}
 
-	output, err := buildCodexConfig(project)
+	output, err := buildCodexConfig(alOnPathSystem(), project)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -99,7 +170,7 @@ func TestBuildCodexConfigStdio(t *testing.T) {
 		Env: map[string]string{"TOKEN": "abc"},
 	}
 
-	output, err := buildCodexConfig(project)
+	output, err := buildCodexConfig(alOnPathSystem(), project)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -137,7 +208,7 @@ func TestBuildCodexConfigInvalidHeader(t *testing.T) {
 		Env: map[string]string{"TOKEN": "abc"},
 	}
 
-	_, err := buildCodexConfig(project)
+	_, err := buildCodexConfig(alOnPathSystem(), project)
 	if err == nil {
 		t.Fatalf("expected error")
 	}
@@ -164,7 +235,7 @@ func TestBuildCodexConfigMissingEnv(t *testing.T) {
 		Env: map[string]string{},
 	}
 
-	_, err := buildCodexConfig(project)
+	_, err := buildCodexConfig(alOnPathSystem(), project)
 	if err == nil {
 		t.Fatalf("expected error")
 	}
@@ -301,7 +372,7 @@ func TestBuildCodexConfigMultipleServers(t *testing.T) {
 		Env: map[string]string{},
 	}
 
-	output, err := buildCodexConfig(project)
+	output, err := buildCodexConfig(alOnPathSystem(), project)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -335,7 +406,7 @@ func TestBuildCodexConfigUnsupportedTransport(t *testing.T) {
 		Env: map[string]string{},
 	}
 
-	_, err := buildCodexConfig(project)
+	_, err := buildCodexConfig(alOnPathSystem(), project)
 	if err == nil {
 		t.Fatalf("expected error for unsupported transport")
 	}
@@ -365,7 +436,7 @@ func TestBuildCodexConfigStdioMissingCommandEnv(t *testing.T) {
 		Env: map[string]string{},
 	}
 
-	_, err := buildCodexConfig(project)
+	_, err := buildCodexConfig(alOnPathSystem(), project)
 	if err == nil {
 		t.Fatalf("expected error for missing command env var")
 	}
@@ -396,7 +467,7 @@ func TestBuildCodexConfigStdioMissingArgEnv(t *testing.T) {
 		Env: map[string]string{},
 	}
 
-	_, err := buildCodexConfig(project)
+	_, err := buildCodexConfig(alOnPathSystem(), project)
 	if err == nil {
 		t.Fatalf("expected error for missing arg env var")
 	}
@@ -427,7 +498,7 @@ func TestBuildCodexConfigStdioMissingEnvVarEnv(t *testing.T) {
 		Env: map[string]string{},
 	}
 
-	_, err := buildCodexConfig(project)
+	_, err := buildCodexConfig(alOnPathSystem(), project)
 	if err == nil {
 		t.Fatalf("expected error for missing env var env")
 	}
@@ -435,3 +506,79 @@ func TestBuildCodexConfigStdioMissingEnvVarEnv(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestBuildCodexConfigPlaceholderForms(t *testing.T) {
+	enabled := true
+	project := &config.ProjectConfig{
+		Config: config.Config{
+			Approvals: config.ApprovalsConfig{Mode: "all"},
+			Agents:    config.AgentsConfig{Codex: config.CodexConfig{Enabled: &enabled}},
+			MCP: config.MCPConfig{
+				Servers: []config.MCPServer{
+					{
+						ID:        "local",
+						Enabled:   &enabled,
+						Transport: "stdio",
+						Command:   "${TOOL:-tool}",
+						Args:      []string{"--template", "$${name}", "--port", "${PORT:-8080}"},
+						Env:       map[string]string{"TOKEN": "${TOKEN:?set TOKEN in .env}"},
+					},
+				},
+			},
+		},
+		Env: map[string]string{"TOKEN": "abc"},
+	}
+
+	output, err := buildCodexConfig(alOnPathSystem(), project)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		`command = "tool"`,
+		`args = ["--template", "${name}", "--port", "8080"]`,
+		`env = { TOKEN = "abc" }`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output:\n%s", want, output)
+		}
+	}
+
+	project.Env = map[string]string{}
+	if _, err := buildCodexConfig(alOnPathSystem(), project); err == nil || !strings.Contains(err.Error(), "TOKEN: set TOKEN in .env") {
+		t.Fatalf("expected custom required message, got %v", err)
+	}
+}
+
+func TestBuildCodexConfigSecrets(t *testing.T) {
+	enabled := true
+	project := &config.ProjectConfig{
+		Config: config.Config{
+			Approvals: config.ApprovalsConfig{Mode: "all"},
+			Agents:    config.AgentsConfig{Codex: config.CodexConfig{Enabled: &enabled}},
+			MCP: config.MCPConfig{
+				Servers: []config.MCPServer{{
+					ID:        "api",
+					Enabled:   &enabled,
+					Transport: "http",
+					URL:       "https://example.com/mcp",
+					Headers:   map[string]string{"Authorization": "Bearer ${secret:API_TOKEN}"},
+				}},
+			},
+		},
+		Env:     map[string]string{},
+		Secrets: map[string]secrets.Resolved{"API_TOKEN": {Value: "s3cr3t", Provider: "file (/tmp)"}},
+	}
+
+	output, err := buildCodexConfig(alOnPathSystem(), project)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(output, `bearer_token_env_var = "API_TOKEN"`) || strings.Contains(output, "s3cr3t") {
+		t.Fatalf("expected bearer env var without the secret value:\n%s", output)
+	}
+
+	project.Config.MCP.Servers[0].URL = "https://example.com/mcp?key=${secret:API_TOKEN}"
+	if _, err := buildCodexConfig(alOnPathSystem(), project); err == nil || !strings.Contains(err.Error(), "secret API_TOKEN would be written to .codex/config.toml") {
+		t.Fatalf("expected secret rejection, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package sync
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+const copilotInstructionsSuffix = ".instructions.md"
+
+// WriteCopilotScopedInstructions writes .github/instructions/<name>.instructions.md, with an applyTo
+// front matter glob list, for every fragment that has applies_to globs and targets vscode.
+// Stale generated files are removed; hand-written instruction files are left alone.
+func WriteCopilotScopedInstructions(sys System, root string, project *config.ProjectConfig) error {
+	var scoped []config.InstructionFile
+	for _, file := range project.Instructions {
+		if len(file.AppliesTo) > 0 {
+			scoped = append(scoped, file)
+		}
+	}
+	scoped, err := shimInstructions(copilotInstructionShim, project, scoped)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(root, ".github", "instructions")
+	if len(scoped) == 0 {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			return nil
+		}
+	} else if err := sys.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf(messages.SyncCreateDirFailedFmt, dir, err)
+	}
+
+	wanted := make(map[string]struct{}, len(scoped))
+	for _, file := range scoped {
+		base := strings.TrimSuffix(file.Name, ".md")
+		wanted[base] = struct{}{}
+		path := filepath.Join(dir, base+copilotInstructionsSuffix)
+		if err := sys.WriteFileAtomic(path, []byte(buildCopilotScopedInstruction(file)), 0o644); err != nil {
+			return fmt.Errorf(messages.SyncWriteFileFailedFmt, path, err)
+		}
+	}
+
+	return removeStaleGeneratedFiles(sys, dir, copilotInstructionsSuffix, wanted)
+}
+
+func buildCopilotScopedInstruction(file config.InstructionFile) string {
+	var builder strings.Builder
+	builder.WriteString("---\n")
+	builder.WriteString("applyTo: ")
+	var globs []string
+	for _, glob := range file.AppliesTo {
+		globs = append(globs, expandBraces(glob)...)
+	}
+	builder.WriteString(strconv.Quote(strings.Join(globs, ",")))
+	builder.WriteString("\n---\n")
+	builder.WriteString(fmt.Sprintf(instructionHeaderFormat, ".agent-layer/instructions/"+file.Name))
+	builder.WriteString(file.Content)
+	if !strings.HasSuffix(file.Content, "\n") {
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+// expandBraces expands {a,b} alternatives in glob, since Copilot splits applyTo on every comma.
+// A brace without a matching close is kept literally.
+func expandBraces(glob string) []string {
+	open := strings.IndexByte(glob, '{')
+	if open < 0 {
+		return []string{glob}
+	}
+	depth, start := 0, open+1
+	var alternatives []string
+	for i := open; i < len(glob); i++ {
+		switch glob[i] {
+		case '{':
+			depth++
+		case ',':
+			if depth == 1 {
+				alternatives = append(alternatives, glob[start:i])
+				start = i + 1
+			}
+		case '}':
+			depth--
+			if depth > 0 {
+				continue
+			}
+			alternatives = append(alternatives, glob[start:i])
+			var expanded []string
+			for _, alternative := range alternatives {
+				expanded = append(expanded, expandBraces(glob[:open]+alternative+glob[i+1:])...)
+			}
+			return expanded
+		}
+	}
+	return []string{glob}
+}
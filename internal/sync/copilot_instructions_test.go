@@ -0,0 +1,70 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+func TestWriteCopilotScopedInstructions(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	project := &config.ProjectConfig{
+		Root: root,
+		Instructions: []config.InstructionFile{
+			{Name: "00_base.md", Content: "base\n"},
+			{Name: "10_web.md", Content: "web rules for {{ .Client }}", Template: true, AppliesTo: []string{"web/**/*.ts", "web/**/*.tsx"}},
+			{Name: "20_api.md", Content: "api rules\n", AppliesTo: []string{"api/**"}, Clients: []string{"claude"}},
+			{Name: "30_apps.md", Content: "apps\n", AppliesTo: []string{"web/{a,b{c,d}}/*.ts", "lib/{x"}},
+		},
+	}
+
+	// Nothing scoped for Copilot yet: no directory is created.
+	if err := WriteCopilotScopedInstructions(RealSystem{}, root, &config.ProjectConfig{Root: root}); err != nil {
+		t.Fatalf("WriteCopilotScopedInstructions error: %v", err)
+	}
+	dir := filepath.Join(root, ".github", "instructions")
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("expected no instructions dir, got %v", err)
+	}
+
+	if err := WriteCopilotScopedInstructions(RealSystem{}, root, project); err != nil {
+		t.Fatalf("WriteCopilotScopedInstructions error: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "10_web.instructions.md"))
+	if err != nil {
+		t.Fatalf("read scoped instructions: %v", err)
+	}
+	content := string(data)
+	if !strings.HasPrefix(content, "---\napplyTo: \"web/**/*.ts,web/**/*.tsx\"\n---\n") {
+		t.Fatalf("unexpected front matter:\n%s", content)
+	}
+	if !strings.Contains(content, "Source: .agent-layer/instructions/10_web.md") || !strings.HasSuffix(content, "web rules for vscode\n") {
+		t.Fatalf("unexpected content:\n%s", content)
+	}
+	data, err = os.ReadFile(filepath.Join(dir, "30_apps.instructions.md"))
+	if err != nil || !strings.HasPrefix(string(data), "---\napplyTo: \"web/a/*.ts,web/bc/*.ts,web/bd/*.ts,lib/{x\"\n") {
+		t.Fatalf("expected brace alternatives to be expanded, got %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "20_api.instructions.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected claude-only fragment to be skipped, got %v", err)
+	}
+
+	manual := filepath.Join(dir, "team.instructions.md")
+	if err := os.WriteFile(manual, []byte("---\napplyTo: \"**\"\n---\nhand written\n"), 0o644); err != nil {
+		t.Fatalf("write manual file: %v", err)
+	}
+	project.Instructions = project.Instructions[:1]
+	if err := WriteCopilotScopedInstructions(RealSystem{}, root, project); err != nil {
+		t.Fatalf("WriteCopilotScopedInstructions error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "10_web.instructions.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected stale scoped file to be removed, got %v", err)
+	}
+	if _, err := os.Stat(manual); err != nil {
+		t.Fatalf("expected hand-written file to be kept: %v", err)
+	}
+}
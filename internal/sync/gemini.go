@@ -74,20 +74,22 @@ func buildGeminiSettings(sys System, project *config.ProjectConfig) (*geminiSett
 	trust := allowMCP
 
 	// Internal prompt server
-	promptCommand, promptArgs, err := resolvePromptServerCommand(sys, project.Root)
+	prompt, err := resolvePromptServer(sys, project, "gemini")
 	if err != nil {
 		return nil, err
 	}
 	settings.MCPServers["agent-layer"] = geminiMCPServer{
-		Command: promptCommand,
-		Args:    promptArgs,
+		Command: prompt.Command,
+		Args:    prompt.Args,
+		HTTPURL: prompt.URL,
+		Headers: prompt.headers("${%s}"),
 		Trust:   &trust,
 	}
 
 	// Preserve env var placeholders - Gemini CLI resolves ${VAR} at runtime.
 	resolved, err := projection.ResolveMCPServers(
 		project.Config.MCP.Servers,
-		project.Env,
+		project.PlaceholderEnv(),
 		"gemini",
 		projection.ClientPlaceholderResolver("${%s}"),
 	)
@@ -0,0 +1,132 @@
+package sync
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+const geminiCommandHeaderTemplate = "# GENERATED FILE\n# Source: .agent-layer/slash-commands/%s.md\n# Regenerate: al sync\n\n"
+
+// WriteGeminiCommands generates Gemini CLI custom commands in .gemini/commands/ for slash commands
+// that target gemini, unless agents.gemini.slash_commands is mcp, in which case it only removes stale
+// generated commands. A name such as git:commit is written to git/commit.toml, which Gemini CLI
+// exposes as /git:commit.
+func WriteGeminiCommands(sys System, root string, project *config.ProjectConfig) error {
+	commandsDir := filepath.Join(root, ".gemini", "commands")
+	if !project.Config.Agents.Gemini.NativeSlashCommands() {
+		if _, err := sys.Stat(commandsDir); err != nil {
+			return nil
+		}
+		return removeStaleGeneratedTree(sys, commandsDir, ".toml", nil)
+	}
+
+	commands, err := clientSlashCommands(project, "gemini", config.ArgumentsGemini)
+	if err != nil {
+		return err
+	}
+	if err := sys.MkdirAll(commandsDir, 0o755); err != nil {
+		return fmt.Errorf(messages.SyncCreateDirFailedFmt, commandsDir, err)
+	}
+
+	wanted := make(map[string]struct{}, len(commands))
+	for _, cmd := range commands {
+		rel := namespacedCommandPath(cmd, ".toml")
+		wanted[rel] = struct{}{}
+		path := filepath.Join(commandsDir, rel)
+		if dir := filepath.Dir(path); dir != commandsDir {
+			if err := sys.MkdirAll(dir, 0o755); err != nil {
+				return fmt.Errorf(messages.SyncCreateDirFailedFmt, dir, err)
+			}
+		}
+		if err := sys.WriteFileAtomic(path, []byte(buildGeminiCommand(cmd)), 0o644); err != nil {
+			return fmt.Errorf(messages.SyncWriteFileFailedFmt, path, err)
+		}
+	}
+
+	return removeStaleGeneratedTree(sys, commandsDir, ".toml", wanted)
+}
+
+// buildGeminiCommand returns the .toml custom command for a slash command.
+func buildGeminiCommand(cmd config.SlashCommand) string {
+	var prompt strings.Builder
+	if len(cmd.Arguments) > 1 {
+		// Gemini CLI passes arguments as one string appended to the prompt; say what it holds.
+		writeArgumentList(&prompt, cmd.Arguments)
+	}
+	prompt.WriteString(cmd.Body)
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf(geminiCommandHeaderTemplate, cmd.RelPath()))
+	builder.WriteString("description = ")
+	builder.WriteString(tomlBasicString(cmd.Description))
+	builder.WriteString("\n")
+	builder.WriteString("prompt = ")
+	builder.WriteString(tomlMultilineString(prompt.String()))
+	builder.WriteString("\n")
+	return builder.String()
+}
+
+// tomlBasicString returns s as a single-line TOML basic string, escaping quotes, backslashes, and
+// control characters.
+func tomlBasicString(s string) string {
+	var builder strings.Builder
+	builder.WriteString(`"`)
+	for _, r := range s {
+		switch {
+		case r == '"':
+			builder.WriteString(`\"`)
+		case r == '\\':
+			builder.WriteString(`\\`)
+		case r == '\n':
+			builder.WriteString(`\n`)
+		case r == '\t':
+			builder.WriteString(`\t`)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&builder, `\u%04X`, r)
+		default:
+			builder.WriteRune(r)
+		}
+	}
+	builder.WriteString(`"`)
+	return builder.String()
+}
+
+// tomlMultilineString returns s as a TOML multi-line basic string. Backslashes and control
+// characters are escaped, and so is every third quote in a row, so s cannot end the string early.
+func tomlMultilineString(s string) string {
+	var builder strings.Builder
+	builder.WriteString("\"\"\"\n")
+	quotes := 0
+	for _, r := range s {
+		if r == '"' {
+			quotes++
+			if quotes == 3 {
+				builder.WriteString(`\"`)
+				quotes = 0
+				continue
+			}
+			builder.WriteRune(r)
+			continue
+		}
+		quotes = 0
+		switch {
+		case r == '\\':
+			builder.WriteString(`\\`)
+		case r == '\n' || r == '\t':
+			builder.WriteRune(r)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&builder, `\u%04X`, r)
+		default:
+			builder.WriteRune(r)
+		}
+	}
+	if !strings.HasSuffix(s, "\n") {
+		builder.WriteString("\n")
+	}
+	builder.WriteString(`"""`)
+	return builder.String()
+}
@@ -0,0 +1,156 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+func TestBuildGeminiCommand(t *testing.T) {
+	cmd := config.SlashCommand{
+		Name:        "finish-task",
+		Description: "Finish the \"current\" task \x1b[1mnow\x1b[0m\\ \u00e9\t",
+		Body:        "Run `make test`.\nQuote: \"\"\"done\"\"\"\nPath: C:\\repo\tend\x01",
+	}
+	content := buildGeminiCommand(cmd)
+	if !strings.HasPrefix(content, "# GENERATED FILE\n# Source: .agent-layer/slash-commands/finish-task.md\n") {
+		t.Fatalf("expected generated header, got:\n%s", content)
+	}
+
+	var parsed struct {
+		Description string `toml:"description"`
+		Prompt      string `toml:"prompt"`
+	}
+	if err := toml.Unmarshal([]byte(content), &parsed); err != nil {
+		t.Fatalf("generated TOML does not parse: %v\n%s", err, content)
+	}
+	if parsed.Description != cmd.Description {
+		t.Fatalf("unexpected description %q", parsed.Description)
+	}
+	if parsed.Prompt != cmd.Body+"\n" {
+		t.Fatalf("prompt did not round-trip:\n got %q\nwant %q", parsed.Prompt, cmd.Body+"\n")
+	}
+}
+
+func TestBuildGeminiCommandArguments(t *testing.T) {
+	cmd := config.SlashCommand{
+		Name:        "fix",
+		Description: "Fix",
+		Body:        "Fix <issue> on <branch>.",
+		Arguments:   []config.SlashCommandArgument{{Name: "issue", Required: true}, {Name: "branch", Default: "main"}},
+	}
+	content := buildGeminiCommand(cmd)
+	if !strings.Contains(content, "## Arguments\n\n- `<issue>` (required)\n") {
+		t.Fatalf("expected argument list, got:\n%s", content)
+	}
+}
+
+func TestWriteGeminiCommands(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	commandsDir := filepath.Join(root, ".gemini", "commands")
+	write := func(rel string, content string) {
+		t.Helper()
+		path := filepath.Join(commandsDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", rel, err)
+		}
+	}
+	write("manual.toml", `prompt = "mine"`)
+	write("old/stale.toml", "# GENERATED FILE\n")
+
+	project := &config.ProjectConfig{SlashCommands: []config.SlashCommand{
+		{Name: "finish-task", Description: "Finish", Body: `Finish {{ arg "task" }}.`, Template: true, Arguments: []config.SlashCommandArgument{{Name: "task"}}},
+		{Name: "git:commit", Description: "Commit", Body: "Commit."},
+		{Name: "codex-only", Description: "desc", Body: "Body", Clients: []string{"codex"}},
+	}}
+	if err := WriteGeminiCommands(RealSystem{}, root, project); err != nil {
+		t.Fatalf("WriteGeminiCommands error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(commandsDir, "finish-task.toml"))
+	if err != nil {
+		t.Fatalf("read finish-task.toml: %v", err)
+	}
+	if !strings.Contains(string(data), "Finish {{args}}.") {
+		t.Fatalf("expected {{args}} placeholder, got:\n%s", data)
+	}
+	expect := map[string]bool{
+		"git/commit.toml":  true,
+		"manual.toml":      true,
+		"codex-only.toml":  false,
+		"old/stale.toml":   false,
+		"old":              false,
+		"finish-task.toml": true,
+	}
+	for rel, exists := range expect {
+		_, err := os.Stat(filepath.Join(commandsDir, filepath.FromSlash(rel)))
+		if exists != (err == nil) {
+			t.Fatalf("%s: expected exists=%v, got %v", rel, exists, err)
+		}
+	}
+
+	project.SlashCommands = nil
+	if err := WriteGeminiCommands(RealSystem{}, root, project); err != nil {
+		t.Fatalf("WriteGeminiCommands cleanup error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(commandsDir, "git")); !os.IsNotExist(err) {
+		t.Fatalf("expected emptied namespace directory to be removed, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(commandsDir, "manual.toml")); err != nil {
+		t.Fatalf("expected manual command to remain: %v", err)
+	}
+}
+
+func TestWriteGeminiCommandsMCPMode(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	commandsDir := filepath.Join(root, ".gemini", "commands")
+	project := &config.ProjectConfig{SlashCommands: []config.SlashCommand{{Name: "review", Description: "Review", Body: "Review."}}}
+	if err := WriteGeminiCommands(RealSystem{}, root, project); err != nil {
+		t.Fatalf("WriteGeminiCommands error: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(commandsDir, "manual.toml"), []byte(`prompt = "mine"`), 0o644); err != nil {
+		t.Fatalf("write manual: %v", err)
+	}
+
+	project.Config.Agents.Gemini.SlashCommands = config.SlashCommandsMCP
+	if err := WriteGeminiCommands(RealSystem{}, root, project); err != nil {
+		t.Fatalf("WriteGeminiCommands mcp error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(commandsDir, "review.toml")); !os.IsNotExist(err) {
+		t.Fatalf("expected generated command to be removed in mcp mode, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(commandsDir, "manual.toml")); err != nil {
+		t.Fatalf("expected manual command to remain: %v", err)
+	}
+
+	other := t.TempDir()
+	if err := WriteGeminiCommands(RealSystem{}, other, project); err != nil {
+		t.Fatalf("WriteGeminiCommands empty error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(other, ".gemini")); !os.IsNotExist(err) {
+		t.Fatalf("expected no .gemini directory, got %v", err)
+	}
+}
+
+func TestWriteGeminiCommandsError(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	file := filepath.Join(root, "file")
+	if err := os.WriteFile(file, []byte("x"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	project := &config.ProjectConfig{SlashCommands: []config.SlashCommand{{Name: "a", Description: "d", Body: "b"}}}
+	if err := WriteGeminiCommands(RealSystem{}, file, project); err == nil {
+		t.Fatalf("expected error")
+	}
+}
@@ -1,11 +1,14 @@
 package sync
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/secrets"
 )
 
 func TestBuildGeminiSettingsCommandsOnly(t *testing.T) {
@@ -38,6 +41,29 @@ func TestBuildGeminiSettingsCommandsOnly(t *testing.T) {
 	}
 }
 
+func TestBuildGeminiSettingsPromptServerHTTP(t *testing.T) {
+	t.Parallel()
+	project := &config.ProjectConfig{
+		Config: config.Config{
+			Approvals: config.ApprovalsConfig{Mode: "all"},
+			MCP:       config.MCPConfig{PromptServer: config.PromptServerConfig{HTTP: "127.0.0.1:7420"}},
+		},
+		Env: map[string]string{config.PromptServerTokenEnvVar: "secret"},
+	}
+
+	settings, err := buildGeminiSettings(&MockSystem{}, project)
+	if err != nil {
+		t.Fatalf("buildGeminiSettings error: %v", err)
+	}
+	server := settings.MCPServers["agent-layer"]
+	if server.HTTPURL != "http://127.0.0.1:7420/mcp/gemini" || server.Command != "" || server.Trust == nil || !*server.Trust {
+		t.Fatalf("unexpected agent-layer server %+v", server)
+	}
+	if server.Headers["Authorization"] != "Bearer ${AL_PROMPT_SERVER_TOKEN}" {
+		t.Fatalf("unexpected headers %v", server.Headers)
+	}
+}
+
 func TestWriteGeminiSettings(t *testing.T) {
 	t.Parallel()
 	root := t.TempDir()
@@ -187,6 +213,47 @@ func TestBuildGeminiSettingsMCPServers(t *testing.T) {
 	}
 }
 
+func TestBuildGeminiSettingsSecretReferences(t *testing.T) {
+	t.Parallel()
+	enabled := true
+	sys := &MockSystem{
+		LookPathFunc: func(file string) (string, error) {
+			return "/usr/local/bin/al", nil
+		},
+	}
+	project := &config.ProjectConfig{
+		Config: config.Config{
+			Approvals: config.ApprovalsConfig{Mode: "all"},
+			MCP: config.MCPConfig{
+				Servers: []config.MCPServer{{
+					ID:        "api",
+					Enabled:   &enabled,
+					Transport: "http",
+					URL:       "https://example.com",
+					Headers:   map[string]string{"Authorization": "Bearer ${secret:API_TOKEN}"},
+				}},
+			},
+		},
+		Env:     map[string]string{},
+		Secrets: map[string]secrets.Resolved{"API_TOKEN": {Value: "s3cr3t", Provider: "keyring (agent-layer)"}},
+		Root:    t.TempDir(),
+	}
+
+	settings, err := buildGeminiSettings(sys, project)
+	if err != nil {
+		t.Fatalf("buildGeminiSettings error: %v", err)
+	}
+	// The secret value stays in the launch environment; the settings file only names it.
+	if got := settings.MCPServers["api"].Headers["Authorization"]; got != "Bearer ${API_TOKEN}" {
+		t.Fatalf("unexpected header value: %s", got)
+	}
+
+	project.Secrets = map[string]secrets.Resolved{"API_TOKEN": {Err: errors.New("not found")}}
+	if _, err := buildGeminiSettings(sys, project); err == nil || !strings.Contains(err.Error(), "missing secrets: API_TOKEN") {
+		t.Fatalf("expected missing secret error, got %v", err)
+	}
+}
+
 func TestBuildGeminiSettingsMissingEnv(t *testing.T) {
 	t.Parallel()
 	enabled := true
@@ -2,32 +2,55 @@ package sync
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/conn-castle/agent-layer/internal/config"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
-const instructionHeader = "<!--\n  GENERATED FILE\n  Source: .agent-layer/instructions/*.md\n  Regenerate: al sync\n-->\n\n"
+const (
+	instructionSource       = ".agent-layer/instructions/*.md"
+	instructionHeaderFormat = "<!--\n  GENERATED FILE\n  Source: %s\n  Regenerate: al sync\n-->\n\n"
+)
+
+// instructionShim is a generated instruction file and the clients that read it.
+// Each shim only includes the fragments that target one of its clients.
+type instructionShim struct {
+	name    string
+	clients []string
+}
+
+// rootInstructionShims are written at the repo root (and nested into child layer directories).
+var rootInstructionShims = []instructionShim{
+	{name: "AGENTS.md", clients: []string{"codex"}},
+	{name: "CLAUDE.md", clients: []string{"claude"}},
+	{name: "GEMINI.md", clients: []string{"gemini", "antigravity"}},
+}
+
+// copilotInstructionShim is .github/copilot-instructions.md for VS Code Copilot Chat.
+var copilotInstructionShim = instructionShim{name: "copilot-instructions.md", clients: []string{"vscode"}}
+
+// codexInstructionShim is .codex/AGENTS.md, loaded from CODEX_HOME.
+var codexInstructionShim = instructionShim{name: "AGENTS.md", clients: []string{"codex"}}
 
 // WriteInstructionShims generates instruction shims for supported clients.
-func WriteInstructionShims(sys System, root string, instructions []config.InstructionFile) error {
-	if err := writeInstructionFile(sys, filepath.Join(root, "AGENTS.md"), instructions); err != nil {
-		return err
-	}
-	if err := writeInstructionFile(sys, filepath.Join(root, "CLAUDE.md"), instructions); err != nil {
-		return err
-	}
-	if err := writeInstructionFile(sys, filepath.Join(root, "GEMINI.md"), instructions); err != nil {
-		return err
+// Fragments with applies_to globs are left out; see WriteNestedInstructionShims and WriteCopilotScopedInstructions.
+func WriteInstructionShims(sys System, root string, project *config.ProjectConfig) error {
+	for _, shim := range rootInstructionShims {
+		if err := writeInstructionFile(sys, filepath.Join(root, shim.name), shim, project, repoWideInstructions(project.Instructions, true)); err != nil {
+			return err
+		}
 	}
 
 	githubDir := filepath.Join(root, ".github")
 	if err := sys.MkdirAll(githubDir, 0o755); err != nil {
 		return fmt.Errorf(messages.SyncCreateDirFailedFmt, githubDir, err)
 	}
-	if err := writeInstructionFile(sys, filepath.Join(githubDir, "copilot-instructions.md"), instructions); err != nil {
+	if err := writeInstructionFile(sys, filepath.Join(githubDir, copilotInstructionShim.name), copilotInstructionShim, project, repoWideInstructions(project.Instructions, false)); err != nil {
 		return err
 	}
 
@@ -35,25 +58,156 @@ func WriteInstructionShims(sys System, root string, instructions []config.Instru
 }
 
 // WriteCodexInstructions generates the Codex-specific instruction shim.
-func WriteCodexInstructions(sys System, root string, instructions []config.InstructionFile) error {
+func WriteCodexInstructions(sys System, root string, project *config.ProjectConfig) error {
 	codexDir := filepath.Join(root, ".codex")
 	if err := sys.MkdirAll(codexDir, 0o755); err != nil {
 		return fmt.Errorf(messages.SyncCreateDirFailedFmt, codexDir, err)
 	}
-	return writeInstructionFile(sys, filepath.Join(codexDir, "AGENTS.md"), instructions)
+	return writeInstructionFile(sys, filepath.Join(codexDir, codexInstructionShim.name), codexInstructionShim, project, repoWideInstructions(project.Instructions, true))
 }
 
-func writeInstructionFile(sys System, path string, instructions []config.InstructionFile) error {
-	content := buildInstructionShim(instructions)
+// repoWideInstructions returns the fragments without applies_to globs. Directory-level clients
+// (withRootGlobs) also get fragments whose globs start at the repo root, such as "**/*.ts",
+// since the repo root is the narrowest directory they can be scoped to.
+func repoWideInstructions(instructions []config.InstructionFile, withRootGlobs bool) []config.InstructionFile {
+	filtered := make([]config.InstructionFile, 0, len(instructions))
+	for _, file := range instructions {
+		if len(file.AppliesTo) == 0 || (withRootGlobs && slices.Contains(config.InstructionScopeDirs(file), "")) {
+			filtered = append(filtered, file)
+		}
+	}
+	return filtered
+}
+
+// nestedScope is a directory below the repo root that gets its own AGENTS.md, CLAUDE.md, and GEMINI.md.
+type nestedScope struct {
+	source       string
+	instructions []config.InstructionFile
+}
+
+// WriteNestedInstructionShims writes nested AGENTS.md, CLAUDE.md, and GEMINI.md for monorepo child layers
+// and for the directories named by applies_to globs (web/**/*.ts scopes to web/). Clients load nested
+// files on top of the root shims, so each contains only the fragments scoped to that directory.
+// Generated nested shims that are no longer wanted are removed anywhere in the project.
+func WriteNestedInstructionShims(sys System, root string, project *config.ProjectConfig) error {
+	dirs, scopes := nestedScopes(root, project)
+	wanted := make(map[string]struct{})
+	for _, dir := range dirs {
+		for _, shim := range rootInstructionShims {
+			instructions, err := shimInstructions(shim, project, scopes[dir].instructions)
+			if err != nil {
+				return err
+			}
+			if len(instructions) == 0 {
+				continue
+			}
+			path := filepath.Join(dir, shim.name)
+			if err := writeInstructionFileFrom(sys, path, scopes[dir].source, instructions); err != nil {
+				return err
+			}
+			wanted[path] = struct{}{}
+		}
+	}
+	return removeStaleNestedShims(sys, root, project.Config.Children.Exclude, wanted)
+}
+
+// nestedScopes groups applies_to fragments and child layer fragments by absolute directory.
+// The directories are returned sorted.
+func nestedScopes(root string, project *config.ProjectConfig) ([]string, map[string]*nestedScope) {
+	scopes := make(map[string]*nestedScope)
+	scope := func(dir string) *nestedScope {
+		if scopes[dir] == nil {
+			scopes[dir] = &nestedScope{source: instructionSource}
+		}
+		return scopes[dir]
+	}
+	for _, file := range project.Instructions {
+		for _, dir := range config.InstructionScopeDirs(file) {
+			if dir == "" {
+				continue
+			}
+			path := filepath.Join(root, filepath.FromSlash(dir))
+			// Globs for directories that do not exist yet only apply to Copilot.
+			if info, err := os.Stat(path); err != nil || !info.IsDir() {
+				continue
+			}
+			s := scope(path)
+			s.instructions = append(s.instructions, file)
+		}
+	}
+	for _, child := range project.Children {
+		s := scope(child.Path)
+		s.source = child.Dir + "/" + instructionSource
+		s.instructions = append(s.instructions, child.Instructions...)
+	}
+
+	dirs := make([]string, 0, len(scopes))
+	for dir := range scopes {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	return dirs, scopes
+}
+
+// removeStaleNestedShims removes generated nested shims that are not in wanted from every project
+// directory below root, the same way stale prompt files are removed.
+func removeStaleNestedShims(sys System, root string, exclude []string, wanted map[string]struct{}) error {
+	return config.WalkProjectDirs(root, exclude, func(dir string) error {
+		for _, shim := range rootInstructionShims {
+			path := filepath.Join(dir, shim.name)
+			if _, ok := wanted[path]; ok {
+				continue
+			}
+			if err := removeGeneratedFile(sys, path); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// writeInstructionFile writes the fragments that target shim's clients to path.
+func writeInstructionFile(sys System, path string, shim instructionShim, project *config.ProjectConfig, instructions []config.InstructionFile) error {
+	filtered, err := shimInstructions(shim, project, instructions)
+	if err != nil {
+		return err
+	}
+	return writeInstructionFileFrom(sys, path, instructionSource, filtered)
+}
+
+// shimInstructions returns the fragments that target shim's clients, rendered for its first client.
+func shimInstructions(shim instructionShim, project *config.ProjectConfig, instructions []config.InstructionFile) ([]config.InstructionFile, error) {
+	filtered := config.InstructionsForClients(instructions, shim.clients...)
+	return config.RenderInstructions(filtered, config.NewTemplateData(project, shim.clients[0]))
+}
+
+func writeInstructionFileFrom(sys System, path string, source string, instructions []config.InstructionFile) error {
+	content := buildInstructionShimFrom(source, instructions)
 	if err := sys.WriteFileAtomic(path, []byte(content), 0o644); err != nil {
 		return fmt.Errorf(messages.SyncWriteFileFailedFmt, path, err)
 	}
 	return nil
 }
 
+// removeGeneratedFile deletes path only when it exists and carries the generated marker.
+func removeGeneratedFile(sys System, path string) error {
+	isGenerated, err := hasGeneratedMarker(sys, path)
+	if err != nil || !isGenerated {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf(messages.SyncRemoveFailedFmt, path, err)
+	}
+	return nil
+}
+
 func buildInstructionShim(instructions []config.InstructionFile) string {
+	return buildInstructionShimFrom(instructionSource, instructions)
+}
+
+func buildInstructionShimFrom(source string, instructions []config.InstructionFile) string {
 	var builder strings.Builder
-	builder.WriteString(instructionHeader)
+	builder.WriteString(fmt.Sprintf(instructionHeaderFormat, source))
 	for _, instruction := range instructions {
 		builder.WriteString("<!-- BEGIN: ")
 		builder.WriteString(instruction.Name)
@@ -28,7 +28,7 @@ func TestWriteInstructionShims(t *testing.T) {
 	t.Parallel()
 	root := t.TempDir()
 	instructions := []config.InstructionFile{{Name: "00_base.md", Content: "base\n"}}
-	if err := WriteInstructionShims(RealSystem{}, root, instructions); err != nil {
+	if err := WriteInstructionShims(RealSystem{}, root, &config.ProjectConfig{Instructions: instructions}); err != nil {
 		t.Fatalf("WriteInstructionShims error: %v", err)
 	}
 
@@ -45,11 +45,49 @@ func TestWriteInstructionShims(t *testing.T) {
 	}
 }
 
+func TestWriteInstructionShimsFiltersByClient(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	instructions := []config.InstructionFile{
+		{Name: "00_base.md", Content: "shared\n"},
+		{Name: "10_codex.md", Content: "codex sandbox\n", Clients: []string{"codex"}},
+		{Name: "20_copilot.md", Content: "copilot tools\n", Clients: []string{"vscode", "claude"}},
+	}
+	if err := WriteInstructionShims(RealSystem{}, root, &config.ProjectConfig{Instructions: instructions}); err != nil {
+		t.Fatalf("WriteInstructionShims error: %v", err)
+	}
+	if err := WriteCodexInstructions(RealSystem{}, root, &config.ProjectConfig{Instructions: instructions}); err != nil {
+		t.Fatalf("WriteCodexInstructions error: %v", err)
+	}
+
+	want := map[string][]string{
+		"AGENTS.md":                       {"00_base.md", "10_codex.md"},
+		"CLAUDE.md":                       {"00_base.md", "20_copilot.md"},
+		"GEMINI.md":                       {"00_base.md"},
+		".github/copilot-instructions.md": {"00_base.md", "20_copilot.md"},
+		".codex/AGENTS.md":                {"00_base.md", "10_codex.md"},
+	}
+	for rel, names := range want {
+		data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(rel)))
+		if err != nil {
+			t.Fatalf("read %s: %v", rel, err)
+		}
+		if got := strings.Count(string(data), "<!-- BEGIN: "); got != len(names) {
+			t.Fatalf("%s: expected %d fragments, got %d:\n%s", rel, len(names), got, data)
+		}
+		for _, name := range names {
+			if !strings.Contains(string(data), "<!-- BEGIN: "+name+" -->") {
+				t.Fatalf("%s: missing %s:\n%s", rel, name, data)
+			}
+		}
+	}
+}
+
 func TestWriteCodexInstructions(t *testing.T) {
 	t.Parallel()
 	root := t.TempDir()
 	instructions := []config.InstructionFile{{Name: "00_base.md", Content: "base\n"}}
-	if err := WriteCodexInstructions(RealSystem{}, root, instructions); err != nil {
+	if err := WriteCodexInstructions(RealSystem{}, root, &config.ProjectConfig{Instructions: instructions}); err != nil {
 		t.Fatalf("WriteCodexInstructions error: %v", err)
 	}
 	if _, err := os.Stat(filepath.Join(root, ".codex", "AGENTS.md")); err != nil {
@@ -65,7 +103,7 @@ func TestWriteInstructionShimsError(t *testing.T) {
 		t.Fatalf("write file: %v", err)
 	}
 	instructions := []config.InstructionFile{{Name: "00_base.md", Content: "base\n"}}
-	if err := WriteInstructionShims(RealSystem{}, file, instructions); err == nil {
+	if err := WriteInstructionShims(RealSystem{}, file, &config.ProjectConfig{Instructions: instructions}); err == nil {
 		t.Fatalf("expected error")
 	}
 }
@@ -78,7 +116,7 @@ func TestWriteCodexInstructionsError(t *testing.T) {
 		t.Fatalf("write file: %v", err)
 	}
 	instructions := []config.InstructionFile{{Name: "00_base.md", Content: "base\n"}}
-	if err := WriteCodexInstructions(RealSystem{}, file, instructions); err == nil {
+	if err := WriteCodexInstructions(RealSystem{}, file, &config.ProjectConfig{Instructions: instructions}); err == nil {
 		t.Fatalf("expected error")
 	}
 }
@@ -133,9 +171,170 @@ func TestWriteInstructionShimsErrorPaths(t *testing.T) {
 			if err := tc.setup(root); err != nil {
 				t.Fatalf("setup: %v", err)
 			}
-			if err := WriteInstructionShims(RealSystem{}, root, instructions); err == nil {
+			if err := WriteInstructionShims(RealSystem{}, root, &config.ProjectConfig{Instructions: instructions}); err == nil {
 				t.Fatalf("expected error")
 			}
 		})
 	}
 }
+
+func TestWriteNestedInstructionShimsChildLayers(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	api := filepath.Join(root, "services", "api")
+	web := filepath.Join(root, "services", "web")
+	for _, dir := range []string{api, web} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+	}
+	// Stale generated shim in a child that no longer has instructions, plus a hand-written file.
+	stale := filepath.Join(web, "AGENTS.md")
+	if err := os.WriteFile(stale, []byte(buildInstructionShim(nil)), 0o644); err != nil {
+		t.Fatalf("write stale shim: %v", err)
+	}
+	manual := filepath.Join(web, "CLAUDE.md")
+	if err := os.WriteFile(manual, []byte("hand written"), 0o644); err != nil {
+		t.Fatalf("write manual file: %v", err)
+	}
+
+	children := []config.ChildLayer{
+		{Dir: "services/api", Path: api, Instructions: []config.InstructionFile{{Name: "00_api.md", Content: "api rules\n"}}},
+		{Dir: "services/web", Path: web},
+	}
+	if err := WriteNestedInstructionShims(RealSystem{}, root, &config.ProjectConfig{Root: root, Children: children}); err != nil {
+		t.Fatalf("WriteNestedInstructionShims error: %v", err)
+	}
+
+	for _, name := range []string{"AGENTS.md", "CLAUDE.md", "GEMINI.md"} {
+		data, err := os.ReadFile(filepath.Join(api, name))
+		if err != nil {
+			t.Fatalf("expected %s: %v", name, err)
+		}
+		content := string(data)
+		if !strings.Contains(content, "Source: services/api/.agent-layer/instructions/*.md") {
+			t.Fatalf("expected child source in header:\n%s", content)
+		}
+		if !strings.Contains(content, "api rules") {
+			t.Fatalf("expected child fragment in %s", name)
+		}
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Fatalf("expected stale generated shim to be removed, got %v", err)
+	}
+	if _, err := os.Stat(manual); err != nil {
+		t.Fatalf("expected hand-written file to be kept: %v", err)
+	}
+}
+
+func TestWriteInstructionShimsRendersTemplates(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	enabled := true
+	project := &config.ProjectConfig{
+		Root: root,
+		Config: config.Config{MCP: config.MCPConfig{Servers: []config.MCPServer{
+			{ID: "github", Enabled: &enabled, Clients: []string{"claude"}},
+		}}},
+		Instructions: []config.InstructionFile{{
+			Name:       "00_base.md",
+			Content:    "Client: {{ .Client }}\n{{ if mcpEnabled \"github\" }}Use the github MCP server.\n{{ end }}",
+			Template:   true,
+			SourcePath: filepath.Join(root, ".agent-layer", "instructions", "00_base.md"),
+			Line:       1,
+		}},
+	}
+	if err := WriteInstructionShims(RealSystem{}, root, project); err != nil {
+		t.Fatalf("WriteInstructionShims error: %v", err)
+	}
+
+	claude, err := os.ReadFile(filepath.Join(root, "CLAUDE.md"))
+	if err != nil {
+		t.Fatalf("read CLAUDE.md: %v", err)
+	}
+	if !strings.Contains(string(claude), "Client: claude\nUse the github MCP server.") {
+		t.Fatalf("unexpected CLAUDE.md:\n%s", claude)
+	}
+	gemini, err := os.ReadFile(filepath.Join(root, "GEMINI.md"))
+	if err != nil {
+		t.Fatalf("read GEMINI.md: %v", err)
+	}
+	if !strings.Contains(string(gemini), "Client: gemini\n") || strings.Contains(string(gemini), "github MCP") {
+		t.Fatalf("unexpected GEMINI.md:\n%s", gemini)
+	}
+
+	project.Instructions[0].Content = "{{ .Nope }}"
+	err = WriteInstructionShims(RealSystem{}, root, project)
+	if err == nil || !strings.HasPrefix(err.Error(), ".agent-layer/instructions/00_base.md:1:") {
+		t.Fatalf("expected template error with source location, got %v", err)
+	}
+}
+
+func TestWriteNestedInstructionShimsAppliesTo(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	for _, dir := range []string{"web", "api", "old"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+	}
+	// Generated by a previous sync for a scope that no longer exists.
+	stale := filepath.Join(root, "old", "CLAUDE.md")
+	if err := os.WriteFile(stale, []byte(buildInstructionShim(nil)), 0o644); err != nil {
+		t.Fatalf("write stale shim: %v", err)
+	}
+
+	project := &config.ProjectConfig{
+		Root: root,
+		Instructions: []config.InstructionFile{
+			{Name: "00_base.md", Content: "base\n"},
+			{Name: "10_web.md", Content: "web rules\n", AppliesTo: []string{"web/**/*.ts", "web/**/*.tsx"}},
+			{Name: "20_ts.md", Content: "ts rules\n", AppliesTo: []string{"**/*.ts"}},
+			{Name: "30_api.md", Content: "api rules\n", AppliesTo: []string{"api/**"}, Clients: []string{"claude"}},
+			{Name: "40_missing.md", Content: "missing\n", AppliesTo: []string{"mobile/**"}},
+		},
+	}
+	if err := WriteInstructionShims(RealSystem{}, root, project); err != nil {
+		t.Fatalf("WriteInstructionShims error: %v", err)
+	}
+	if err := WriteNestedInstructionShims(RealSystem{}, root, project); err != nil {
+		t.Fatalf("WriteNestedInstructionShims error: %v", err)
+	}
+
+	want := map[string][]string{
+		"AGENTS.md":                       {"00_base.md", "20_ts.md"},
+		".github/copilot-instructions.md": {"00_base.md"},
+		"web/AGENTS.md":                   {"10_web.md"},
+		"web/CLAUDE.md":                   {"10_web.md"},
+		"web/GEMINI.md":                   {"10_web.md"},
+		"api/CLAUDE.md":                   {"30_api.md"},
+	}
+	for rel, names := range want {
+		data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(rel)))
+		if err != nil {
+			t.Fatalf("read %s: %v", rel, err)
+		}
+		if got := strings.Count(string(data), "<!-- BEGIN: "); got != len(names) {
+			t.Fatalf("%s: expected %d fragments, got %d:\n%s", rel, len(names), got, data)
+		}
+		for _, name := range names {
+			if !strings.Contains(string(data), "<!-- BEGIN: "+name+" -->") {
+				t.Fatalf("%s: missing %s:\n%s", rel, name, data)
+			}
+		}
+	}
+	for _, rel := range []string{"api/AGENTS.md", "old/CLAUDE.md", "mobile"} {
+		if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(rel))); !os.IsNotExist(err) {
+			t.Fatalf("expected %s to be absent, got %v", rel, err)
+		}
+	}
+
+	// Dropping the scope removes the nested shims it generated.
+	project.Instructions = project.Instructions[:1]
+	if err := WriteNestedInstructionShims(RealSystem{}, root, project); err != nil {
+		t.Fatalf("WriteNestedInstructionShims error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "web", "AGENTS.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected web/AGENTS.md to be removed, got %v", err)
+	}
+}
@@ -48,20 +48,21 @@ func buildMCPConfig(sys System, project *config.ProjectConfig) (*mcpConfig, erro
 		Servers: make(OrderedMap[mcpServer]),
 	}
 
-	// Internal prompt server for Claude.
-	promptCommand, promptArgs, err := resolvePromptServerCommand(sys, project.Root)
+	// Internal prompt server for Claude. It also serves resources and memory tools, so it stays when
+	// slash commands are only written natively; mcp-prompts then serves no prompts to Claude.
+	prompt, err := resolvePromptServer(sys, project, "claude")
 	if err != nil {
 		return nil, err
 	}
-	cfg.Servers["agent-layer"] = mcpServer{
-		Type:    "stdio",
-		Command: promptCommand,
-		Args:    promptArgs,
+	entry := mcpServer{Type: "stdio", Command: prompt.Command, Args: prompt.Args}
+	if prompt.URL != "" {
+		entry = mcpServer{Type: "http", URL: prompt.URL, Headers: prompt.headers("${%s}")}
 	}
+	cfg.Servers["agent-layer"] = entry
 
 	resolved, err := projection.ResolveMCPServers(
 		project.Config.MCP.Servers,
-		project.Env,
+		project.PlaceholderEnv(),
 		"claude",
 		projection.ClientPlaceholderResolver("${%s}"),
 	)
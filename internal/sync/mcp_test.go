@@ -3,6 +3,7 @@ package sync
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/conn-castle/agent-layer/internal/config"
@@ -61,6 +62,49 @@ func TestBuildMCPConfig(t *testing.T) {
 	}
 }
 
+func TestBuildMCPConfigNativeSlashCommands(t *testing.T) {
+	t.Parallel()
+	sys := &MockSystem{
+		LookPathFunc: func(file string) (string, error) {
+			return "/usr/local/bin/al", nil
+		},
+	}
+	project := &config.ProjectConfig{
+		Config: config.Config{Agents: config.AgentsConfig{Claude: config.ClaudeConfig{SlashCommands: config.SlashCommandsNative}}},
+		Root:   t.TempDir(),
+	}
+
+	cfg, err := buildMCPConfig(sys, project)
+	if err != nil {
+		t.Fatalf("buildMCPConfig error: %v", err)
+	}
+	// The server stays for resources and memory tools; mcp-prompts serves Claude no prompts.
+	server, ok := cfg.Servers["agent-layer"]
+	if !ok || server.Command != "al" || strings.Join(server.Args, " ") != "mcp-prompts --client claude" {
+		t.Fatalf("expected the internal server in native mode, got %v", cfg.Servers)
+	}
+}
+
+func TestBuildMCPConfigPromptServerHTTP(t *testing.T) {
+	t.Parallel()
+	project := &config.ProjectConfig{
+		Config: config.Config{MCP: config.MCPConfig{PromptServer: config.PromptServerConfig{HTTP: "127.0.0.1:7420"}}},
+		Env:    map[string]string{config.PromptServerTokenEnvVar: "secret"},
+	}
+
+	cfg, err := buildMCPConfig(&MockSystem{}, project)
+	if err != nil {
+		t.Fatalf("buildMCPConfig error: %v", err)
+	}
+	server := cfg.Servers["agent-layer"]
+	if server.Type != "http" || server.URL != "http://127.0.0.1:7420/mcp/claude" || server.Command != "" {
+		t.Fatalf("unexpected agent-layer server %+v", server)
+	}
+	if server.Headers["Authorization"] != "Bearer ${AL_PROMPT_SERVER_TOKEN}" {
+		t.Fatalf("unexpected headers %v", server.Headers)
+	}
+}
+
 func TestWriteMCPConfig(t *testing.T) {
 	t.Parallel()
 	root := t.TempDir()
@@ -236,3 +280,32 @@ func TestBuildMCPConfigStdioServer(t *testing.T) {
 		t.Fatalf("unexpected env: %s", server.Env["TOKEN"])
 	}
 }
+
+func TestBuildMCPConfigClientPlaceholdersPassThrough(t *testing.T) {
+	t.Parallel()
+	enabled := true
+	project := &config.ProjectConfig{
+		Config: config.Config{
+			MCP: config.MCPConfig{Servers: []config.MCPServer{
+				{ID: "local", Enabled: &enabled, Transport: "stdio", Command: "node", Args: []string{"${workspaceFolder}/server.js"}},
+			}},
+		},
+		Env: map[string]string{},
+	}
+	sys := &MockSystem{LookPathFunc: func(string) (string, error) { return "/usr/local/bin/al", nil }}
+
+	cfg, err := buildMCPConfig(sys, project)
+	if err != nil {
+		t.Fatalf("buildMCPConfig error: %v", err)
+	}
+	if got := cfg.Servers["local"].Args; len(got) != 1 || got[0] != "${workspaceFolder}/server.js" {
+		t.Fatalf("expected ${workspaceFolder} to pass through, got %v", got)
+	}
+	vscode, err := buildVSCodeMCPConfig(&MockSystem{}, project)
+	if err != nil {
+		t.Fatalf("buildVSCodeMCPConfig error: %v", err)
+	}
+	if got := vscode.Servers["local"].Args; len(got) != 1 || got[0] != "${workspaceFolder}/server.js" {
+		t.Fatalf("expected ${workspaceFolder} to pass through, got %v", got)
+	}
+}
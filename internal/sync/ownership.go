@@ -0,0 +1,141 @@
+package sync
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// generatedMarker identifies Markdown and TOML outputs written by sync.
+const generatedMarker = "GENERATED FILE"
+
+// jsonOutputs maps JSON outputs (which cannot carry the marker) to a check that recognizes sync's output.
+var jsonOutputs = map[string]func([]byte) bool{
+	".mcp.json":             canonicalJSON[mcpConfig],
+	".gemini/settings.json": canonicalJSON[geminiSettings],
+	".vscode/mcp.json":      canonicalJSON[vscodeMCPConfig],
+	".claude/settings.json": canonicalJSON[claudeSettings],
+}
+
+// UserFiles returns the files a sync of project would overwrite that exist but were not generated
+// by Agent Layer, such as a hand-written CLAUDE.md. Paths are absolute and in write order.
+func UserFiles(sys System, root string, project *config.ProjectConfig) ([]string, error) {
+	var files []string
+	for _, path := range plannedOutputs(root, project) {
+		data, err := sys.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf(messages.SyncReadFailedFmt, path, err)
+		}
+		if !IsGeneratedOutput(root, path, data) {
+			files = append(files, path)
+		}
+	}
+	return files, nil
+}
+
+// checkUserFiles refuses to sync when it would overwrite files that Agent Layer did not generate.
+func checkUserFiles(sys System, root string, project *config.ProjectConfig) error {
+	files, err := UserFiles(sys, root, project)
+	if err != nil || len(files) == 0 {
+		return err
+	}
+	var list strings.Builder
+	for _, path := range files {
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			rel = path
+		}
+		fmt.Fprintf(&list, messages.SyncUserFileLineFmt, filepath.ToSlash(rel))
+	}
+	return fmt.Errorf(messages.SyncUserFilesConflictFmt, list.String())
+}
+
+// plannedOutputs lists the files sync writes for project that could collide with hand-written files.
+func plannedOutputs(root string, project *config.ProjectConfig) []string {
+	var paths []string
+	for _, shim := range rootInstructionShims {
+		paths = append(paths, filepath.Join(root, shim.name))
+	}
+	paths = append(paths, filepath.Join(root, ".github", copilotInstructionShim.name))
+	for _, file := range project.Instructions {
+		if len(file.AppliesTo) > 0 && len(config.InstructionsForClients([]config.InstructionFile{file}, copilotInstructionShim.clients...)) > 0 {
+			paths = append(paths, filepath.Join(root, ".github", "instructions", strings.TrimSuffix(file.Name, ".md")+copilotInstructionsSuffix))
+		}
+	}
+	dirs, scopes := nestedScopes(root, project)
+	for _, dir := range dirs {
+		for _, shim := range rootInstructionShims {
+			if len(config.InstructionsForClients(scopes[dir].instructions, shim.clients...)) > 0 {
+				paths = append(paths, filepath.Join(dir, shim.name))
+			}
+		}
+	}
+
+	agents := project.Config.Agents
+	if isEnabled(agents.Codex.Enabled) {
+		paths = append(paths,
+			filepath.Join(root, ".codex", codexInstructionShim.name),
+			filepath.Join(root, ".codex", "config.toml"),
+			filepath.Join(root, ".codex", "rules", "default.rules"),
+		)
+	}
+	if isEnabled(agents.VSCode.Enabled) {
+		paths = append(paths, filepath.Join(root, ".vscode", "mcp.json"))
+	}
+	if isEnabled(agents.Gemini.Enabled) {
+		paths = append(paths, filepath.Join(root, ".gemini", "settings.json"))
+		for _, cmd := range config.SlashCommandsForClient(project.SlashCommands, "gemini") {
+			paths = append(paths, filepath.Join(root, ".gemini", "commands", namespacedCommandPath(cmd, ".toml")))
+		}
+	}
+	if isEnabled(agents.Claude.Enabled) {
+		paths = append(paths, filepath.Join(root, ".claude", "settings.json"), filepath.Join(root, ".mcp.json"))
+		if agents.Claude.NativeSlashCommands() {
+			for _, cmd := range config.SlashCommandsForClient(project.SlashCommands, "claude") {
+				paths = append(paths, filepath.Join(root, ".claude", "commands", namespacedCommandPath(cmd, ".md")))
+			}
+		}
+	}
+	return paths
+}
+
+// IsGeneratedOutput reports whether data, the current content of path under root, was written by sync.
+// Empty files hold nothing to lose and count as generated.
+func IsGeneratedOutput(root string, path string, data []byte) bool {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return true
+	}
+	if rel, err := filepath.Rel(root, path); err == nil {
+		if check, ok := jsonOutputs[filepath.ToSlash(rel)]; ok {
+			return check(data)
+		}
+	}
+	return bytes.Contains(data, []byte(generatedMarker))
+}
+
+// canonicalJSON reports whether data is exactly what sync's marshalling of T produces for the same
+// content. Generated files round-trip byte for byte; hand-written ones almost never do.
+func canonicalJSON[T any](data []byte) bool {
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	var value T
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&value); err != nil {
+		return false
+	}
+	out, err := json.MarshalIndent(value, "", "  ")
+	return err == nil && bytes.Equal(append(out, '\n'), data)
+}
+
+func isEnabled(enabled *bool) bool {
+	return enabled != nil && *enabled
+}
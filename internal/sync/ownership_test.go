@@ -0,0 +1,106 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+func TestRunWithProjectRefusesUserFiles(t *testing.T) {
+	root := t.TempDir()
+	writePromptServerBinary(t, t.TempDir())
+	enabled := true
+	project := &config.ProjectConfig{
+		Root:         root,
+		Instructions: []config.InstructionFile{{Name: "00_base.md", Content: "base\n"}},
+		Config: config.Config{Agents: config.AgentsConfig{
+			Claude: config.ClaudeConfig{Enabled: &enabled},
+		}},
+	}
+	claudeMD := filepath.Join(root, "CLAUDE.md")
+	if err := os.WriteFile(claudeMD, []byte("# Our rules\n"), 0o644); err != nil {
+		t.Fatalf("write CLAUDE.md: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".mcp.json"), []byte(`{"mcpServers": {"github": {"command": "gh-mcp"}}}`), 0o644); err != nil {
+		t.Fatalf("write .mcp.json: %v", err)
+	}
+
+	_, err := RunWithProject(RealSystem{}, root, project)
+	if err == nil || !strings.Contains(err.Error(), "  - CLAUDE.md\n  - .mcp.json\n") || !strings.Contains(err.Error(), "al import") {
+		t.Fatalf("expected user file conflict, got %v", err)
+	}
+	if data, _ := os.ReadFile(claudeMD); string(data) != "# Our rules\n" {
+		t.Fatalf("expected CLAUDE.md to be untouched, got %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(root, "AGENTS.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected nothing to be written, got %v", err)
+	}
+
+	if _, err := runProject(RealSystem{}, root, project, true); err != nil {
+		t.Fatalf("forced sync error: %v", err)
+	}
+	// Everything sync wrote is recognized on the next run.
+	files, err := UserFiles(RealSystem{}, root, project)
+	if err != nil || len(files) != 0 {
+		t.Fatalf("expected generated outputs only, got %v, %v", files, err)
+	}
+}
+
+func TestUserFilesClaudeCommands(t *testing.T) {
+	root := t.TempDir()
+	enabled := true
+	project := &config.ProjectConfig{
+		Root:          root,
+		SlashCommands: []config.SlashCommand{{Name: "review", Description: "Review", Body: "Review."}},
+		Config: config.Config{Agents: config.AgentsConfig{
+			Claude: config.ClaudeConfig{Enabled: &enabled, SlashCommands: config.SlashCommandsBoth},
+		}},
+	}
+	review := filepath.Join(root, ".claude", "commands", "review.md")
+	if err := os.MkdirAll(filepath.Dir(review), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(review, []byte("Our review steps\n"), 0o644); err != nil {
+		t.Fatalf("write review.md: %v", err)
+	}
+
+	files, err := UserFiles(RealSystem{}, root, project)
+	if err != nil || len(files) != 1 || files[0] != review {
+		t.Fatalf("expected hand-written command to be reported, got %v, %v", files, err)
+	}
+
+	project.Config.Agents.Claude.SlashCommands = config.SlashCommandsMCP
+	if files, err := UserFiles(RealSystem{}, root, project); err != nil || len(files) != 0 {
+		t.Fatalf("expected no conflicts without native commands, got %v, %v", files, err)
+	}
+}
+
+func TestIsGeneratedOutput(t *testing.T) {
+	t.Parallel()
+	root := "/repo"
+	cases := []struct {
+		rel  string
+		data string
+		want bool
+	}{
+		{"CLAUDE.md", "", true},
+		{"CLAUDE.md", "<!--\n  GENERATED FILE\n-->\n", true},
+		{"CLAUDE.md", "# hand written\n", false},
+		{".claude/settings.json", "{}\n", true},
+		{".claude/settings.json", "{\n  \"permissions\": {\n    \"allow\": [\n      \"Bash(git status:*)\"\n    ]\n  }\n}\n", true},
+		{".claude/settings.json", "{\r\n  \"permissions\": {\r\n    \"allow\": [\r\n      \"Bash(git status:*)\"\r\n    ]\r\n  }\r\n}\r\n", true},
+		{".claude/settings.json", "{\"permissions\": {\"allow\": [\"Bash(git status:*)\"]}}", false},
+		{".claude/settings.json", "{\n  \"hooks\": {}\n}\n", false},
+		{".vscode/mcp.json", "{\n  \"servers\": {\n    \"b\": {},\n    \"a\": {}\n  }\n}\n", false},
+		{".vscode/mcp.json", "{\n  \"servers\": {\n    \"a\": {},\n    \"b\": {}\n  }\n}\n", true},
+		{".mcp.json", "not json", false},
+	}
+	for _, tc := range cases {
+		if got := IsGeneratedOutput(root, filepath.Join(root, tc.rel), []byte(tc.data)); got != tc.want {
+			t.Fatalf("%s %q: got %v, want %v", tc.rel, tc.data, got, tc.want)
+		}
+	}
+}
@@ -6,15 +6,56 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/conn-castle/agent-layer/internal/config"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
-// resolvePromptServerCommand returns the command and args used to run the internal MCP prompt server.
+// commandToolsFlag has the prompt server also serve slash commands as tools.
+const commandToolsFlag = "--command-tools"
+
+// promptServer is how a client reaches the internal MCP prompt server: a stdio command, or the URL
+// of the shared HTTP server when mcp.prompt_server.http is set.
+type promptServer struct {
+	Command string
+	Args    []string
+	URL     string
+	// TokenEnv names the env var holding the HTTP server's bearer token; empty when .env sets none.
+	TokenEnv string
+}
+
+// resolvePromptServer returns how client reaches the internal MCP prompt server.
+func resolvePromptServer(sys System, project *config.ProjectConfig, client string) (promptServer, error) {
+	if addr := project.Config.MCP.PromptServer.HTTP; addr != "" {
+		server := promptServer{URL: config.PromptServerURL(addr, client)}
+		if project.Env[config.PromptServerTokenEnvVar] != "" {
+			server.TokenEnv = config.PromptServerTokenEnvVar
+		}
+		return server, nil
+	}
+	command, args, err := resolvePromptServerCommand(sys, project.Root, client)
+	if err != nil {
+		return promptServer{}, err
+	}
+	return promptServer{Command: command, Args: args}, nil
+}
+
+// headers returns the Authorization header for the HTTP server, with the token as a placeholder in
+// the client's format, or nil when no token is set.
+func (s promptServer) headers(placeholder string) OrderedMap[string] {
+	if s.TokenEnv == "" {
+		return nil
+	}
+	return OrderedMap[string]{"Authorization": "Bearer " + fmt.Sprintf(placeholder, s.TokenEnv)}
+}
+
+// resolvePromptServerCommand returns the command and args used to run the internal MCP prompt server
+// for client, which only serves the slash commands that target that client.
 // It prefers the globally installed "al mcp-prompts" and falls back to "go run <root>/cmd/al mcp-prompts" for dev usage.
 // It returns an error when it cannot resolve a runnable command.
-func resolvePromptServerCommand(sys System, root string) (string, []string, error) {
+func resolvePromptServerCommand(sys System, root string, client string) (string, []string, error) {
+	clientArgs := []string{"mcp-prompts", "--client", client}
 	if _, err := sys.LookPath("al"); err == nil {
-		return "al", []string{"mcp-prompts"}, nil
+		return "al", clientArgs, nil
 	}
 
 	if root == "" {
@@ -37,5 +78,5 @@ func resolvePromptServerCommand(sys System, root string) (string, []string, erro
 		return "", nil, fmt.Errorf(messages.SyncMissingGoForPromptServerFmt, err)
 	}
 
-	return "go", []string{"run", sourcePath, "mcp-prompts"}, nil
+	return "go", append([]string{"run", sourcePath}, clientArgs...), nil
 }
@@ -21,7 +21,7 @@ func TestResolvePromptServerCommand_MissingSource(t *testing.T) {
 	}
 
 	// Neither al on PATH nor ./cmd/al exists
-	_, _, err := resolvePromptServerCommand(sys, root)
+	_, _, err := resolvePromptServerCommand(sys, root, "claude")
 	if err == nil {
 		t.Fatalf("expected error for missing source")
 	}
@@ -45,7 +45,7 @@ func TestResolvePromptServerCommand_SourceNotDir(t *testing.T) {
 		},
 	}
 
-	_, _, err := resolvePromptServerCommand(sys, root)
+	_, _, err := resolvePromptServerCommand(sys, root, "claude")
 	if err == nil {
 		t.Fatalf("expected error for file source")
 	}
@@ -66,7 +66,7 @@ func TestResolvePromptServerCommand_SourceStatError(t *testing.T) {
 		},
 	}
 
-	_, _, err := resolvePromptServerCommand(sys, root)
+	_, _, err := resolvePromptServerCommand(sys, root, "claude")
 	if err == nil {
 		t.Fatalf("expected error for source stat failure")
 	}
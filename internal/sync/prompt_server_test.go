@@ -4,9 +4,46 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/config"
 )
 
+func TestResolvePromptServerHTTP(t *testing.T) {
+	t.Parallel()
+	project := &config.ProjectConfig{
+		Config: config.Config{MCP: config.MCPConfig{PromptServer: config.PromptServerConfig{HTTP: "127.0.0.1:7420"}}},
+		Env:    map[string]string{},
+	}
+
+	server, err := resolvePromptServer(&MockSystem{}, project, "claude")
+	if err != nil {
+		t.Fatalf("resolvePromptServer error: %v", err)
+	}
+	if server.URL != "http://127.0.0.1:7420/mcp/claude" || server.Command != "" || server.TokenEnv != "" || server.headers("${%s}") != nil {
+		t.Fatalf("unexpected server without a token: %+v", server)
+	}
+
+	project.Env[config.PromptServerTokenEnvVar] = "secret"
+	server, err = resolvePromptServer(&MockSystem{}, project, "claude")
+	if err != nil {
+		t.Fatalf("resolvePromptServer error: %v", err)
+	}
+	if got := server.headers("${env:%s}")["Authorization"]; got != "Bearer ${env:AL_PROMPT_SERVER_TOKEN}" {
+		t.Fatalf("unexpected Authorization header %q", got)
+	}
+
+	project.Config.MCP.PromptServer.HTTP = ""
+	server, err = resolvePromptServer(alOnPathSystem(), project, "claude")
+	if err != nil || server.Command != "al" || server.URL != "" {
+		t.Fatalf("expected stdio command without mcp.prompt_server.http, got %+v, %v", server, err)
+	}
+	if _, err := resolvePromptServer(&MockSystem{}, project, "claude"); err == nil {
+		t.Fatalf("expected error when the prompt server command cannot be resolved")
+	}
+}
+
 func TestResolvePromptServerCommandUsesGlobalBinary(t *testing.T) {
 	t.Parallel()
 	sys := &MockSystem{
@@ -18,14 +55,14 @@ func TestResolvePromptServerCommandUsesGlobalBinary(t *testing.T) {
 		},
 	}
 
-	command, args, err := resolvePromptServerCommand(sys, t.TempDir())
+	command, args, err := resolvePromptServerCommand(sys, t.TempDir(), "claude")
 	if err != nil {
 		t.Fatalf("resolvePromptServerCommand error: %v", err)
 	}
 	if command != "al" {
 		t.Fatalf("expected al, got %q", command)
 	}
-	if len(args) != 1 || args[0] != "mcp-prompts" {
+	if strings.Join(args, " ") != "mcp-prompts --client claude" {
 		t.Fatalf("unexpected args: %#v", args)
 	}
 }
@@ -38,7 +75,7 @@ func TestResolvePromptServerCommandRootEmptyNoGlobalBinary(t *testing.T) {
 		},
 	}
 
-	_, _, err := resolvePromptServerCommand(sys, "")
+	_, _, err := resolvePromptServerCommand(sys, "", "claude")
 	if err == nil {
 		t.Fatalf("expected error for missing al")
 	}
@@ -65,7 +102,7 @@ func TestResolvePromptServerCommandFallsBackToGoRun(t *testing.T) {
 		},
 	}
 
-	command, args, err := resolvePromptServerCommand(sys, root)
+	command, args, err := resolvePromptServerCommand(sys, root, "claude")
 	if err != nil {
 		t.Fatalf("resolvePromptServerCommand error: %v", err)
 	}
@@ -73,7 +110,7 @@ func TestResolvePromptServerCommandFallsBackToGoRun(t *testing.T) {
 		t.Fatalf("expected go, got %q", command)
 	}
 	expectedSource := filepath.Join(root, "cmd", "al")
-	if len(args) != 3 || args[0] != "run" || args[1] != expectedSource || args[2] != "mcp-prompts" {
+	if strings.Join(args, " ") != "run "+expectedSource+" mcp-prompts --client claude" {
 		t.Fatalf("unexpected args: %#v", args)
 	}
 }
@@ -93,7 +130,7 @@ func TestResolvePromptServerCommandMissingGo(t *testing.T) {
 		},
 	}
 
-	_, _, err := resolvePromptServerCommand(sys, root)
+	_, _, err := resolvePromptServerCommand(sys, root, "claude")
 	if err == nil {
 		t.Fatalf("expected error")
 	}
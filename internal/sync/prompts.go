@@ -2,19 +2,27 @@ package sync
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 
+	"gopkg.in/yaml.v3"
+
 	"github.com/conn-castle/agent-layer/internal/config"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
 const promptHeaderTemplate = "<!--\n  GENERATED FILE\n  Source: .agent-layer/slash-commands/%s.md\n  Regenerate: al sync\n-->\n"
 
-// WriteVSCodePrompts generates VS Code prompt files for slash commands.
-func WriteVSCodePrompts(sys System, root string, commands []config.SlashCommand) error {
+// WriteVSCodePrompts generates VS Code prompt files for slash commands that target vscode.
+func WriteVSCodePrompts(sys System, root string, project *config.ProjectConfig) error {
+	commands, err := clientSlashCommands(project, "vscode", config.ArgumentsVSCodeInput)
+	if err != nil {
+		return err
+	}
 	promptDir := filepath.Join(root, ".vscode", "prompts")
 	if err := sys.MkdirAll(promptDir, 0o755); err != nil {
 		return fmt.Errorf(messages.SyncCreateDirFailedFmt, promptDir, err)
@@ -22,9 +30,9 @@ func WriteVSCodePrompts(sys System, root string, commands []config.SlashCommand)
 
 	wanted := make(map[string]struct{}, len(commands))
 	for _, cmd := range commands {
-		wanted[cmd.Name] = struct{}{}
+		wanted[cmd.FlatName()] = struct{}{}
 		content := buildVSCodePrompt(cmd)
-		path := filepath.Join(promptDir, fmt.Sprintf("%s.prompt.md", cmd.Name))
+		path := filepath.Join(promptDir, fmt.Sprintf("%s.prompt.md", cmd.FlatName()))
 		if err := sys.WriteFileAtomic(path, []byte(content), 0o644); err != nil {
 			return fmt.Errorf(messages.SyncWriteFileFailedFmt, path, err)
 		}
@@ -33,13 +41,29 @@ func WriteVSCodePrompts(sys System, root string, commands []config.SlashCommand)
 	return removeStalePromptFiles(sys, promptDir, wanted)
 }
 
+// clientSlashCommands returns the project's slash commands that target client, rendered for it
+// with arguments written in style.
+func clientSlashCommands(project *config.ProjectConfig, client string, style config.ArgumentStyle) ([]config.SlashCommand, error) {
+	commands := config.SlashCommandsForClient(project.SlashCommands, client)
+	return config.RenderSlashCommands(commands, config.NewTemplateData(project, client).WithArgumentStyle(style))
+}
+
 func buildVSCodePrompt(cmd config.SlashCommand) string {
 	var builder strings.Builder
 	builder.WriteString("---\n")
 	builder.WriteString("name: ")
-	builder.WriteString(cmd.Name)
-	builder.WriteString("\n---\n")
-	builder.WriteString(fmt.Sprintf(promptHeaderTemplate, cmd.Name))
+	builder.WriteString(cmd.FlatName())
+	builder.WriteString("\n")
+	writeFrontMatterField(&builder, "argument-hint", cmd.EffectiveArgumentHint())
+	writeFrontMatterField(&builder, "agent", cmd.Agent)
+	writeFrontMatterField(&builder, "model", cmd.Model)
+	if len(cmd.Tools) > 0 {
+		builder.WriteString("tools: ")
+		builder.WriteString(yamlFlowList(cmd.Tools))
+		builder.WriteString("\n")
+	}
+	builder.WriteString("---\n")
+	builder.WriteString(fmt.Sprintf(promptHeaderTemplate, cmd.RelPath()))
 	if cmd.Body != "" {
 		builder.WriteString(cmd.Body)
 		if !strings.HasSuffix(cmd.Body, "\n") {
@@ -50,9 +74,14 @@ func buildVSCodePrompt(cmd config.SlashCommand) string {
 }
 
 func removeStalePromptFiles(sys System, promptDir string, wanted map[string]struct{}) error {
-	entries, err := os.ReadDir(promptDir)
+	return removeStaleGeneratedFiles(sys, promptDir, ".prompt.md", wanted)
+}
+
+// removeStaleGeneratedFiles removes generated files in dir named <base><suffix> whose base is not in wanted.
+func removeStaleGeneratedFiles(sys System, dir string, suffix string, wanted map[string]struct{}) error {
+	entries, err := os.ReadDir(dir)
 	if err != nil {
-		return fmt.Errorf(messages.SyncReadFailedFmt, promptDir, err)
+		return fmt.Errorf(messages.SyncReadFailedFmt, dir, err)
 	}
 
 	for _, entry := range entries {
@@ -60,14 +89,14 @@ func removeStalePromptFiles(sys System, promptDir string, wanted map[string]stru
 			continue
 		}
 		name := entry.Name()
-		if !strings.HasSuffix(name, ".prompt.md") {
+		if !strings.HasSuffix(name, suffix) {
 			continue
 		}
-		base := strings.TrimSuffix(name, ".prompt.md")
+		base := strings.TrimSuffix(name, suffix)
 		if _, ok := wanted[base]; ok {
 			continue
 		}
-		path := filepath.Join(promptDir, name)
+		path := filepath.Join(dir, name)
 		isGenerated, err := hasGeneratedMarker(sys, path)
 		if err != nil {
 			return err
@@ -82,8 +111,89 @@ func removeStalePromptFiles(sys System, promptDir string, wanted map[string]stru
 	return nil
 }
 
-// WriteCodexSkills generates Codex skill files for slash commands.
-func WriteCodexSkills(sys System, root string, commands []config.SlashCommand) error {
+// WriteClaudeCommands generates .claude/commands/<name>.md for slash commands that target claude
+// when agents.claude.slash_commands is native or both; git:commit goes to git/commit.md, which Claude
+// Code lists as /commit (project:git). Otherwise it only removes stale generated commands.
+func WriteClaudeCommands(sys System, root string, project *config.ProjectConfig) error {
+	commandsDir := filepath.Join(root, ".claude", "commands")
+	if !project.Config.Agents.Claude.NativeSlashCommands() {
+		if _, err := sys.Stat(commandsDir); err != nil {
+			return nil
+		}
+		return removeStaleGeneratedTree(sys, commandsDir, ".md", nil)
+	}
+
+	commands, err := clientSlashCommands(project, "claude", config.ArgumentsShell)
+	if err != nil {
+		return err
+	}
+	if err := sys.MkdirAll(commandsDir, 0o755); err != nil {
+		return fmt.Errorf(messages.SyncCreateDirFailedFmt, commandsDir, err)
+	}
+
+	wanted := make(map[string]struct{}, len(commands))
+	for _, cmd := range commands {
+		rel := namespacedCommandPath(cmd, ".md")
+		wanted[rel] = struct{}{}
+		path := filepath.Join(commandsDir, rel)
+		if dir := filepath.Dir(path); dir != commandsDir {
+			if err := sys.MkdirAll(dir, 0o755); err != nil {
+				return fmt.Errorf(messages.SyncCreateDirFailedFmt, dir, err)
+			}
+		}
+		if err := sys.WriteFileAtomic(path, []byte(buildClaudeCommand(cmd)), 0o644); err != nil {
+			return fmt.Errorf(messages.SyncWriteFileFailedFmt, path, err)
+		}
+	}
+
+	return removeStaleGeneratedTree(sys, commandsDir, ".md", wanted)
+}
+
+// buildClaudeCommand returns the Claude Code custom command file for a slash command.
+func buildClaudeCommand(cmd config.SlashCommand) string {
+	var builder strings.Builder
+	builder.WriteString("---\n")
+	builder.WriteString("description: >-\n")
+	for _, line := range wrapDescription(cmd.Description, 72) {
+		builder.WriteString("  ")
+		builder.WriteString(line)
+		builder.WriteString("\n")
+	}
+	writeFrontMatterField(&builder, "argument-hint", cmd.EffectiveArgumentHint())
+	writeFrontMatterField(&builder, "model", cmd.Model)
+	writeFrontMatterField(&builder, "allowed-tools", strings.Join(cmd.AllowedTools, ", "))
+	if cmd.DisableModelInvocation {
+		builder.WriteString("disable-model-invocation: true\n")
+	}
+	builder.WriteString("---\n")
+	builder.WriteString(fmt.Sprintf(promptHeaderTemplate, cmd.RelPath()))
+	if hasArgumentDefaults(cmd.Arguments) {
+		// Claude Code has no argument defaults; state them next to the $ARGUMENTS/$N placeholders.
+		builder.WriteString("\n")
+		writeArgumentDefaults(&builder, cmd.Arguments, config.ArgumentsShell)
+	}
+	if cmd.Body != "" {
+		builder.WriteString("\n")
+		builder.WriteString(cmd.Body)
+		if !strings.HasSuffix(cmd.Body, "\n") {
+			builder.WriteString("\n")
+		}
+	}
+	return builder.String()
+}
+
+// namespacedCommandPath returns cmd's file relative to a client's commands folder, with namespaces
+// as subdirectories: git:commit is git/commit<suffix>.
+func namespacedCommandPath(cmd config.SlashCommand, suffix string) string {
+	return filepath.FromSlash(cmd.RelPath()) + suffix
+}
+
+// WriteCodexSkills generates Codex skill files for slash commands that target codex.
+func WriteCodexSkills(sys System, root string, project *config.ProjectConfig) error {
+	commands, err := clientSlashCommands(project, "codex", config.ArgumentsText)
+	if err != nil {
+		return err
+	}
 	skillsDir := filepath.Join(root, ".codex", "skills")
 	if err := sys.MkdirAll(skillsDir, 0o755); err != nil {
 		return fmt.Errorf(messages.SyncCreateDirFailedFmt, skillsDir, err)
@@ -91,8 +201,8 @@ func WriteCodexSkills(sys System, root string, commands []config.SlashCommand) e
 
 	wanted := make(map[string]struct{}, len(commands))
 	for _, cmd := range commands {
-		wanted[cmd.Name] = struct{}{}
-		skillDir := filepath.Join(skillsDir, cmd.Name)
+		wanted[cmd.FlatName()] = struct{}{}
+		skillDir := filepath.Join(skillsDir, cmd.FlatName())
 		if err := sys.MkdirAll(skillDir, 0o755); err != nil {
 			return fmt.Errorf(messages.SyncCreateDirFailedFmt, skillDir, err)
 		}
@@ -106,8 +216,12 @@ func WriteCodexSkills(sys System, root string, commands []config.SlashCommand) e
 	return removeStaleSkillDirs(sys, skillsDir, wanted)
 }
 
-// WriteAntigravitySkills generates Antigravity skill files for slash commands.
-func WriteAntigravitySkills(sys System, root string, commands []config.SlashCommand) error {
+// WriteAntigravitySkills generates Antigravity skill files for slash commands that target antigravity.
+func WriteAntigravitySkills(sys System, root string, project *config.ProjectConfig) error {
+	commands, err := clientSlashCommands(project, "antigravity", config.ArgumentsText)
+	if err != nil {
+		return err
+	}
 	skillsDir := filepath.Join(root, ".agent", "skills")
 	if err := sys.MkdirAll(skillsDir, 0o755); err != nil {
 		return fmt.Errorf(messages.SyncCreateDirFailedFmt, skillsDir, err)
@@ -115,8 +229,8 @@ func WriteAntigravitySkills(sys System, root string, commands []config.SlashComm
 
 	wanted := make(map[string]struct{}, len(commands))
 	for _, cmd := range commands {
-		wanted[cmd.Name] = struct{}{}
-		skillDir := filepath.Join(skillsDir, cmd.Name)
+		wanted[cmd.FlatName()] = struct{}{}
+		skillDir := filepath.Join(skillsDir, cmd.FlatName())
 		if err := sys.MkdirAll(skillDir, 0o755); err != nil {
 			return fmt.Errorf(messages.SyncCreateDirFailedFmt, skillDir, err)
 		}
@@ -134,7 +248,7 @@ func buildCodexSkill(cmd config.SlashCommand) string {
 	var builder strings.Builder
 	builder.WriteString("---\n")
 	builder.WriteString("name: ")
-	builder.WriteString(cmd.Name)
+	builder.WriteString(cmd.FlatName())
 	builder.WriteString("\n")
 	builder.WriteString("description: >-\n")
 	wrapped := wrapDescription(cmd.Description, 72)
@@ -143,13 +257,15 @@ func buildCodexSkill(cmd config.SlashCommand) string {
 		builder.WriteString(line)
 		builder.WriteString("\n")
 	}
+	writeFrontMatterField(&builder, "allowed-tools", strings.Join(cmd.AllowedTools, " "))
 	builder.WriteString("---\n\n")
-	builder.WriteString(fmt.Sprintf(promptHeaderTemplate, cmd.Name))
+	builder.WriteString(fmt.Sprintf(promptHeaderTemplate, cmd.RelPath()))
 	builder.WriteString("\n# ")
-	builder.WriteString(cmd.Name)
+	builder.WriteString(cmd.FlatName())
 	builder.WriteString("\n\n")
 	builder.WriteString(cmd.Description)
 	builder.WriteString("\n\n")
+	writeArgumentList(&builder, cmd.Arguments)
 	if cmd.Body != "" {
 		builder.WriteString(cmd.Body)
 		if !strings.HasSuffix(cmd.Body, "\n") {
@@ -164,7 +280,7 @@ func buildAntigravitySkill(cmd config.SlashCommand) string {
 	var builder strings.Builder
 	builder.WriteString("---\n")
 	builder.WriteString("name: ")
-	builder.WriteString(cmd.Name)
+	builder.WriteString(cmd.FlatName())
 	builder.WriteString("\n")
 	builder.WriteString("description: >-\n")
 	wrapped := wrapDescription(cmd.Description, 72)
@@ -173,8 +289,13 @@ func buildAntigravitySkill(cmd config.SlashCommand) string {
 		builder.WriteString(line)
 		builder.WriteString("\n")
 	}
+	writeFrontMatterField(&builder, "allowed-tools", strings.Join(cmd.AllowedTools, " "))
 	builder.WriteString("---\n\n")
-	builder.WriteString(fmt.Sprintf(promptHeaderTemplate, cmd.Name))
+	builder.WriteString(fmt.Sprintf(promptHeaderTemplate, cmd.RelPath()))
+	if len(cmd.Arguments) > 0 {
+		builder.WriteString("\n")
+		writeArgumentList(&builder, cmd.Arguments)
+	}
 	if cmd.Body != "" {
 		builder.WriteString("\n")
 		builder.WriteString(cmd.Body)
@@ -185,6 +306,86 @@ func buildAntigravitySkill(cmd config.SlashCommand) string {
 	return builder.String()
 }
 
+// writeFrontMatterField writes "key: value" to YAML front matter, quoting value when needed. Empty values are skipped.
+func writeFrontMatterField(builder *strings.Builder, key string, value string) {
+	if value == "" {
+		return
+	}
+	builder.WriteString(key)
+	builder.WriteString(": ")
+	builder.WriteString(yamlScalar(value))
+	builder.WriteString("\n")
+}
+
+// yamlScalar returns value as a single-line YAML scalar, quoted when a plain scalar would change its meaning.
+func yamlScalar(value string) string {
+	data, err := yaml.Marshal(value)
+	out := strings.TrimSuffix(string(data), "\n")
+	if err != nil || strings.Contains(out, "\n") {
+		return strconv.Quote(value)
+	}
+	return out
+}
+
+// yamlFlowList returns values as a YAML flow sequence of quoted strings.
+func yamlFlowList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = strconv.Quote(value)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// writeArgumentList lists a command's arguments, which bodies rendered with config.ArgumentsText reference as <name>.
+func writeArgumentList(builder *strings.Builder, args []config.SlashCommandArgument) {
+	if len(args) == 0 {
+		return
+	}
+	builder.WriteString("## Arguments\n\n")
+	for _, arg := range args {
+		builder.WriteString("- `<")
+		builder.WriteString(arg.Name)
+		builder.WriteString(">`")
+		switch {
+		case arg.Required:
+			builder.WriteString(" (required)")
+		case arg.Default != "":
+			builder.WriteString(" (default: `")
+			builder.WriteString(arg.Default)
+			builder.WriteString("`)")
+		default:
+			builder.WriteString(" (optional)")
+		}
+		if arg.Description != "" {
+			builder.WriteString(": ")
+			builder.WriteString(arg.Description)
+		}
+		builder.WriteString("\n")
+	}
+	builder.WriteString("\n")
+}
+
+// hasArgumentDefaults reports whether any of args has a default.
+func hasArgumentDefaults(args []config.SlashCommandArgument) bool {
+	for _, arg := range args {
+		if arg.Default != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// writeArgumentDefaults lists the defaults of args by the placeholder style writes for them.
+func writeArgumentDefaults(builder *strings.Builder, args []config.SlashCommandArgument, style config.ArgumentStyle) {
+	builder.WriteString("## Argument defaults\n\n")
+	for i, arg := range args {
+		if arg.Default == "" {
+			continue
+		}
+		fmt.Fprintf(builder, "- `%s` (`%s`): use `%s` when it is empty\n", style.Placeholder(args, i), arg.Name, arg.Default)
+	}
+}
+
 func wrapDescription(text string, width int) []string {
 	if width <= 0 {
 		return []string{text}
@@ -258,3 +459,50 @@ func hasGeneratedMarker(sys System, path string) (bool, error) {
 	}
 	return strings.Contains(string(data), "GENERATED FILE"), nil
 }
+
+// removeStaleGeneratedTree removes generated files ending in suffix anywhere under dir whose relative
+// path is not in wanted, then namespace directories left empty by the removal. Hand-written files are kept.
+func removeStaleGeneratedTree(sys System, dir string, suffix string, wanted map[string]struct{}) error {
+	var stale []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf(messages.SyncReadFailedFmt, path, err)
+		}
+		if entry.IsDir() || !strings.HasSuffix(path, suffix) {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if _, ok := wanted[rel]; ok {
+			return nil
+		}
+		isGenerated, err := hasGeneratedMarker(sys, path)
+		if err != nil {
+			return err
+		}
+		if isGenerated {
+			stale = append(stale, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, path := range stale {
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf(messages.SyncRemoveFailedFmt, path, err)
+		}
+		for parent := filepath.Dir(path); parent != dir; parent = filepath.Dir(parent) {
+			if entries, err := os.ReadDir(parent); err != nil || len(entries) > 0 {
+				break
+			}
+			if err := os.Remove(parent); err != nil {
+				return fmt.Errorf(messages.SyncRemoveFailedFmt, parent, err)
+			}
+		}
+	}
+	return nil
+}
@@ -1,11 +1,15 @@
 package sync
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
+	"gopkg.in/yaml.v3"
+
 	"github.com/conn-castle/agent-layer/internal/config"
 )
 
@@ -27,7 +31,7 @@ func TestWriteVSCodePromptsError(t *testing.T) {
 	if err := os.WriteFile(file, []byte("x"), 0o644); err != nil {
 		t.Fatalf("write file: %v", err)
 	}
-	err := WriteVSCodePrompts(RealSystem{}, file, nil)
+	err := WriteVSCodePrompts(RealSystem{}, file, &config.ProjectConfig{})
 	if err == nil {
 		t.Fatalf("expected error")
 	}
@@ -44,7 +48,7 @@ func TestWriteVSCodePromptsWriteError(t *testing.T) {
 		t.Fatalf("mkdir prompt: %v", err)
 	}
 	cmds := []config.SlashCommand{{Name: "alpha", Body: "Body"}}
-	if err := WriteVSCodePrompts(RealSystem{}, root, cmds); err == nil {
+	if err := WriteVSCodePrompts(RealSystem{}, root, &config.ProjectConfig{SlashCommands: cmds}); err == nil {
 		t.Fatalf("expected error")
 	}
 }
@@ -134,7 +138,7 @@ func TestWriteCodexSkillsError(t *testing.T) {
 	if err := os.WriteFile(file, []byte("x"), 0o644); err != nil {
 		t.Fatalf("write file: %v", err)
 	}
-	err := WriteCodexSkills(RealSystem{}, file, nil)
+	err := WriteCodexSkills(RealSystem{}, file, &config.ProjectConfig{})
 	if err == nil {
 		t.Fatalf("expected error")
 	}
@@ -151,11 +155,252 @@ func TestWriteCodexSkillsWriteError(t *testing.T) {
 		t.Fatalf("mkdir SKILL.md: %v", err)
 	}
 	cmds := []config.SlashCommand{{Name: "alpha", Description: "desc", Body: "Body"}}
-	if err := WriteCodexSkills(RealSystem{}, root, cmds); err == nil {
+	if err := WriteCodexSkills(RealSystem{}, root, &config.ProjectConfig{SlashCommands: cmds}); err == nil {
 		t.Fatalf("expected error")
 	}
 }
 
+func TestWriteSlashCommandsFilterByClient(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	cmds := []config.SlashCommand{
+		{Name: "shared", Description: "desc", Body: "Body"},
+		{Name: "codex-only", Description: "desc", Body: "Body", Clients: []string{"codex"}},
+		{Name: "editors", Description: "desc", Body: "Body", Clients: []string{"vscode", "antigravity"}},
+	}
+	if err := WriteCodexSkills(RealSystem{}, root, &config.ProjectConfig{SlashCommands: cmds}); err != nil {
+		t.Fatalf("WriteCodexSkills error: %v", err)
+	}
+	if err := WriteVSCodePrompts(RealSystem{}, root, &config.ProjectConfig{SlashCommands: cmds}); err != nil {
+		t.Fatalf("WriteVSCodePrompts error: %v", err)
+	}
+	if err := WriteAntigravitySkills(RealSystem{}, root, &config.ProjectConfig{SlashCommands: cmds}); err != nil {
+		t.Fatalf("WriteAntigravitySkills error: %v", err)
+	}
+
+	expect := map[string]bool{
+		".codex/skills/shared/SKILL.md":        true,
+		".codex/skills/codex-only/SKILL.md":    true,
+		".codex/skills/editors/SKILL.md":       false,
+		".vscode/prompts/shared.prompt.md":     true,
+		".vscode/prompts/codex-only.prompt.md": false,
+		".vscode/prompts/editors.prompt.md":    true,
+		".agent/skills/codex-only/SKILL.md":    false,
+		".agent/skills/editors/SKILL.md":       true,
+	}
+	for rel, exists := range expect {
+		_, err := os.Stat(filepath.Join(root, filepath.FromSlash(rel)))
+		if exists && err != nil {
+			t.Fatalf("expected %s: %v", rel, err)
+		}
+		if !exists && !os.IsNotExist(err) {
+			t.Fatalf("expected %s to be skipped, got %v", rel, err)
+		}
+	}
+}
+
+func TestWriteSlashCommandArguments(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	project := &config.ProjectConfig{
+		Config: config.Config{Agents: config.AgentsConfig{Claude: config.ClaudeConfig{SlashCommands: config.SlashCommandsNative}}},
+		SlashCommands: []config.SlashCommand{{
+			Name:        "fix",
+			Description: "Fix an issue",
+			Body:        `Fix {{ arg "issue" }} on {{ arg "branch" }}.`,
+			Template:    true,
+			Arguments: []config.SlashCommandArgument{
+				{Name: "issue", Description: "Issue number", Required: true},
+				{Name: "branch", Default: "main"},
+			},
+		}},
+	}
+	if err := WriteClaudeCommands(RealSystem{}, root, project); err != nil {
+		t.Fatalf("WriteClaudeCommands error: %v", err)
+	}
+	if err := WriteVSCodePrompts(RealSystem{}, root, project); err != nil {
+		t.Fatalf("WriteVSCodePrompts error: %v", err)
+	}
+	if err := WriteCodexSkills(RealSystem{}, root, project); err != nil {
+		t.Fatalf("WriteCodexSkills error: %v", err)
+	}
+	if err := WriteAntigravitySkills(RealSystem{}, root, project); err != nil {
+		t.Fatalf("WriteAntigravitySkills error: %v", err)
+	}
+
+	expect := map[string][]string{
+		".claude/commands/fix.md":       {"argument-hint: <issue> [branch]\n", "- `$2` (`branch`): use `main` when it is empty\n", "Fix $1 on $2."},
+		".vscode/prompts/fix.prompt.md": {"argument-hint: <issue> [branch]\n", "Fix ${input:issue} on ${input:branch}."},
+		".codex/skills/fix/SKILL.md":    {"- `<issue>` (required): Issue number\n- `<branch>` (default: `main`)\n", "Fix <issue> on <branch>."},
+		".agent/skills/fix/SKILL.md":    {"## Arguments\n", "Fix <issue> on <branch>."},
+	}
+	for rel, parts := range expect {
+		data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(rel)))
+		if err != nil {
+			t.Fatalf("read %s: %v", rel, err)
+		}
+		for _, part := range parts {
+			if !strings.Contains(string(data), part) {
+				t.Fatalf("expected %s to contain %q, got:\n%s", rel, part, data)
+			}
+		}
+	}
+}
+
+func TestSlashCommandFrontMatterFields(t *testing.T) {
+	cmd := config.SlashCommand{
+		Name:                   "review",
+		Description:            "Review a change",
+		Body:                   "Review.",
+		Model:                  "claude-sonnet-4-5",
+		AllowedTools:           []string{"Bash(git diff:*)", "Read"},
+		ArgumentHint:           "[branch]",
+		Agent:                  "agent",
+		Tools:                  []string{"search", "githubRepo"},
+		DisableModelInvocation: true,
+	}
+	cases := map[string]struct {
+		content string
+		want    map[string]any
+	}{
+		"vscode": {buildVSCodePrompt(cmd), map[string]any{
+			"argument-hint": "[branch]",
+			"agent":         "agent",
+			"model":         "claude-sonnet-4-5",
+			"tools":         []any{"search", "githubRepo"},
+		}},
+		"claude": {buildClaudeCommand(cmd), map[string]any{
+			"argument-hint":            "[branch]",
+			"model":                    "claude-sonnet-4-5",
+			"allowed-tools":            "Bash(git diff:*), Read",
+			"disable-model-invocation": true,
+		}},
+		"codex":       {buildCodexSkill(cmd), map[string]any{"allowed-tools": "Bash(git diff:*) Read"}},
+		"antigravity": {buildAntigravitySkill(cmd), map[string]any{"allowed-tools": "Bash(git diff:*) Read"}},
+	}
+	for name, tc := range cases {
+		parts := strings.SplitN(tc.content, "---\n", 3)
+		if len(parts) != 3 {
+			t.Fatalf("%s: expected front matter, got:\n%s", name, tc.content)
+		}
+		var fields map[string]any
+		if err := yaml.Unmarshal([]byte(parts[1]), &fields); err != nil {
+			t.Fatalf("%s: front matter is not valid YAML: %v\n%s", name, err, parts[1])
+		}
+		for key, want := range tc.want {
+			if !reflect.DeepEqual(fields[key], want) {
+				t.Fatalf("%s: %s = %#v, want %#v", name, key, fields[key], want)
+			}
+		}
+	}
+}
+
+func TestWriteClaudeCommands(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	commandsDir := filepath.Join(root, ".claude", "commands")
+	if err := os.MkdirAll(commandsDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	manual := filepath.Join(commandsDir, "manual.md")
+	stale := filepath.Join(commandsDir, "stale.md")
+	if err := os.WriteFile(manual, []byte("hand-written"), 0o644); err != nil {
+		t.Fatalf("write manual: %v", err)
+	}
+	if err := os.WriteFile(stale, []byte("<!-- GENERATED FILE -->"), 0o644); err != nil {
+		t.Fatalf("write stale: %v", err)
+	}
+
+	project := &config.ProjectConfig{
+		Config: config.Config{Agents: config.AgentsConfig{Claude: config.ClaudeConfig{SlashCommands: config.SlashCommandsNative}}},
+		SlashCommands: []config.SlashCommand{
+			{Name: "fix", Description: "Fix an issue", Body: `Fix {{ arg "issue" }}.`, Template: true, Arguments: []config.SlashCommandArgument{{Name: "issue", Required: true}}},
+			{Name: "vscode-only", Description: "desc", Body: "Body", Clients: []string{"vscode"}},
+		},
+	}
+	if err := WriteClaudeCommands(RealSystem{}, root, project); err != nil {
+		t.Fatalf("WriteClaudeCommands error: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(commandsDir, "fix.md"))
+	if err != nil {
+		t.Fatalf("read fix.md: %v", err)
+	}
+	want := "---\ndescription: >-\n  Fix an issue\nargument-hint: <issue>\n---\n" + fmt.Sprintf(promptHeaderTemplate, "fix") + "\nFix $ARGUMENTS.\n"
+	if string(data) != want {
+		t.Fatalf("unexpected command file:\n%s", data)
+	}
+	for path, exists := range map[string]bool{manual: true, stale: false, filepath.Join(commandsDir, "vscode-only.md"): false} {
+		if _, err := os.Stat(path); exists != (err == nil) {
+			t.Fatalf("%s: expected exists=%v, got %v", path, exists, err)
+		}
+	}
+
+	project.Config.Agents.Claude.SlashCommands = config.SlashCommandsMCP
+	if err := WriteClaudeCommands(RealSystem{}, root, project); err != nil {
+		t.Fatalf("WriteClaudeCommands mcp error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(commandsDir, "fix.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected generated command to be removed, got %v", err)
+	}
+	if _, err := os.Stat(manual); err != nil {
+		t.Fatalf("expected manual command to remain: %v", err)
+	}
+
+	// Without native commands and no directory, nothing is created.
+	other := t.TempDir()
+	if err := WriteClaudeCommands(RealSystem{}, other, project); err != nil {
+		t.Fatalf("WriteClaudeCommands empty error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(other, ".claude")); !os.IsNotExist(err) {
+		t.Fatalf("expected no .claude directory, got %v", err)
+	}
+}
+
+func TestWriteNamespacedSlashCommands(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	project := &config.ProjectConfig{
+		Config:        config.Config{Agents: config.AgentsConfig{Claude: config.ClaudeConfig{SlashCommands: config.SlashCommandsNative}}},
+		SlashCommands: []config.SlashCommand{{Name: "git:commit", Description: "Commit", Body: "Commit."}},
+	}
+	for name, write := range map[string]func(System, string, *config.ProjectConfig) error{
+		"vscode":      WriteVSCodePrompts,
+		"codex":       WriteCodexSkills,
+		"antigravity": WriteAntigravitySkills,
+		"claude":      WriteClaudeCommands,
+		"gemini":      WriteGeminiCommands,
+	} {
+		if err := write(RealSystem{}, root, project); err != nil {
+			t.Fatalf("%s: write error: %v", name, err)
+		}
+	}
+
+	expect := map[string]string{
+		".vscode/prompts/git-commit.prompt.md": "name: git-commit\n",
+		".codex/skills/git-commit/SKILL.md":    "name: git-commit\n",
+		".agent/skills/git-commit/SKILL.md":    "name: git-commit\n",
+		".claude/commands/git/commit.md":       "Source: .agent-layer/slash-commands/git/commit.md",
+		".gemini/commands/git/commit.toml":     "Source: .agent-layer/slash-commands/git/commit.md",
+	}
+	for rel, part := range expect {
+		data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(rel)))
+		if err != nil {
+			t.Fatalf("read %s: %v", rel, err)
+		}
+		if !strings.Contains(string(data), part) {
+			t.Fatalf("expected %s to contain %q, got:\n%s", rel, part, data)
+		}
+	}
+
+	project.SlashCommands = nil
+	if err := WriteClaudeCommands(RealSystem{}, root, project); err != nil {
+		t.Fatalf("WriteClaudeCommands cleanup error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".claude", "commands", "git")); !os.IsNotExist(err) {
+		t.Fatalf("expected emptied namespace directory to be removed, got %v", err)
+	}
+}
+
 func TestWriteAntigravitySkillsError(t *testing.T) {
 	t.Parallel()
 	root := t.TempDir()
@@ -163,7 +408,7 @@ func TestWriteAntigravitySkillsError(t *testing.T) {
 	if err := os.WriteFile(file, []byte("x"), 0o644); err != nil {
 		t.Fatalf("write file: %v", err)
 	}
-	err := WriteAntigravitySkills(RealSystem{}, file, nil)
+	err := WriteAntigravitySkills(RealSystem{}, file, &config.ProjectConfig{})
 	if err == nil {
 		t.Fatalf("expected error")
 	}
@@ -180,7 +425,7 @@ func TestWriteAntigravitySkillsWriteError(t *testing.T) {
 		t.Fatalf("mkdir SKILL.md: %v", err)
 	}
 	cmds := []config.SlashCommand{{Name: "alpha", Description: "desc", Body: "Body"}}
-	if err := WriteAntigravitySkills(RealSystem{}, root, cmds); err == nil {
+	if err := WriteAntigravitySkills(RealSystem{}, root, &config.ProjectConfig{SlashCommands: cmds}); err == nil {
 		t.Fatalf("expected error")
 	}
 }
@@ -196,7 +441,7 @@ func TestWriteAntigravitySkillsMkdirSkillDirError(t *testing.T) {
 		t.Fatalf("write file: %v", err)
 	}
 	cmds := []config.SlashCommand{{Name: "alpha", Description: "desc", Body: "Body"}}
-	err := WriteAntigravitySkills(RealSystem{}, root, cmds)
+	err := WriteAntigravitySkills(RealSystem{}, root, &config.ProjectConfig{SlashCommands: cmds})
 	if err == nil {
 		t.Fatalf("expected error for skill dir creation failure")
 	}
@@ -217,7 +462,7 @@ func TestWriteCodexSkillsMkdirSkillDirError(t *testing.T) {
 		t.Fatalf("write file: %v", err)
 	}
 	cmds := []config.SlashCommand{{Name: "alpha", Description: "desc", Body: "Body"}}
-	err := WriteCodexSkills(RealSystem{}, root, cmds)
+	err := WriteCodexSkills(RealSystem{}, root, &config.ProjectConfig{SlashCommands: cmds})
 	if err == nil {
 		t.Fatalf("expected error for skill dir creation failure")
 	}
@@ -8,36 +8,66 @@ import (
 	"github.com/conn-castle/agent-layer/internal/warnings"
 )
 
+// Options controls a sync run.
+type Options struct {
+	// Load resolves the project config (for example, a profile).
+	Load config.LoadOptions
+	// Force overwrites existing outputs that Agent Layer did not generate.
+	Force bool
+}
+
 // Run regenerates all configured outputs for the repo.
 // Returns any sync-time warnings and an error if sync failed.
 func Run(root string) ([]warnings.Warning, error) {
-	project, err := config.LoadProjectConfig(root)
+	return RunWithOptions(root, Options{})
+}
+
+// RunWithOptions is like Run but resolves the project config with opts.Load and honors opts.Force.
+func RunWithOptions(root string, opts Options) ([]warnings.Warning, error) {
+	project, err := config.LoadProjectConfigWithOptions(root, opts.Load)
 	if err != nil {
 		return nil, err
 	}
 
-	return RunWithProject(RealSystem{}, root, project)
+	return runProject(RealSystem{}, root, project, opts.Force)
 }
 
 // RunWithProject regenerates outputs using an already loaded project config.
+// It refuses to overwrite files that Agent Layer did not generate; see UserFiles.
 // Returns any sync-time warnings and an error if sync failed.
 func RunWithProject(sys System, root string, project *config.ProjectConfig) ([]warnings.Warning, error) {
+	return runProject(sys, root, project, false)
+}
+
+func runProject(sys System, root string, project *config.ProjectConfig, force bool) ([]warnings.Warning, error) {
+	if !force {
+		if err := checkUserFiles(sys, root, project); err != nil {
+			return nil, err
+		}
+	}
+
 	steps := []func() error{
 		func() error {
-			return WriteInstructionShims(sys, root, project.Instructions)
+			return WriteInstructionShims(sys, root, project)
+		},
+		func() error {
+			return WriteNestedInstructionShims(sys, root, project)
+		},
+		func() error {
+			return WriteCopilotScopedInstructions(sys, root, project)
 		},
 	}
 
 	if project.Config.Agents.Codex.Enabled != nil && *project.Config.Agents.Codex.Enabled {
 		steps = append(steps,
-			func() error { return WriteCodexInstructions(sys, root, project.Instructions) },
-			func() error { return WriteCodexSkills(sys, root, project.SlashCommands) },
+			func() error { return WriteCodexInstructions(sys, root, project) },
+			func() error { return WriteCodexSkills(sys, root, project) },
 		)
 	}
 
 	if project.Config.Agents.VSCode.Enabled != nil && *project.Config.Agents.VSCode.Enabled {
 		steps = append(steps,
-			func() error { return WriteVSCodePrompts(sys, root, project.SlashCommands) },
+			func() error { return WriteVSCodePrompts(sys, root, project) },
 			func() error { return WriteVSCodeSettings(sys, root, project) },
 			func() error { return WriteVSCodeMCPConfig(sys, root, project) },
 			func() error { return WriteVSCodeLaunchers(sys, root) },
@@ -45,17 +75,21 @@ func RunWithProject(sys System, root string, project *config.ProjectConfig) ([]w
 	}
 
 	if project.Config.Agents.Antigravity.Enabled != nil && *project.Config.Agents.Antigravity.Enabled {
-		steps = append(steps, func() error { return WriteAntigravitySkills(sys, root, project.SlashCommands) })
+		steps = append(steps, func() error { return WriteAntigravitySkills(sys, root, project) })
 	}
 
 	if project.Config.Agents.Gemini.Enabled != nil && *project.Config.Agents.Gemini.Enabled {
-		steps = append(steps, func() error { return WriteGeminiSettings(sys, root, project) })
+		steps = append(steps,
+			func() error { return WriteGeminiSettings(sys, root, project) },
+			func() error { return WriteGeminiCommands(sys, root, project) },
+		)
 	}
 
 	if project.Config.Agents.Claude.Enabled != nil && *project.Config.Agents.Claude.Enabled {
 		steps = append(steps,
 			func() error { return WriteClaudeSettings(sys, root, project) },
 			func() error { return WriteMCPConfig(sys, root, project) },
+			func() error { return WriteClaudeCommands(sys, root, project) },
 		)
 	}
 
@@ -76,8 +110,14 @@ func RunWithProject(sys System, root string, project *config.ProjectConfig) ([]w
 
 // collectWarnings gathers all sync-time warnings based on the project config.
 func collectWarnings(project *config.ProjectConfig) ([]warnings.Warning, error) {
-	// Only check instructions size per spec for sync
-	return warnings.CheckInstructions(project.Root, project.Config.Warnings.InstructionTokenThreshold)
+	// Sync checks instruction size, tokenizers, and instruction and slash command front matter; MCP discovery is left to doctor.
+	list, err := warnings.CheckClientInstructions(project)
+	if err != nil {
+		return nil, err
+	}
+	list = append(list, warnings.CheckTokenizers(project)...)
+	list = append(list, warnings.CheckInstructionFrontMatter(project)...)
+	return append(list, warnings.CheckSlashCommands(project)...), nil
 }
 
 func runSteps(steps []func() error) error {
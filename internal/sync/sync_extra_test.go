@@ -33,6 +33,23 @@ func TestRunMissingConfig(t *testing.T) {
 	}
 }
 
+func TestRunWithOptionsUnknownProfile(t *testing.T) {
+	root := t.TempDir()
+	paths := config.DefaultPaths(root)
+	if err := os.MkdirAll(filepath.Dir(paths.ConfigPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	cfg := "[approvals]\nmode = \"all\"\n[agents.gemini]\nenabled = false\n[agents.claude]\nenabled = false\n" +
+		"[agents.codex]\nenabled = false\n[agents.vscode]\nenabled = false\n[agents.antigravity]\nenabled = false\n"
+	if err := os.WriteFile(paths.ConfigPath, []byte(cfg), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	_, err := RunWithOptions(root, Options{Load: config.LoadOptions{Profile: "missing"}})
+	if err == nil {
+		t.Fatalf("expected unknown profile error")
+	}
+}
+
 func TestRunWithProjectError(t *testing.T) {
 	t.Parallel()
 	root := t.TempDir()
@@ -44,7 +61,7 @@ func TestRunWithProjectError(t *testing.T) {
 	project := &config.ProjectConfig{
 		Config: config.Config{
 			Agents: config.AgentsConfig{
-				Gemini: config.AgentConfig{Enabled: boolPtr(true)},
+				Gemini: config.GeminiConfig{Enabled: boolPtr(true)},
 			},
 		},
 		Instructions: []config.InstructionFile{{Name: "00_base.md", Content: "base"}},
@@ -24,7 +24,7 @@ type vscodeMCPServer struct {
 
 // WriteVSCodeMCPConfig generates .vscode/mcp.json.
 func WriteVSCodeMCPConfig(sys System, root string, project *config.ProjectConfig) error {
-	cfg, err := buildVSCodeMCPConfig(project)
+	cfg, err := buildVSCodeMCPConfig(sys, project)
 	if err != nil {
 		return err
 	}
@@ -48,15 +48,30 @@ func WriteVSCodeMCPConfig(sys System, root string, project *config.ProjectConfig
 	return nil
 }
 
-func buildVSCodeMCPConfig(project *config.ProjectConfig) (*vscodeMCPConfig, error) {
+func buildVSCodeMCPConfig(sys System, project *config.ProjectConfig) (*vscodeMCPConfig, error) {
 	cfg := &vscodeMCPConfig{
 		Servers: make(OrderedMap[vscodeMCPServer]),
 	}
 
+	// VS Code reads slash commands from prompt files; the internal server is only added to serve
+	// them as tools in agent mode.
+	if project.Config.Agents.VSCode.SlashCommandTools {
+		prompt, err := resolvePromptServer(sys, project, "vscode")
+		if err != nil {
+			return nil, err
+		}
+		entry := vscodeMCPServer{Type: "stdio", Command: prompt.Command, Args: append(prompt.Args, commandToolsFlag)}
+		if prompt.URL != "" {
+			// The HTTP server reads slash_command_tools itself.
+			entry = vscodeMCPServer{Type: "http", URL: prompt.URL, Headers: prompt.headers("${env:%s}")}
+		}
+		cfg.Servers["agent-layer"] = entry
+	}
+
 	// Transform to VS Code env syntax - VS Code resolves ${env:VAR} at runtime.
 	resolved, err := projection.ResolveMCPServers(
 		project.Config.MCP.Servers,
-		project.Env,
+		project.PlaceholderEnv(),
 		"vscode",
 		projection.ClientPlaceholderResolver("${env:%s}"),
 	)
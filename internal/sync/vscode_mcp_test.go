@@ -4,6 +4,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/conn-castle/agent-layer/internal/config"
@@ -28,7 +29,7 @@ func TestBuildVSCodeMCPConfig(t *testing.T) {
 		Env: map[string]string{"TOKEN": "abc"},
 	}
 
-	cfg, err := buildVSCodeMCPConfig(project)
+	cfg, err := buildVSCodeMCPConfig(alOnPathSystem(), project)
 	if err != nil {
 		t.Fatalf("buildVSCodeMCPConfig error: %v", err)
 	}
@@ -45,6 +46,42 @@ func TestBuildVSCodeMCPConfig(t *testing.T) {
 	}
 }
 
+func TestBuildVSCodeMCPConfigCommandTools(t *testing.T) {
+	t.Parallel()
+	project := &config.ProjectConfig{}
+	cfg, err := buildVSCodeMCPConfig(&MockSystem{}, project)
+	if err != nil {
+		t.Fatalf("buildVSCodeMCPConfig error: %v", err)
+	}
+	if _, ok := cfg.Servers["agent-layer"]; ok {
+		t.Fatalf("expected no agent-layer server by default")
+	}
+
+	project.Config.Agents.VSCode.SlashCommandTools = true
+	cfg, err = buildVSCodeMCPConfig(alOnPathSystem(), project)
+	if err != nil {
+		t.Fatalf("buildVSCodeMCPConfig error: %v", err)
+	}
+	server := cfg.Servers["agent-layer"]
+	if server.Type != "stdio" || server.Command != "al" || strings.Join(server.Args, " ") != "mcp-prompts --client vscode --command-tools" {
+		t.Fatalf("unexpected agent-layer server %+v", server)
+	}
+	if _, err := buildVSCodeMCPConfig(&MockSystem{}, project); err == nil {
+		t.Fatalf("expected error when the prompt server cannot be resolved")
+	}
+
+	project.Config.MCP.PromptServer.HTTP = "127.0.0.1:7420"
+	project.Env = map[string]string{config.PromptServerTokenEnvVar: "secret"}
+	cfg, err = buildVSCodeMCPConfig(&MockSystem{}, project)
+	if err != nil {
+		t.Fatalf("buildVSCodeMCPConfig error: %v", err)
+	}
+	server = cfg.Servers["agent-layer"]
+	if server.Type != "http" || server.URL != "http://127.0.0.1:7420/mcp/vscode" || len(server.Args) != 0 || server.Headers["Authorization"] != "Bearer ${env:AL_PROMPT_SERVER_TOKEN}" {
+		t.Fatalf("unexpected HTTP agent-layer server %+v", server)
+	}
+}
+
 func TestBuildVSCodeMCPConfigHeadersAndEnv(t *testing.T) {
 	t.Parallel()
 	enabled := true
@@ -73,7 +110,7 @@ func TestBuildVSCodeMCPConfigHeadersAndEnv(t *testing.T) {
 		Env: map[string]string{"TOKEN": "abc", "KEY": "123"},
 	}
 
-	cfg, err := buildVSCodeMCPConfig(project)
+	cfg, err := buildVSCodeMCPConfig(alOnPathSystem(), project)
 	if err != nil {
 		t.Fatalf("buildVSCodeMCPConfig error: %v", err)
 	}
@@ -189,7 +226,7 @@ func TestBuildVSCodeMCPConfigMissingEnv(t *testing.T) {
 		Env: map[string]string{},
 	}
 
-	_, err := buildVSCodeMCPConfig(project)
+	_, err := buildVSCodeMCPConfig(alOnPathSystem(), project)
 	if err == nil {
 		t.Fatalf("expected error")
 	}
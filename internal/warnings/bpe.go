@@ -0,0 +1,142 @@
+package warnings
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// whitespaceClass is the body of a character class matching Unicode whitespace, like \s in the
+// tiktoken patterns (Go's \s is ASCII only).
+const whitespaceClass = `\t\n\v\f\r \x{85}\p{Z}`
+
+// bpePatterns are the pre-tokenization patterns of the supported vocabularies, translated for RE2.
+// RE2 has no lookahead, so the trailing `\s+(?!\S)|\s+` is a single capturing `(\s+)` group and
+// splitPieces gives its last whitespace rune back to the following piece, as the lookahead would.
+var bpePatterns = map[string]*regexp.Regexp{
+	"cl100k_base": regexp.MustCompile(withWhitespace(`(?i:'s|'t|'re|'ve|'m|'ll|'d)|[^\r\n\p{L}\p{N}]?\p{L}+|\p{N}{1,3}| ?[^WS\p{L}\p{N}]+[\r\n]*|[WS]*[\r\n]+|([WS]+)`)),
+	"o200k_base": regexp.MustCompile(withWhitespace(`[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]*[\p{Ll}\p{Lm}\p{Lo}\p{M}]+(?i:'s|'t|'re|'ve|'m|'ll|'d)?` +
+		`|[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]+[\p{Ll}\p{Lm}\p{Lo}\p{M}]*(?i:'s|'t|'re|'ve|'m|'ll|'d)?` +
+		`|\p{N}{1,3}| ?[^WS\p{L}\p{N}]+[\r\n/]*|[WS]*[\r\n]+|([WS]+)`)),
+}
+
+func withWhitespace(pattern string) string {
+	return strings.ReplaceAll(pattern, "WS", whitespaceClass)
+}
+
+// BPE is a byte-pair-encoding Tokenizer backed by a tiktoken rank file.
+type BPE struct {
+	name    string
+	pattern *regexp.Regexp
+	ranks   map[string]int
+}
+
+// LoadBPE reads a tiktoken vocabulary ("<base64 token> <rank>" per line) for the named encoding.
+func LoadBPE(name string, r io.Reader) (*BPE, error) {
+	pattern, ok := bpePatterns[name]
+	if !ok {
+		return nil, fmt.Errorf(messages.WarningsTokenizerUnknownFmt, name)
+	}
+	ranks := make(map[string]int)
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		encoded, rankText, ok := strings.Cut(text, " ")
+		if !ok {
+			return nil, fmt.Errorf(messages.WarningsTokenizerInvalidLineFmt, line)
+		}
+		token, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf(messages.WarningsTokenizerInvalidLineFmt, line)
+		}
+		rank, err := strconv.Atoi(rankText)
+		if err != nil {
+			return nil, fmt.Errorf(messages.WarningsTokenizerInvalidLineFmt, line)
+		}
+		ranks[string(token)] = rank
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(ranks) == 0 {
+		return nil, fmt.Errorf(messages.WarningsTokenizerEmpty)
+	}
+	return &BPE{name: name, pattern: pattern, ranks: ranks}, nil
+}
+
+// Name returns the encoding name, such as o200k_base.
+func (b *BPE) Name() string { return b.name }
+
+// CountTokens returns the number of tokens text encodes to.
+func (b *BPE) CountTokens(text string) int {
+	count := 0
+	for _, piece := range b.splitPieces(text) {
+		count += b.countPiece(piece)
+	}
+	return count
+}
+
+// splitPieces pre-tokenizes text with the encoding's pattern.
+func (b *BPE) splitPieces(text string) []string {
+	var pieces []string
+	for pos := 0; pos < len(text); {
+		loc := b.pattern.FindStringSubmatchIndex(text[pos:])
+		if loc == nil || loc[1] == 0 {
+			// Unreachable for valid UTF-8; consume a rune so malformed input cannot loop.
+			_, size := utf8.DecodeRuneInString(text[pos:])
+			pieces = append(pieces, text[pos:pos+size])
+			pos += size
+			continue
+		}
+		if loc[0] > 0 {
+			pieces = append(pieces, text[pos:pos+loc[0]])
+		}
+		start, end := pos+loc[0], pos+loc[1]
+		if loc[2] >= 0 && end < len(text) {
+			// \s+(?!\S): leave the last whitespace rune to prefix the next piece.
+			if _, size := utf8.DecodeLastRuneInString(text[start:end]); end-size > start {
+				end -= size
+			}
+		}
+		pieces = append(pieces, text[start:end])
+		pos = end
+	}
+	return pieces
+}
+
+// countPiece applies the lowest-rank-first merges to piece and returns the resulting token count.
+// Bytes missing from the vocabulary count as one token each.
+func (b *BPE) countPiece(piece string) int {
+	if _, ok := b.ranks[piece]; ok {
+		return 1
+	}
+	// bounds[i] is the start of part i; the last entry is len(piece).
+	bounds := make([]int, len(piece)+1)
+	for i := range bounds {
+		bounds[i] = i
+	}
+	for len(bounds) > 2 {
+		best, bestRank := -1, 0
+		for i := 0; i+2 < len(bounds); i++ {
+			rank, ok := b.ranks[piece[bounds[i]:bounds[i+2]]]
+			if ok && (best < 0 || rank < bestRank) {
+				best, bestRank = i, rank
+			}
+		}
+		if best < 0 {
+			break
+		}
+		bounds = append(bounds[:best+1], bounds[best+2:]...)
+	}
+	return len(bounds) - 1
+}
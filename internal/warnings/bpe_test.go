@@ -0,0 +1,66 @@
+package warnings
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testVocabulary returns a tiktoken rank file with every byte plus the given merges, in rank order.
+func testVocabulary(merges ...string) string {
+	var sb strings.Builder
+	for i := 0; i < 256; i++ {
+		fmt.Fprintf(&sb, "%s %d\n", base64.StdEncoding.EncodeToString([]byte{byte(i)}), i)
+	}
+	for i, merge := range merges {
+		fmt.Fprintf(&sb, "%s %d\n", base64.StdEncoding.EncodeToString([]byte(merge)), 256+i)
+	}
+	return sb.String()
+}
+
+func TestBPESplitPieces(t *testing.T) {
+	cl100k, err := LoadBPE("cl100k_base", strings.NewReader(testVocabulary()))
+	require.NoError(t, err)
+	assert.Equal(t,
+		[]string{"hello", " ", " world", "\n\n", "foo", "123", "456", "'s", " (", "x", "  "},
+		cl100k.splitPieces("hello  world\n\nfoo123456's (x  "))
+	assert.Equal(t, []string{" ", " x"}, cl100k.splitPieces("  x"))
+
+	o200k, err := LoadBPE("o200k_base", strings.NewReader(testVocabulary()))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Hello", "World's", " path", "/\n"}, o200k.splitPieces("HelloWorld's path/\n"))
+}
+
+func TestBPECountTokens(t *testing.T) {
+	tok, err := LoadBPE("cl100k_base", strings.NewReader(testVocabulary("ll", "he", "hell", "hello", " w", "or")))
+	require.NoError(t, err)
+	assert.Equal(t, "cl100k_base", tok.Name())
+
+	// "hello" is one token; " world" merges to " w" + "or" + "l" + "d".
+	assert.Equal(t, 1, tok.CountTokens("hello"))
+	assert.Equal(t, 5, tok.CountTokens("hello world"))
+	assert.Equal(t, 0, tok.CountTokens(""))
+	// Bytes of a multi-byte rune stay separate without merges.
+	assert.Equal(t, 3, tok.CountTokens("世"))
+}
+
+func TestLoadBPEErrors(t *testing.T) {
+	_, err := LoadBPE("gpt2", strings.NewReader(testVocabulary()))
+	assert.ErrorContains(t, err, `unknown tokenizer "gpt2"`)
+
+	_, err = LoadBPE("cl100k_base", strings.NewReader("aGVsbG8=\n"))
+	assert.ErrorContains(t, err, "line 1")
+
+	_, err = LoadBPE("cl100k_base", strings.NewReader("YQ== 0\n!!! 1\n"))
+	assert.ErrorContains(t, err, "line 2")
+
+	_, err = LoadBPE("cl100k_base", strings.NewReader("YQ== one\n"))
+	assert.ErrorContains(t, err, "line 1")
+
+	_, err = LoadBPE("cl100k_base", strings.NewReader("\n"))
+	assert.ErrorContains(t, err, "empty")
+}
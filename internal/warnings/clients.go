@@ -0,0 +1,87 @@
+package warnings
+
+import (
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// clientInstructionFiles maps each client, in config order, to the repo-relative instruction file it loads.
+var clientInstructionFiles = []struct {
+	client string
+	file   string
+}{
+	{"gemini", "GEMINI.md"},
+	{"claude", "CLAUDE.md"},
+	{"codex", "AGENTS.md"},
+	{"vscode", ".github/copilot-instructions.md"},
+	{"antigravity", "GEMINI.md"},
+}
+
+// EnabledClients returns the enabled clients in config order.
+func EnabledClients(agents config.AgentsConfig) []string {
+	enabled := map[string]*bool{
+		"gemini":      agents.Gemini.Enabled,
+		"claude":      agents.Claude.Enabled,
+		"codex":       agents.Codex.Enabled,
+		"vscode":      agents.VSCode.Enabled,
+		"antigravity": agents.Antigravity.Enabled,
+	}
+	var clients []string
+	for _, entry := range clientInstructionFiles {
+		if flag := enabled[entry.client]; flag != nil && *flag {
+			clients = append(clients, entry.client)
+		}
+	}
+	return clients
+}
+
+// InstructionFile returns the repo-relative instruction file sync generates for client.
+func InstructionFile(client string) string {
+	for _, entry := range clientInstructionFiles {
+		if entry.client == client {
+			return entry.file
+		}
+	}
+	return ""
+}
+
+// warningSet merges the warnings of several per-client evaluations. Identical warnings are reported
+// once; a warning raised for only some of the evaluated clients names them in its details.
+type warningSet struct {
+	clients []string
+	order   []string
+	byKey   map[string]*Warning
+	raisers map[string][]string
+}
+
+func newWarningSet(clients []string) *warningSet {
+	return &warningSet{clients: clients, byKey: map[string]*Warning{}, raisers: map[string][]string{}}
+}
+
+// add records warnings raised while evaluating client.
+func (s *warningSet) add(client string, warnings ...Warning) {
+	for _, w := range warnings {
+		key := w.Code + "\x00" + w.Subject + "\x00" + w.Message + "\x00" + strings.Join(w.Details, "\x00")
+		if _, ok := s.byKey[key]; !ok {
+			copied := w
+			s.byKey[key] = &copied
+			s.order = append(s.order, key)
+		}
+		s.raisers[key] = append(s.raisers[key], client)
+	}
+}
+
+// list returns the merged warnings in the order they were first raised.
+func (s *warningSet) list() []Warning {
+	var warnings []Warning
+	for _, key := range s.order {
+		w := *s.byKey[key]
+		if raisers := s.raisers[key]; len(raisers) < len(s.clients) {
+			w.Details = append(append([]string(nil), w.Details...), messages.WarningsClientsDetailPrefix+strings.Join(raisers, ", "))
+		}
+		warnings = append(warnings, w)
+	}
+	return warnings
+}
@@ -7,6 +7,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/conn-castle/agent-layer/internal/config"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -14,6 +15,45 @@ import (
 // rootDir is the project root directory; threshold is the max token count (nil disables warnings).
 // It returns any warnings and an error if the payload cannot be read.
 func CheckInstructions(rootDir string, threshold *int) ([]Warning, error) {
+	return checkInstructionPayload(rootDir, threshold, Heuristic)
+}
+
+// CheckClientInstructions checks the instruction file each enabled client loads against that
+// client's [warnings] threshold and tokenizer. Clients whose file has not been generated yet are
+// checked against the combined payload. With no client enabled it behaves like CheckInstructions.
+func CheckClientInstructions(project *config.ProjectConfig) ([]Warning, error) {
+	clients := EnabledClients(project.Config.Agents)
+	if len(clients) == 0 {
+		shared := project.Config.Warnings.ForClient("")
+		return checkInstructionPayload(project.Root, shared.InstructionTokenThreshold, resolveTokenizerQuiet(shared.Tokenizer))
+	}
+	set := newWarningSet(clients)
+	for _, client := range clients {
+		settings := project.Config.Warnings.ForClient(client)
+		if settings.InstructionTokenThreshold == nil {
+			continue
+		}
+		tokenizer := resolveTokenizerQuiet(settings.Tokenizer)
+		file := InstructionFile(client)
+		content, err := os.ReadFile(filepath.Join(project.Root, filepath.FromSlash(file)))
+		if err != nil {
+			if !os.IsNotExist(err) {
+				return nil, err
+			}
+			warnings, err := checkInstructionPayload(project.Root, settings.InstructionTokenThreshold, tokenizer)
+			if err != nil {
+				return nil, err
+			}
+			set.add(client, warnings...)
+			continue
+		}
+		set.add(client, instructionWarnings(string(content), file, *settings.InstructionTokenThreshold, tokenizer)...)
+	}
+	return set.list(), nil
+}
+
+// checkInstructionPayload counts the combined instruction payload under rootDir with tokenizer.
+func checkInstructionPayload(rootDir string, threshold *int, tokenizer Tokenizer) ([]Warning, error) {
 	if threshold == nil {
 		return nil, nil
 	}
@@ -21,18 +61,25 @@ func CheckInstructions(rootDir string, threshold *int) ([]Warning, error) {
 	if err != nil {
 		return nil, err
 	}
+	return instructionWarnings(content, subject, *threshold, tokenizer), nil
+}
 
-	tokens := EstimateTokens(content)
-	if tokens > *threshold {
-		return []Warning{{
-			Code:    CodeInstructionsTooLarge,
-			Subject: subject,
-			Message: fmt.Sprintf(messages.WarningsInstructionsTooLargeFmt, *threshold, tokens, *threshold),
-			Fix:     messages.WarningsInstructionsTooLargeFix,
-		}}, nil
+// instructionWarnings returns the INSTRUCTIONS_TOO_LARGE warning when content exceeds threshold.
+func instructionWarnings(content string, subject string, threshold int, tokenizer Tokenizer) []Warning {
+	tokens := tokenizer.CountTokens(content)
+	if tokens <= threshold {
+		return nil
 	}
-
-	return nil, nil
+	w := Warning{
+		Code:    CodeInstructionsTooLarge,
+		Subject: subject,
+		Message: fmt.Sprintf(messages.WarningsInstructionsTooLargeFmt, threshold, tokens, threshold),
+		Fix:     messages.WarningsInstructionsTooLargeFix,
+	}
+	if tokenizer.Name() != config.HeuristicTokenizer {
+		w.Details = []string{messages.WarningsTokenizerDetailPrefix + tokenizer.Name()}
+	}
+	return []Warning{w}
 }
 
 // getInstructionPayload returns the combined instruction content and the source subject.
@@ -3,10 +3,13 @@ package warnings
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/conn-castle/agent-layer/internal/config"
 )
 
 func TestCheckInstructions_AgentsMD(t *testing.T) {
@@ -120,3 +123,67 @@ func TestCheckInstructions_InstructionsDirReadError(t *testing.T) {
 	_, err = CheckInstructions(tmpDir, &threshold)
 	require.Error(t, err)
 }
+
+func TestCheckClientInstructions(t *testing.T) {
+	tmpDir := t.TempDir()
+	enabled := true
+	shared, claudeThreshold := 4000, 100
+	project := &config.ProjectConfig{
+		Root: tmpDir,
+		Config: config.Config{
+			Agents: config.AgentsConfig{
+				Gemini:      config.GeminiConfig{Enabled: &enabled},
+				Claude:      config.ClaudeConfig{Enabled: &enabled},
+				Antigravity: config.AgentConfig{Enabled: &enabled},
+			},
+			Warnings: config.WarningsConfig{
+				InstructionTokenThreshold: &shared,
+				Clients: map[string]config.ClientWarningsConfig{
+					"claude": {InstructionTokenThreshold: &claudeThreshold},
+				},
+			},
+		},
+	}
+	medium := strings.Repeat("a", 1000)
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "CLAUDE.md"), []byte(medium), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "GEMINI.md"), []byte(medium), 0o644))
+
+	warnings, err := CheckClientInstructions(project)
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "CLAUDE.md", warnings[0].Subject)
+	assert.Equal(t, []string{"clients: claude"}, warnings[0].Details)
+
+	// The shared threshold applies to both Gemini clients, reported once.
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "GEMINI.md"), []byte(strings.Repeat("a", 20000)), 0o644))
+	warnings, err = CheckClientInstructions(project)
+	require.NoError(t, err)
+	require.Len(t, warnings, 2)
+	assert.Equal(t, "GEMINI.md", warnings[0].Subject)
+	assert.Equal(t, []string{"clients: gemini, antigravity"}, warnings[0].Details)
+}
+
+func TestCheckClientInstructions_Fallbacks(t *testing.T) {
+	tmpDir := t.TempDir()
+	threshold := 100
+	project := &config.ProjectConfig{
+		Root:   tmpDir,
+		Config: config.Config{Warnings: config.WarningsConfig{InstructionTokenThreshold: &threshold}},
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "AGENTS.md"), []byte(strings.Repeat("a", 1000)), 0o644))
+
+	// No client enabled: the combined payload is checked.
+	warnings, err := CheckClientInstructions(project)
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "AGENTS.md", warnings[0].Subject)
+	assert.Empty(t, warnings[0].Details)
+
+	// A client whose file was not generated yet is checked against the combined payload too.
+	enabled := true
+	project.Config.Agents.VSCode.Enabled = &enabled
+	warnings, err = CheckClientInstructions(project)
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "AGENTS.md", warnings[0].Subject)
+}
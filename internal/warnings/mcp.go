@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"sort"
 	"sync"
 
 	"github.com/conn-castle/agent-layer/internal/config"
@@ -13,13 +15,14 @@ import (
 
 // CheckMCPServers performs discovery on enabled MCP servers and checks against warning thresholds.
 // cfg supplies the configured thresholds; nil thresholds disable the corresponding warnings.
+// Thresholds apply to the servers each enabled client loads, using that client's [warnings] settings.
 func CheckMCPServers(ctx context.Context, cfg *config.ProjectConfig, connector Connector) ([]Warning, error) {
 	if connector == nil {
 		connector = &RealConnector{}
 	}
 
 	// 1. Identify enabled servers
-	enabledServers, err := projection.ResolveEnabledMCPServers(cfg.Config.MCP.Servers, cfg.Env)
+	enabledServers, err := projection.ResolveEnabledMCPServers(cfg.Config.MCP.Servers, cfg.PlaceholderEnv())
 	if err != nil {
 		subject := "mcp.servers"
 		var resolveErr *projection.MCPServerResolveError
@@ -34,38 +37,81 @@ func CheckMCPServers(ctx context.Context, cfg *config.ProjectConfig, connector C
 		}}, nil
 	}
 
+	// 2. Discovery (Parallel)
+	results := discoverTools(ctx, enabledServers, connector)
+
 	var warnings []Warning
+	reachable := make([]DiscoveryResult, 0, len(results))
+	for _, res := range results {
+		if res.Error != nil {
+			warnings = append(warnings, Warning{
+				Code:    CodeMCPServerUnreachable,
+				Subject: res.ServerID,
+				Message: fmt.Sprintf(messages.WarningsMCPConnectFailedFmt, res.Error),
+				Fix:     messages.WarningsMCPConnectFix,
+			})
+			continue
+		}
+		reachable = append(reachable, res)
+	}
+
+	// 3. Check thresholds against what each enabled client loads, or project-wide when none is enabled.
+	clients := EnabledClients(cfg.Config.Agents)
+	if len(clients) == 0 {
+		return append(warnings, mcpThresholdWarnings(len(enabledServers), reachable, cfg.Config.Warnings.ForClient(""))...), nil
+	}
+	set := newWarningSet(clients)
+	for _, client := range clients {
+		ids := projection.EnabledServerIDs(cfg.Config.MCP.Servers, client)
+		set.add(client, mcpThresholdWarnings(len(ids), ResultsForServers(reachable, ids), cfg.Config.Warnings.ForClient(client))...)
+	}
+	return append(warnings, set.list()...), nil
+}
 
-	thresholds := cfg.Config.Warnings
+// DiscoverMCPTools resolves the enabled MCP servers of cfg and lists their tools, in config order.
+func DiscoverMCPTools(ctx context.Context, cfg *config.ProjectConfig, connector Connector) ([]DiscoveryResult, error) {
+	if connector == nil {
+		connector = &RealConnector{}
+	}
+	servers, err := projection.ResolveEnabledMCPServers(cfg.Config.MCP.Servers, cfg.PlaceholderEnv())
+	if err != nil {
+		return nil, err
+	}
+	return discoverTools(ctx, servers, connector), nil
+}
+
+// ResultsForServers returns the results whose server is in ids, keeping their order.
+func ResultsForServers(results []DiscoveryResult, ids []string) []DiscoveryResult {
+	var filtered []DiscoveryResult
+	for _, res := range results {
+		if slices.Contains(ids, res.ServerID) {
+			filtered = append(filtered, res)
+		}
+	}
+	return filtered
+}
+
+// mcpThresholdWarnings checks serverCount enabled servers and their reachable results against thresholds.
+func mcpThresholdWarnings(serverCount int, results []DiscoveryResult, thresholds config.WarningsConfig) []Warning {
+	var warnings []Warning
+	tokenizer := resolveTokenizerQuiet(thresholds.Tokenizer)
 
 	// Check: MCP_TOO_MANY_SERVERS_ENABLED
-	if thresholds.MCPServerThreshold != nil && len(enabledServers) > *thresholds.MCPServerThreshold {
+	if thresholds.MCPServerThreshold != nil && serverCount > *thresholds.MCPServerThreshold {
 		warnings = append(warnings, Warning{
 			Code:    CodeMCPTooManyServers,
 			Subject: "mcp.servers",
-			Message: fmt.Sprintf(messages.WarningsTooManyServersFmt, *thresholds.MCPServerThreshold, len(enabledServers), *thresholds.MCPServerThreshold),
+			Message: fmt.Sprintf(messages.WarningsTooManyServersFmt, *thresholds.MCPServerThreshold, serverCount, *thresholds.MCPServerThreshold),
 			Fix:     messages.WarningsTooManyServersFix,
 		})
 	}
 
-	// 2. Discovery (Parallel)
-	results := discoverTools(ctx, enabledServers, connector)
-
-	// 3. Process results
 	var totalTools int
 	var totalSchemaTokens int
 	toolNames := make(map[string][]string) // name -> serverIDs
 
 	for _, res := range results {
-		if res.Error != nil {
-			warnings = append(warnings, Warning{
-				Code:    CodeMCPServerUnreachable,
-				Subject: res.ServerID,
-				Message: fmt.Sprintf(messages.WarningsMCPConnectFailedFmt, res.Error),
-				Fix:     messages.WarningsMCPConnectFix,
-			})
-			continue
-		}
+		schemaTokens := res.CountSchemaTokens(tokenizer)
 
 		// Check: MCP_SERVER_TOO_MANY_TOOLS
 		if thresholds.MCPServerToolsThreshold != nil && len(res.Tools) > *thresholds.MCPServerToolsThreshold {
@@ -78,17 +124,17 @@ func CheckMCPServers(ctx context.Context, cfg *config.ProjectConfig, connector C
 		}
 
 		// Check: MCP_TOOL_SCHEMA_BLOAT_SERVER
-		if thresholds.MCPSchemaTokensServerThreshold != nil && res.SchemaTokens > *thresholds.MCPSchemaTokensServerThreshold {
+		if thresholds.MCPSchemaTokensServerThreshold != nil && schemaTokens > *thresholds.MCPSchemaTokensServerThreshold {
 			warnings = append(warnings, Warning{
 				Code:    CodeMCPToolSchemaBloatServer,
 				Subject: res.ServerID,
-				Message: fmt.Sprintf(messages.WarningsMCPSchemaBloatServerFmt, *thresholds.MCPSchemaTokensServerThreshold, res.SchemaTokens, *thresholds.MCPSchemaTokensServerThreshold),
+				Message: fmt.Sprintf(messages.WarningsMCPSchemaBloatServerFmt, *thresholds.MCPSchemaTokensServerThreshold, schemaTokens, *thresholds.MCPSchemaTokensServerThreshold),
 				Fix:     messages.WarningsMCPSchemaBloatFix,
 			})
 		}
 
 		totalTools += len(res.Tools)
-		totalSchemaTokens += res.SchemaTokens
+		totalSchemaTokens += schemaTokens
 
 		for _, t := range res.Tools {
 			toolNames[t.Name] = append(toolNames[t.Name], res.ServerID)
@@ -116,8 +162,13 @@ func CheckMCPServers(ctx context.Context, cfg *config.ProjectConfig, connector C
 	}
 
 	// Check: MCP_TOOL_NAME_COLLISION
-	for name, servers := range toolNames {
-		if len(servers) > 1 {
+	names := make([]string, 0, len(toolNames))
+	for name := range toolNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if servers := toolNames[name]; len(servers) > 1 {
 			warnings = append(warnings, Warning{
 				Code:    CodeMCPToolNameCollision,
 				Subject: name,
@@ -127,22 +178,35 @@ func CheckMCPServers(ctx context.Context, cfg *config.ProjectConfig, connector C
 		}
 	}
 
-	return warnings, nil
+	return warnings
 }
 
 // ToolDef represents a discovered tool from an MCP server.
+// Schema is the tool definition as JSON, when known.
 type ToolDef struct {
-	Name string
+	Name   string
+	Schema string
 }
 
 // DiscoveryResult contains the results of discovering tools from an MCP server.
+// SchemaTokens is the heuristic estimate of Schema, the JSON of all tool definitions.
 type DiscoveryResult struct {
 	ServerID     string
 	Tools        []ToolDef
 	SchemaTokens int
+	Schema       string
 	Error        error
 }
 
+// CountSchemaTokens returns the size of the tool definitions under tokenizer. Results without the
+// schema text keep their SchemaTokens estimate.
+func (r DiscoveryResult) CountSchemaTokens(tokenizer Tokenizer) int {
+	if r.Schema == "" || tokenizer.Name() == config.HeuristicTokenizer {
+		return r.SchemaTokens
+	}
+	return tokenizer.CountTokens(r.Schema)
+}
+
 // Connector interface for mocking.
 type Connector interface {
 	ConnectAndDiscover(ctx context.Context, server projection.ResolvedMCPServer) DiscoveryResult
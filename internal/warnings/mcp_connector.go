@@ -169,14 +169,19 @@ func (r *RealConnector) ConnectAndDiscover(ctx context.Context, server projectio
 	// Process tools
 	var toolsJSON []any
 	for _, t := range allTools {
-		res.Tools = append(res.Tools, ToolDef{Name: t.Name})
+		def := ToolDef{Name: t.Name}
+		if bytes, err := json.Marshal(t); err == nil {
+			def.Schema = string(bytes)
+		}
+		res.Tools = append(res.Tools, def)
 		toolsJSON = append(toolsJSON, t)
 	}
 
 	if len(toolsJSON) > 0 {
 		bytes, err := json.Marshal(toolsJSON)
 		if err == nil {
-			res.SchemaTokens = EstimateTokens(string(bytes))
+			res.Schema = string(bytes)
+			res.SchemaTokens = EstimateTokens(res.Schema)
 		}
 	}
 
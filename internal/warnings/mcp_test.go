@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -205,6 +207,63 @@ func TestCheckMCPServers_ThresholdsDisabled(t *testing.T) {
 	assert.False(t, codes[CodeMCPToolSchemaBloatTotal], "Did not expect TOOL_SCHEMA_BLOAT_TOTAL")
 }
 
+func TestCheckMCPServers_PerClient(t *testing.T) {
+	dir := t.TempDir()
+	withTokenizerDir(t, dir)
+	enabled := true
+	serverThreshold, codexServerThreshold, schemaThreshold := 2, 1, 50
+	cfg := &config.ProjectConfig{
+		Config: config.Config{
+			Agents: config.AgentsConfig{
+				Claude: config.ClaudeConfig{Enabled: &enabled},
+				Codex:  config.CodexConfig{Enabled: &enabled},
+			},
+			MCP: config.MCPConfig{
+				Servers: []config.MCPServer{
+					{ID: "s1", Enabled: &enabled, Transport: "stdio", Command: "echo"},
+					{ID: "s2", Enabled: &enabled, Clients: []string{"claude"}, Transport: "stdio", Command: "echo"},
+				},
+			},
+			Warnings: config.WarningsConfig{
+				MCPServerThreshold:             &serverThreshold,
+				MCPSchemaTokensServerThreshold: &schemaThreshold,
+				Clients: map[string]config.ClientWarningsConfig{
+					"codex": {MCPServerThreshold: &codexServerThreshold, Tokenizer: "cl100k_base"},
+				},
+			},
+		},
+		Env: map[string]string{},
+	}
+	mock := &MockConnector{Results: map[string]DiscoveryResult{
+		"s1": {ServerID: "s1", Tools: []ToolDef{{Name: "a"}}, SchemaTokens: 60, Schema: "ab"},
+		"s2": {ServerID: "s2", Tools: []ToolDef{{Name: "b"}}, SchemaTokens: 10},
+	}}
+
+	// Codex loads one server, within its own threshold; its tokenizer has no vocabulary, so
+	// schema tokens fall back to the heuristic estimate and both clients agree on s1.
+	warnings, err := CheckMCPServers(context.Background(), cfg, mock)
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, CodeMCPToolSchemaBloatServer, warnings[0].Code)
+	assert.Equal(t, "s1", warnings[0].Subject)
+	assert.Empty(t, warnings[0].Details)
+
+	// With a vocabulary, Codex counts s1's schema as one token and only Claude is warned.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cl100k_base.tiktoken"), []byte(testVocabulary("ab")), 0o644))
+	warnings, err = CheckMCPServers(context.Background(), cfg, mock)
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, []string{"clients: claude"}, warnings[0].Details)
+
+	// Claude loads both servers; Codex loads only s1 and has its own server threshold.
+	serverThreshold = 1
+	warnings, err = CheckMCPServers(context.Background(), cfg, mock)
+	require.NoError(t, err)
+	require.Len(t, warnings, 2)
+	assert.Equal(t, CodeMCPTooManyServers, warnings[0].Code)
+	assert.Equal(t, []string{"clients: claude"}, warnings[0].Details)
+}
+
 func TestCheckMCPServers_NilConnector(t *testing.T) {
 	// When connector is nil, a RealConnector should be created (but we can't easily test the real one)
 	// This test ensures the nil check doesn't panic and the function handles disabled servers
@@ -10,6 +10,9 @@ const (
 	CodeMCPToolSchemaBloatTotal  = "MCP_TOOL_SCHEMA_BLOAT_TOTAL"
 	CodeMCPToolSchemaBloatServer = "MCP_TOOL_SCHEMA_BLOAT_SERVER"
 	CodeMCPToolNameCollision     = "MCP_TOOL_NAME_COLLISION"
+	CodeSlashCommandUnknownKeys  = "SLASH_COMMAND_UNKNOWN_KEYS"
+	CodeInstructionUnknownKeys   = "INSTRUCTION_UNKNOWN_KEYS"
+	CodeTokenizerUnavailable     = "TOKENIZER_UNAVAILABLE"
 )
 
 // Warning represents a warning message.
@@ -0,0 +1,52 @@
+package warnings
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// CheckSlashCommands warns about slash command front matter keys that no client receives.
+func CheckSlashCommands(project *config.ProjectConfig) []Warning {
+	var warnings []Warning
+	for _, cmd := range project.SlashCommands {
+		if len(cmd.UnknownKeys) == 0 {
+			continue
+		}
+		warnings = append(warnings, Warning{
+			Code:    CodeSlashCommandUnknownKeys,
+			Subject: projectRelPath(project.Root, cmd.SourcePath),
+			Message: fmt.Sprintf(messages.WarningsSlashCommandUnknownKeysFmt, strings.Join(cmd.UnknownKeys, ", ")),
+			Fix:     messages.WarningsSlashCommandUnknownKeysFix,
+		})
+	}
+	return warnings
+}
+
+// CheckInstructionFrontMatter warns about instruction front matter keys other than clients, applies_to, and template.
+func CheckInstructionFrontMatter(project *config.ProjectConfig) []Warning {
+	var warnings []Warning
+	for _, file := range project.Instructions {
+		if len(file.UnknownKeys) == 0 {
+			continue
+		}
+		warnings = append(warnings, Warning{
+			Code:    CodeInstructionUnknownKeys,
+			Subject: projectRelPath(project.Root, file.SourcePath),
+			Message: fmt.Sprintf(messages.WarningsInstructionUnknownKeysFmt, strings.Join(file.UnknownKeys, ", ")),
+			Fix:     messages.WarningsInstructionUnknownKeysFix,
+		})
+	}
+	return warnings
+}
+
+// projectRelPath returns path relative to root with forward slashes, or path itself when it is outside root.
+func projectRelPath(root string, path string) string {
+	if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
@@ -0,0 +1,45 @@
+package warnings
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+func TestCheckSlashCommands(t *testing.T) {
+	root := t.TempDir()
+	project := &config.ProjectConfig{
+		Root: root,
+		SlashCommands: []config.SlashCommand{
+			{Name: "ok", SourcePath: filepath.Join(root, ".agent-layer", "slash-commands", "ok.md")},
+			{Name: "typo", SourcePath: filepath.Join(root, ".agent-layer", "slash-commands", "typo.md"), UnknownKeys: []string{"modle", "tags"}},
+		},
+	}
+
+	warnings := CheckSlashCommands(project)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, CodeSlashCommandUnknownKeys, warnings[0].Code)
+	assert.Equal(t, ".agent-layer/slash-commands/typo.md", warnings[0].Subject)
+	assert.Contains(t, warnings[0].Message, "modle, tags")
+}
+
+func TestCheckInstructionFrontMatter(t *testing.T) {
+	root := t.TempDir()
+	project := &config.ProjectConfig{
+		Root: root,
+		Instructions: []config.InstructionFile{
+			{Name: "00_base.md", SourcePath: filepath.Join(root, ".agent-layer", "instructions", "00_base.md")},
+			{Name: "10_web.md", SourcePath: filepath.Join(root, ".agent-layer", "instructions", "10_web.md"), UnknownKeys: []string{"applies-to"}},
+		},
+	}
+
+	warnings := CheckInstructionFrontMatter(project)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, CodeInstructionUnknownKeys, warnings[0].Code)
+	assert.Equal(t, ".agent-layer/instructions/10_web.md", warnings[0].Subject)
+	assert.Contains(t, warnings[0].Message, "applies-to")
+}
@@ -0,0 +1,126 @@
+package warnings
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/projection"
+)
+
+// Stats is the context-budget breakdown reported by al stats.
+type Stats struct {
+	// Tokenizer is the shared [warnings] tokenizer actually used for fragments, commands, and servers.
+	Tokenizer string
+	// Notes explain tokenizers that fell back to the heuristic.
+	Notes     []string
+	Fragments []ItemTokens
+	Commands  []ItemTokens
+	// Servers is nil when MCP discovery was skipped.
+	Servers []ServerTokens
+	Clients []ClientTokens
+}
+
+// ItemTokens is the token count of one fragment, slash command, or tool. Empty Clients means all clients.
+type ItemTokens struct {
+	Name    string
+	Tokens  int
+	Clients []string
+}
+
+// ServerTokens is one MCP server's tool definitions. Error is set when discovery failed.
+type ServerTokens struct {
+	ID           string
+	Clients      []string
+	Tools        []ItemTokens
+	SchemaTokens int
+	Error        error
+}
+
+// ClientTokens is what one enabled client loads: its instruction file and the tool definitions of
+// the MCP servers projected into its config, counted with the client's tokenizer.
+type ClientTokens struct {
+	Client          string
+	Tokenizer       string
+	InstructionFile string
+	// InstructionTokens is -1 when the instruction file has not been generated yet.
+	InstructionTokens int
+	Servers           int
+	Tools             int
+	SchemaTokens      int
+}
+
+// Total returns the client's instruction and tool schema tokens combined.
+func (c ClientTokens) Total() int {
+	return max(c.InstructionTokens, 0) + c.SchemaTokens
+}
+
+// CollectStats counts the tokens of project's instruction fragments, slash commands, generated
+// instruction files, and the tool definitions in results. results is nil when discovery was skipped.
+func CollectStats(project *config.ProjectConfig, results []DiscoveryResult) (*Stats, error) {
+	stats := &Stats{}
+	noted := make(map[string]bool)
+	resolve := func(name string) Tokenizer {
+		tok, err := ResolveTokenizer(name)
+		if err != nil && !noted[err.Error()] {
+			noted[err.Error()] = true
+			stats.Notes = append(stats.Notes, err.Error())
+		}
+		return tok
+	}
+
+	tokenizer := resolve(project.Config.Warnings.ForClient("").Tokenizer)
+	stats.Tokenizer = tokenizer.Name()
+	for _, file := range project.Instructions {
+		stats.Fragments = append(stats.Fragments, ItemTokens{Name: file.Name, Tokens: tokenizer.CountTokens(file.Content), Clients: file.Clients})
+	}
+	for _, command := range project.SlashCommands {
+		tokens := tokenizer.CountTokens(command.Description + "\n" + command.Body)
+		stats.Commands = append(stats.Commands, ItemTokens{Name: command.Name, Tokens: tokens, Clients: command.Clients})
+	}
+
+	var reachable []DiscoveryResult
+	if results != nil {
+		stats.Servers = []ServerTokens{}
+		serverClients := make(map[string][]string)
+		for _, server := range project.Config.MCP.Servers {
+			serverClients[server.ID] = server.Clients
+		}
+		for _, res := range results {
+			entry := ServerTokens{ID: res.ServerID, Clients: serverClients[res.ServerID], Error: res.Error}
+			if res.Error == nil {
+				reachable = append(reachable, res)
+				entry.SchemaTokens = res.CountSchemaTokens(tokenizer)
+				for _, tool := range res.Tools {
+					entry.Tools = append(entry.Tools, ItemTokens{Name: tool.Name, Tokens: tokenizer.CountTokens(tool.Schema)})
+				}
+			}
+			stats.Servers = append(stats.Servers, entry)
+		}
+	}
+
+	for _, client := range EnabledClients(project.Config.Agents) {
+		clientTokenizer := resolve(project.Config.Warnings.ForClient(client).Tokenizer)
+		entry := ClientTokens{
+			Client:            client,
+			Tokenizer:         clientTokenizer.Name(),
+			InstructionFile:   InstructionFile(client),
+			InstructionTokens: -1,
+		}
+		content, err := os.ReadFile(filepath.Join(project.Root, filepath.FromSlash(entry.InstructionFile)))
+		switch {
+		case err == nil:
+			entry.InstructionTokens = clientTokenizer.CountTokens(string(content))
+		case !os.IsNotExist(err):
+			return nil, err
+		}
+		ids := projection.EnabledServerIDs(project.Config.MCP.Servers, client)
+		entry.Servers = len(ids)
+		for _, res := range ResultsForServers(reachable, ids) {
+			entry.Tools += len(res.Tools)
+			entry.SchemaTokens += res.CountSchemaTokens(clientTokenizer)
+		}
+		stats.Clients = append(stats.Clients, entry)
+	}
+	return stats, nil
+}
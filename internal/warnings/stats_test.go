@@ -0,0 +1,61 @@
+package warnings
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+func TestCollectStats(t *testing.T) {
+	dir := t.TempDir()
+	withTokenizerDir(t, dir)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "o200k_base.tiktoken"), []byte(testVocabulary("ab", "abab")), 0o644))
+
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "AGENTS.md"), []byte("abababab"), 0o644))
+	enabled := true
+	project := &config.ProjectConfig{
+		Root: root,
+		Config: config.Config{
+			Agents: config.AgentsConfig{
+				Claude: config.ClaudeConfig{Enabled: &enabled},
+				Codex:  config.CodexConfig{Enabled: &enabled},
+			},
+			MCP: config.MCPConfig{Servers: []config.MCPServer{
+				{ID: "s1", Enabled: &enabled, Clients: []string{"codex"}},
+				{ID: "s2", Enabled: &enabled},
+			}},
+			Warnings: config.WarningsConfig{Clients: map[string]config.ClientWarningsConfig{"codex": {Tokenizer: "o200k_base"}}},
+		},
+		Instructions:  []config.InstructionFile{{Name: "00_base.md", Content: "abababab"}},
+		SlashCommands: []config.SlashCommand{{Name: "review", Description: "d", Body: "b", Clients: []string{"claude"}}},
+	}
+	results := []DiscoveryResult{
+		{ServerID: "s1", Tools: []ToolDef{{Name: "t", Schema: "abab"}}, SchemaTokens: 3, Schema: "abababab"},
+		{ServerID: "s2", Error: errors.New("down")},
+	}
+
+	stats, err := CollectStats(project, results)
+	require.NoError(t, err)
+	assert.Equal(t, "heuristic", stats.Tokenizer)
+	assert.Empty(t, stats.Notes)
+	assert.Equal(t, []ItemTokens{{Name: "00_base.md", Tokens: EstimateTokens("abababab")}}, stats.Fragments)
+	assert.Equal(t, []ItemTokens{{Name: "review", Tokens: EstimateTokens("d\nb"), Clients: []string{"claude"}}}, stats.Commands)
+	require.Len(t, stats.Servers, 2)
+	assert.Equal(t, 3, stats.Servers[0].SchemaTokens)
+	assert.Equal(t, []string{"codex"}, stats.Servers[0].Clients)
+	assert.EqualError(t, stats.Servers[1].Error, "down")
+
+	require.Len(t, stats.Clients, 2)
+	claude, codex := stats.Clients[0], stats.Clients[1]
+	assert.Equal(t, ClientTokens{Client: "claude", Tokenizer: "heuristic", InstructionFile: "CLAUDE.md", InstructionTokens: -1, Servers: 1}, claude)
+	assert.Equal(t, ClientTokens{Client: "codex", Tokenizer: "o200k_base", InstructionFile: "AGENTS.md", InstructionTokens: 2, Servers: 2, Tools: 1, SchemaTokens: 2}, codex)
+	assert.Equal(t, 4, codex.Total())
+	assert.Equal(t, 0, claude.Total())
+}
@@ -0,0 +1,105 @@
+package warnings
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// Tokenizer counts the tokens a model would see for a piece of text.
+type Tokenizer interface {
+	Name() string
+	CountTokens(text string) int
+}
+
+// Heuristic is the fallback Tokenizer; it wraps EstimateTokens and needs no vocabulary.
+var Heuristic Tokenizer = heuristicTokenizer{}
+
+type heuristicTokenizer struct{}
+
+func (heuristicTokenizer) Name() string { return config.HeuristicTokenizer }
+
+func (heuristicTokenizer) CountTokens(text string) int { return EstimateTokens(text) }
+
+// tokenizerDir is where BPE vocabularies are looked up; a package var so tests can point it at testdata.
+var tokenizerDir = config.TokenizerDir
+
+var (
+	tokenizerMu    sync.Mutex
+	tokenizerCache = map[string]Tokenizer{}
+)
+
+// ResolveTokenizer returns the tokenizer named name. BPE vocabularies are loaded from
+// <tokenizer dir>/<name>.tiktoken once per process. When the vocabulary is missing or invalid it
+// returns Heuristic together with an error explaining why, so callers can keep going.
+func ResolveTokenizer(name string) (Tokenizer, error) {
+	if name == "" || name == config.HeuristicTokenizer {
+		return Heuristic, nil
+	}
+	if _, ok := bpePatterns[name]; !ok {
+		return Heuristic, fmt.Errorf(messages.WarningsTokenizerUnknownFmt, name)
+	}
+	dir := tokenizerDir()
+	if dir == "" {
+		return Heuristic, fmt.Errorf(messages.WarningsTokenizerNoDirFmt, name)
+	}
+	path := filepath.Join(dir, name+".tiktoken")
+
+	tokenizerMu.Lock()
+	defer tokenizerMu.Unlock()
+	if tok, ok := tokenizerCache[path]; ok {
+		return tok, nil
+	}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return Heuristic, fmt.Errorf(messages.WarningsTokenizerMissingFmt, name, path)
+	}
+	if err != nil {
+		return Heuristic, fmt.Errorf(messages.WarningsTokenizerLoadFailedFmt, path, err)
+	}
+	defer func() { _ = file.Close() }()
+	tok, err := LoadBPE(name, file)
+	if err != nil {
+		return Heuristic, fmt.Errorf(messages.WarningsTokenizerLoadFailedFmt, path, err)
+	}
+	tokenizerCache[path] = tok
+	return tok, nil
+}
+
+// CheckTokenizers warns about each configured [warnings] tokenizer that cannot be loaded, since
+// the other checks then count with Heuristic.
+func CheckTokenizers(project *config.ProjectConfig) []Warning {
+	settings := project.Config.Warnings
+	keys := []string{"warnings.tokenizer"}
+	names := map[string]string{"warnings.tokenizer": settings.Tokenizer}
+	for _, client := range slices.Sorted(maps.Keys(settings.Clients)) {
+		key := "warnings.clients." + client + ".tokenizer"
+		keys = append(keys, key)
+		names[key] = settings.Clients[client].Tokenizer
+	}
+	var warnings []Warning
+	for _, key := range keys {
+		if _, err := ResolveTokenizer(names[key]); err != nil {
+			warnings = append(warnings, Warning{
+				Code:    CodeTokenizerUnavailable,
+				Subject: key,
+				Message: fmt.Sprintf(messages.WarningsTokenizerUnavailableFmt, err),
+				Fix:     messages.WarningsTokenizerUnavailableFix,
+			})
+		}
+	}
+	return warnings
+}
+
+// resolveTokenizerQuiet is ResolveTokenizer for the threshold checks, which fall back to the
+// heuristic silently; CheckTokenizers reports why.
+func resolveTokenizerQuiet(name string) Tokenizer {
+	tok, _ := ResolveTokenizer(name)
+	return tok
+}
@@ -0,0 +1,91 @@
+package warnings
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/conn-castle/agent-layer/internal/config"
+)
+
+func withTokenizerDir(t *testing.T, dir string) {
+	t.Helper()
+	orig := tokenizerDir
+	tokenizerDir = func() string { return dir }
+	t.Cleanup(func() {
+		tokenizerDir = orig
+		tokenizerMu.Lock()
+		tokenizerCache = map[string]Tokenizer{}
+		tokenizerMu.Unlock()
+	})
+}
+
+func TestResolveTokenizer(t *testing.T) {
+	dir := t.TempDir()
+	withTokenizerDir(t, dir)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "o200k_base.tiktoken"), []byte(testVocabulary("ab")), 0o644))
+
+	tok, err := ResolveTokenizer("")
+	require.NoError(t, err)
+	assert.Equal(t, Heuristic, tok)
+	assert.Equal(t, EstimateTokens("some text"), tok.CountTokens("some text"))
+
+	tok, err = ResolveTokenizer("o200k_base")
+	require.NoError(t, err)
+	assert.Equal(t, "o200k_base", tok.Name())
+	assert.Equal(t, 1, tok.CountTokens("ab"))
+	cached, err := ResolveTokenizer("o200k_base")
+	require.NoError(t, err)
+	assert.Same(t, tok, cached)
+
+	tok, err = ResolveTokenizer("cl100k_base")
+	assert.ErrorContains(t, err, "cl100k_base.tiktoken")
+	assert.Equal(t, Heuristic, tok)
+
+	tok, err = ResolveTokenizer("gpt2")
+	assert.ErrorContains(t, err, "unknown tokenizer")
+	assert.Equal(t, Heuristic, tok)
+}
+
+func TestResolveTokenizer_InvalidVocabulary(t *testing.T) {
+	dir := t.TempDir()
+	withTokenizerDir(t, dir)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cl100k_base.tiktoken"), []byte("not a vocabulary\n"), 0o644))
+
+	tok, err := ResolveTokenizer("cl100k_base")
+	assert.ErrorContains(t, err, "invalid tokenizer vocabulary")
+	assert.Equal(t, Heuristic, tok)
+}
+
+func TestResolveTokenizer_NoDir(t *testing.T) {
+	withTokenizerDir(t, "")
+	_, err := ResolveTokenizer("cl100k_base")
+	assert.ErrorContains(t, err, "no home directory")
+}
+
+func TestCheckTokenizers(t *testing.T) {
+	dir := t.TempDir()
+	withTokenizerDir(t, dir)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "o200k_base.tiktoken"), []byte(testVocabulary("ab")), 0o644))
+
+	project := &config.ProjectConfig{Config: config.Config{Warnings: config.WarningsConfig{
+		Tokenizer: "cl100k_base",
+		Clients: map[string]config.ClientWarningsConfig{
+			"codex":  {Tokenizer: "o200k_base"},
+			"claude": {Tokenizer: "cl100k_base"},
+			"gemini": {},
+		},
+	}}}
+	warnings := CheckTokenizers(project)
+	require.Len(t, warnings, 2)
+	assert.Equal(t, CodeTokenizerUnavailable, warnings[0].Code)
+	assert.Equal(t, "warnings.tokenizer", warnings[0].Subject)
+	assert.Contains(t, warnings[0].Message, "cl100k_base.tiktoken not found")
+	assert.Contains(t, warnings[0].Message, "heuristic")
+	assert.Equal(t, "warnings.clients.claude.tokenizer", warnings[1].Subject)
+
+	assert.Empty(t, CheckTokenizers(&config.ProjectConfig{}))
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/fatih/color"
 
+	"github.com/conn-castle/agent-layer/internal/config"
 	"github.com/conn-castle/agent-layer/internal/envfile"
 	"github.com/conn-castle/agent-layer/internal/fsutil"
 	"github.com/conn-castle/agent-layer/internal/messages"
@@ -41,6 +42,8 @@ func applyChanges(root, configPath, envPath string, c *Choices, runSync syncer)
 	}
 
 	// Env
+	// Secrets go to the encrypted .env.age when one exists; its backup stays encrypted.
+	envPath = config.EnvFilePath(envPath)
 	// Backup if exists
 	rawEnv, err := os.ReadFile(envPath)
 	envPerm, permErr := filePermOr(envPath, 0600)
@@ -65,14 +68,24 @@ func applyChanges(root, configPath, envPath string, c *Choices, runSync syncer)
 		return err
 	}
 	// Patch
+	newEnv, err := patchEnv(envPath, rawEnv, c.Secrets)
+	if err != nil {
+		if configBackupCreated {
+			_ = os.Remove(configBackupPath)
+		}
+		return err
+	}
 	if err := writeFileAtomic(configPath, []byte(newConfig), configPerm); err != nil {
 		return fmt.Errorf(messages.WizardWriteConfigFailedFmt, err)
 	}
-	newEnv := envfile.Patch(string(rawEnv), c.Secrets)
-	if err := writeFileAtomic(envPath, []byte(newEnv), envPerm); err != nil {
+	if err := writeFileAtomic(envPath, newEnv, envPerm); err != nil {
 		return fmt.Errorf(messages.WizardWriteEnvFailedFmt, err)
 	}
 
+	if err := storeProviderSecrets(c); err != nil {
+		return err
+	}
+
 	// Sync
 	fmt.Println(messages.WizardRunningSync)
 	warnings, err := runSync(root)
@@ -91,6 +104,29 @@ func applyChanges(root, configPath, envPath string, c *Choices, runSync syncer)
 	return nil
 }
 
+// storeProviderSecrets writes values for ${secret:NAME} references to the chosen secret provider.
+func storeProviderSecrets(c *Choices) error {
+	if len(c.ProviderSecrets) == 0 {
+		return nil
+	}
+	for _, name := range sortedKeys(c.ProviderSecrets) {
+		if err := c.SecretStore.Store(name, c.ProviderSecrets[name]); err != nil {
+			return fmt.Errorf(messages.WizardStoreSecretFailedFmt, name, c.SecretStore.Name(), err)
+		}
+	}
+	return nil
+}
+
+// patchEnv applies secrets to env file content read from envPath, decrypting and
+// re-encrypting .env.age content in memory.
+func patchEnv(envPath string, raw []byte, secrets map[string]string) ([]byte, error) {
+	plaintext, err := config.DecodeEnvFile(envPath, raw)
+	if err != nil {
+		return nil, err
+	}
+	return config.EncodeEnvFile(envPath, []byte(envfile.Patch(string(plaintext), secrets)))
+}
+
 // filePermOr returns the file permission bits or a fallback when the file is missing.
 // path is the file to inspect; fallback is the permission to use when the file does not exist.
 func filePermOr(path string, fallback os.FileMode) (os.FileMode, error) {
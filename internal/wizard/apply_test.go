@@ -4,11 +4,13 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/conn-castle/agent-layer/internal/config"
 	"github.com/conn-castle/agent-layer/internal/warnings"
 )
 
@@ -380,3 +382,36 @@ mode = "none"
 	err := applyChanges(tmpDir, configPath, envPath, choices, mockSync)
 	require.NoError(t, err)
 }
+
+func TestApplyChanges_EncryptedEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+	// Fake age: "encrypts" by prefixing AGE:.
+	binDir := t.TempDir()
+	script := "#!/bin/sh\ncase \"$1\" in\n--encrypt) printf 'AGE:'; cat ;;\n--decrypt) tail -c +5 ;;\nesac\n"
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "age"), []byte(script), 0755))
+	identity := filepath.Join(binDir, "identity.txt")
+	require.NoError(t, os.WriteFile(identity, []byte("AGE-SECRET-KEY-TEST\n"), 0600))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv(config.AgeIdentityEnvVar, identity)
+
+	choices := NewChoices()
+	choices.Secrets["NEW"] = "secret"
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.toml")
+	envPath := filepath.Join(tmpDir, ".env")
+	require.NoError(t, os.WriteFile(configPath, []byte("[approvals]\nmode = \"none\"\n"), 0644))
+	require.NoError(t, os.WriteFile(envPath+".age", []byte("AGE:OLD=value\n"), 0600))
+
+	mockSync := func(root string) ([]warnings.Warning, error) { return nil, nil }
+	require.NoError(t, applyChanges(tmpDir, configPath, envPath, choices, mockSync))
+
+	encrypted, err := os.ReadFile(envPath + ".age")
+	require.NoError(t, err)
+	assert.Equal(t, "AGE:OLD=value\n\nNEW=secret", string(encrypted))
+	backup, err := os.ReadFile(envPath + ".age.bak")
+	require.NoError(t, err)
+	assert.Equal(t, "AGE:OLD=value\n", string(backup))
+	assert.NoFileExists(t, envPath)
+}
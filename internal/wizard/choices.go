@@ -1,5 +1,7 @@
 package wizard
 
+import "github.com/conn-castle/agent-layer/internal/secrets"
+
 // Choices tracks user selections in the wizard.
 type Choices struct {
 	// Approvals
@@ -33,6 +35,12 @@ type Choices struct {
 
 	// Secrets (Env vars)
 	Secrets map[string]string
+	// SecretReferences lists env vars that default servers should reference as ${secret:NAME}
+	// because a secret provider already holds them.
+	SecretReferences map[string]bool
+	// ProviderSecrets are values for ${secret:NAME} references, stored through SecretStore.
+	ProviderSecrets map[string]string
+	SecretStore     secrets.WritableProvider
 
 	// Warnings
 	WarningsEnabled                bool
@@ -52,5 +60,7 @@ func NewChoices() *Choices {
 		EnabledMCPServers:  make(map[string]bool),
 		DisabledMCPServers: make(map[string]bool),
 		Secrets:            make(map[string]string),
+		SecretReferences:   make(map[string]bool),
+		ProviderSecrets:    make(map[string]string),
 	}
 }
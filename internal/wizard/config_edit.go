@@ -0,0 +1,197 @@
+package wizard
+
+import (
+	"fmt"
+	"strings"
+
+	toml "github.com/pelletier/go-toml"
+
+	"github.com/conn-castle/agent-layer/internal/messages"
+)
+
+// SetConfigValue sets a dotted config key in content by editing only the lines that hold it, so
+// comments, key order, and the formatting of everything else are kept. A new key is added next to
+// its siblings or at the end of its table.
+// MCP servers are addressed by id (mcp.servers.<id>.<field>); setting a field on an unknown id appends a new entry.
+func SetConfigValue(content string, key string, value interface{}) (string, error) {
+	tree, err := toml.LoadBytes([]byte(content))
+	if err != nil {
+		return "", fmt.Errorf(messages.WizardParseConfigFailedFmt, err)
+	}
+	doc, err := parseConfigDoc(content)
+	if err != nil {
+		return "", fmt.Errorf(messages.WizardParseConfigFailedFmt, err)
+	}
+	keys, err := splitConfigKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	var updated string
+	if listPath, id, rest, ok := splitServerKey(keys); ok {
+		if id == "" || len(rest) == 0 {
+			return "", fmt.Errorf(messages.WizardConfigServerKeyInvalidFmt, key, strings.Join(listPath, "."))
+		}
+		if err := ensureTablePath(tree, key, listPath[:len(listPath)-1]); err != nil {
+			return "", err
+		}
+		servers, err := serverTreesAt(tree, listPath)
+		if err != nil {
+			return "", err
+		}
+		index := findServerIndex(servers, id)
+		if index < 0 {
+			idLine, err := formatTOMLValue(id)
+			if err != nil {
+				return "", err
+			}
+			rendered, err := formatTOMLValue(value)
+			if err != nil {
+				return "", err
+			}
+			updated = doc.appendArrayEntry(strings.Join(listPath, "."), "id = "+idLine, formatTOMLKeyPath(strings.Join(rest, "."))+" = "+rendered)
+		} else {
+			if err := ensureTablePath(servers[index], key, rest[:len(rest)-1]); err != nil {
+				return "", err
+			}
+			updated, err = doc.set(strings.Join(listPath, "."), index, listPath, rest, servers[index], value)
+			if err != nil {
+				return "", err
+			}
+		}
+	} else {
+		if err := ensureTablePath(tree, key, keys[:len(keys)-1]); err != nil {
+			return "", err
+		}
+		updated, err = doc.set("", -1, nil, keys, tree, value)
+		if err != nil {
+			return "", err
+		}
+	}
+	return checkEditedConfig(updated)
+}
+
+// UnsetConfigValue removes a dotted config key from content, deleting only its lines (or its table).
+// Unsetting mcp.servers.<id> removes the whole server entry; a key that is not present is an error.
+func UnsetConfigValue(content string, key string) (string, error) {
+	tree, err := toml.LoadBytes([]byte(content))
+	if err != nil {
+		return "", fmt.Errorf(messages.WizardParseConfigFailedFmt, err)
+	}
+	doc, err := parseConfigDoc(content)
+	if err != nil {
+		return "", fmt.Errorf(messages.WizardParseConfigFailedFmt, err)
+	}
+	keys, err := splitConfigKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	listPath, id, rest, ok := splitServerKey(keys)
+	if !ok {
+		if !tree.HasPath(keys) {
+			return "", fmt.Errorf(messages.WizardConfigKeyNotSetFmt, key)
+		}
+		updated, removed, err := doc.remove("", -1, nil, keys, tree)
+		if err != nil {
+			return "", err
+		}
+		if !removed {
+			return "", fmt.Errorf(messages.WizardConfigKeyNotSetFmt, key)
+		}
+		return checkEditedConfig(updated)
+	}
+	if id == "" {
+		return "", fmt.Errorf(messages.WizardConfigServerKeyInvalidFmt, key, strings.Join(listPath, "."))
+	}
+	servers, err := serverTreesAt(tree, listPath)
+	if err != nil {
+		return "", err
+	}
+	index := findServerIndex(servers, id)
+	if index < 0 || (len(rest) > 0 && !servers[index].HasPath(rest)) {
+		return "", fmt.Errorf(messages.WizardConfigKeyNotSetFmt, key)
+	}
+	if len(rest) == 0 {
+		return checkEditedConfig(doc.removeScope(strings.Join(listPath, "."), index))
+	}
+	updated, removed, err := doc.remove(strings.Join(listPath, "."), index, listPath, rest, servers[index])
+	if err != nil {
+		return "", err
+	}
+	if !removed {
+		return "", fmt.Errorf(messages.WizardConfigKeyNotSetFmt, key)
+	}
+	return checkEditedConfig(updated)
+}
+
+// checkEditedConfig rejects an edit that left content unparsable, such as a table added where
+// dotted keys already define it.
+func checkEditedConfig(content string) (string, error) {
+	if _, err := toml.LoadBytes([]byte(content)); err != nil {
+		return "", fmt.Errorf(messages.WizardParseConfigFailedFmt, err)
+	}
+	return content, nil
+}
+
+// ParseConfigValue interprets a command-line value as a TOML literal (true, 5, ["a", "b"], "quoted").
+// Anything that does not parse as TOML is used as a plain string, so `commands` needs no quoting.
+func ParseConfigValue(raw string) interface{} {
+	tree, err := toml.Load("value = " + raw)
+	if err != nil {
+		return raw
+	}
+	return tree.Get("value")
+}
+
+// splitConfigKey splits a dotted key, rejecting empty segments.
+func splitConfigKey(key string) ([]string, error) {
+	keys := strings.Split(key, ".")
+	for _, part := range keys {
+		if strings.TrimSpace(part) == "" {
+			return nil, fmt.Errorf(messages.WizardConfigKeyInvalidFmt, key)
+		}
+	}
+	return keys, nil
+}
+
+// splitServerKey detects keys that address an MCP server list (mcp.servers or profiles.<name>.mcp.servers).
+// It returns the list path, the server id (empty when absent), and the field path within the server.
+func splitServerKey(keys []string) (listPath []string, id string, field []string, ok bool) {
+	for i := 1; i < len(keys); i++ {
+		if keys[i-1] != "mcp" || keys[i] != "servers" {
+			continue
+		}
+		listPath = keys[:i+1]
+		if i+1 < len(keys) {
+			id = keys[i+1]
+			field = keys[i+2:]
+		}
+		return listPath, id, field, true
+	}
+	return nil, "", nil, false
+}
+
+// ensureTablePath verifies that every existing element of path is a table so SetPath can descend into it.
+func ensureTablePath(tree *toml.Tree, key string, path []string) error {
+	for i := 1; i <= len(path); i++ {
+		value := tree.GetPath(path[:i])
+		if value == nil {
+			return nil
+		}
+		if _, ok := value.(*toml.Tree); !ok {
+			return fmt.Errorf(messages.WizardConfigKeyNotTableFmt, key, strings.Join(path[:i], "."))
+		}
+	}
+	return nil
+}
+
+// findServerIndex returns the position of the server entry with id, or -1.
+func findServerIndex(servers []*toml.Tree, id string) int {
+	for i, server := range servers {
+		if serverID, ok := server.Get("id").(string); ok && serverID == id {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,229 @@
+package wizard
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/conn-castle/agent-layer/internal/templates"
+)
+
+const editBaseConfig = `[approvals]
+# one of: "all", "mcp", "commands", "none"
+mode = "all"
+
+[agents.claude]
+enabled = true
+
+[[mcp.servers]]
+id = "github"
+enabled = false # toggled by scripts
+transport = "http"
+url = "https://example.com/mcp"
+`
+
+func TestSetConfigValuePreservesComment(t *testing.T) {
+	out, err := SetConfigValue(editBaseConfig, "approvals.mode", "commands")
+	if err != nil {
+		t.Fatalf("SetConfigValue error: %v", err)
+	}
+	if !strings.Contains(out, "# one of: \"all\", \"mcp\", \"commands\", \"none\"\nmode = \"commands\"") {
+		t.Fatalf("expected updated mode with comment, got:\n%s", out)
+	}
+}
+
+func TestSetConfigValueServerByID(t *testing.T) {
+	out, err := SetConfigValue(editBaseConfig, "mcp.servers.github.enabled", true)
+	if err != nil {
+		t.Fatalf("SetConfigValue error: %v", err)
+	}
+	if out != strings.Replace(editBaseConfig, "enabled = false # toggled", "enabled = true # toggled", 1) {
+		t.Fatalf("expected only the enabled line to change, got:\n%s", out)
+	}
+
+	out, err = SetConfigValue(out, "mcp.servers.local.command", "run-server")
+	if err != nil {
+		t.Fatalf("SetConfigValue new server error: %v", err)
+	}
+	if !strings.HasSuffix(out, "url = \"https://example.com/mcp\"\n\n[[mcp.servers]]\nid = \"local\"\ncommand = \"run-server\"\n") {
+		t.Fatalf("expected appended server, got:\n%s", out)
+	}
+
+	out, err = SetConfigValue(out, "mcp.servers.local.args", ParseConfigValue(`["--port", "1"]`))
+	if err != nil {
+		t.Fatalf("SetConfigValue args error: %v", err)
+	}
+	if !strings.HasSuffix(out, "command = \"run-server\"\nargs = [\"--port\", \"1\"]\n") {
+		t.Fatalf("expected args next to command, got:\n%s", out)
+	}
+}
+
+func TestSetConfigValueProfileServer(t *testing.T) {
+	out, err := SetConfigValue(editBaseConfig, "profiles.review.mcp.servers.github.enabled", false)
+	if err != nil {
+		t.Fatalf("SetConfigValue error: %v", err)
+	}
+	if out != editBaseConfig+"\n[[profiles.review.mcp.servers]]\nid = \"github\"\nenabled = false\n" {
+		t.Fatalf("expected profile server entry, got:\n%s", out)
+	}
+}
+
+func TestSetConfigValueNewKeys(t *testing.T) {
+	cases := []struct {
+		key   string
+		value interface{}
+		want  string
+	}{
+		{"agents.claude.model", "opus", "[agents.claude]\nenabled = true\nmodel = \"opus\"\n"},
+		{"approvals.extra", int64(5), "mode = \"all\"\nextra = 5\n"},
+		{"agents.codex.model", "gpt", editBaseConfig + "\n[agents.codex]\nmodel = \"gpt\"\n"},
+		{"config_version", int64(1), "config_version = 1\n\n[approvals]"},
+	}
+	for _, tc := range cases {
+		out, err := SetConfigValue(editBaseConfig, tc.key, tc.value)
+		if err != nil {
+			t.Fatalf("SetConfigValue(%s) error: %v", tc.key, err)
+		}
+		if !strings.Contains(out, tc.want) {
+			t.Fatalf("SetConfigValue(%s): expected %q in:\n%s", tc.key, tc.want, out)
+		}
+	}
+}
+
+func TestSetConfigValueInlineTable(t *testing.T) {
+	content := "[[mcp.servers]]\nid = \"local\"\nenv = { A = \"1\" } # keep\n"
+	out, err := SetConfigValue(content, "mcp.servers.local.env.B", "2")
+	if err != nil {
+		t.Fatalf("SetConfigValue error: %v", err)
+	}
+	if out != "[[mcp.servers]]\nid = \"local\"\nenv = { A = \"1\", B = \"2\" } # keep\n" {
+		t.Fatalf("expected inline table updated in place, got:\n%s", out)
+	}
+	out, err = UnsetConfigValue(out, "mcp.servers.local.env.A")
+	if err != nil {
+		t.Fatalf("UnsetConfigValue error: %v", err)
+	}
+	if out != "[[mcp.servers]]\nid = \"local\"\nenv = { B = \"2\" } # keep\n" {
+		t.Fatalf("expected inline table key removed, got:\n%s", out)
+	}
+}
+
+// TestSetConfigValueTemplateRoundTrip edits the shipped template and checks that only the edited
+// line changes.
+func TestSetConfigValueTemplateRoundTrip(t *testing.T) {
+	data, err := templates.Read("config.toml")
+	if err != nil {
+		t.Fatalf("read template: %v", err)
+	}
+	original := string(data)
+	cases := []struct {
+		key   string
+		value interface{}
+		line  string
+	}{
+		{"approvals.mode", "commands", `mode = "commands"`},
+		{"agents.codex.reasoning_effort", "low", `reasoning_effort = "low" # codex only`},
+		{"mcp.servers.github.enabled", true, "enabled = true"},
+		{"mcp.servers.context7.env.CONTEXT7_API_KEY", "${KEY}", `env = { CONTEXT7_API_KEY = "${KEY}" }`},
+		{"mcp.servers.filesystem.args", []interface{}{"-y"}, `args = ["-y"]`},
+		{"warnings.mcp_server_threshold", int64(20), "mcp_server_threshold = 20"},
+	}
+	for _, tc := range cases {
+		out, err := SetConfigValue(original, tc.key, tc.value)
+		if err != nil {
+			t.Fatalf("SetConfigValue(%s) error: %v", tc.key, err)
+		}
+		before, after := strings.Split(original, "\n"), strings.Split(out, "\n")
+		if len(before) != len(after) {
+			t.Fatalf("SetConfigValue(%s) changed the line count:\n%s", tc.key, out)
+		}
+		var changed []string
+		for i := range before {
+			if before[i] != after[i] {
+				changed = append(changed, after[i])
+			}
+		}
+		if len(changed) != 1 || changed[0] != tc.line {
+			t.Fatalf("SetConfigValue(%s): expected only %q to change, got %q", tc.key, tc.line, changed)
+		}
+	}
+
+	out, err := UnsetConfigValue(original, "mcp.servers.tavily")
+	if err != nil {
+		t.Fatalf("UnsetConfigValue error: %v", err)
+	}
+	removed := "\n[[mcp.servers]]\nid = \"tavily\"\nenabled = false\ntransport = \"http\"\nhttp_transport = \"streamable\"\nurl = \"https://mcp.tavily.com/mcp/?tavilyApiKey=${TAVILY_API_KEY}\"\n"
+	if out != strings.Replace(original, removed, "", 1) {
+		t.Fatalf("expected only the tavily entry to be removed, got:\n%s", out)
+	}
+}
+
+func TestSetConfigValueErrors(t *testing.T) {
+	cases := map[string]string{
+		"agents..model":           "invalid config key",
+		"mcp.servers":             "address MCP servers as mcp.servers.<id>.<field>",
+		"mcp.servers.github":      "address MCP servers as mcp.servers.<id>.<field>",
+		"approvals.mode.nested":   "approvals.mode is not a table",
+		"agents.claude.enabled.x": "agents.claude.enabled is not a table",
+	}
+	for key, want := range cases {
+		if _, err := SetConfigValue(editBaseConfig, key, "x"); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("SetConfigValue(%s) error = %v, want %q", key, err, want)
+		}
+	}
+	if _, err := SetConfigValue("[broken", "a", "b"); err == nil || !strings.Contains(err.Error(), "parse config") {
+		t.Fatalf("expected parse error, got %v", err)
+	}
+}
+
+func TestUnsetConfigValue(t *testing.T) {
+	out, err := UnsetConfigValue(editBaseConfig, "agents.claude.enabled")
+	if err != nil {
+		t.Fatalf("UnsetConfigValue error: %v", err)
+	}
+	if strings.Contains(out, "enabled = true") {
+		t.Fatalf("expected key removed, got:\n%s", out)
+	}
+
+	out, err = UnsetConfigValue(editBaseConfig, "mcp.servers.github.url")
+	if err != nil {
+		t.Fatalf("UnsetConfigValue server field error: %v", err)
+	}
+	if strings.Contains(out, "url =") || !strings.Contains(out, "id = \"github\"") {
+		t.Fatalf("expected url removed, got:\n%s", out)
+	}
+
+	out, err = UnsetConfigValue(editBaseConfig, "mcp.servers.github")
+	if err != nil {
+		t.Fatalf("UnsetConfigValue server error: %v", err)
+	}
+	if strings.Contains(out, "mcp.servers") {
+		t.Fatalf("expected server list removed, got:\n%s", out)
+	}
+
+	for _, key := range []string{"agents.gemini.model", "mcp.servers.missing", "mcp.servers.github.command"} {
+		if _, err := UnsetConfigValue(editBaseConfig, key); err == nil || !strings.Contains(err.Error(), "is not set") {
+			t.Fatalf("UnsetConfigValue(%s) error = %v, want not set", key, err)
+		}
+	}
+	if _, err := UnsetConfigValue(editBaseConfig, "mcp.servers"); err == nil {
+		t.Fatalf("expected error for server list without id")
+	}
+}
+
+func TestParseConfigValue(t *testing.T) {
+	cases := map[string]string{
+		"true":          "bool:true",
+		"5":             "int64:5",
+		"commands":      "string:commands",
+		`"42"`:          "string:42",
+		"gpt-5.2-codex": "string:gpt-5.2-codex",
+		`["a", "b"]`:    "[]interface {}:[a b]",
+	}
+	for raw, want := range cases {
+		got := ParseConfigValue(raw)
+		if desc := fmt.Sprintf("%T:%v", got, got); desc != want {
+			t.Fatalf("ParseConfigValue(%q) = %s, want %s", raw, desc, want)
+		}
+	}
+}
@@ -0,0 +1,439 @@
+package wizard
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	toml "github.com/pelletier/go-toml"
+	"github.com/pelletier/go-toml/v2/unstable"
+)
+
+// configDoc locates the key/values and table headers of a config file so single keys can be
+// edited in place, leaving comments, order, and formatting of the other lines alone.
+type configDoc struct {
+	data    []byte
+	lines   []string
+	entries []configEntry
+}
+
+// configEntry is a key/value or a table header. Scope is the array table key and Index the entry
+// of it ([[mcp.servers]] number Index) the line belongs to; Scope is empty outside array tables.
+type configEntry struct {
+	Key     string
+	Table   string
+	Header  bool
+	Scope   string
+	Index   int
+	Start   int
+	End     int
+	ValueAt int
+	Comment string
+}
+
+var bareKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// parseConfigDoc indexes content; lines are 1-based.
+func parseConfigDoc(content string) (*configDoc, error) {
+	doc := &configDoc{data: []byte(content), lines: strings.Split(content, "\n")}
+	var parser unstable.Parser
+	parser.KeepComments = true
+	parser.Reset(doc.data)
+
+	table, scope, index := "", "", -1
+	arrays := map[string]int{}
+	var starts []int
+	for parser.NextExpression() {
+		expr := parser.Expression()
+		if expr.Kind == unstable.Comment {
+			starts = append(starts, parser.Shape(expr.Raw).Start.Line)
+			continue
+		}
+		key, line, keyEnd := exprKey(&parser, expr)
+		starts = append(starts, line)
+		switch expr.Kind {
+		case unstable.Table, unstable.ArrayTable:
+			table = key
+			if expr.Kind == unstable.ArrayTable {
+				arrays[key]++
+			}
+			scope, index = arrayScope(key, arrays)
+			doc.entries = append(doc.entries, configEntry{Key: key, Header: true, Scope: scope, Index: index, Start: line, End: line})
+		case unstable.KeyValue:
+			entry := configEntry{Key: joinKey(table, key), Table: table, Scope: scope, Index: index, Start: line}
+			entry.ValueAt = keyEnd + bytes.IndexByte(doc.data[keyEnd:], '=') + 1
+			if next := expr.Next(); next != nil && next.Valid() && next.Kind == unstable.Comment {
+				entry.Comment = string(next.Data)
+			}
+			doc.entries = append(doc.entries, entry)
+		}
+	}
+	if err := parser.Error(); err != nil {
+		return nil, err
+	}
+
+	// A key/value runs until the next expression, less trailing blank lines.
+	last := len(doc.lines)
+	for i := range doc.entries {
+		entry := &doc.entries[i]
+		if entry.Header {
+			continue
+		}
+		end := last
+		for _, start := range starts {
+			if start > entry.Start && start-1 < end {
+				end = start - 1
+			}
+		}
+		for end > entry.Start && strings.TrimSpace(doc.lines[end-1]) == "" {
+			end--
+		}
+		entry.End = end
+	}
+	return doc, nil
+}
+
+// exprKey returns the dotted key of a table header or key/value, its line, and the byte offset
+// just past the key.
+func exprKey(parser *unstable.Parser, expr *unstable.Node) (string, int, int) {
+	var parts []string
+	line, end := 0, 0
+	it := expr.Key()
+	for it.Next() {
+		part := it.Node()
+		if len(parts) == 0 {
+			line = parser.Shape(part.Raw).Start.Line
+		}
+		end = int(part.Raw.Offset + part.Raw.Length)
+		parts = append(parts, string(part.Data))
+	}
+	return strings.Join(parts, "."), line, end
+}
+
+// arrayScope returns the innermost array table that key is, or is nested in, and the entry number.
+func arrayScope(key string, arrays map[string]int) (string, int) {
+	scope := ""
+	for array := range arrays {
+		if (key == array || strings.HasPrefix(key, array+".")) && len(array) > len(scope) {
+			scope = array
+		}
+	}
+	if scope == "" {
+		return "", -1
+	}
+	return scope, arrays[scope] - 1
+}
+
+// keyValue returns the key/value for key within an array table entry (or outside them).
+func (d *configDoc) keyValue(scope string, index int, key string) (configEntry, bool) {
+	for _, entry := range d.entries {
+		if !entry.Header && entry.Scope == scope && entry.Index == index && entry.Key == key {
+			return entry, true
+		}
+	}
+	return configEntry{}, false
+}
+
+// set assigns value to prefix+field within scope/index: in place when the key or an inline table
+// holding it exists, otherwise next to its siblings or at the end of its table. target is the parsed
+// table that field is relative to.
+func (d *configDoc) set(scope string, index int, prefix []string, field []string, target *toml.Tree, value interface{}) (string, error) {
+	full := joinKey(append(append([]string{}, prefix...), field...)...)
+	for i := len(field); i > 0; i-- {
+		entry, ok := d.keyValue(scope, index, joinKey(append(append([]string{}, prefix...), field[:i]...)...))
+		if !ok {
+			continue
+		}
+		newValue := value
+		if i < len(field) {
+			inline, ok := target.GetPath(field[:i]).(*toml.Tree)
+			if !ok {
+				continue
+			}
+			table := inline.ToMap()
+			setNested(table, field[i:], value)
+			newValue = table
+		}
+		rendered, err := formatTOMLValue(newValue)
+		if err != nil {
+			return "", err
+		}
+		return d.replaceValue(entry, rendered), nil
+	}
+
+	rendered, err := formatTOMLValue(value)
+	if err != nil {
+		return "", err
+	}
+	parent := parentKey(full)
+	var sibling *configEntry
+	for i := range d.entries {
+		entry := &d.entries[i]
+		if !entry.Header && entry.Scope == scope && entry.Index == index && parentKey(entry.Key) == parent {
+			sibling = entry
+		}
+	}
+	if sibling != nil {
+		indent := leadingSpace(d.lines[sibling.Start-1])
+		return d.insert(sibling.End, indent+relativeKey(full, sibling.Table)+" = "+rendered), nil
+	}
+
+	var header *configEntry
+	for i := range d.entries {
+		entry := &d.entries[i]
+		if entry.Header && entry.Scope == scope && entry.Index == index && (parent == entry.Key || strings.HasPrefix(parent, entry.Key+".")) {
+			if header == nil || len(entry.Key) > len(header.Key) {
+				header = entry
+			}
+		}
+	}
+	if header != nil {
+		after := header.Start
+		for _, entry := range d.entries {
+			if !entry.Header && entry.Scope == scope && entry.Index == index && entry.Table == header.Key && entry.End > after {
+				after = entry.End
+			}
+		}
+		return d.insert(after, relativeKey(full, header.Key)+" = "+rendered), nil
+	}
+	if parent == "" {
+		at := 0
+		for at < len(d.lines) && strings.HasPrefix(strings.TrimSpace(d.lines[at]), "#:") {
+			at++
+		}
+		line := full + " = " + rendered
+		if at < len(d.lines) && strings.TrimSpace(d.lines[at]) != "" {
+			return d.insert(at, line, ""), nil
+		}
+		return d.insert(at, line), nil
+	}
+	return d.appendBlock("["+formatTOMLKeyPath(parent)+"]", formatTOMLKeyPath(field[len(field)-1])+" = "+rendered), nil
+}
+
+// remove deletes prefix+field within scope/index: its key/values and table blocks, or its entry in
+// an inline table. It reports false when nothing matched.
+func (d *configDoc) remove(scope string, index int, prefix []string, field []string, target *toml.Tree) (string, bool, error) {
+	full := joinKey(append(append([]string{}, prefix...), field...)...)
+	drop := map[int]bool{}
+	for _, entry := range d.entries {
+		if entry.Scope != scope || entry.Index != index || (entry.Key != full && !strings.HasPrefix(entry.Key, full+".")) {
+			continue
+		}
+		if entry.Header {
+			d.dropBlock(drop, entry)
+			continue
+		}
+		for line := entry.Start; line <= entry.End; line++ {
+			drop[line] = true
+		}
+	}
+	if len(drop) > 0 {
+		return d.without(drop), true, nil
+	}
+
+	for i := len(field) - 1; i > 0; i-- {
+		entry, ok := d.keyValue(scope, index, joinKey(append(append([]string{}, prefix...), field[:i]...)...))
+		if !ok {
+			continue
+		}
+		inline, ok := target.GetPath(field[:i]).(*toml.Tree)
+		if !ok {
+			continue
+		}
+		table := inline.ToMap()
+		deleteNested(table, field[i:])
+		rendered, err := formatTOMLValue(table)
+		if err != nil {
+			return "", false, err
+		}
+		return d.replaceValue(entry, rendered), true, nil
+	}
+	return "", false, nil
+}
+
+// removeScope deletes every table block of one array table entry.
+func (d *configDoc) removeScope(scope string, index int) string {
+	drop := map[int]bool{}
+	for _, entry := range d.entries {
+		if entry.Header && entry.Scope == scope && entry.Index == index {
+			d.dropBlock(drop, entry)
+		}
+	}
+	return d.without(drop)
+}
+
+// appendArrayEntry adds a new [[key]] entry holding lines at the end of the file.
+func (d *configDoc) appendArrayEntry(key string, lines ...string) string {
+	return d.appendBlock(append([]string{"[[" + formatTOMLKeyPath(key) + "]]"}, lines...)...)
+}
+
+// dropBlock marks a table header, the lines up to the next header, and one blank line above it.
+func (d *configDoc) dropBlock(drop map[int]bool, header configEntry) {
+	end := len(d.lines)
+	for _, next := range d.entries {
+		if next.Header && next.Start > header.Start && next.Start-1 < end {
+			end = next.Start - 1
+		}
+	}
+	for end > header.Start && strings.TrimSpace(d.lines[end-1]) == "" {
+		end--
+	}
+	for line := header.Start; line <= end; line++ {
+		drop[line] = true
+	}
+	if header.Start > 1 && strings.TrimSpace(d.lines[header.Start-2]) == "" {
+		drop[header.Start-1] = true
+	}
+}
+
+// replaceValue rewrites entry's value, keeping its key text, indentation, and trailing comment.
+func (d *configDoc) replaceValue(entry configEntry, rendered string) string {
+	lineStart := 0
+	for i := 0; i < entry.Start-1; i++ {
+		lineStart += len(d.lines[i]) + 1
+	}
+	line := string(d.data[lineStart:entry.ValueAt]) + " " + rendered
+	if entry.Comment != "" {
+		line += " " + entry.Comment
+	}
+	out := append([]string{}, d.lines[:entry.Start-1]...)
+	out = append(out, line)
+	out = append(out, d.lines[entry.End:]...)
+	return strings.Join(out, "\n")
+}
+
+// insert places lines after 1-based line after (0 inserts at the top).
+func (d *configDoc) insert(after int, lines ...string) string {
+	out := append([]string{}, d.lines[:after]...)
+	out = append(out, lines...)
+	out = append(out, d.lines[after:]...)
+	return strings.Join(out, "\n")
+}
+
+// appendBlock adds lines at the end of the file, separated from existing content by a blank line.
+func (d *configDoc) appendBlock(lines ...string) string {
+	content := strings.TrimRight(string(d.data), "\n")
+	if content != "" {
+		content += "\n\n"
+	}
+	return content + strings.Join(lines, "\n") + "\n"
+}
+
+// without returns the content minus the marked 1-based lines.
+func (d *configDoc) without(drop map[int]bool) string {
+	out := make([]string, 0, len(d.lines))
+	for i, line := range d.lines {
+		if !drop[i+1] {
+			out = append(out, line)
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// formatTOMLValue renders value as a TOML literal; tables are rendered inline.
+func formatTOMLValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case *toml.Tree:
+		return formatTOMLValue(v.ToMap())
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return "{}", nil
+		}
+		parts := make([]string, 0, len(v))
+		for _, key := range sortedKeys(v) {
+			rendered, err := formatTOMLValue(v[key])
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, formatTOMLKeyPath(key)+" = "+rendered)
+		}
+		return "{ " + strings.Join(parts, ", ") + " }", nil
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			rendered, err := formatTOMLValue(item)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, rendered)
+		}
+		return "[" + strings.Join(parts, ", ") + "]", nil
+	case int:
+		value = int64(v)
+	}
+	tree, err := toml.TreeFromMap(map[string]interface{}{"v": value})
+	if err != nil {
+		return "", err
+	}
+	out, err := tree.ToTomlString()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(strings.TrimPrefix(out, "v = ")), nil
+}
+
+// formatTOMLKeyPath quotes the segments of a dotted key that are not bare keys.
+func formatTOMLKeyPath(key string) string {
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		if !bareKeyPattern.MatchString(part) {
+			quoted, _ := formatTOMLValue(part)
+			parts[i] = quoted
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// relativeKey returns key below table, formatted for a key/value line.
+func relativeKey(key string, table string) string {
+	if table != "" {
+		key = strings.TrimPrefix(key, table+".")
+	}
+	return formatTOMLKeyPath(key)
+}
+
+func joinKey(parts ...string) string {
+	var nonEmpty []string
+	for _, part := range parts {
+		if part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return strings.Join(nonEmpty, ".")
+}
+
+func parentKey(key string) string {
+	if cut := strings.LastIndex(key, "."); cut >= 0 {
+		return key[:cut]
+	}
+	return ""
+}
+
+func leadingSpace(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
+
+// setNested sets keys in table, creating intermediate tables.
+func setNested(table map[string]interface{}, keys []string, value interface{}) {
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := table[key].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			table[key] = next
+		}
+		table = next
+	}
+	table[keys[len(keys)-1]] = value
+}
+
+// deleteNested removes keys from table.
+func deleteNested(table map[string]interface{}, keys []string) {
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := table[key].(map[string]interface{})
+		if !ok {
+			return
+		}
+		table = next
+	}
+	delete(table, keys[len(keys)-1])
+}
@@ -61,10 +61,16 @@ func buildSummary(c *Choices) string {
 	}
 
 	sb.WriteString(messages.WizardSummarySecretsHeader)
-	if len(c.Secrets) > 0 {
+	if len(c.Secrets)+len(c.ProviderSecrets)+len(c.SecretReferences) > 0 {
 		for k := range c.Secrets {
 			sb.WriteString(fmt.Sprintf(messages.WizardSummaryListItemFmt, k))
 		}
+		for _, k := range sortedKeys(c.ProviderSecrets) {
+			sb.WriteString(fmt.Sprintf(messages.WizardSummaryProviderSecretFmt, k, c.SecretStore.Name()))
+		}
+		for _, k := range sortedKeys(c.SecretReferences) {
+			sb.WriteString(fmt.Sprintf(messages.WizardSummarySecretReferenceFmt, k, k))
+		}
 	} else {
 		sb.WriteString(messages.WizardSummaryNone)
 	}
@@ -260,3 +266,13 @@ func restoredMCPServers(c *Choices) []string {
 	ids = append(ids, c.MissingDefaultMCPServers...)
 	return ids
 }
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -88,7 +88,12 @@ func appendMissingDefaultMCPServers(tree *toml.Tree, missing []string) error {
 // mcpServerTrees returns the parsed MCP server trees from the config.
 // tree is the parsed config; returns a slice of server trees or an error for unexpected data.
 func mcpServerTrees(tree *toml.Tree) ([]*toml.Tree, error) {
-	raw := tree.GetPath([]string{"mcp", "servers"})
+	return serverTreesAt(tree, []string{"mcp", "servers"})
+}
+
+// serverTreesAt returns the array-of-tables at path (for example a profile's mcp.servers).
+func serverTreesAt(tree *toml.Tree, path []string) ([]*toml.Tree, error) {
+	raw := tree.GetPath(path)
 	if raw == nil {
 		return nil, nil
 	}
@@ -6,6 +6,7 @@ import (
 
 	toml "github.com/pelletier/go-toml"
 
+	"github.com/conn-castle/agent-layer/internal/config"
 	"github.com/conn-castle/agent-layer/internal/messages"
 )
 
@@ -81,6 +82,17 @@ func PatchConfig(content string, choices *Choices) (string, error) {
 		}
 	}
 
+	// Reference secrets that a provider already holds instead of .env values.
+	if len(choices.SecretReferences) > 0 {
+		servers, err := mcpServerTrees(configTree)
+		if err != nil {
+			return "", err
+		}
+		for _, server := range choices.DefaultMCPServers {
+			useSecretReferences(servers, server.ID, choices.SecretReferences)
+		}
+	}
+
 	// 4. Warnings
 	if choices.WarningsEnabledTouched {
 		if !choices.WarningsEnabled {
@@ -97,7 +109,12 @@ func PatchConfig(content string, choices *Choices) (string, error) {
 		}
 	}
 
-	updated, err := configTree.ToTomlString()
+	return renderConfigTree(configTree)
+}
+
+// renderConfigTree renders a patched config tree as unindented TOML.
+func renderConfigTree(tree *toml.Tree) (string, error) {
+	updated, err := tree.ToTomlString()
 	if err != nil {
 		return "", fmt.Errorf(messages.WizardRenderConfigFailedFmt, err)
 	}
@@ -164,3 +181,47 @@ func commentForPath(tree *toml.Tree, lines []string, keys []string) string {
 	}
 	return commentForLine(lines, pos.Line-1)
 }
+
+// useSecretReferences rewrites ${NAME} placeholders to ${secret:NAME} for names in refs across
+// a server's url, command, args, headers, and env.
+func useSecretReferences(servers []*toml.Tree, serverID string, refs map[string]bool) {
+	for _, server := range servers {
+		if id, _ := server.Get("id").(string); id != serverID {
+			continue
+		}
+		for _, key := range []string{"url", "command"} {
+			if value, ok := server.Get(key).(string); ok {
+				server.Set(key, secretReferenceValue(value, refs))
+			}
+		}
+		if args, ok := server.Get("args").([]interface{}); ok {
+			for i, arg := range args {
+				if value, ok := arg.(string); ok {
+					args[i] = secretReferenceValue(value, refs)
+				}
+			}
+			server.Set("args", args)
+		}
+		for _, table := range []string{"headers", "env"} {
+			values, ok := server.Get(table).(*toml.Tree)
+			if !ok {
+				continue
+			}
+			for _, key := range values.Keys() {
+				if value, ok := values.Get(key).(string); ok {
+					values.Set(key, secretReferenceValue(value, refs))
+				}
+			}
+		}
+	}
+}
+
+// secretReferenceValue rewrites ${NAME} and ${NAME:...} placeholders for names in refs.
+func secretReferenceValue(value string, refs map[string]bool) string {
+	for name := range refs {
+		secret := "${" + config.SecretPlaceholderPrefix + name
+		value = strings.ReplaceAll(value, "${"+name+"}", secret+"}")
+		value = strings.ReplaceAll(value, "${"+name+":", secret+":")
+	}
+	return value
+}
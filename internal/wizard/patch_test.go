@@ -450,3 +450,32 @@ func TestScanTomlLineForComment_MultilineStrings(t *testing.T) {
 		assert.Equal(t, tomlStateNone, state)
 	})
 }
+
+func TestPatchConfig_SecretReferences(t *testing.T) {
+	content := `[[mcp.servers]]
+id = "tool"
+enabled = true
+transport = "stdio"
+command = "run-${TOKEN}"
+args = ["--token", "${TOKEN:?set TOKEN}", "${OTHER}"]
+env = { API_KEY = "${TOKEN}", PLAIN = "x" }
+
+[[mcp.servers]]
+id = "custom"
+enabled = true
+transport = "stdio"
+command = "${TOKEN}"
+`
+	choices := NewChoices()
+	choices.DefaultMCPServers = []DefaultMCPServer{{ID: "tool"}}
+	choices.SecretReferences["TOKEN"] = true
+
+	out, err := PatchConfig(content, choices)
+	require.NoError(t, err)
+	assert.Contains(t, out, `command = "run-${secret:TOKEN}"`)
+	assert.Contains(t, out, `"${secret:TOKEN:?set TOKEN}"`)
+	assert.Contains(t, out, `"${OTHER}"`)
+	assert.Contains(t, out, `API_KEY = "${secret:TOKEN}"`)
+	// Only default servers are rewritten.
+	assert.Contains(t, out, `command = "${TOKEN}"`)
+}
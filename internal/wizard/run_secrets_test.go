@@ -4,11 +4,13 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/conn-castle/agent-layer/internal/secrets"
 	"github.com/conn-castle/agent-layer/internal/warnings"
 )
 
@@ -461,3 +463,109 @@ func TestRun_ExistingSecret_OverrideConfirmError(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "override confirm error")
 }
+
+func TestRun_SecretFromProvider_ReferencesSecret(t *testing.T) {
+	t.Setenv("GITHUB_PERSONAL_ACCESS_TOKEN", "")
+	root := t.TempDir()
+	setupRepo(t, root)
+	configDir := filepath.Join(root, ".agent-layer")
+	secretsDir := filepath.Join(root, "secrets")
+	require.NoError(t, os.MkdirAll(secretsDir, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(secretsDir, "GITHUB_PERSONAL_ACCESS_TOKEN"), []byte("from-provider\n"), 0o600))
+
+	initialConfig := basicAgentConfig() + `
+[[secrets.providers]]
+type = "file"
+dir = "secrets"
+`
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.toml"), []byte(initialConfig), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, ".env"), []byte(""), 0600))
+
+	var prompts []string
+	ui := &MockUI{
+		NoteFunc:   func(title, body string) error { return nil },
+		SelectFunc: func(title string, options []string, current *string) error { return nil },
+		MultiSelectFunc: func(title string, options []string, selected *[]string) error {
+			if title == "Enable Default MCP Servers" {
+				*selected = []string{"github"}
+			}
+			return nil
+		},
+		SecretInputFunc: func(title string, value *string) error {
+			t.Fatalf("unexpected secret prompt %q", title)
+			return nil
+		},
+		ConfirmFunc: func(title string, value *bool) error {
+			prompts = append(prompts, title)
+			*value = true
+			return nil
+		},
+	}
+
+	err := Run(root, ui, func(r string) ([]warnings.Warning, error) { return nil, nil }, "")
+	require.NoError(t, err)
+
+	assert.Contains(t, prompts, "Found GITHUB_PERSONAL_ACCESS_TOKEN in secret provider file ("+secretsDir+"). Reference it as ${secret:GITHUB_PERSONAL_ACCESS_TOKEN} instead of writing it to .agent-layer/.env?")
+	configData, err := os.ReadFile(filepath.Join(configDir, "config.toml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(configData), "Bearer ${secret:GITHUB_PERSONAL_ACCESS_TOKEN}")
+	envData, err := os.ReadFile(filepath.Join(configDir, ".env"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(envData), "GITHUB_PERSONAL_ACCESS_TOKEN")
+}
+
+func TestRun_SecretReference_StoresInProvider(t *testing.T) {
+	t.Setenv("GITHUB_PERSONAL_ACCESS_TOKEN", "")
+	root := t.TempDir()
+	setupRepo(t, root)
+	configDir := filepath.Join(root, ".agent-layer")
+	keyringFile := filepath.Join(root, "keyring.json")
+
+	initialConfig := basicAgentConfig() + `
+[[secrets.providers]]
+type = "keyring"
+file = "keyring.json"
+
+[[mcp.servers]]
+id = "github"
+enabled = false
+transport = "http"
+url = "https://api.githubcopilot.com/mcp/"
+headers = { Authorization = "Bearer ${secret:GITHUB_PERSONAL_ACCESS_TOKEN}" }
+`
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.toml"), []byte(initialConfig), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, ".env"), []byte(""), 0600))
+
+	var secretPrompt string
+	ui := &MockUI{
+		NoteFunc:   func(title, body string) error { return nil },
+		SelectFunc: func(title string, options []string, current *string) error { return nil },
+		MultiSelectFunc: func(title string, options []string, selected *[]string) error {
+			if title == "Enable Default MCP Servers" {
+				*selected = []string{"github"}
+			}
+			return nil
+		},
+		SecretInputFunc: func(title string, value *string) error {
+			secretPrompt = title
+			*value = "stored-token"
+			return nil
+		},
+		ConfirmFunc: func(title string, value *bool) error {
+			// Decline restoring the other default servers; accept everything else.
+			*value = !strings.HasPrefix(title, "Default MCP server entries are missing")
+			return nil
+		},
+	}
+
+	err := Run(root, ui, func(r string) ([]warnings.Warning, error) { return nil, nil }, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Enter GITHUB_PERSONAL_ACCESS_TOKEN to store in secret provider keyring ("+keyringFile+") (leave blank to skip)", secretPrompt)
+	value, err := (secrets.KeyringProvider{File: keyringFile}).Lookup("GITHUB_PERSONAL_ACCESS_TOKEN")
+	require.NoError(t, err)
+	assert.Equal(t, "stored-token", value)
+	envData, err := os.ReadFile(filepath.Join(configDir, ".env"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(envData), "stored-token")
+}
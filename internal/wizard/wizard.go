@@ -43,8 +43,16 @@ func Run(root string, ui UI, runSync syncer, pinVersion string) error {
 		fmt.Println(messages.WizardInstallComplete)
 	}
 
-	// 3. Load config
-	cfg, err := config.LoadProjectConfig(root)
+	// 3. Load config: validate the merged layers, but seed choices from the repo config the wizard edits.
+	project, err := config.LoadProjectConfig(root)
+	if err != nil {
+		return fmt.Errorf(messages.WizardLoadConfigFailedFmt, err)
+	}
+	providers, err := config.SecretProviders(&project.Config, root)
+	if err != nil {
+		return fmt.Errorf(messages.WizardLoadConfigFailedFmt, err)
+	}
+	cfg, err := config.LoadConfigLayer(configPath)
 	if err != nil {
 		return fmt.Errorf(messages.WizardLoadConfigFailedFmt, err)
 	}
@@ -69,58 +77,58 @@ func Run(root string, ui UI, runSync syncer, pinVersion string) error {
 	choices.MCPSchemaTokensServerThreshold = warningDefaults.MCPSchemaTokensServerThreshold
 
 	// Approvals
-	choices.ApprovalMode = cfg.Config.Approvals.Mode
+	choices.ApprovalMode = cfg.Approvals.Mode
 	if choices.ApprovalMode == "" {
 		choices.ApprovalMode = ApprovalAll
 	}
 
 	// Agents
 	agentConfigs := []agentEnabledConfig{
-		{id: AgentGemini, enabled: cfg.Config.Agents.Gemini.Enabled},
-		{id: AgentClaude, enabled: cfg.Config.Agents.Claude.Enabled},
-		{id: AgentCodex, enabled: cfg.Config.Agents.Codex.Enabled},
-		{id: AgentVSCode, enabled: cfg.Config.Agents.VSCode.Enabled},
-		{id: AgentAntigravity, enabled: cfg.Config.Agents.Antigravity.Enabled},
+		{id: AgentGemini, enabled: cfg.Agents.Gemini.Enabled},
+		{id: AgentClaude, enabled: cfg.Agents.Claude.Enabled},
+		{id: AgentCodex, enabled: cfg.Agents.Codex.Enabled},
+		{id: AgentVSCode, enabled: cfg.Agents.VSCode.Enabled},
+		{id: AgentAntigravity, enabled: cfg.Agents.Antigravity.Enabled},
 	}
 	setEnabledAgentsFromConfig(choices.EnabledAgents, agentConfigs)
 
 	// Models
-	choices.GeminiModel = cfg.Config.Agents.Gemini.Model
-	choices.ClaudeModel = cfg.Config.Agents.Claude.Model
-	choices.CodexModel = cfg.Config.Agents.Codex.Model
-	choices.CodexReasoning = cfg.Config.Agents.Codex.ReasoningEffort
+	choices.GeminiModel = cfg.Agents.Gemini.Model
+	choices.ClaudeModel = cfg.Agents.Claude.Model
+	choices.CodexModel = cfg.Agents.Codex.Model
+	choices.CodexReasoning = cfg.Agents.Codex.ReasoningEffort
 
 	// MCP Servers
-	for _, srv := range cfg.Config.MCP.Servers {
+	for _, srv := range cfg.MCP.Servers {
 		if srv.Enabled != nil && *srv.Enabled {
 			choices.EnabledMCPServers[srv.ID] = true
 		}
 	}
 
 	// Warnings
-	choices.WarningsEnabled = cfg.Config.Warnings.InstructionTokenThreshold != nil ||
-		cfg.Config.Warnings.MCPServerThreshold != nil ||
-		cfg.Config.Warnings.MCPToolsTotalThreshold != nil ||
-		cfg.Config.Warnings.MCPServerToolsThreshold != nil ||
-		cfg.Config.Warnings.MCPSchemaTokensTotalThreshold != nil ||
-		cfg.Config.Warnings.MCPSchemaTokensServerThreshold != nil
-	if cfg.Config.Warnings.InstructionTokenThreshold != nil {
-		choices.InstructionTokenThreshold = *cfg.Config.Warnings.InstructionTokenThreshold
+	choices.WarningsEnabled = cfg.Warnings.InstructionTokenThreshold != nil ||
+		cfg.Warnings.MCPServerThreshold != nil ||
+		cfg.Warnings.MCPToolsTotalThreshold != nil ||
+		cfg.Warnings.MCPServerToolsThreshold != nil ||
+		cfg.Warnings.MCPSchemaTokensTotalThreshold != nil ||
+		cfg.Warnings.MCPSchemaTokensServerThreshold != nil
+	if cfg.Warnings.InstructionTokenThreshold != nil {
+		choices.InstructionTokenThreshold = *cfg.Warnings.InstructionTokenThreshold
 	}
-	if cfg.Config.Warnings.MCPServerThreshold != nil {
-		choices.MCPServerThreshold = *cfg.Config.Warnings.MCPServerThreshold
+	if cfg.Warnings.MCPServerThreshold != nil {
+		choices.MCPServerThreshold = *cfg.Warnings.MCPServerThreshold
 	}
-	if cfg.Config.Warnings.MCPToolsTotalThreshold != nil {
-		choices.MCPToolsTotalThreshold = *cfg.Config.Warnings.MCPToolsTotalThreshold
+	if cfg.Warnings.MCPToolsTotalThreshold != nil {
+		choices.MCPToolsTotalThreshold = *cfg.Warnings.MCPToolsTotalThreshold
 	}
-	if cfg.Config.Warnings.MCPServerToolsThreshold != nil {
-		choices.MCPServerToolsThreshold = *cfg.Config.Warnings.MCPServerToolsThreshold
+	if cfg.Warnings.MCPServerToolsThreshold != nil {
+		choices.MCPServerToolsThreshold = *cfg.Warnings.MCPServerToolsThreshold
 	}
-	if cfg.Config.Warnings.MCPSchemaTokensTotalThreshold != nil {
-		choices.MCPSchemaTokensTotalThreshold = *cfg.Config.Warnings.MCPSchemaTokensTotalThreshold
+	if cfg.Warnings.MCPSchemaTokensTotalThreshold != nil {
+		choices.MCPSchemaTokensTotalThreshold = *cfg.Warnings.MCPSchemaTokensTotalThreshold
 	}
-	if cfg.Config.Warnings.MCPSchemaTokensServerThreshold != nil {
-		choices.MCPSchemaTokensServerThreshold = *cfg.Config.Warnings.MCPSchemaTokensServerThreshold
+	if cfg.Warnings.MCPSchemaTokensServerThreshold != nil {
+		choices.MCPSchemaTokensServerThreshold = *cfg.Warnings.MCPSchemaTokensServerThreshold
 	}
 
 	// 5. UI Flow
@@ -180,7 +188,7 @@ func Run(root string, ui UI, runSync syncer, pinVersion string) error {
 	}
 
 	// MCP Servers
-	missingDefaults := missingDefaultMCPServers(choices.DefaultMCPServers, cfg.Config.MCP.Servers)
+	missingDefaults := missingDefaultMCPServers(choices.DefaultMCPServers, cfg.MCP.Servers)
 	if len(missingDefaults) > 0 {
 		choices.MissingDefaultMCPServers = missingDefaults
 		restore := true
@@ -213,10 +221,14 @@ func Run(root string, ui UI, runSync syncer, pinVersion string) error {
 	// Load existing env to know what's set
 	envPath := filepath.Join(root, ".agent-layer", ".env")
 	envValues := make(map[string]string)
-	if b, err := os.ReadFile(envPath); err == nil {
+	envSource := config.EnvFilePath(envPath)
+	if b, err := os.ReadFile(envSource); err == nil {
+		if b, err = config.DecodeEnvFile(envSource, b); err != nil {
+			return err
+		}
 		parsed, err := envfile.Parse(string(b))
 		if err != nil {
-			return fmt.Errorf(messages.WizardInvalidEnvFileFmt, envPath, err)
+			return fmt.Errorf(messages.WizardInvalidEnvFileFmt, envSource, err)
 		}
 		envValues = parsed
 	} else if !os.IsNotExist(err) {
@@ -236,7 +248,30 @@ func Run(root string, ui UI, runSync syncer, pinVersion string) error {
 				if existing, ok := choices.Secrets[key]; ok && existing != "" {
 					continue
 				}
-				if val, ok := envValues[key]; ok && val != "" {
+				// Values for ${secret:KEY} references live in a provider, never in .env.
+				store := choices.Secrets
+				prompt := fmt.Sprintf(messages.WizardSecretInputPromptFmt, key)
+				if referencesSecret(project.Config.MCP.Servers, srv.ID, key) {
+					if providers.Resolve(key).Err == nil {
+						continue
+					}
+					writer, ok := providers.Writer()
+					if !ok {
+						disable := true
+						if err := ui.Confirm(fmt.Sprintf(messages.WizardSecretNoWritableProviderFmt, key, key, srv.ID), &disable); err != nil {
+							return err
+						}
+						if disable {
+							choices.EnabledMCPServers[srv.ID] = false
+							choices.DisabledMCPServers[srv.ID] = true
+							break
+						}
+						continue
+					}
+					choices.SecretStore = writer
+					store = choices.ProviderSecrets
+					prompt = fmt.Sprintf(messages.WizardProviderSecretInputPromptFmt, key, writer.Name())
+				} else if val, ok := envValues[key]; ok && val != "" {
 					override := false
 					if err := ui.Confirm(fmt.Sprintf(messages.WizardSecretAlreadySetPromptFmt, key), &override); err != nil {
 						return err
@@ -246,6 +281,18 @@ func Run(root string, ui UI, runSync syncer, pinVersion string) error {
 						continue
 					}
 				} else {
+					if len(providers) > 0 {
+						if found := providers.Resolve(key); found.Err == nil {
+							useProvider := true
+							if err := ui.Confirm(fmt.Sprintf(messages.WizardSecretProviderFoundPromptFmt, key, found.Provider, key), &useProvider); err != nil {
+								return err
+							}
+							if useProvider {
+								choices.SecretReferences[key] = true
+								continue
+							}
+						}
+					}
 					if val := os.Getenv(key); val != "" {
 						useEnv := false
 						if err := ui.Confirm(fmt.Sprintf(messages.WizardEnvSecretFoundPromptFmt, key), &useEnv); err != nil {
@@ -260,11 +307,11 @@ func Run(root string, ui UI, runSync syncer, pinVersion string) error {
 
 				for {
 					var val string
-					if err := ui.SecretInput(fmt.Sprintf(messages.WizardSecretInputPromptFmt, key), &val); err != nil {
+					if err := ui.SecretInput(prompt, &val); err != nil {
 						return err
 					}
 					if val != "" {
-						choices.Secrets[key] = val
+						store[key] = val
 						break
 					}
 					disable := true
@@ -334,3 +381,18 @@ func Run(root string, ui UI, runSync syncer, pinVersion string) error {
 	_, _ = color.New(color.FgGreen).Println(messages.WizardCompleted)
 	return nil
 }
+
+// referencesSecret reports whether the configured server id references key as ${secret:key}.
+func referencesSecret(servers []config.MCPServer, id string, key string) bool {
+	for _, server := range servers {
+		if server.ID != id {
+			continue
+		}
+		for _, name := range config.RequiredSecretsForMCPServer(server) {
+			if name == key {
+				return true
+			}
+		}
+	}
+	return false
+}